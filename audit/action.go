@@ -49,4 +49,10 @@ const (
 
 	// Deploy is the string value for the deploy action when logging.
 	Deploy = "deploy"
+
+	// Scale is the string for the autoscale action when logging.
+	Scale = "scale"
+
+	// Preempt is the string for the preempt action when logging.
+	Preempt = "preempt"
 )
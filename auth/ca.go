@@ -0,0 +1,284 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// CAKeyFileName is where the master keeps the internal certificate
+	// authority, next to the master keys
+	CAKeyFileName = ".keys/ca.keys"
+
+	certificatePEMType = "CERTIFICATE"
+)
+
+var (
+	// ErrNoCA is returned when the internal certificate authority is not
+	// loaded, i.e. when this process is not the master
+	ErrNoCA = errors.New("Cannot retrieve the internal certificate authority")
+
+	// ErrBadCAFile is returned when the certificate authority file cannot
+	// be read
+	ErrBadCAFile = errors.New("Unable to read certificate authority file")
+
+	// CAValidity is how long a certificate authority is valid
+	CAValidity = 10 * 365 * 24 * time.Hour
+
+	// HostCertificateValidity is how long a host certificate is valid.
+	// Resetting the host key issues a new certificate.
+	HostCertificateValidity = 2 * 365 * 24 * time.Hour
+
+	caLock   sync.RWMutex
+	masterCA *certificateAuthority
+)
+
+// certificateAuthority issues the host certificates of the mux.  Previous
+// certificates are still trusted so that hosts can be moved to a rotated
+// authority one at a time.
+type certificateAuthority struct {
+	private  *rsa.PrivateKey
+	cert     *x509.Certificate
+	previous []*x509.Certificate
+}
+
+// trustedPEM returns the current and previous certificates of the authority
+func (ca *certificateAuthority) trustedPEM() []byte {
+	var out bytes.Buffer
+	for _, cert := range append([]*x509.Certificate{ca.cert}, ca.previous...) {
+		pem.Encode(&out, &pem.Block{Type: certificatePEMType, Bytes: cert.Raw})
+	}
+	return out.Bytes()
+}
+
+// GenerateCAPEM generates the private key and self-signed certificate of a
+// new certificate authority, PEM-encoded in a single byte array.
+func GenerateCAPEM() ([]byte, error) {
+	private, err := rsa.GenerateKey(rand.Reader, rsaKeyLength)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Control Center Internal CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(CAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, private.Public(), private)
+	if err != nil {
+		return nil, err
+	}
+	privatePEM, err := PEMFromRSAPrivateKey(private, map[string]string{"purpose": "ca"})
+	if err != nil {
+		return nil, err
+	}
+	return append(privatePEM, pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: der})...), nil
+}
+
+// LoadCAFromPEM sets the internal certificate authority from PEM data: its
+// private key, its certificate, then the previous certificates it still
+// trusts.
+func LoadCAFromPEM(data []byte) error {
+	ca, err := parseCA(data)
+	if err != nil {
+		return err
+	}
+	caLock.Lock()
+	masterCA = ca
+	caLock.Unlock()
+	return nil
+}
+
+func parseCA(data []byte) (*certificateAuthority, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, ErrBadCAFile
+	}
+	private, err := RSAPrivateKeyFromPEM(pem.EncodeToMemory(block))
+	if err != nil {
+		return nil, err
+	}
+	ca := &certificateAuthority{private: private}
+	for {
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type != certificatePEMType {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if ca.cert == nil {
+			ca.cert = cert
+		} else {
+			ca.previous = append(ca.previous, cert)
+		}
+	}
+	if ca.cert == nil {
+		return nil, ErrBadCAFile
+	}
+	return ca, nil
+}
+
+// LoadCAFile loads the internal certificate authority from disk.
+func LoadCAFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return LoadCAFromPEM(data)
+}
+
+// CreateOrLoadCA will load the internal certificate authority from disk.
+// If the file does not exist, it will generate a new authority and write it
+// to disk.
+func CreateOrLoadCA(filename string) error {
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		if err = os.MkdirAll(path.Dir(filename), os.ModeDir|0755); err != nil {
+			return err
+		}
+		data, err := GenerateCAPEM()
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, data, 0600); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	return LoadCAFile(filename)
+}
+
+// RotateCAFile replaces the certificate authority on disk with a new one.
+// The certificates of the old authority that have not expired are kept as
+// trusted, so hosts keep connecting until their keys are reset.
+func RotateCAFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	old, err := parseCA(data)
+	if err != nil {
+		return err
+	}
+	if data, err = GenerateCAPEM(); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, cert := range append([]*x509.Certificate{old.cert}, old.previous...) {
+		if cert.NotAfter.After(now) {
+			data = append(data, pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: cert.Raw})...)
+		}
+	}
+	return ioutil.WriteFile(filename, data, 0600)
+}
+
+// WatchCAFile reloads the internal certificate authority when the file on
+// disk changes, e.g. when it is rotated.
+func WatchCAFile(filename string, cancel <-chan interface{}) error {
+	filename = filepath.Clean(filename)
+	log := log.WithFields(logrus.Fields{
+		"cafile": filename,
+	})
+	filechanges, err := NotifyOnChange(filename, fsnotify.Write|fsnotify.Create, cancel)
+	if err != nil {
+		return err
+	}
+	for _ = range filechanges {
+		if err := LoadCAFile(filename); err != nil {
+			log.WithError(err).Warn("Unable to load certificate authority from file. Continuing to watch for changes")
+		} else {
+			log.Info("Loaded certificate authority from file")
+		}
+	}
+	return nil
+}
+
+// GetCACertificatesPEM returns the PEM-encoded certificates the internal
+// certificate authority trusts, the current one first.
+func GetCACertificatesPEM() ([]byte, error) {
+	caLock.RLock()
+	defer caLock.RUnlock()
+	if masterCA == nil {
+		return nil, ErrNoCA
+	}
+	return masterCA.trustedPEM(), nil
+}
+
+// IssueHostCertificate returns a PEM-encoded certificate for the public key
+// of a host, valid both to serve and to dial its mux.
+func IssueHostCertificate(hostID, ipAddr string, public crypto.PublicKey) ([]byte, error) {
+	caLock.RLock()
+	defer caLock.RUnlock()
+	if masterCA == nil {
+		return nil, ErrNoCA
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: hostID},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(HostCertificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ip := net.ParseIP(ipAddr); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, masterCA.cert, public, masterCA.private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: der}), nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
@@ -178,12 +178,20 @@ func getMasterPrivateKey() (crypto.PrivateKey, error) {
 	return masterKeys.private, nil
 }
 
-// LoadKeysFromFile loads keys from a file on disk.
+// LoadKeysFromFile loads keys from a file on disk, along with the mux
+// certificates that follow them.
 func LoadDelegateKeysFromFile(filename string) error {
-	pub, priv, err := LoadKeyPairFromFile(filename)
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	pub, priv, err := LoadRSAKeyPairPackage(data)
 	if err != nil {
 		return err
 	}
+	if err := loadMuxCertificates(data, priv); err != nil {
+		return err
+	}
 	updateDelegateKeys(pub, priv)
 	return nil
 }
@@ -302,6 +310,10 @@ func ClearKeys() {
 	mKeyLock.Lock()
 	masterKeys = MasterKeys{}
 	mKeyLock.Unlock()
+	caLock.Lock()
+	masterCA = nil
+	caLock.Unlock()
+	loadMuxCertificates(nil, nil)
 	updateDelegateKeys(nil, nil)
 }
 
@@ -27,12 +27,20 @@ import (
    ---------------------------------------------------------------------------------------------------------
    | Auth Token length (4 bytes)  |     Auth Token (N bytes)  | Address (6 bytes) |  Signature (256 bytes) |
    ---------------------------------------------------------------------------------------------------------
+
+   A connection for a protocol other than tcp appends a byte with the protocol to the address.
 */
 
 const (
 	ADDRESS_BYTES = 6
 )
 
+// Protocols of the connection the mux proxies to the address
+const (
+	MuxProtocolTCP byte = iota
+	MuxProtocolUDP
+)
+
 var (
 	ErrBadMuxAddress = errors.New("Bad mux address")
 
@@ -48,6 +56,34 @@ func AddSignedMuxHeader(w io.Writer, address []byte, token string) error {
 	return err
 }
 
+// AddSignedMuxProtocolHeader adds a mux header for a connection that the mux
+// proxies to the address with the protocol
+func AddSignedMuxProtocolHeader(w io.Writer, address []byte, protocol byte, token string) error {
+	if protocol == MuxProtocolTCP {
+		return AddSignedMuxHeader(w, address, token)
+	}
+	if len(address) != ADDRESS_BYTES {
+		return ErrBadMuxAddress
+	}
+	payload := make([]byte, 0, ADDRESS_BYTES+1)
+	payload = append(append(payload, address...), protocol)
+	header := NewAuthHeaderWriterTo([]byte(token), payload, &delegateKeys)
+	_, err := header.WriteTo(w)
+	return err
+}
+
+// SplitMuxAddress returns the address and protocol of the payload of a mux
+// header.  Headers without a protocol are tcp.
+func SplitMuxAddress(payload []byte) ([]byte, byte, error) {
+	switch len(payload) {
+	case ADDRESS_BYTES:
+		return payload, MuxProtocolTCP, nil
+	case ADDRESS_BYTES + 1:
+		return payload[:ADDRESS_BYTES], payload[ADDRESS_BYTES], nil
+	}
+	return nil, 0, ErrBadMuxAddress
+}
+
 func ReadMuxHeader(r io.Reader) ([]byte, Identity, error) {
 	sender, _, address, err := ReadAuthHeader(r)
 	return address, sender, err
@@ -58,3 +58,31 @@ func (s *TestAuthSuite) TestBuildAndExtractHeader(c *C) {
 	c.Assert(s.admin, Equals, ident.HasAdminAccess())
 	c.Assert(s.dfs, Equals, ident.HasDFSAccess())
 }
+
+func (s *TestAuthSuite) TestBuildAndExtractProtocolHeader(c *C) {
+	token, _, _ := auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, s.delegatePubPEM, time.Hour)
+	addr := "zenoss"
+	var b bytes.Buffer
+
+	err := auth.AddSignedMuxProtocolHeader(&b, []byte(addr), auth.MuxProtocolUDP, token)
+	c.Assert(err, IsNil)
+	payload, _, err := auth.ReadMuxHeader(&b)
+	c.Assert(err, IsNil)
+	extractedAddr, protocol, err := auth.SplitMuxAddress(payload)
+	c.Assert(err, IsNil)
+	c.Check(string(extractedAddr), Equals, addr)
+	c.Check(protocol, Equals, auth.MuxProtocolUDP)
+
+	// headers without a protocol are tcp
+	err = auth.AddSignedMuxProtocolHeader(&b, []byte(addr), auth.MuxProtocolTCP, token)
+	c.Assert(err, IsNil)
+	payload, _, err = auth.ReadMuxHeader(&b)
+	c.Assert(err, IsNil)
+	c.Check(payload, HasLen, auth.ADDRESS_BYTES)
+	_, protocol, err = auth.SplitMuxAddress(payload)
+	c.Assert(err, IsNil)
+	c.Check(protocol, Equals, auth.MuxProtocolTCP)
+
+	_, _, err = auth.SplitMuxAddress([]byte("this is more than 7 bytes"))
+	c.Check(err, Equals, auth.ErrBadMuxAddress)
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrNoMuxCertificate is returned when the delegate keys do not include
+	// a certificate issued by the internal certificate authority
+	ErrNoMuxCertificate = errors.New("No mux certificate available")
+
+	// ErrMuxCertificateMismatch is returned when the certificate in the
+	// delegate keys is not for the delegate's private key
+	ErrMuxCertificateMismatch = errors.New("Mux certificate does not match the delegate key")
+
+	// MuxCertVerify requires both ends of a mux connection to present a
+	// certificate issued by the internal certificate authority.  Otherwise
+	// certificates are presented and verified when available, so hosts
+	// whose keys have not been reset yet can still connect.
+	MuxCertVerify = false
+
+	muxCertLock sync.RWMutex
+	muxCert     *tls.Certificate
+	muxCAs      *x509.CertPool
+)
+
+// loadMuxCertificates loads the host certificate and trusted authorities
+// that follow the keys in a delegate key package.  The first certificate is
+// the host's, for the delegate private key, and the rest are the trusted
+// authorities.
+func loadMuxCertificates(data []byte, private crypto.PrivateKey) error {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != certificatePEMType {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+
+	var cert *tls.Certificate
+	var pool *x509.CertPool
+	if len(certs) > 1 {
+		key, ok := private.(*rsa.PrivateKey)
+		if !ok {
+			return ErrNotRSAPrivateKey
+		}
+		if public, ok := certs[0].PublicKey.(*rsa.PublicKey); !ok || public.N.Cmp(key.N) != 0 || public.E != key.E {
+			return ErrMuxCertificateMismatch
+		}
+		cert = &tls.Certificate{
+			Certificate: [][]byte{certs[0].Raw},
+			PrivateKey:  key,
+			Leaf:        certs[0],
+		}
+		pool = x509.NewCertPool()
+		for _, ca := range certs[1:] {
+			pool.AddCert(ca)
+		}
+	}
+
+	muxCertLock.Lock()
+	muxCert, muxCAs = cert, pool
+	muxCertLock.Unlock()
+	return nil
+}
+
+// GetMuxCertificate returns the certificate this host presents on mux
+// connections.
+func GetMuxCertificate() (*tls.Certificate, error) {
+	muxCertLock.RLock()
+	defer muxCertLock.RUnlock()
+	if muxCert == nil {
+		return nil, ErrNoMuxCertificate
+	}
+	return muxCert, nil
+}
+
+func getMuxCAs() *x509.CertPool {
+	muxCertLock.RLock()
+	defer muxCertLock.RUnlock()
+	return muxCAs
+}
+
+// MuxServerTLSConfig returns the tls config of the mux listener.  Each
+// connection uses the host certificate that is current when it connects, so
+// a reset host key takes effect without a restart; until the host has one,
+// the certificates of the base config are served.  If MuxCertVerify is set,
+// connections are refused until the host has the trusted authorities to
+// verify client certificates with.
+func MuxServerTLSConfig(base *tls.Config) *tls.Config {
+	config := base.Clone()
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		config := base.Clone()
+		if cert, err := GetMuxCertificate(); err == nil {
+			config.Certificates = []tls.Certificate{*cert}
+			config.ClientCAs = getMuxCAs()
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		if MuxCertVerify {
+			// without trusted authorities, client certificates would be
+			// verified against the system roots
+			if config.ClientCAs == nil {
+				return nil, ErrNoMuxCertificate
+			}
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		return config, nil
+	}
+	return config
+}
+
+// MuxClientTLSConfig returns the tls config to dial the mux of a host.  The
+// host certificate is presented when this host has one.  If MuxCertVerify
+// is set, the remote host must present a certificate issued by the internal
+// certificate authority; hosts are dialed by address, so the name on the
+// certificate is not checked.
+func MuxClientTLSConfig() *tls.Config {
+	config := &tls.Config{
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert, err := GetMuxCertificate(); err == nil {
+				return cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}
+	if MuxCertVerify {
+		config.VerifyPeerCertificate = verifyMuxPeer
+	}
+	return config
+}
+
+// verifyMuxPeer verifies that the certificate chain of the remote end of a
+// mux connection was issued by the internal certificate authority
+func verifyMuxPeer(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	roots := getMuxCAs()
+	if roots == nil || len(rawCerts) == 0 {
+		return ErrNoMuxCertificate
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	return err
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package auth_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+
+	"github.com/control-center/serviced/auth"
+	. "gopkg.in/check.v1"
+)
+
+// writeDelegatePackage writes delegate keys for the public/private pair
+// with a mux certificate issued for certPub
+func (s *TestAuthSuite) writeDelegatePackage(c *C, filename string, private, certPub []byte) {
+	public, err := auth.RSAPublicKeyFromPEM(certPub)
+	c.Assert(err, IsNil)
+	certPEM, err := auth.IssueHostCertificate(s.hostId, "10.0.0.1", public)
+	c.Assert(err, IsNil)
+	caPEM, err := auth.GetCACertificatesPEM()
+	c.Assert(err, IsNil)
+
+	data := append(append(append([]byte{}, private...), s.masterPubPEM...), certPEM...)
+	err = ioutil.WriteFile(filename, append(data, caPEM...), 0600)
+	c.Assert(err, IsNil)
+}
+
+// handshake connects a mux client to a mux server and returns the client
+// and server errors, and the name on the client certificate
+func handshake(client *tls.Config) (error, error, string) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	server := tls.Server(serverConn, auth.MuxServerTLSConfig(&tls.Config{}))
+	serverErr := make(chan error, 1)
+	go func() {
+		err := server.Handshake()
+		serverConn.Close()
+		serverErr <- err
+	}()
+	clientErr := tls.Client(clientConn, client).Handshake()
+	clientConn.Close()
+	err := <-serverErr
+
+	name := ""
+	if certs := server.ConnectionState().PeerCertificates; len(certs) > 0 {
+		name = certs[0].Subject.CommonName
+	}
+	return clientErr, err, name
+}
+
+func (s *TestAuthSuite) TestMuxCertificates(c *C) {
+	tmpDir := c.MkDir()
+	err := auth.CreateOrLoadCA(filepath.Join(tmpDir, "ca.keys"))
+	c.Assert(err, IsNil)
+
+	// delegate keys without a certificate
+	_, err = auth.GetMuxCertificate()
+	c.Assert(err, Equals, auth.ErrNoMuxCertificate)
+
+	keyFile := filepath.Join(tmpDir, "delegate.keys")
+	s.writeDelegatePackage(c, keyFile, s.delegatePrivPEM, s.delegatePubPEM)
+	err = auth.LoadDelegateKeysFromFile(keyFile)
+	c.Assert(err, IsNil)
+	cert, err := auth.GetMuxCertificate()
+	c.Assert(err, IsNil)
+	c.Assert(cert.Leaf.Subject.CommonName, Equals, s.hostId)
+
+	auth.MuxCertVerify = true
+	defer func() { auth.MuxCertVerify = false }()
+
+	// both ends present and verify certificates
+	clientErr, serverErr, name := handshake(auth.MuxClientTLSConfig())
+	c.Assert(clientErr, IsNil)
+	c.Assert(serverErr, IsNil)
+	c.Assert(name, Equals, s.hostId)
+
+	// clients without a certificate are refused
+	_, serverErr, _ = handshake(&tls.Config{InsecureSkipVerify: true})
+	c.Assert(serverErr, NotNil)
+
+	// unless certificates are not required
+	auth.MuxCertVerify = false
+	clientErr, serverErr, _ = handshake(&tls.Config{InsecureSkipVerify: true})
+	c.Assert(clientErr, IsNil)
+	c.Assert(serverErr, IsNil)
+}
+
+func (s *TestAuthSuite) TestMuxCertificatesRequiredWithoutCAs(c *C) {
+	tmpDir := c.MkDir()
+	err := auth.CreateOrLoadCA(filepath.Join(tmpDir, "ca.keys"))
+	c.Assert(err, IsNil)
+
+	// delegate keys without a certificate or trusted authorities
+	keyFile := filepath.Join(tmpDir, "delegate.keys")
+	data := append(append([]byte{}, s.delegatePrivPEM...), s.masterPubPEM...)
+	err = ioutil.WriteFile(keyFile, data, 0600)
+	c.Assert(err, IsNil)
+	err = auth.LoadDelegateKeysFromFile(keyFile)
+	c.Assert(err, IsNil)
+	_, err = auth.GetMuxCertificate()
+	c.Assert(err, Equals, auth.ErrNoMuxCertificate)
+
+	auth.MuxCertVerify = true
+	defer func() { auth.MuxCertVerify = false }()
+
+	// connections are refused rather than verified against the system roots
+	_, serverErr, _ := handshake(&tls.Config{InsecureSkipVerify: true})
+	c.Assert(serverErr, Equals, auth.ErrNoMuxCertificate)
+}
+
+func (s *TestAuthSuite) TestMuxCertificateMismatch(c *C) {
+	tmpDir := c.MkDir()
+	err := auth.CreateOrLoadCA(filepath.Join(tmpDir, "ca.keys"))
+	c.Assert(err, IsNil)
+
+	keyFile := filepath.Join(tmpDir, "delegate.keys")
+	s.writeDelegatePackage(c, keyFile, s.delegatePrivPEM, s.masterPubPEM)
+	err = auth.LoadDelegateKeysFromFile(keyFile)
+	c.Assert(err, Equals, auth.ErrMuxCertificateMismatch)
+}
+
+func (s *TestAuthSuite) TestRotateCA(c *C) {
+	caFile := filepath.Join(c.MkDir(), "ca.keys")
+	err := auth.CreateOrLoadCA(caFile)
+	c.Assert(err, IsNil)
+	oldPEM, err := auth.GetCACertificatesPEM()
+	c.Assert(err, IsNil)
+
+	err = auth.RotateCAFile(caFile)
+	c.Assert(err, IsNil)
+	err = auth.LoadCAFile(caFile)
+	c.Assert(err, IsNil)
+	newPEM, err := auth.GetCACertificatesPEM()
+	c.Assert(err, IsNil)
+
+	// the new authority is first, and the old one is still trusted
+	current, rest := pem.Decode(newPEM)
+	c.Assert(current, NotNil)
+	c.Assert(bytes.HasPrefix(oldPEM, pem.EncodeToMemory(current)), Equals, false)
+	c.Assert(rest, DeepEquals, oldPEM)
+}
@@ -2,14 +2,17 @@ package mocks
 
 import api "github.com/control-center/serviced/cli/api"
 import applicationendpoint "github.com/control-center/serviced/domain/applicationendpoint"
+import certificate "github.com/control-center/serviced/domain/certificate"
 import dao "github.com/control-center/serviced/dao"
 import host "github.com/control-center/serviced/domain/host"
+import health "github.com/control-center/serviced/health"
 import io "io"
 import isvcs "github.com/control-center/serviced/isvcs"
 import metrics "github.com/control-center/serviced/metrics"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
 import script "github.com/control-center/serviced/script"
+import scriptjob "github.com/control-center/serviced/domain/scriptjob"
 import "github.com/control-center/serviced/utils"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
@@ -249,6 +252,188 @@ func (_m *API) AssignIP(_a0 api.IPConfig) error {
 	return r0
 }
 
+// GetHostInstances provides a mock function with given fields: hostID
+func (_m *API) GetHostInstances(hostID string) ([]service.Instance, error) {
+	ret := _m.Called(hostID)
+
+	var r0 []service.Instance
+	if rf, ok := ret.Get(0).(func(string) []service.Instance); ok {
+		r0 = rf(hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Instance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPoolUsage provides a mock function with given fields: _a0
+func (_m *API) GetPoolUsage(_a0 string) (*pool.Usage, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *pool.Usage
+	if rf, ok := ret.Get(0).(func(string) *pool.Usage); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pool.Usage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicEndpointCertificates provides a mock function with given fields: 
+func (_m *API) GetPublicEndpointCertificates() ([]certificate.Certificate, error) {
+	ret := _m.Called()
+
+	var r0 []certificate.Certificate
+	if rf, ok := ret.Get(0).(func() []certificate.Certificate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetScriptJob provides a mock function with given fields: jobID
+func (_m *API) GetScriptJob(jobID string) (*scriptjob.Job, error) {
+	ret := _m.Called(jobID)
+
+	var r0 *scriptjob.Job
+	if rf, ok := ret.Get(0).(func(string) *scriptjob.Job); ok {
+		r0 = rf(jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scriptjob.Job)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetScriptJobs provides a mock function with given fields: 
+func (_m *API) GetScriptJobs() ([]scriptjob.Job, error) {
+	ret := _m.Called()
+
+	var r0 []scriptjob.Job
+	if rf, ok := ret.Get(0).(func() []scriptjob.Job); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]scriptjob.Job)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceHealth provides a mock function with given fields: serviceID
+func (_m *API) GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 map[int]map[string]health.HealthStatus
+	if rf, ok := ret.Get(0).(func(string) map[int]map[string]health.HealthStatus); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]map[string]health.HealthStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceHealthHistory provides a mock function with given fields: serviceID
+func (_m *API) GetServiceHealthHistory(serviceID string) (map[int]map[string]health.HealthHistory, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 map[int]map[string]health.HealthHistory
+	if rf, ok := ret.Get(0).(func(string) map[int]map[string]health.HealthHistory); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]map[string]health.HealthHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LintServiceTemplate provides a mock function with given fields: path
+func (_m *API) LintServiceTemplate(path string) (servicetemplate.Diagnostics, error) {
+	ret := _m.Called(path)
+
+	var r0 servicetemplate.Diagnostics
+	if rf, ok := ret.Get(0).(func(string) servicetemplate.Diagnostics); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Get(0).(servicetemplate.Diagnostics)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveIP provides a mock function with given fields: args
 func (_m *API) RemoveIP(args []string) error {
 	ret := _m.Called(args)
@@ -263,6 +448,83 @@ func (_m *API) RemoveIP(args []string) error {
 	return r0
 }
 
+// RemovePublicEndpointCertificate provides a mock function with given fields: certType, name
+func (_m *API) RemovePublicEndpointCertificate(certType string, name string) error {
+	ret := _m.Called(certType, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(certType, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetCA provides a mock function with given fields: 
+func (_m *API) ResetCA() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeScriptJob provides a mock function with given fields: jobID
+func (_m *API) ResumeScriptJob(jobID string) error {
+	ret := _m.Called(jobID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(jobID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ScriptRunJob provides a mock function with given fields: fileName, serviceID, noOp
+func (_m *API) ScriptRunJob(fileName string, serviceID string, noOp bool) (string, error) {
+	ret := _m.Called(fileName, serviceID, noOp)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, bool) string); ok {
+		r0 = rf(fileName, serviceID, noOp)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, bool) error); ok {
+		r1 = rf(fileName, serviceID, noOp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetHostDrained provides a mock function with given fields: hostID, drained
+func (_m *API) SetHostDrained(hostID string, drained bool) error {
+	ret := _m.Called(hostID, drained)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
+		r0 = rf(hostID, drained)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetIP provides a mock function with given fields: _a0
 func (_m *API) SetIP(_a0 api.IPConfig) error {
 	ret := _m.Called(_a0)
@@ -1395,13 +1657,13 @@ func (_m *API) RunShell(_a0 api.ShellConfig, _a1 chan struct{}) (int, error) {
 	return r0, r1
 }
 
-// ScriptParse provides a mock function with given fields: fileName, config
-func (_m *API) ScriptParse(fileName string, config *script.Config) error {
-	ret := _m.Called(fileName, config)
+// ScriptParse provides a mock function with given fields: fileName
+func (_m *API) ScriptParse(fileName string) error {
+	ret := _m.Called(fileName)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *script.Config) error); ok {
-		r0 = rf(fileName, config)
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(fileName)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1474,6 +1736,80 @@ func (_m *API) SetHostMemory(_a0 api.HostUpdateConfig) error {
 	return r0
 }
 
+// SetPublicEndpointACME provides a mock function with given fields: serviceid, endpointName, certType, name, domains
+func (_m *API) SetPublicEndpointACME(serviceid string, endpointName string, certType string, name string, domains []string) (*certificate.Certificate, error) {
+	ret := _m.Called(serviceid, endpointName, certType, name, domains)
+
+	var r0 *certificate.Certificate
+	if rf, ok := ret.Get(0).(func(string, string, string, string, []string) *certificate.Certificate); ok {
+		r0 = rf(serviceid, endpointName, certType, name, domains)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string, []string) error); ok {
+		r1 = rf(serviceid, endpointName, certType, name, domains)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPublicEndpointCertificate provides a mock function with given fields: serviceid, endpointName, certType, name, certPEM, keyPEM
+func (_m *API) SetPublicEndpointCertificate(serviceid string, endpointName string, certType string, name string, certPEM string, keyPEM string) (*certificate.Certificate, error) {
+	ret := _m.Called(serviceid, endpointName, certType, name, certPEM, keyPEM)
+
+	var r0 *certificate.Certificate
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string) *certificate.Certificate); ok {
+		r0 = rf(serviceid, endpointName, certType, name, certPEM, keyPEM)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string, string) error); ok {
+		r1 = rf(serviceid, endpointName, certType, name, certPEM, keyPEM)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPublicEndpointPortPolicy provides a mock function with given fields: serviceid, endpointName, portAddr, policy
+func (_m *API) SetPublicEndpointPortPolicy(serviceid string, endpointName string, portAddr string, policy servicedefinition.AccessPolicy) error {
+	ret := _m.Called(serviceid, endpointName, portAddr, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(serviceid, endpointName, portAddr, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPublicEndpointVHostPolicy provides a mock function with given fields: serviceid, endpointName, vhost, policy
+func (_m *API) SetPublicEndpointVHostPolicy(serviceid string, endpointName string, vhost string, policy servicedefinition.AccessPolicy) error {
+	ret := _m.Called(serviceid, endpointName, vhost, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(serviceid, endpointName, vhost, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StartServer provides a mock function with given fields:
 func (_m *API) StartServer() error {
 	ret := _m.Called()
@@ -33,9 +33,11 @@ import (
 	"github.com/control-center/serviced/dfs/nfs"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/properties"
+	"github.com/control-center/serviced/domain/scriptjob"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
@@ -457,6 +459,18 @@ func (d *daemon) startMaster() (err error) {
 
 	keylog.Info("Loaded master keys from disk")
 
+	// Load the internal certificate authority that issues the hosts' mux
+	// certificates, else generate it
+	caFile := filepath.Join(options.IsvcsPath, auth.CAKeyFileName)
+	calog := log.WithFields(logrus.Fields{
+		"cafile": caFile,
+	})
+	if err = auth.CreateOrLoadCA(caFile); err != nil {
+		calog.WithError(err).Fatal("Unable to load or create certificate authority")
+	}
+	go auth.WatchCAFile(caFile, d.shutdown)
+	calog.Info("Loaded certificate authority from disk")
+
 	// This is storage related
 	storagelogger := log.WithFields(logrus.Fields{
 		"path":   options.VolumesPath,
@@ -681,7 +695,7 @@ func createMuxListener() net.Listener {
 		if err != nil {
 			log.WithError(err).Fatal("Invalid TLS configuration")
 		}
-		listener, err = tls.Listen("tcp", fmt.Sprintf(":%d", options.MuxPort), tlsConfig)
+		listener, err = tls.Listen("tcp", fmt.Sprintf(":%d", options.MuxPort), auth.MuxServerTLSConfig(tlsConfig))
 		log = log.WithFields(logrus.Fields{
 			"ciphersuite": strings.Join(utils.CipherSuitesByName(tlsConfig), ","),
 		})
@@ -695,10 +709,21 @@ func createMuxListener() net.Listener {
 	return listener
 }
 
+// muxDialerOptions returns the timeouts and circuit breaker settings of
+// connections proxied through the mux
+func muxDialerOptions(options config.Options) proxy.DialerOptions {
+	return proxy.DialerOptions{
+		DialTimeout:      time.Duration(options.MuxDialTimeout) * time.Second,
+		IdleTimeout:      time.Duration(options.MuxIdleTimeout) * time.Second,
+		FailureThreshold: options.MuxBreakerFailures,
+		ResetTimeout:     time.Duration(options.MuxBreakerReset) * time.Second,
+	}
+}
+
 func (d *daemon) startAgent() error {
 	options := config.GetOptions()
 	muxListener := createMuxListener()
-	mux, err := proxy.NewTCPMux(muxListener)
+	mux, err := proxy.NewTCPMux(muxListener, proxy.NewDialer(muxDialerOptions(options)))
 	if err != nil {
 		log.WithError(err).Fatal("Could not start TCP multiplexer")
 	}
@@ -975,6 +1000,7 @@ func (d *daemon) startAgent() error {
 			Mux:                   mux,
 			MuxPort:               fmt.Sprintf("%d", options.MuxPort),
 			UseTLS:                !muxDisableTLS,
+			MuxDialer:             muxDialerOptions(options),
 			DockerRegistry:        options.DockerRegistry,
 			MaxContainerAge:       time.Duration(int(time.Second) * options.MaxContainerAge),
 			VirtualAddressSubnet:  options.VirtualAddressSubnet,
@@ -1032,6 +1058,18 @@ func (d *daemon) startAgent() error {
 					log.Info("Stopping stats reporting")
 				}()
 			}
+
+			// dials and circuit breakers of the mux
+			muxStatsReporter, err := stats.NewMuxStatsReporter(statsdest, statsduration, mux.Stats)
+			if err != nil {
+				log.WithError(err).Error("Unable to start reporting mux stats")
+			} else {
+				go func() {
+					defer muxStatsReporter.Close()
+					<-d.shutdown
+					log.Info("Stopping mux stats reporting")
+				}()
+			}
 		}
 
 		// storage stats (thinpool, etc)
@@ -1185,6 +1223,8 @@ func (d *daemon) initDriver() datastore.Driver {
 	eDriver.AddMapping(addressassignment.MAPPING)
 	eDriver.AddMapping(serviceconfigfile.MAPPING)
 	eDriver.AddMapping(user.MAPPING)
+	eDriver.AddMapping(scriptjob.MAPPING)
+	eDriver.AddMapping(certificate.MAPPING)
 	err := eDriver.Initialize(10 * time.Second)
 	if err != nil {
 		log.WithError(err).Fatal("Unable to establish connection to Elastic database")
@@ -1226,6 +1266,7 @@ func (d *daemon) initFacade() *facade.Facade {
 		log.WithError(err).Fatal("Unable to update the service cache")
 	}
 	f.SetRollingRestartTimeout(time.Duration(options.ServiceRunLevelTimeout) * time.Second)
+	f.SetScriptConfigurer(f.NewScriptConfigurer(options.SnapshotSpacePercent))
 	return f
 }
 
@@ -1409,6 +1450,38 @@ func (d *daemon) initWeb() {
 		options.UIPollFrequency,
 		options.SnapshotSpacePercent,
 		d.facade)
+	if options.ACMEDirectory != "" {
+		if err := cpserver.SetACME(options.ACMEDirectory, options.ACMEEmail, options.ACMEAccountKey, options.ACMERenewDays); err != nil {
+			log.WithError(err).Error("Could not set up acme certificates for public endpoints")
+		} else {
+			log.WithField("directory", options.ACMEDirectory).Info("Enabled acme certificates for public endpoints")
+		}
+	}
+
+	// access logs and metrics of the requests to the public endpoints
+	var recorders []web.RequestRecorder
+	if accessLog, _ := strconv.ParseBool(options.PublicEndpointAccessLog); accessLog {
+		accessLog := web.NewAccessLog(options.LogstashURL)
+		go accessLog.Run(d.shutdown)
+		recorders = append(recorders, accessLog)
+		log.WithField("logstashurl", options.LogstashURL).Debug("Sending public endpoint access logs to logstash")
+	}
+	if options.ReportStats {
+		statsdest := fmt.Sprintf("http://%s/api/metrics/store", options.HostStats)
+		reporter, err := stats.NewPublicEndpointStatsReporter(statsdest, time.Duration(options.StatsPeriod)*time.Second)
+		if err != nil {
+			log.WithError(err).Error("Unable to start reporting public endpoint stats")
+		} else {
+			go func() {
+				defer reporter.Close()
+				<-d.shutdown
+			}()
+			recorders = append(recorders, web.RequestRecorderFunc(func(record web.RequestRecord) {
+				reporter.Record(record.ServiceID, record.EndpointType, record.Endpoint, record.Status, record.Duration)
+			}))
+		}
+	}
+	web.SetRequestRecorders(recorders...)
 
 	web.SetServiceStatsCacheTimeout(options.SvcStatsCacheTimeout)
 	log.WithFields(logrus.Fields{
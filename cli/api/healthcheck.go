@@ -14,6 +14,7 @@
 package api
 
 import (
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
 )
 
@@ -29,3 +30,22 @@ func (a *api) ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHealt
 		return results, nil
 	}
 }
+
+// GetServiceHealth returns the health of the instances of a service
+func (a *api) GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServiceHealth(serviceID)
+}
+
+// GetServiceHealthHistory returns the recent results of the health checks of
+// the instances of a service
+func (a *api) GetServiceHealthHistory(serviceID string) (map[int]map[string]health.HealthHistory, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServiceHealthHistory(serviceID)
+}
@@ -14,9 +14,12 @@
 package api
 
 import (
+	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/metrics"
@@ -246,6 +249,23 @@ func (a *api) SetHostMemory(config HostUpdateConfig) error {
 	return client.UpdateHost(*h)
 }
 
+// SetHostDrained marks whether instances should be moved off of an existing
+// host
+func (a *api) SetHostDrained(hostID string, drained bool) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	h, err := client.GetHost(hostID)
+	if err != nil {
+		return err
+	} else if h == nil {
+		return fmt.Errorf("host not found: %s", hostID)
+	}
+	h.Drained = drained
+	return client.UpdateHost(*h)
+}
+
 func (a *api) AuthenticateHost(hostID string) (string, int64, error) {
 	client, err := a.connectMaster()
 	if err != nil {
@@ -272,6 +292,13 @@ func (a *api) ResetHostKey(id string) ([]byte, error) {
 	return client.ResetHostKey(id)
 }
 
+// Rotate the internal certificate authority.  Must run on the master, where
+// the running daemon reloads it from disk.
+func (a *api) ResetCA() error {
+	options := config.GetOptions()
+	return auth.RotateCAFile(filepath.Join(options.IsvcsPath, auth.CAKeyFileName))
+}
+
 // Write delegate keys to disk
 func (a *api) RegisterHost(keydata []byte) error {
 	return auth.RegisterLocalHost(keydata)
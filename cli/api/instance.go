@@ -34,6 +34,15 @@ func (a *api) GetServiceInstances(serviceID string) ([]service.Instance, error)
 	return client.GetServiceInstances(serviceID)
 }
 
+// GetHostInstances returns all instances running on a host
+func (a *api) GetHostInstances(hostID string) ([]service.Instance, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetHostInstances(hostID)
+}
+
 // StopServiceInstance stops a running instance of a service.
 func (a *api) StopServiceInstance(serviceID string, instanceID int) error {
 	client, err := a.connectMaster()
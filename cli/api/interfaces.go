@@ -18,11 +18,14 @@ import (
 
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/scriptjob"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	template "github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/script"
@@ -46,12 +49,14 @@ type API interface {
 	RemoveHost(string) error
 	GetHostMemory(string) (*metrics.MemoryUsageStats, error)
 	SetHostMemory(HostUpdateConfig) error
+	SetHostDrained(hostID string, drained bool) error
 	GetHostPublicKey(string) ([]byte, error)
 	RegisterHost([]byte) error
 	RegisterRemoteHost(*host.Host, utils.URL, []byte, bool) error
 	WriteDelegateKey(string, []byte) error
 	AuthenticateHost(string) (string, int64, error)
 	ResetHostKey(string) ([]byte, error)
+	ResetCA() error
 	GetHostWithAuthInfo(string) (*AuthHost, error)
 	GetHostsWithAuthInfo() ([]AuthHost, error)
 
@@ -62,6 +67,7 @@ type API interface {
 	RemoveResourcePool(string) error
 	UpdateResourcePool(pool pool.ResourcePool) error
 	GetPoolIPs(string) (*pool.PoolIPs, error)
+	GetPoolUsage(string) (*pool.Usage, error)
 	AddVirtualIP(pool.VirtualIP) error
 	RemoveVirtualIP(pool.VirtualIP) error
 
@@ -69,6 +75,8 @@ type API interface {
 	GetAllServiceDetails() ([]service.ServiceDetails, error)
 	GetServiceDetails(serviceID string) (*service.ServiceDetails, error)
 	GetServiceStatus(string) (map[string]map[string]interface{}, error)
+	GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error)
+	GetServiceHealthHistory(serviceID string) (map[int]map[string]health.HealthHistory, error)
 	GetService(string) (*service.Service, error)
 	AddService(ServiceConfig) (*service.ServiceDetails, error)
 	CloneService(string, string) (*service.ServiceDetails, error)
@@ -107,6 +115,7 @@ type API interface {
 	AddServiceTemplate(io.Reader) (*template.ServiceTemplate, error)
 	RemoveServiceTemplate(string) error
 	CompileServiceTemplate(CompileTemplateConfig) (*template.ServiceTemplate, error)
+	LintServiceTemplate(path string) (template.Diagnostics, error)
 	DeployServiceTemplate(DeployTemplateConfig) ([]service.ServiceDetails, error)
 
 	// Backup & Restore
@@ -128,7 +137,11 @@ type API interface {
 
 	// Scripts
 	ScriptRun(fileName string, config *script.Config, stopChan chan struct{}) error
-	ScriptParse(fileName string, config *script.Config) error
+	ScriptParse(fileName string) error
+	ScriptRunJob(fileName, serviceID string, noOp bool) (string, error)
+	ResumeScriptJob(jobID string) error
+	GetScriptJob(jobID string) (*scriptjob.Job, error)
+	GetScriptJobs() ([]scriptjob.Job, error)
 
 	// Volumes
 	GetVolumeStatus() (*volume.Statuses, error)
@@ -140,10 +153,17 @@ type API interface {
 	AddPublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled, restart bool) (*servicedefinition.VHost, error)
 	RemovePublicEndpointVHost(serviceid, endpointName, vhost string) error
 	EnablePublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled bool) error
+	SetPublicEndpointPortPolicy(serviceid, endpointName, portAddr string, policy servicedefinition.AccessPolicy) error
+	SetPublicEndpointVHostPolicy(serviceid, endpointName, vhost string, policy servicedefinition.AccessPolicy) error
 	GetAllPublicEndpoints() ([]service.PublicEndpoint, error)
+	SetPublicEndpointCertificate(serviceid, endpointName, certType, name, certPEM, keyPEM string) (*certificate.Certificate, error)
+	RemovePublicEndpointCertificate(certType, name string) error
+	SetPublicEndpointACME(serviceid, endpointName, certType, name string, domains []string) (*certificate.Certificate, error)
+	GetPublicEndpointCertificates() ([]certificate.Certificate, error)
 
 	// Service Instances
 	GetServiceInstances(serviceID string) ([]service.Instance, error)
+	GetHostInstances(hostID string) ([]service.Instance, error)
 	StopServiceInstance(serviceID string, instanceID int) error
 	AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error
 	LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error
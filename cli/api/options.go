@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/isvcs"
@@ -49,6 +50,10 @@ func ValidateCommonOptions(opts config.Options) error {
 	if err != nil {
 		return fmt.Errorf("error parsing rpc-disable-tls value %v", err)
 	}
+	auth.MuxCertVerify, err = strconv.ParseBool(opts.MuxCertVerify)
+	if err != nil {
+		return fmt.Errorf("error parsing mux-cert-verify value %v", err)
+	}
 
 	if err := validation.ValidUIAddress(opts.UIPort); err != nil {
 		return fmt.Errorf("error validating UI port: %s", err)
@@ -135,6 +140,11 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		Master:                     cfg.BoolVal("MASTER", false),
 		MuxPort:                    cfg.IntVal("MUX_PORT", 22250),
 		MuxDisableTLS:              strconv.FormatBool(cfg.BoolVal("MUX_DISABLE_TLS", false)),
+		MuxCertVerify:              strconv.FormatBool(cfg.BoolVal("MUX_CERT_VERIFY", false)),
+		MuxDialTimeout:             cfg.IntVal("MUX_DIAL_TIMEOUT", 10),
+		MuxIdleTimeout:             cfg.IntVal("MUX_IDLE_TIMEOUT", 0),
+		MuxBreakerFailures:         cfg.IntVal("MUX_BREAKER_FAILURES", 5),
+		MuxBreakerReset:            cfg.IntVal("MUX_BREAKER_RESET", 10),
 		KeyPEMFile:                 cfg.StringVal("KEY_FILE", ""),
 		CertPEMFile:                cfg.StringVal("CERT_FILE", ""),
 		Zookeepers:                 cfg.StringSlice("ZK", []string{}),
@@ -211,6 +221,12 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		// Parameters for api-key-proxy isvc configuration
 		KeyProxyJsonServer: cfg.StringVal("KEYPROXY_JSON_SERVER", ""),
 		KeyProxyListenPort: cfg.StringVal("KEYPROXY_LISTEN_PORT", ":6443"),
+		// Parameters for acme public endpoint certificates
+		ACMEDirectory: cfg.StringVal("ACME_DIRECTORY", ""),
+		ACMEEmail:     cfg.StringVal("ACME_EMAIL", ""),
+		ACMERenewDays: cfg.IntVal("ACME_RENEW_DAYS", 30),
+		// Access log of the vhost and port public endpoints
+		PublicEndpointAccessLog: strconv.FormatBool(cfg.BoolVal("PUBLIC_ENDPOINT_ACCESS_LOG", true)),
 	}
 
 	options.Endpoint = cfg.StringVal("ENDPOINT", "")
@@ -232,6 +248,7 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 	options.VolumesPath = cfg.StringVal("VOLUMES_PATH", filepath.Join(varpath, "volumes"))
 	options.BackupsPath = cfg.StringVal("BACKUPS_PATH", filepath.Join(varpath, "backups"))
 	options.EtcPath = cfg.StringVal("ETC_PATH", filepath.Join(options.HomePath, "etc"))
+	options.ACMEAccountKey = cfg.StringVal("ACME_ACCOUNT_KEY", filepath.Join(varpath, "acme", "account.key"))
 	options.StorageArgs = getDefaultStorageOptions(options.FSType, cfg)
 
 	return options
@@ -102,6 +102,16 @@ func (a *api) GetPoolIPs(id string) (*pool.PoolIPs, error) {
 	return client.GetPoolIPs(id)
 }
 
+// Returns the resources committed to a pool against its quotas
+func (a *api) GetPoolUsage(id string) (*pool.Usage, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetPoolUsage(id)
+}
+
 // Add a VirtualIP to a specific pool
 func (a *api) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	client, err := a.connectMaster()
@@ -14,6 +14,7 @@
 package api
 
 import (
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 )
@@ -76,6 +77,26 @@ func (a *api) EnablePublicEndpointVHost(serviceid, endpointName, vhost string, i
 	return client.EnablePublicEndpointVHost(serviceid, endpointName, vhost, isEnabled)
 }
 
+// Set the access policy of a port public endpoint.
+func (a *api) SetPublicEndpointPortPolicy(serviceid, endpointName, portAddr string, policy servicedefinition.AccessPolicy) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetPublicEndpointPortPolicy(serviceid, endpointName, portAddr, policy)
+}
+
+// Set the access policy of a vhost public endpoint.
+func (a *api) SetPublicEndpointVHostPolicy(serviceid, endpointName, vhost string, policy servicedefinition.AccessPolicy) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetPublicEndpointVHostPolicy(serviceid, endpointName, vhost, policy)
+}
+
 func (a *api) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	client, err := a.connectMaster()
 	if err != nil {
@@ -84,3 +105,43 @@ func (a *api) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 
 	return client.GetAllPublicEndpoints()
 }
+
+// Set the certificate served for a vhost or port public endpoint.
+func (a *api) SetPublicEndpointCertificate(serviceid, endpointName, certType, name, certPEM, keyPEM string) (*certificate.Certificate, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SetPublicEndpointCertificate(serviceid, endpointName, certType, name, certPEM, keyPEM)
+}
+
+// Remove the certificate of a vhost or port public endpoint.
+func (a *api) RemovePublicEndpointCertificate(certType, name string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemovePublicEndpointCertificate(certType, name)
+}
+
+// Request an acme certificate for a vhost or port public endpoint.
+func (a *api) SetPublicEndpointACME(serviceid, endpointName, certType, name string, domains []string) (*certificate.Certificate, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SetPublicEndpointACME(serviceid, endpointName, certType, name, domains)
+}
+
+// Get the certificates of all public endpoints.
+func (a *api) GetPublicEndpointCertificates() ([]certificate.Certificate, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetPublicEndpointCertificates()
+}
@@ -15,11 +15,11 @@ package api
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math"
-	"path"
-	"strings"
 	"time"
 
+	"github.com/control-center/serviced/domain/scriptjob"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/script"
 )
@@ -36,9 +36,58 @@ func (a *api) ScriptRun(fileName string, config *script.Config, stopChan chan st
 	return r.Run(stopChan)
 }
 
-func (a *api) ScriptParse(fileName string, config *script.Config) error {
-	_, err := script.NewRunnerFromFile(fileName, config)
-	return err
+// ScriptParse parses a script and reports all errors found with their line numbers
+func (a *api) ScriptParse(fileName string) error {
+	return script.ParseFile(fileName)
+}
+
+// ScriptRunJob submits a script to the master, which runs it as a resumable
+// job
+func (a *api) ScriptRunJob(fileName, serviceID string, noOp bool) (string, error) {
+	if err := script.ParseFile(fileName); err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return "", err
+	}
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+	return client.RunScriptJob(scriptjob.Job{
+		ServiceID: serviceID,
+		FileName:  fileName,
+		Script:    string(data),
+		NoOp:      noOp,
+	})
+}
+
+// ResumeScriptJob resumes a failed or interrupted script job
+func (a *api) ResumeScriptJob(jobID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.ResumeScriptJob(jobID)
+}
+
+// GetScriptJob returns the progress of a script job
+func (a *api) GetScriptJob(jobID string) (*scriptjob.Job, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetScriptJob(jobID)
+}
+
+// GetScriptJobs returns all script jobs
+func (a *api) GetScriptJobs() ([]scriptjob.Job, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetScriptJobs()
 }
 
 func initConfig(config *script.Config, a *api) {
@@ -56,6 +105,51 @@ func initConfig(config *script.Config, a *api) {
 		return a.AddSnapshot(SnapshotConfig{DockerID: containerID})
 	}
 	config.SvcUse = cliServiceUse(a)
+	config.SvcState = cliServiceState(a)
+	config.SvcImage = cliServiceImage(a)
+	config.SvcChildren = cliServiceChildren(a)
+}
+
+func cliServiceState(a *api) script.ServiceStateLookup {
+	return func(svcID string) (script.ServiceState, error) {
+		client, err := a.connectMaster()
+		if err != nil {
+			return "", err
+		}
+		svc, err := client.GetServiceDetails(svcID)
+		if err != nil {
+			return "", err
+		}
+		return script.DesiredStateToScriptState(service.DesiredState(svc.DesiredState))
+	}
+}
+
+func cliServiceImage(a *api) script.ServiceImageLookup {
+	return func(svcID string) (string, error) {
+		client, err := a.connectMaster()
+		if err != nil {
+			return "", err
+		}
+		svc, err := client.GetServiceDetails(svcID)
+		if err != nil {
+			return "", err
+		}
+		return svc.ImageID, nil
+	}
+}
+
+func cliServiceChildren(a *api) script.ServiceChildren {
+	return func(tenantID string, svcPath string) ([]string, error) {
+		client, err := a.connectMaster()
+		if err != nil {
+			return nil, err
+		}
+		var svcs []service.ServiceDetails
+		if svcs, err = client.GetServiceDetailsByTenantID(tenantID); err != nil {
+			return nil, err
+		}
+		return service.ChildServicePaths(svcs, svcPath)
+	}
 }
 
 func cliServiceUse(a *api) script.ServiceUse {
@@ -132,28 +226,6 @@ func cliServiceIDFromPath(a *api) script.ServiceIDFromPath {
 		if svcs, err = client.GetServiceDetailsByTenantID(tenantID); err != nil {
 			return "", err
 		}
-
-		svcMap := make(map[string]service.ServiceDetails)
-		for _, svc := range svcs {
-			svcMap[svc.ID] = svc
-		}
-
-		// recursively build full path for all services
-		pathmap := make(map[string]string) //path to service id
-		for _, svc := range svcs {
-			fullpath := svc.Name
-			parentServiceID := svc.ParentServiceID
-
-			for parentServiceID != "" {
-				fullpath = path.Join(svcMap[parentServiceID].Name, fullpath)
-				parentServiceID = svcMap[parentServiceID].ParentServiceID
-			}
-			pathmap[strings.ToLower(fullpath)] = svc.ID
-		}
-		svcID, found := pathmap[strings.ToLower(svcPath)]
-		if !found {
-			return "", fmt.Errorf("did not find service %s", svcPath)
-		}
-		return svcID, nil
+		return service.ServiceIDFromPath(svcs, svcPath)
 	}
 }
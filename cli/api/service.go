@@ -167,6 +167,7 @@ func (a *api) GetServiceStatus(serviceID string) (map[string]map[string]interfac
 				row["Hostname"] = stat.HostName
 				row["DockerID"] = fmt.Sprintf("%.12s", stat.ContainerID)
 				row["Uptime"] = uptime.String()
+				row["Restarts"] = stat.Restarts
 
 				if stat.ImageSynced {
 					row["InSync"] = "Y"
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
@@ -135,6 +136,33 @@ func (a *api) CompileServiceTemplate(config CompileTemplateConfig) (*template.Se
 	return st, nil
 }
 
+// LintServiceTemplate reports every issue found in a template, given either
+// a directory of service definitions or a compiled template file
+func (a *api) LintServiceTemplate(path string) (template.Diagnostics, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var st *template.ServiceTemplate
+	if fi.IsDir() {
+		if st, err = template.ReadFromPath(path); err != nil {
+			return nil, err
+		}
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		st = &template.ServiceTemplate{}
+		if err := json.NewDecoder(file).Decode(st); err != nil {
+			return nil, fmt.Errorf("could not unmarshal json: %s", err)
+		}
+	}
+	return st.Lint(), nil
+}
+
 // DeployTemplate deploys a template given its template ID
 func (a *api) DeployServiceTemplate(config DeployTemplateConfig) ([]service.ServiceDetails, error) {
 	client, err := a.connectMaster()
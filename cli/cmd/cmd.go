@@ -77,6 +77,11 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.BoolFlag{"agent", "deprecated"},
 		cli.IntFlag{"mux", defaultOps.MuxPort, "multiplexing port"},
 		cli.StringFlag{"mux-disable-tls", defaultOps.MuxDisableTLS, "disable TLS for mux connections"},
+		cli.StringFlag{"mux-cert-verify", defaultOps.MuxCertVerify, "require mux connections to present certificates issued by the internal certificate authority"},
+		cli.IntFlag{"mux-dial-timeout", defaultOps.MuxDialTimeout, "seconds to wait for a proxied connection through the mux"},
+		cli.IntFlag{"mux-idle-timeout", defaultOps.MuxIdleTimeout, "seconds before an idle proxied connection is closed, 0 for never"},
+		cli.IntFlag{"mux-breaker-failures", defaultOps.MuxBreakerFailures, "consecutive failed connections to a host that trip its circuit breaker, 0 for never"},
+		cli.IntFlag{"mux-breaker-reset", defaultOps.MuxBreakerReset, "seconds a tripped circuit breaker fails connections fast before it probes the host"},
 		cli.StringSliceFlag{"mux-tls-ciphers", convertToStringSlice(defaultOps.MUXTLSCiphers), "list of supported TLS ciphers for MUX"},
 		cli.StringFlag{"mux-tls-min-version", string(defaultOps.MUXTLSMinVersion), "mininum TLS version for MUX"},
 		cli.StringFlag{"volumes-path", defaultOps.VolumesPath, "path where application data is stored"},
@@ -168,6 +173,11 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.StringFlag{"auth0-scope", defaultOps.Auth0Scope, "Scope to request in Auth0"},
 		cli.StringFlag{"keyproxy-json-server", defaultOps.KeyProxyJsonServer, "URL for API key server (cc auth token endpoint)"},
 		cli.StringFlag{"keyproxy-listen-port", defaultOps.KeyProxyListenPort, "Port for API key proxy to listen on"},
+		cli.StringFlag{"acme-directory", defaultOps.ACMEDirectory, "directory url of the acme server that issues public endpoint certificates"},
+		cli.StringFlag{"acme-email", defaultOps.ACMEEmail, "contact email of the acme account"},
+		cli.StringFlag{"acme-account-key", defaultOps.ACMEAccountKey, "path to the key of the acme account"},
+		cli.IntFlag{"acme-renew-days", defaultOps.ACMERenewDays, "days before expiry to renew acme certificates"},
+		cli.StringFlag{"public-endpoint-access-log", defaultOps.PublicEndpointAccessLog, "whether to send access logs of the vhost and port public endpoints to logstash"},
 		cli.BoolFlag{"no-prefix-match", "Make matches on SERVICEID by name strictly 'ends-with' rather than 'contains'"},
 	}
 
@@ -249,6 +259,11 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		Master:                     ctx.GlobalBool("master"),
 		MuxPort:                    ctx.GlobalInt("mux"),
 		MuxDisableTLS:              ctx.GlobalString("mux-disable-tls"),
+		MuxCertVerify:              ctx.GlobalString("mux-cert-verify"),
+		MuxDialTimeout:             ctx.GlobalInt("mux-dial-timeout"),
+		MuxIdleTimeout:             ctx.GlobalInt("mux-idle-timeout"),
+		MuxBreakerFailures:         ctx.GlobalInt("mux-breaker-failures"),
+		MuxBreakerReset:            ctx.GlobalInt("mux-breaker-reset"),
 		MUXTLSCiphers:              ctx.GlobalStringSlice("mux-tls-ciphers"),
 		MUXTLSMinVersion:           ctx.GlobalString("mux-tls-min-version"),
 		HomePath:                   api.GetDefaultOptions(cfg).HomePath,
@@ -331,6 +346,11 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		Auth0Scope:                 ctx.String("auth0-scope"),
 		KeyProxyJsonServer:         ctx.String("keyproxy-json-server"),
 		KeyProxyListenPort:         ctx.String("keyproxy-listen-port"),
+		ACMEDirectory:              ctx.GlobalString("acme-directory"),
+		ACMEEmail:                  ctx.GlobalString("acme-email"),
+		ACMEAccountKey:             ctx.GlobalString("acme-account-key"),
+		ACMERenewDays:              ctx.GlobalInt("acme-renew-days"),
+		PublicEndpointAccessLog:    ctx.GlobalString("public-endpoint-access-log"),
 	}
 
 	// Long story, but due to the way codegangsta handles bools and the way we start system services vs
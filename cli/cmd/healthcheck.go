@@ -16,8 +16,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/health"
 )
 
 // Initializer for serviced healthcheck subcommands
@@ -25,8 +27,15 @@ func (c *ServicedCli) initHealthCheck() {
 	c.app.Commands = append(c.app.Commands, cli.Command{
 		Name:        "healthcheck",
 		Usage:       "Reports on health of serviced",
-		Description: "serviced healthcheck [ISERVICENAME-1 [ISERVICENAME-2 ... [ISERVICENAME-N]]]",
+		Description: "serviced healthcheck [--service SERVICEID ...] [ISERVICENAME-1 [ISERVICENAME-2 ... [ISERVICENAME-N]]]",
 		Before:      c.cmdHealthCheck,
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "service, s",
+				Value: &cli.StringSlice{},
+				Usage: "Report the health checks of the instances of a service instead",
+			},
+		},
 	})
 }
 
@@ -36,6 +45,10 @@ func (c *ServicedCli) cmdHealthCheck(ctx *cli.Context) error {
 		return nil
 	}
 
+	if services := ctx.StringSlice("service"); len(services) > 0 {
+		return c.exit(c.serviceHealthCheck(services))
+	}
+
 	if results, err := c.driver.ServicedHealthCheck(ctx.Args()); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return c.exit(2)
@@ -67,6 +80,58 @@ func (c *ServicedCli) cmdHealthCheck(ctx *cli.Context) error {
 	}
 }
 
+// serviceHealthCheck prints the health checks of the instances of services,
+// with the output of failing checks, and returns the exit status.
+func (c *ServicedCli) serviceHealthCheck(serviceIDs []string) int {
+	exitStatus := 0
+	t := NewTable("Service Name,Instance,Health Check,Status")
+	t.Padding = 2
+	for _, serviceID := range serviceIDs {
+		svc, _, err := c.searchForService(serviceID, false)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		statuses, err := c.driver.GetServiceHealth(svc.ID)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		for _, key := range sortedHealthStatusKeys(statuses) {
+			stat := statuses[key.InstanceID][key.HealthCheckName]
+			if stat.Status != health.OK {
+				exitStatus = 1
+			}
+			t.AddRow(map[string]interface{}{
+				"Service Name": svc.Name,
+				"Instance":     key.InstanceID,
+				"Health Check": key.HealthCheckName,
+				"Status":       getCombinedStatus(stat.Status.Name(), healthCheckOutput(stat.Output)),
+			})
+		}
+	}
+	t.Print()
+	return exitStatus
+}
+
+// sortedHealthStatusKeys returns the instances and health checks of the
+// statuses, ordered by instance and name
+func sortedHealthStatusKeys(statuses map[int]map[string]health.HealthStatus) []health.HealthStatusKey {
+	var keys []health.HealthStatusKey
+	for instanceID, checks := range statuses {
+		for name := range checks {
+			keys = append(keys, health.HealthStatusKey{InstanceID: instanceID, HealthCheckName: name})
+		}
+	}
+	sortHealthCheckKeys(keys)
+	return keys
+}
+
+// healthCheckOutput fits the output of a health check on one line
+func healthCheckOutput(output string) string {
+	return strings.Join(strings.Fields(output), " ")
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -176,3 +176,21 @@ func ExampleServicedCLI_CmdHealthCheck_unknownStatus() {
 	// test-iservice-unknown  container-unknown  id-unknown    running       unknown
 	// exit code 1
 }
+
+func ExampleServicedCLI_CmdHealthCheck_service() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "healthcheck", "--service", "test-service-2") })
+
+	// Output:
+	// Service Name  Instance  Health Check  Status
+	// Zope          0         answering     failed - connection refused
+	// Zope          0         running       passed
+	// exit code 1
+}
+
+func ExampleServicedCLI_CmdHealthCheck_undefinedServiceID() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "healthcheck", "--service", "test-service-0") })
+
+	// Output:
+	// service not found
+	// exit code 2
+}
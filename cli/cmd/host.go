@@ -20,6 +20,7 @@ import (
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
@@ -115,6 +116,28 @@ func (c *ServicedCli) initHost() {
 				Description:  "serviced host set-memory HOSTID ALLOCATION",
 				BashComplete: c.printHostsAll,
 				Action:       c.cmdHostSetMemory,
+			}, {
+				Name:         "drain",
+				Usage:        "Moves all instances off of a host and stops scheduling new ones on it",
+				Description:  "serviced host drain HOSTID",
+				BashComplete: c.printHostsFirst,
+				Action:       c.cmdHostDrain,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "nowait",
+						Usage: "Return without waiting for the instances to move off of the host",
+					},
+					cli.IntFlag{
+						Name:  "timeout",
+						Usage: "Seconds to wait for the host to be empty; 0 waits indefinitely",
+					},
+				},
+			}, {
+				Name:         "undrain",
+				Usage:        "Allows instances to be scheduled on drained hosts again",
+				Description:  "serviced host undrain HOSTID ...",
+				BashComplete: c.printHostsAll,
+				Action:       c.cmdHostUndrain,
 			},
 		},
 	})
@@ -243,6 +266,7 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 				"Cur/Max/Avg": usage,
 				"Network":     h.PrivateNetwork,
 				"Release":     h.ServiceD.Release,
+				"Drained":     h.Drained,
 			})
 		}
 		t.Padding = 6
@@ -428,6 +452,84 @@ func (c *ServicedCli) cmdHostSetMemory(ctx *cli.Context) {
 	}
 }
 
+// drainPollInterval is how often host drain checks the instances left on the
+// host
+var drainPollInterval = 5 * time.Second
+
+// serviced host drain HOSTID [--nowait] [--timeout SECONDS]
+func (c *ServicedCli) cmdHostDrain(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "drain")
+		return
+	}
+	hostID := args[0]
+
+	if err := c.driver.SetHostDrained(hostID, true); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if ctx.Bool("nowait") {
+		fmt.Println(hostID)
+		return
+	}
+
+	var timeout <-chan time.Time
+	if seconds := ctx.Int("timeout"); seconds > 0 {
+		timeout = time.After(time.Duration(seconds) * time.Second)
+	}
+
+	// the scheduler moves the instances one at a time, so report each time
+	// the number left on the host changes
+	remaining := -1
+	for {
+		insts, err := c.driver.GetHostInstances(hostID)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		} else if len(insts) == 0 {
+			break
+		}
+
+		if len(insts) != remaining {
+			remaining = len(insts)
+			fmt.Printf("Waiting for %d instance(s) to move off of host %s\n", remaining, hostID)
+		}
+
+		select {
+		case <-timeout:
+			fmt.Fprintf(os.Stderr, "Timed out waiting for host %s to drain; %d instance(s) remaining\n", hostID, remaining)
+			c.exit(1)
+			return
+		case <-time.After(drainPollInterval):
+		}
+	}
+	fmt.Println(hostID)
+}
+
+// serviced host undrain HOSTID ...
+func (c *ServicedCli) cmdHostUndrain(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "undrain")
+		return
+	}
+
+	for _, id := range args {
+		if err := c.driver.SetHostDrained(id, false); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", id, err)
+			c.exit(1)
+		} else {
+			fmt.Println(id)
+		}
+	}
+}
+
 // serviced host register (KEYSFILE | -)
 func (c *ServicedCli) cmdHostRegister(ctx *cli.Context) {
 	args := ctx.Args()
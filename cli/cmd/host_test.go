@@ -24,12 +24,16 @@ import (
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/utils"
 )
 
 var DefaultHostAPITest = HostAPITest{
 	pools: DefaultTestPools,
 	hosts: DefaultTestHosts,
+	instances: map[string][]service.Instance{
+		"test-host-id-2": {{HostID: "test-host-id-2", ServiceID: "test-service-1", InstanceID: 0}},
+	},
 }
 
 var DefaultTestHosts = []host.Host{
@@ -72,6 +76,7 @@ type HostAPITest struct {
 	writeFail    bool
 	pools        []pool.ResourcePool
 	hosts        []host.Host
+	instances    map[string][]service.Instance
 }
 
 func InitHostAPITest(args ...string) {
@@ -170,6 +175,22 @@ func (t HostAPITest) GetHostsWithAuthInfo() ([]api.AuthHost, error) {
 	return authHosts, nil
 }
 
+func (t HostAPITest) SetHostDrained(id string, drained bool) error {
+	if h, err := t.GetHost(id); err != nil {
+		return err
+	} else if h == nil {
+		return ErrNoHostFound
+	}
+	return nil
+}
+
+func (t HostAPITest) GetHostInstances(id string) ([]service.Instance, error) {
+	if t.fail {
+		return nil, ErrInvalidHost
+	}
+	return t.instances[id], nil
+}
+
 func TestServicedCLI_CmdHostList_one(t *testing.T) {
 	hostID := "test-host-id-1"
 
@@ -420,6 +441,58 @@ func ExampleServicedCLI_CmdHostRemove_complete() {
 	// test-host-id-3
 }
 
+func ExampleServicedCLI_CmdHostDrain() {
+	InitHostAPITest("serviced", "host", "drain", "test-host-id-1")
+	InitHostAPITest("serviced", "host", "drain", "test-host-id-2", "--nowait")
+
+	// Output:
+	// test-host-id-1
+	// test-host-id-2
+}
+
+func ExampleServicedCLI_CmdHostDrain_timeout() {
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "drain", "test-host-id-2", "--timeout=1") })
+
+	// Output:
+	// Waiting for 1 instance(s) to move off of host test-host-id-2
+	// Timed out waiting for host test-host-id-2 to drain; 1 instance(s) remaining
+}
+
+func ExampleServicedCLI_CmdHostDrain_usage() {
+	InitHostAPITest("serviced", "host", "drain")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    drain - Moves all instances off of a host and stops scheduling new ones on it
+	//
+	// USAGE:
+	//    command drain [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced host drain HOSTID
+	//
+	// OPTIONS:
+	//    --nowait		Return without waiting for the instances to move off of the host
+	//    --timeout '0'	Seconds to wait for the host to be empty; 0 waits indefinitely
+}
+
+func ExampleServicedCLI_CmdHostDrain_err() {
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "drain", "test-host-id-0") })
+
+	// Output:
+	// no host found
+}
+
+func ExampleServicedCLI_CmdHostUndrain() {
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "undrain", "test-host-id-2", "test-host-id-0") })
+
+	// Output:
+	// test-host-id-2
+	// test-host-id-0: no host found
+}
+
 func ExampleServicedCLI_CmdHostRegister_usage() {
 	InitHostAPITest("serviced", "host", "register")
 
@@ -41,7 +41,7 @@ func (c *ServicedCli) initKey() {
 				Action:       c.cmdHostKey,
 			}, {
 				Name:         "reset",
-				Usage:        "Regenerate host key",
+				Usage:        "Regenerate host key and mux certificate",
 				Description:  "serviced key reset HostID",
 				BashComplete: c.printHostsFirst,
 				Action:       c.cmdKeyReset,
@@ -61,6 +61,11 @@ func (c *ServicedCli) initKey() {
 						Usage: "Register delegate keys on the host via ssh",
 					},
 				},
+			}, {
+				Name:        "reset-ca",
+				Usage:       "Rotate the certificate authority of the mux",
+				Description: "serviced key reset-ca",
+				Action:      c.cmdKeyResetCA,
 			},
 		},
 	}
@@ -138,6 +143,14 @@ func (c *ServicedCli) cmdKeyReset(ctx *cli.Context) {
 	c.outputDelegateKey(host, nat, key, keyfileName, registerHost)
 }
 
+func (c *ServicedCli) cmdKeyResetCA(ctx *cli.Context) {
+	if err := c.driver.ResetCA(); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not rotate the certificate authority. ", err.Error())
+		return
+	}
+	fmt.Println("Rotated the certificate authority. Reset the key of each host to issue its certificate from it.")
+}
+
 func (c *ServicedCli) outputDelegateKey(host *host.Host, nat utils.URL, keyData []byte, keyfileName string, register bool) {
 	writeKeyFile := false
 	if register {
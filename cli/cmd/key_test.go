@@ -66,6 +66,12 @@ func (s *mySuite) Test_cmdKeyReset(c *C) {
 	s.api.AssertExpectations(c)
 }
 
+func (s *mySuite) Test_cmdKeyResetCA(c *C) {
+	s.api.On("ResetCA").Return(nil)
+	s.cli.Run(strings.Split("serviced key reset-ca", " "))
+	s.api.AssertExpectations(c)
+}
+
 func (s *mySuite) Test_cmdKeyReset_hostNotFound(c *C) {
 	s.api.On("GetHost", testHost.ID).Return(nil, errors.New("?"))
 	s.cli.Run(strings.Split("serviced key reset "+testHost.ID, " "))
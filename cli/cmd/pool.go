@@ -23,6 +23,7 @@ import (
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/utils"
 )
 
 // Initializer for serviced pool subcommands
@@ -108,6 +109,45 @@ func (c *ServicedCli) initPool() {
 				Description:  "serviced pool set-conn-timeout POOLID TIMEOUT",
 				BashComplete: c.printPoolsFirst,
 				Action:       c.cmdSetConnTimeout,
+			}, {
+				Name:        "quota",
+				Usage:       "Manage the core and memory quotas of a pool",
+				Description: "serviced pool quota",
+				Subcommands: []cli.Command{
+					{
+						Name:         "show",
+						Usage:        "Shows the resources committed to a pool against its quotas",
+						Description:  "serviced pool quota show POOLID",
+						BashComplete: c.printPoolsFirst,
+						Action:       c.cmdPoolQuotaShow,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "verbose, v",
+								Usage: "Show JSON format",
+							},
+						},
+					}, {
+						Name:         "set",
+						Usage:        "Sets the core and memory quotas of a pool (0 = unlimited)",
+						Description:  "serviced pool quota set POOLID [--cores CORES] [--memory MEMORY] [--preemption POLICY]",
+						BashComplete: c.printPoolsFirst,
+						Action:       c.cmdPoolQuotaSet,
+						Flags: []cli.Flag{
+							cli.IntFlag{
+								Name:  "cores",
+								Usage: "Number of cores the running services of the pool may commit",
+							},
+							cli.StringFlag{
+								Name:  "memory",
+								Usage: "Amount of memory the running services of the pool may commit (e.g. 512M, 16G)",
+							},
+							cli.StringFlag{
+								Name:  "preemption",
+								Usage: "Whether instances over quota may preempt lower priority instances (never, lower-priority)",
+							},
+						},
+					},
+				},
 			}, {
 				Name:         "set-permission",
 				Usage:        "Set permission flags for hosts in a pool",
@@ -422,6 +462,108 @@ func (c *ServicedCli) cmdSetConnTimeout(ctx *cli.Context) {
 	}
 }
 
+// serviced pool quota show POOLID
+func (c *ServicedCli) cmdPoolQuotaShow(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "show")
+		return
+	}
+
+	usage, err := c.driver.GetPoolUsage(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonUsage, err := json.MarshalIndent(usage, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal pool usage: %s", err)
+			c.exit(1)
+		} else {
+			fmt.Println(string(jsonUsage))
+		}
+		return
+	}
+
+	t := NewTable("Resource,Committed,Limit,Capacity")
+	t.AddRow(map[string]interface{}{
+		"Resource":  "cores",
+		"Committed": usage.CoreCommitment,
+		"Limit":     quotaLimit(uint64(usage.CoreLimit)),
+		"Capacity":  usage.CoreCapacity,
+	})
+	t.AddRow(map[string]interface{}{
+		"Resource":  "memory",
+		"Committed": usage.MemoryCommitment,
+		"Limit":     quotaLimit(usage.MemoryLimit),
+		"Capacity":  usage.MemoryCapacity,
+	})
+	t.Print()
+}
+
+// quotaLimit describes a pool quota, where 0 is unlimited
+func quotaLimit(limit uint64) interface{} {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return limit
+}
+
+// serviced pool quota set POOLID [--cores CORES] [--memory MEMORY] [--preemption POLICY]
+func (c *ServicedCli) cmdPoolQuotaSet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 || (!ctx.IsSet("cores") && !ctx.IsSet("memory") && !ctx.IsSet("preemption")) {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set")
+		return
+	}
+
+	pool, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if pool == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		c.exit(1)
+		return
+	}
+
+	if ctx.IsSet("cores") {
+		cores := ctx.Int("cores")
+		if cores < 0 {
+			fmt.Fprintln(os.Stderr, "cores cannot be negative")
+			c.exit(1)
+			return
+		}
+		pool.CoreLimit = cores
+	}
+
+	if ctx.IsSet("memory") {
+		memory, err := utils.ParseEngineeringNotation(ctx.String("memory"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not parse memory: %s\n", err)
+			c.exit(1)
+			return
+		}
+		pool.MemoryLimit = memory
+	}
+
+	if ctx.IsSet("preemption") {
+		pool.PreemptionPolicy = ctx.String("preemption")
+	}
+
+	if err := c.driver.UpdateResourcePool(*pool); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(pool.ID)
+}
+
 func (c *ServicedCli) cmdSetPermission(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) != 1 {
@@ -163,6 +163,19 @@ func (t PoolAPITest) GetPoolIPs(id string) (*pool.PoolIPs, error) {
 	return &pool.PoolIPs{PoolID: p.ID, HostIPs: t.hostIPs}, nil
 }
 
+func (t PoolAPITest) GetPoolUsage(id string) (*pool.Usage, error) {
+	p, err := t.GetResourcePool(id)
+	if err != nil {
+		return nil, err
+	} else if p == nil {
+		return nil, ErrNoPoolFound
+	}
+
+	usage := pool.NewUsage(p)
+	usage.Commit(1, 256*1024*1024, 2)
+	return usage, nil
+}
+
 func (t PoolAPITest) UpdateResourcePool(pool pool.ResourcePool) error {
 	for i, p := range *t.pools {
 		if p.ID == pool.ID {
@@ -417,6 +430,48 @@ func ExampleServicedCLI_CmdPoolListIPs_err() {
 	// no resource pool IPs found
 }
 
+func ExampleServicedCLI_CmdPoolQuotaShow() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "quota", "show", "test-pool-id-3")
+
+	// Output:
+	// Resource Committed Limit     Capacity
+	// cores    2         2         0
+	// memory   536870912 536870912 0
+}
+
+func ExampleServicedCLI_CmdPoolQuotaShow_fail() {
+	pipeStderr(func() { RunCmd(DefaultPoolAPI(), "serviced", "pool", "quota", "show", "test-pool-id-0") })
+
+	// Output:
+	// no pool found
+}
+
+func TestServicedCLI_CmdPoolQuotaSet(t *testing.T) {
+	test := DefaultPoolAPI()
+	poolID := "test-pool-id-1"
+
+	RunCmd(test, "serviced", "pool", "quota", "set", poolID, "--cores", "2", "--memory", "4G")
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatal(err)
+	} else if p.CoreLimit != 2 || p.MemoryLimit != 4*1024*1024*1024 {
+		t.Fatalf("unexpected quota: %d cores, %d bytes", p.CoreLimit, p.MemoryLimit)
+	}
+
+	RunCmd(test, "serviced", "pool", "quota", "set", poolID, "--memory", "0")
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatal(err)
+	} else if p.CoreLimit != 2 || p.MemoryLimit != 0 {
+		t.Fatalf("unexpected quota: %d cores, %d bytes", p.CoreLimit, p.MemoryLimit)
+	}
+
+	RunCmd(test, "serviced", "pool", "quota", "set", poolID, "--preemption", pool.PreemptLowerPriority)
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatal(err)
+	} else if p.PreemptionPolicy != pool.PreemptLowerPriority {
+		t.Fatalf("unexpected preemption policy: %s", p.PreemptionPolicy)
+	}
+}
+
 func TestServicedCLI_CmdPoolSetPermission(t *testing.T) {
 	test := EmptyPoolAPI()
 	assertPerm := func(poolID string, expected pool.Permission) {
@@ -16,11 +16,15 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 // The vhost and port public endpoint structures are different, so we'll
@@ -34,6 +38,9 @@ type PublicEndpoint struct {
 	Protocol    string
 	Name        string
 	Enabled     bool
+	Certificate string
+	Renewal     string
+	Policy      *servicedefinition.AccessPolicy `json:",omitempty"`
 }
 
 func NewPublicEndpoint(service string, serviceID string, endpoint string, epType string,
@@ -46,6 +53,7 @@ func NewPublicEndpoint(service string, serviceID string, endpoint string, epType
 		Protocol:    protocol,
 		Name:        name,
 		Enabled:     enabled,
+		Certificate: "default",
 	}
 }
 
@@ -104,6 +112,8 @@ func cmdPublicEndpointsList(c *ServicedCli, ctx *cli.Context, showVHosts bool, s
 		publicEndpoints, err = c.convertPublicEndpoints(peps, showVHosts, showPorts)
 	}
 
+	c.setPublicEndpointCertificates(publicEndpoints)
+
 	// If we're generating JSON..
 	if ctx.Bool("verbose") {
 		if jsonOutput, err := json.MarshalIndent(publicEndpoints, " ", "  "); err != nil {
@@ -138,14 +148,20 @@ func cmdPublicEndpointsList(c *ServicedCli, ctx *cli.Context, showVHosts bool, s
 	t.IndentRow()
 
 	for _, pep := range publicEndpoints {
+		renewal := pep.Renewal
+		if renewal == "" {
+			renewal = "--"
+		}
 		t.AddRow(map[string]interface{}{
-			"Service":   pep.Service,
-			"ServiceID": pep.ServiceID,
-			"Endpoint":  pep.Application,
-			"Type":      pep.EpType,
-			"Protocol":  pep.Protocol,
-			"Name":      pep.Name,
-			"Enabled":   pep.Enabled,
+			"Service":     pep.Service,
+			"ServiceID":   pep.ServiceID,
+			"Endpoint":    pep.Application,
+			"Type":        pep.EpType,
+			"Protocol":    pep.Protocol,
+			"Name":        pep.Name,
+			"Enabled":     pep.Enabled,
+			"Certificate": pep.Certificate,
+			"Renewal":     renewal,
 		})
 	}
 
@@ -173,12 +189,50 @@ func (c *ServicedCli) convertPublicEndpoints(peps []service.PublicEndpoint, show
 		}
 		if add {
 			npep := NewPublicEndpoint(pep.ServiceName, pep.ServiceID, pep.Application, pepType, proto, pepName, pep.Enabled)
+			npep.Policy = pep.Policy
 			result = append(result, npep)
 		}
 	}
 	return result, nil
 }
 
+// Sets the certificate served for each public endpoint, and the renewal
+// status of acme certificates.  Endpoints without a certificate of their
+// own are served with the default certificate.
+func (c *ServicedCli) setPublicEndpointCertificates(publicEndpoints []PublicEndpoint) {
+	certs, err := c.driver.GetPublicEndpointCertificates()
+	if err != nil || len(certs) == 0 {
+		return
+	}
+	byID := make(map[string]certificate.Certificate)
+	for _, cert := range certs {
+		byID[cert.ID] = cert
+	}
+	for i, pep := range publicEndpoints {
+		name := pep.Name
+		if pep.EpType == certificate.TypePort {
+			name = service.ScrubPortString(name)
+		}
+		cert, ok := byID[certificate.ID(pep.EpType, name)]
+		if !ok {
+			continue
+		}
+		if !cert.ACME {
+			publicEndpoints[i].Certificate = "custom"
+			continue
+		}
+		publicEndpoints[i].Certificate = "acme"
+		switch cert.RenewalStatus {
+		case certificate.RenewalValid:
+			publicEndpoints[i].Renewal = fmt.Sprintf("valid until %s", cert.NotAfter.Format("2006-01-02"))
+		case certificate.RenewalFailed:
+			publicEndpoints[i].Renewal = fmt.Sprintf("failed: %s", cert.RenewalError)
+		default:
+			publicEndpoints[i].Renewal = cert.RenewalStatus
+		}
+	}
+}
+
 // Create a unified list of vhosts and port based public endpoints.
 func (c *ServicedCli) createPublicEndpoints(ctx *cli.Context, services []service.Service,
 	showVHosts bool, showPorts bool) ([]PublicEndpoint, error) {
@@ -209,6 +263,7 @@ func (c *ServicedCli) createPublicEndpoints(ctx *cli.Context, services []service
 						vhost.Name,
 						vhost.Enabled,
 					)
+					publicEndpoint.Policy = publicEndpointPolicy(vhost.Policy)
 					publicEndpoints = append(publicEndpoints, publicEndpoint)
 				}
 			}
@@ -231,6 +286,7 @@ func (c *ServicedCli) createPublicEndpoints(ctx *cli.Context, services []service
 						port.PortAddr,
 						port.Enabled,
 					)
+					publicEndpoint.Policy = publicEndpointPolicy(port.Policy)
 					publicEndpoints = append(publicEndpoints, publicEndpoint)
 				}
 			}
@@ -244,6 +300,15 @@ func (c *ServicedCli) createPublicEndpoints(ctx *cli.Context, services []service
 	return publicEndpoints, nil
 }
 
+// Returns the access policy of a public endpoint for output, or nil if it
+// does not restrict anything.
+func publicEndpointPolicy(policy servicedefinition.AccessPolicy) *servicedefinition.AccessPolicy {
+	if policy.IsEmpty() {
+		return nil
+	}
+	return &policy
+}
+
 // List port public endpoints
 // serviced service public-endpoints port list [SERVICEID] [ENDPOINTNAME]
 func (c *ServicedCli) cmdPublicEndpointsPortList(ctx *cli.Context) {
@@ -285,8 +350,10 @@ func (c *ServicedCli) cmdPublicEndpointsPortAdd(ctx *cli.Context) {
 		protocol = "" // Stored as an empty string.
 		usetls = true
 		break
+	case servicedefinition.PortProtocolUDP, servicedefinition.PortProtocolTLSPassthrough:
+		break
 	default:
-		fmt.Fprintln(os.Stderr, "The protocol must be one of: https, http, other-tls, other")
+		fmt.Fprintln(os.Stderr, "The protocol must be one of: https, http, other-tls, other, udp, tls-passthrough")
 		c.exit(1)
 		return
 	}
@@ -488,3 +555,186 @@ func (c *ServicedCli) cmdPublicEndpointsVHostEnable(ctx *cli.Context) {
 	}
 	return
 }
+
+// Set the certificate of a port public endpoint
+// serviced service public-endpoints port set-cert <SERVICEID> <ENDPOINTNAME> <PORTADDR> <CERTFILE> <KEYFILE>
+func (c *ServicedCli) cmdPublicEndpointsPortSetCert(ctx *cli.Context) {
+	cmdPublicEndpointsSetCert(c, ctx, certificate.TypePort)
+}
+
+// Remove the certificate of a port public endpoint
+// serviced service public-endpoints port remove-cert <PORTADDR>
+func (c *ServicedCli) cmdPublicEndpointsPortRemoveCert(ctx *cli.Context) {
+	cmdPublicEndpointsRemoveCert(c, ctx, certificate.TypePort)
+}
+
+// Set the certificate of a vhost public endpoint
+// serviced service public-endpoints vhost set-cert <SERVICEID> <ENDPOINTNAME> <VHOST> <CERTFILE> <KEYFILE>
+func (c *ServicedCli) cmdPublicEndpointsVHostSetCert(ctx *cli.Context) {
+	cmdPublicEndpointsSetCert(c, ctx, certificate.TypeVHost)
+}
+
+// Remove the certificate of a vhost public endpoint
+// serviced service public-endpoints vhost remove-cert <VHOST>
+func (c *ServicedCli) cmdPublicEndpointsVHostRemoveCert(ctx *cli.Context) {
+	cmdPublicEndpointsRemoveCert(c, ctx, certificate.TypeVHost)
+}
+
+// Set the access policy of a port public endpoint
+// serviced service public-endpoints port set-policy <SERVICEID> <ENDPOINTNAME> <PORTADDR>
+func (c *ServicedCli) cmdPublicEndpointsPortSetPolicy(ctx *cli.Context) {
+	cmdPublicEndpointsSetPolicy(c, ctx, c.driver.SetPublicEndpointPortPolicy)
+}
+
+// Set the access policy of a vhost public endpoint
+// serviced service public-endpoints vhost set-policy <SERVICEID> <ENDPOINTNAME> <VHOST>
+func (c *ServicedCli) cmdPublicEndpointsVHostSetPolicy(ctx *cli.Context) {
+	cmdPublicEndpointsSetPolicy(c, ctx, c.driver.SetPublicEndpointVHostPolicy)
+}
+
+// Method that executes the port and vhost set-policy subcommands.  The flags
+// replace the whole policy of the endpoint, so running the command without
+// flags lets every client through again.
+func cmdPublicEndpointsSetPolicy(c *ServicedCli, ctx *cli.Context, setPolicy func(serviceid, endpointName, name string, policy servicedefinition.AccessPolicy) error) {
+	// Make sure we have each argument.
+	if len(ctx.Args()) != 3 {
+		cli.ShowCommandHelp(ctx, "set-policy")
+		return
+	}
+
+	serviceid := ctx.Args()[0]
+	endpointName := ctx.Args()[1]
+	name := ctx.Args()[2]
+	policy := servicedefinition.AccessPolicy{
+		AllowCIDRs:     ctx.StringSlice("allow"),
+		DenyCIDRs:      ctx.StringSlice("deny"),
+		ConnectionRate: ctx.Float64("connection-rate"),
+		RequestRate:    ctx.Float64("request-rate"),
+		RateBurst:      ctx.Int("burst"),
+		MaxConnections: ctx.Int("max-connections"),
+	}
+
+	// We need the serviceid, but they may have provided the service id or name.
+	svc, _, err := c.searchForService(serviceid, ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if err := setPolicy(svc.ID, endpointName, name, policy); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("%s\n", name)
+}
+
+// Method that executes the port and vhost set-cert subcommands.  The
+// certificate and key are read from pem files and stored on the master,
+// which serves them for the public endpoint in place of the default
+// certificate.
+func cmdPublicEndpointsSetCert(c *ServicedCli, ctx *cli.Context, certType string) {
+	if ctx.Bool("acme") {
+		cmdPublicEndpointsSetACME(c, ctx, certType)
+		return
+	}
+
+	// Make sure we have each argument.
+	if len(ctx.Args()) != 5 {
+		cli.ShowCommandHelp(ctx, "set-cert")
+		return
+	}
+
+	serviceid := ctx.Args()[0]
+	endpointName := ctx.Args()[1]
+	name := ctx.Args()[2]
+
+	certPEM, err := ioutil.ReadFile(ctx.Args()[3])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read certificate file: %s\n", err)
+		c.exit(1)
+		return
+	}
+	keyPEM, err := ioutil.ReadFile(ctx.Args()[4])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read key file: %s\n", err)
+		c.exit(1)
+		return
+	}
+
+	// We need the serviceid, but they may have provided the service id or name.
+	svc, _, err := c.searchForService(serviceid, ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	cert, err := c.driver.SetPublicEndpointCertificate(svc.ID, endpointName, certType, name, string(certPEM), string(keyPEM))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("%s (expires %s)\n", cert.Name, cert.NotAfter.Format("2006-01-02"))
+}
+
+// Method that executes the port and vhost set-cert subcommands with --acme.
+// The master requests a certificate for the domains from its acme server and
+// renews it before it expires.  A vhost is issued a certificate for its own
+// name, unless other domains are given.
+func cmdPublicEndpointsSetACME(c *ServicedCli, ctx *cli.Context, certType string) {
+	// Make sure we have each argument.
+	if len(ctx.Args()) != 3 {
+		cli.ShowCommandHelp(ctx, "set-cert")
+		return
+	}
+
+	serviceid := ctx.Args()[0]
+	endpointName := ctx.Args()[1]
+	name := ctx.Args()[2]
+
+	domains := ctx.StringSlice("domain")
+	if len(domains) == 0 {
+		if certType != certificate.TypeVHost || !strings.Contains(name, ".") {
+			fmt.Fprintf(os.Stderr, "A domain is required for the acme certificate of %s; use --domain\n", name)
+			c.exit(1)
+			return
+		}
+		domains = []string{name}
+	}
+
+	// We need the serviceid, but they may have provided the service id or name.
+	svc, _, err := c.searchForService(serviceid, ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	cert, err := c.driver.SetPublicEndpointACME(svc.ID, endpointName, certType, name, domains)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("%s (acme %s)\n", cert.Name, cert.RenewalStatus)
+}
+
+// Method that executes the port and vhost remove-cert subcommands.
+func cmdPublicEndpointsRemoveCert(c *ServicedCli, ctx *cli.Context, certType string) {
+	// Make sure we have each argument.
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelp(ctx, "remove-cert")
+		return
+	}
+
+	name := ctx.Args()[0]
+	if err := c.driver.RemovePublicEndpointCertificate(certType, name); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("%s\n", name)
+}
@@ -17,17 +17,22 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/utils"
 )
 
 var DefaultTestPublicEndpointPorts = []servicedefinition.Port{
 	servicedefinition.Port{PortAddr: ":22222", Enabled: true, UseTLS: true, Protocol: "https"},
-	servicedefinition.Port{PortAddr: ":22223", Enabled: true, UseTLS: false, Protocol: "http"},
+	servicedefinition.Port{PortAddr: ":22223", Enabled: true, UseTLS: false, Protocol: "http",
+		Policy: servicedefinition.AccessPolicy{AllowCIDRs: []string{"10.0.0.0/8"}, RequestRate: 20}},
 	servicedefinition.Port{PortAddr: ":22224", Enabled: true, UseTLS: true, Protocol: ""},
 	servicedefinition.Port{PortAddr: ":22225", Enabled: false, UseTLS: false, Protocol: ""},
 }
@@ -96,6 +101,68 @@ func (t ServiceAPITest) EnablePublicEndpointVHost(serviceID, endpointName, vhost
 	return nil
 }
 
+func (t ServiceAPITest) SetPublicEndpointPortPolicy(serviceID, endpointName, portAddr string, policy servicedefinition.AccessPolicy) error {
+	if t.errs["SetPublicEndpointPortPolicy"] != nil {
+		return t.errs["SetPublicEndpointPortPolicy"]
+	}
+	return policy.ValidEntity()
+}
+
+func (t ServiceAPITest) SetPublicEndpointVHostPolicy(serviceID, endpointName, vhost string, policy servicedefinition.AccessPolicy) error {
+	if t.errs["SetPublicEndpointVHostPolicy"] != nil {
+		return t.errs["SetPublicEndpointVHostPolicy"]
+	}
+	return policy.ValidEntity()
+}
+
+func (t ServiceAPITest) SetPublicEndpointCertificate(serviceID, endpointName, certType, name, certPEM, keyPEM string) (*certificate.Certificate, error) {
+	if t.errs["SetPublicEndpointCertificate"] != nil {
+		return nil, t.errs["SetPublicEndpointCertificate"]
+	}
+	return &certificate.Certificate{Type: certType, Name: name, NotAfter: time.Date(2027, 3, 3, 0, 0, 0, 0, time.UTC)}, nil
+}
+
+func (t ServiceAPITest) RemovePublicEndpointCertificate(certType, name string) error {
+	if t.errs["RemovePublicEndpointCertificate"] != nil {
+		return t.errs["RemovePublicEndpointCertificate"]
+	}
+	return nil
+}
+
+func (t ServiceAPITest) SetPublicEndpointACME(serviceID, endpointName, certType, name string, domains []string) (*certificate.Certificate, error) {
+	if t.errs["SetPublicEndpointACME"] != nil {
+		return nil, t.errs["SetPublicEndpointACME"]
+	}
+	return certificate.NewACME(certType, name, serviceID, endpointName, domains), nil
+}
+
+func (t ServiceAPITest) GetPublicEndpointCertificates() ([]certificate.Certificate, error) {
+	if t.errs["GetPublicEndpointCertificates"] != nil {
+		return nil, t.errs["GetPublicEndpointCertificates"]
+	}
+	return []certificate.Certificate{
+		{ID: certificate.ID(certificate.TypePort, ":22225"), Type: certificate.TypePort, Name: ":22225"},
+		{
+			ID:            certificate.ID(certificate.TypePort, ":22223"),
+			Type:          certificate.TypePort,
+			Name:          ":22223",
+			ACME:          true,
+			Domains:       []string{"zenoss.example.com"},
+			RenewalStatus: certificate.RenewalValid,
+			NotAfter:      time.Date(2027, 3, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:            certificate.ID(certificate.TypePort, ":22224"),
+			Type:          certificate.TypePort,
+			Name:          ":22224",
+			ACME:          true,
+			Domains:       []string{"zenoss.example.com"},
+			RenewalStatus: certificate.RenewalFailed,
+			RenewalError:  "connection refused",
+		},
+	}, nil
+}
+
 func InitPublicEndpointPortTest(args ...string) {
 	c := New(DefaultServiceAPITest, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
 	c.exitDisabled = true
@@ -118,7 +185,7 @@ func ExampleServicedCLI_CmdPublicEndpointsList_usage(t *testing.T) {
 			"   --ascii, -a                                                                  use ascii characters for service tree (env SERVICED_TREE_ASCII=1 will default to ascii)\n" +
 			"   --ports                                                                      Show port public endpoints\n" +
 			"   --vhosts                                                                     Show vhost public endpoints\n" +
-			"   --show-fields 'Service,ServiceID,Endpoint,Type,Protocol,Name,Enabled'        Comma-delimited list describing which fields to display\n" +
+			"   --show-fields 'Service,ServiceID,Endpoint,Type,Protocol,Name,Enabled,Certificate,Renewal'        Comma-delimited list describing which fields to display\n" +
 			"   --verbose, -v                                                                Show JSON format"
 
 	outStr := TrimLines(fmt.Sprintf("%s", output))
@@ -142,55 +209,55 @@ func ExampleServicedCLI_CmdPublicEndpointsList() {
 	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "list")
 
 	// Output:
-	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled
-	//   Zenoss      test-service-1      zproxy        port      https          :22222      true
-	//   Zenoss      test-service-1      zproxy        port      http           :22223      true
-	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true
-	//   Zenoss      test-service-1      zproxy        port      other          :22225      false
+	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled      Certificate      Renewal
+	//   Zenoss      test-service-1      zproxy        port      https          :22222      true         default          --
+	//   Zenoss      test-service-1      zproxy        port      http           :22223      true         acme             valid until 2027-03-03
+	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true         acme             failed: connection refused
+	//   Zenoss      test-service-1      zproxy        port      other          :22225      false        custom           --
 }
 
 func ExampleServicedCLI_CmdPublicEndpointsList_endpointsByService() {
 	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "list", "Zenoss")
 
 	// Output:
-	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled
-	//   Zenoss      test-service-1      zproxy        port      https          :22222      true
-	//   Zenoss      test-service-1      zproxy        port      http           :22223      true
-	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true
-	//   Zenoss      test-service-1      zproxy        port      other          :22225      false
+	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled      Certificate      Renewal
+	//   Zenoss      test-service-1      zproxy        port      https          :22222      true         default          --
+	//   Zenoss      test-service-1      zproxy        port      http           :22223      true         acme             valid until 2027-03-03
+	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true         acme             failed: connection refused
+	//   Zenoss      test-service-1      zproxy        port      other          :22225      false        custom           --
 }
 
 func ExampleServicedCLI_CmdPublicEndpointsList_endpointsByServiceID() {
 	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "list", "test-service-1")
 
 	// Output:
-	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled
-	//   Zenoss      test-service-1      zproxy        port      https          :22222      true
-	//   Zenoss      test-service-1      zproxy        port      http           :22223      true
-	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true
-	//   Zenoss      test-service-1      zproxy        port      other          :22225      false
+	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled      Certificate      Renewal
+	//   Zenoss      test-service-1      zproxy        port      https          :22222      true         default          --
+	//   Zenoss      test-service-1      zproxy        port      http           :22223      true         acme             valid until 2027-03-03
+	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true         acme             failed: connection refused
+	//   Zenoss      test-service-1      zproxy        port      other          :22225      false        custom           --
 }
 
 func ExampleServicedCLI_CmdPublicEndpointsList_endpointByEndpointName() {
 	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "list", "Zenoss", "zproxy")
 
 	// Output:
-	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled
-	//   Zenoss      test-service-1      zproxy        port      https          :22222      true
-	//   Zenoss      test-service-1      zproxy        port      http           :22223      true
-	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true
-	//   Zenoss      test-service-1      zproxy        port      other          :22225      false
+	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled      Certificate      Renewal
+	//   Zenoss      test-service-1      zproxy        port      https          :22222      true         default          --
+	//   Zenoss      test-service-1      zproxy        port      http           :22223      true         acme             valid until 2027-03-03
+	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true         acme             failed: connection refused
+	//   Zenoss      test-service-1      zproxy        port      other          :22225      false        custom           --
 }
 
 func ExampleServicedCLI_CmdPublicEndpointsList_endpointTypePort() {
 	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "list", "Zenoss", "zproxy", "--ports")
 
 	// Output:
-	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled
-	//   Zenoss      test-service-1      zproxy        port      https          :22222      true
-	//   Zenoss      test-service-1      zproxy        port      http           :22223      true
-	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true
-	//   Zenoss      test-service-1      zproxy        port      other          :22225      false
+	// Service       ServiceID           Endpoint      Type      Protocol       Name        Enabled      Certificate      Renewal
+	//   Zenoss      test-service-1      zproxy        port      https          :22222      true         default          --
+	//   Zenoss      test-service-1      zproxy        port      http           :22223      true         acme             valid until 2027-03-03
+	//   Zenoss      test-service-1      zproxy        port      other-tls      :22224      true         acme             failed: connection refused
+	//   Zenoss      test-service-1      zproxy        port      other          :22225      false        custom           --
 }
 
 func ExampleServicedCLI_CmdPublicEndpointsList_endpointTypeVHost() {
@@ -243,7 +310,9 @@ func ExampleServicedCLI_CmdPublicEndpointsList_endpoint_service1_verbose() {
 	//      "EpType": "port",
 	//      "Protocol": "https",
 	//      "Name": ":22222",
-	//      "Enabled": true
+	//      "Enabled": true,
+	//      "Certificate": "default",
+	//      "Renewal": ""
 	//    },
 	//    {
 	//      "Service": "Zenoss",
@@ -252,7 +321,15 @@ func ExampleServicedCLI_CmdPublicEndpointsList_endpoint_service1_verbose() {
 	//      "EpType": "port",
 	//      "Protocol": "http",
 	//      "Name": ":22223",
-	//      "Enabled": true
+	//      "Enabled": true,
+	//      "Certificate": "acme",
+	//      "Renewal": "valid until 2027-03-03",
+	//      "Policy": {
+	//        "AllowCIDRs": [
+	//          "10.0.0.0/8"
+	//        ],
+	//        "RequestRate": 20
+	//      }
 	//    },
 	//    {
 	//      "Service": "Zenoss",
@@ -261,7 +338,9 @@ func ExampleServicedCLI_CmdPublicEndpointsList_endpoint_service1_verbose() {
 	//      "EpType": "port",
 	//      "Protocol": "other-tls",
 	//      "Name": ":22224",
-	//      "Enabled": true
+	//      "Enabled": true,
+	//      "Certificate": "acme",
+	//      "Renewal": "failed: connection refused"
 	//    },
 	//    {
 	//      "Service": "Zenoss",
@@ -270,7 +349,9 @@ func ExampleServicedCLI_CmdPublicEndpointsList_endpoint_service1_verbose() {
 	//      "EpType": "port",
 	//      "Protocol": "other",
 	//      "Name": ":22225",
-	//      "Enabled": false
+	//      "Enabled": false,
+	//      "Certificate": "custom",
+	//      "Renewal": ""
 	//    }
 	//  ]
 }
@@ -297,7 +378,7 @@ func ExampleServicedCLI_CmdPublicEndpointsPortAdd_InvalidProtocol() {
 	})
 
 	// Output:
-	// The protocol must be one of: https, http, other-tls, other
+	// The protocol must be one of: https, http, other-tls, other, udp, tls-passthrough
 }
 
 func ExampleServicedCLI_CmdPublicEndpointsPortAdd_ValidProtocol() {
@@ -305,12 +386,16 @@ func ExampleServicedCLI_CmdPublicEndpointsPortAdd_ValidProtocol() {
 	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "add", "Zenoss", "zproxy", ":22222", "https", "true")
 	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "add", "Zenoss", "zproxy", ":22222", "other", "true")
 	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "add", "Zenoss", "zproxy", ":22222", "other-tls", "true")
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "add", "Zenoss", "zproxy", ":22222", "udp", "true")
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "add", "Zenoss", "zproxy", ":22222", "tls-passthrough", "true")
 
 	// Output:
 	// :22222
 	// :22222
 	// :22222
 	// :22222
+	// :22222
+	// :22222
 }
 
 func ExampleServicedCLI_CmdPublicEndpointsPortRemove() {
@@ -460,3 +545,74 @@ func ExampleServicedCLI_CmdPublicEndpointsVHostEnable_ValidFlags() {
 	// zproxy
 	// zproxy
 }
+
+func ExampleServicedCLI_CmdPublicEndpointsVHostSetCert() {
+	certFile, _ := proxy.TempCertFile()
+	defer os.Remove(certFile)
+	keyFile, _ := proxy.TempKeyFile()
+	defer os.Remove(keyFile)
+
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "vhost", "set-cert", "Zenoss", "zproxy", "zproxy", certFile, keyFile)
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "set-cert", "Zenoss", "zproxy", ":22222", certFile, keyFile)
+
+	// Output:
+	// zproxy (expires 2027-03-03)
+	// :22222 (expires 2027-03-03)
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsVHostSetCert_MissingFile() {
+	pipeStderr(func() {
+		InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "vhost", "set-cert", "Zenoss", "zproxy", "zproxy", "/nonexistent.crt", "/nonexistent.key")
+	})
+
+	// Output:
+	// Could not read certificate file: open /nonexistent.crt: no such file or directory
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsVHostSetCert_ACME() {
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "vhost", "set-cert", "--acme", "Zenoss", "zproxy", "zproxy.example.com")
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "set-cert", "--acme", "--domain", "zenoss.example.com", "Zenoss", "zproxy", ":22222")
+
+	// Output:
+	// zproxy.example.com (acme pending)
+	// :22222 (acme pending)
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsVHostSetCert_ACMENoDomain() {
+	pipeStderr(func() {
+		InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "vhost", "set-cert", "--acme", "Zenoss", "zproxy", "zproxy")
+	})
+
+	// Output:
+	// A domain is required for the acme certificate of zproxy; use --domain
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsVHostRemoveCert() {
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "vhost", "remove-cert", "zproxy")
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "remove-cert", ":22222")
+
+	// Output:
+	// zproxy
+	// :22222
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsSetPolicy() {
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "set-policy", "--allow", "10.0.0.0/8", "--deny", "10.0.5.0/24",
+		"--connection-rate", "5", "--max-connections", "100", "Zenoss", "zproxy", ":22222")
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "vhost", "set-policy", "--request-rate", "50", "--burst", "100", "Zenoss", "zproxy", "zproxy")
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "vhost", "set-policy", "Zenoss", "zproxy", "zproxy")
+
+	// Output:
+	// :22222
+	// zproxy
+	// zproxy
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsSetPolicy_InvalidCIDR() {
+	pipeStderr(func() {
+		InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "set-policy", "--allow", "10.0.0.1", "Zenoss", "zproxy", ":22222")
+	})
+
+	// Output:
+	// invalid cidr 10.0.0.1
+}
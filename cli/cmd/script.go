@@ -58,8 +58,24 @@ func (c *ServicedCli) initScript() {
 						Name:  "no-op, n",
 						Usage: "Run through script without modifying system",
 					},
+					cli.BoolFlag{
+						Name:  "job",
+						Usage: "Run the script as a resumable job on the master",
+					},
 				},
 			},
+			{
+				Name:        "status",
+				Usage:       "Show the progress of script jobs",
+				Description: "serviced script status [JOBID]",
+				Action:      c.cmdScriptStatus,
+			},
+			{
+				Name:        "resume",
+				Usage:       "Resume a failed or interrupted script job",
+				Description: "serviced script resume JOBID",
+				Action:      c.cmdScriptResume,
+			},
 		},
 	})
 }
@@ -100,6 +116,17 @@ func (c *ServicedCli) cmdScriptRun(ctx *cli.Context) {
 		config.ServiceID = svc.ID
 	}
 
+	if ctx.Bool("job") {
+		jobID, err := c.driver.ScriptRunJob(fileName, config.ServiceID, ctx.Bool("no-op"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		fmt.Println(jobID)
+		return
+	}
+
 	// exec unix script command to log output
 	if isWithin := os.Getenv("IS_WITHIN_UNIX_SCRIPT"); isWithin != "TRUE" {
 		os.Setenv("IS_WITHIN_UNIX_SCRIPT", "TRUE") // prevent inception problem
@@ -141,8 +168,7 @@ func (c *ServicedCli) cmdScriptParse(ctx *cli.Context) {
 		return
 	}
 	fileName := args[0]
-	config := script.Config{}
-	err := c.driver.ScriptParse(fileName, &config)
+	err := c.driver.ScriptParse(fileName)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		c.exit(1)
@@ -150,6 +176,100 @@ func (c *ServicedCli) cmdScriptParse(ctx *cli.Context) {
 	return
 }
 
+// cmdScriptStatus serviced script status [JOBID]
+func (c *ServicedCli) cmdScriptStatus(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "Incorrect Usage.\n\n")
+		cli.ShowSubcommandHelp(ctx)
+		c.exit(1)
+		return
+	}
+
+	if len(args) == 0 {
+		jobs, err := c.driver.GetScriptJobs()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		if len(jobs) == 0 {
+			fmt.Fprintln(os.Stderr, "no script jobs found")
+			return
+		}
+		t := NewTable("JobID,File,Status,Step,Updated")
+		for _, job := range jobs {
+			t.AddRow(map[string]interface{}{
+				"JobID":   job.ID,
+				"File":    job.FileName,
+				"Status":  job.Status,
+				"Step":    fmt.Sprintf("%d/%d", completedSteps(job.Steps), len(job.Steps)),
+				"Updated": job.UpdatedAt.Format(time.RFC3339),
+			})
+		}
+		t.Padding = 6
+		t.Print()
+		return
+	}
+
+	job, err := c.driver.GetScriptJob(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("Job %s: %s\n", job.ID, job.Status)
+	if job.Error != "" {
+		fmt.Printf("Error: %s\n", job.Error)
+	}
+	if job.RestoredSnapshot != "" {
+		fmt.Printf("Restored snapshot: %s\n", job.RestoredSnapshot)
+	}
+	t := NewTable("Line,Command,Status,Detail")
+	for _, step := range job.Steps {
+		detail := step.Error
+		if detail == "" {
+			detail = step.SnapshotID
+		}
+		t.AddRow(map[string]interface{}{
+			"Line":    step.LineNum,
+			"Command": step.Line,
+			"Status":  step.Status,
+			"Detail":  detail,
+		})
+	}
+	t.Padding = 6
+	t.Print()
+}
+
+// cmdScriptResume serviced script resume JOBID
+func (c *ServicedCli) cmdScriptResume(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Incorrect Usage.\n\n")
+		cli.ShowSubcommandHelp(ctx)
+		c.exit(1)
+		return
+	}
+	if err := c.driver.ResumeScriptJob(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// completedSteps returns the number of steps of a job that succeeded
+func completedSteps(steps []script.Step) int {
+	count := 0
+	for _, step := range steps {
+		if step.Status == script.StepSucceeded {
+			count++
+		}
+	}
+	return count
+}
+
 func runScript(c *ServicedCli, ctx *cli.Context, fileName string, config *script.Config) {
 	stopChan := make(chan struct{})
 	signalHandlerChan := make(chan os.Signal, 1)
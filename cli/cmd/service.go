@@ -18,10 +18,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -34,6 +36,7 @@ import (
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -97,6 +100,22 @@ func (c *ServicedCli) initService() {
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
 					},
 				},
+			}, {
+				Name:         "health",
+				Usage:        "Displays the health checks of the instances of a service",
+				Description:  "serviced service health { SERVICEID | SERVICENAME | [POOL/]...PARENTNAME.../SERVICENAME }",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceHealth,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "history",
+						Usage: "show the recent results of each health check",
+					},
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
 			}, {
 				Name:        "add",
 				Usage:       "Adds a new service",
@@ -449,7 +468,7 @@ func (c *ServicedCli) initService() {
 							},
 							cli.StringFlag{
 								Name:  "show-fields",
-								Value: "Service,ServiceID,Endpoint,Type,Protocol,Name,Enabled",
+								Value: "Service,ServiceID,Endpoint,Type,Protocol,Name,Enabled,Certificate,Renewal",
 								Usage: "Comma-delimited list describing which fields to display",
 							},
 							cli.BoolFlag{
@@ -479,7 +498,7 @@ func (c *ServicedCli) initService() {
 									},
 									cli.StringFlag{
 										Name:  "show-fields",
-										Value: "Service,ServiceID,Endpoint,Type,Protocol,Name,Enabled",
+										Value: "Service,ServiceID,Endpoint,Type,Protocol,Name,Enabled,Certificate,Renewal",
 										Usage: "Comma-delimited list describing which fields to display",
 									},
 									cli.BoolFlag{
@@ -533,6 +552,71 @@ func (c *ServicedCli) initService() {
 									},
 								},
 							},
+							{
+								Name:        "set-cert",
+								Usage:       "Set the TLS certificate served for a port public endpoint",
+								Description: "serviced service public-endpoints port set-cert <SERVICEID> <ENDPOINTNAME> <PORTADDR> <CERTFILE> <KEYFILE> | --acme <SERVICEID> <ENDPOINTNAME> <PORTADDR>",
+								Action:      c.cmdPublicEndpointsPortSetCert,
+								Flags: []cli.Flag{
+									cli.BoolFlag{
+										Name:  "no-prefix-match, np",
+										Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+									},
+									cli.BoolFlag{
+										Name:  "acme",
+										Usage: "Issue and renew the certificate with the acme server of the master",
+									},
+									cli.StringSliceFlag{
+										Name:  "domain",
+										Value: &cli.StringSlice{},
+										Usage: "Domain of the acme certificate (repeatable)",
+									},
+								},
+							},
+							{
+								Name:        "set-policy",
+								Usage:       "Set which clients may use a port public endpoint and how often",
+								Description: "serviced service public-endpoints port set-policy <SERVICEID> <ENDPOINTNAME> <PORTADDR>",
+								Action:      c.cmdPublicEndpointsPortSetPolicy,
+								Flags: []cli.Flag{
+									cli.BoolFlag{
+										Name:  "no-prefix-match, np",
+										Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+									},
+									cli.StringSliceFlag{
+										Name:  "allow",
+										Value: &cli.StringSlice{},
+										Usage: "CIDR of the clients that may connect; all clients if not set (repeatable)",
+									},
+									cli.StringSliceFlag{
+										Name:  "deny",
+										Value: &cli.StringSlice{},
+										Usage: "CIDR of the clients that are refused (repeatable)",
+									},
+									cli.Float64Flag{
+										Name:  "connection-rate",
+										Usage: "Maximum new connections per second from each client IP, 0 for no limit",
+									},
+									cli.Float64Flag{
+										Name:  "request-rate",
+										Usage: "Maximum http requests per second from each client IP, 0 for no limit",
+									},
+									cli.IntFlag{
+										Name:  "burst",
+										Usage: "Requests or connections a client may make at once above the rate; defaults to the rate",
+									},
+									cli.IntFlag{
+										Name:  "max-connections",
+										Usage: "Maximum open connections to the port, 0 for no limit",
+									},
+								},
+							},
+							{
+								Name:        "remove-cert",
+								Usage:       "Remove the TLS certificate of a port public endpoint",
+								Description: "serviced service public-endpoints port remove-cert <PORTADDR>",
+								Action:      c.cmdPublicEndpointsPortRemoveCert,
+							},
 						},
 					},
 					{
@@ -552,7 +636,7 @@ func (c *ServicedCli) initService() {
 									},
 									cli.StringFlag{
 										Name:  "show-fields",
-										Value: "Service,ServiceID,Endpoint,Type,Protocol,Name,Enabled",
+										Value: "Service,ServiceID,Endpoint,Type,Protocol,Name,Enabled,Certificate,Renewal",
 										Usage: "Comma-delimited list describing which fields to display",
 									},
 									cli.BoolFlag{
@@ -602,6 +686,67 @@ func (c *ServicedCli) initService() {
 									},
 								},
 							},
+							{
+								Name:        "set-cert",
+								Usage:       "Set the TLS certificate served for a vhost public endpoint",
+								Description: "serviced service public-endpoints vhost set-cert <SERVICEID> <ENDPOINTNAME> <VHOST> <CERTFILE> <KEYFILE> | --acme <SERVICEID> <ENDPOINTNAME> <VHOST>",
+								Action:      c.cmdPublicEndpointsVHostSetCert,
+								Flags: []cli.Flag{
+									cli.BoolFlag{
+										Name:  "no-prefix-match, np",
+										Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+									},
+									cli.BoolFlag{
+										Name:  "acme",
+										Usage: "Issue and renew the certificate with the acme server of the master",
+									},
+									cli.StringSliceFlag{
+										Name:  "domain",
+										Value: &cli.StringSlice{},
+										Usage: "Domain of the acme certificate (repeatable)",
+									},
+								},
+							},
+							{
+								Name:        "set-policy",
+								Usage:       "Set which clients may use a vhost public endpoint and how often",
+								Description: "serviced service public-endpoints vhost set-policy <SERVICEID> <ENDPOINTNAME> <VHOST>",
+								Action:      c.cmdPublicEndpointsVHostSetPolicy,
+								Flags: []cli.Flag{
+									cli.BoolFlag{
+										Name:  "no-prefix-match, np",
+										Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+									},
+									cli.StringSliceFlag{
+										Name:  "allow",
+										Value: &cli.StringSlice{},
+										Usage: "CIDR of the clients that may connect; all clients if not set (repeatable)",
+									},
+									cli.StringSliceFlag{
+										Name:  "deny",
+										Value: &cli.StringSlice{},
+										Usage: "CIDR of the clients that are refused (repeatable)",
+									},
+									cli.Float64Flag{
+										Name:  "request-rate",
+										Usage: "Maximum http requests per second from each client IP, 0 for no limit",
+									},
+									cli.IntFlag{
+										Name:  "burst",
+										Usage: "Requests a client may make at once above the rate; defaults to the rate",
+									},
+									cli.IntFlag{
+										Name:  "max-connections",
+										Usage: "Maximum requests in flight to the vhost, 0 for no limit",
+									},
+								},
+							},
+							{
+								Name:        "remove-cert",
+								Usage:       "Remove the TLS certificate of a vhost public endpoint",
+								Description: "serviced service public-endpoints vhost remove-cert <VHOST>",
+								Action:      c.cmdPublicEndpointsVHostRemoveCert,
+							},
 						},
 					},
 				},
@@ -655,8 +800,8 @@ func (c *ServicedCli) initService() {
 			},
 			{
 				Name:        "tune",
-				Usage:       "Adjust launch mode, instance count, RAM commitment, or RAM threshold for a service",
-				Description: "serviced service tune SERVICEID",
+				Usage:       "Adjust launch mode, instance count, RAM commitment, RAM threshold, priority, or resource limits for a service, or the memory limit and environment of one instance",
+				Description: "serviced service tune SERVICEID [--instance N --memoryLimit SIZE --env NAME=VALUE --unsetEnv NAME]",
 				Action:      c.cmdServiceTune,
 				Flags: []cli.Flag{
 					cli.StringFlag{
@@ -675,6 +820,54 @@ func (c *ServicedCli) initService() {
 						Name:  "ramThreshold",
 						Usage: "RAM Threshold for this service",
 					},
+					cli.IntFlag{
+						Name:  "priority",
+						Usage: "Scheduling priority for this service; higher priorities may preempt lower ones",
+					},
+					cli.BoolTFlag{
+						Name:  "preemptible",
+						Usage: "Whether instances of this service may be preempted",
+					},
+					cli.Float64Flag{
+						Name:  "cpuQuota",
+						Usage: "Cores of CPU time each instance may use; 0 is unlimited",
+					},
+					cli.StringFlag{
+						Name:  "cpuSet",
+						Usage: "CPUs each instance may run on, e.g. 0-3,6",
+					},
+					cli.IntFlag{
+						Name:  "blkioWeight",
+						Usage: "Relative block I/O weight of each instance (10 to 1000)",
+					},
+					cli.StringSliceFlag{
+						Name:  "deviceReadBps",
+						Value: &cli.StringSlice{},
+						Usage: "Read rate limit of each instance on a device, as PATH:RATE",
+					},
+					cli.StringSliceFlag{
+						Name:  "deviceWriteBps",
+						Value: &cli.StringSlice{},
+						Usage: "Write rate limit of each instance on a device, as PATH:RATE",
+					},
+					cli.IntFlag{
+						Name:  "instance",
+						Usage: "Instance of the service to override the memory limit or environment for",
+					},
+					cli.StringFlag{
+						Name:  "memoryLimit",
+						Usage: "Memory limit of the instance; 0 removes the override",
+					},
+					cli.StringSliceFlag{
+						Name:  "env",
+						Value: &cli.StringSlice{},
+						Usage: "Environment variable of the instance, as NAME=VALUE",
+					},
+					cli.StringSliceFlag{
+						Name:  "unsetEnv",
+						Value: &cli.StringSlice{},
+						Usage: "Environment variable to remove from the overrides of the instance",
+					},
 					cli.BoolFlag{
 						Name:  "no-prefix-match, np",
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -689,9 +882,13 @@ func (c *ServicedCli) initService() {
 					{
 						Name:        "list",
 						Usage:       "List all config files for a given service, or the contents of one named file",
-						Description: "serviced service config list SERVICEID [FILENAME]",
+						Description: "serviced service config list SERVICEID [FILENAME] [--instance N]",
 						Action:      c.cmdServiceConfigList,
 						Flags: []cli.Flag{
+							cli.IntFlag{
+								Name:  "instance",
+								Usage: "Instance of the service to show the config files of, including its overrides",
+							},
 							cli.BoolFlag{
 								Name:  "no-prefix-match, np",
 								Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -715,6 +912,43 @@ func (c *ServicedCli) initService() {
 							},
 						},
 					},
+					{
+						Name:        "set",
+						Usage:       "Set the contents of one config file for a service, or override it for one instance",
+						Description: "serviced service config set SERVICEID FILENAME [--instance N] [--from PATH]",
+						Action:      c.cmdServiceConfigSet,
+						Flags: []cli.Flag{
+							cli.IntFlag{
+								Name:  "instance",
+								Usage: "Instance of the service to override the config file for",
+							},
+							cli.StringFlag{
+								Name:  "from",
+								Value: "",
+								Usage: "File to read the contents from, instead of stdin",
+							},
+							cli.BoolFlag{
+								Name:  "no-prefix-match, np",
+								Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+							},
+						},
+					},
+					{
+						Name:        "unset",
+						Usage:       "Remove the override of one config file for an instance of a service",
+						Description: "serviced service config unset SERVICEID FILENAME --instance N",
+						Action:      c.cmdServiceConfigUnset,
+						Flags: []cli.Flag{
+							cli.IntFlag{
+								Name:  "instance",
+								Usage: "Instance of the service to remove the override from",
+							},
+							cli.BoolFlag{
+								Name:  "no-prefix-match, np",
+								Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+							},
+						},
+					},
 				},
 			},
 			{
@@ -749,9 +983,13 @@ func (c *ServicedCli) initService() {
 					{
 						Name:        "set",
 						Usage:       "Add or update one variable's value for a given service",
-						Description: "serviced service variable set SERVICEID VARIABLE VALUE",
+						Description: "serviced service variable set SERVICEID VARIABLE VALUE [--instance N]",
 						Action:      c.cmdServiceVariableSet,
 						Flags: []cli.Flag{
+							cli.IntFlag{
+								Name:  "instance",
+								Usage: "Instance of the service to set the variable for",
+							},
 							cli.BoolFlag{
 								Name:  "no-prefix-match, np",
 								Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -761,9 +999,13 @@ func (c *ServicedCli) initService() {
 					{
 						Name:        "unset",
 						Usage:       "Remove a variable from a given service",
-						Description: "serviced service variable unset SERVICEID VARIABLE",
+						Description: "serviced service variable unset SERVICEID VARIABLE [--instance N]",
 						Action:      c.cmdServiceVariableUnset,
 						Flags: []cli.Flag{
+							cli.IntFlag{
+								Name:  "instance",
+								Usage: "Instance of the service to unset the variable for",
+							},
 							cli.BoolFlag{
 								Name:  "no-prefix-match, np",
 								Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -1096,6 +1338,101 @@ func (c *ServicedCli) cmdServiceStatus(ctx *cli.Context) {
 	return
 }
 
+// serviced service health [--history] SERVICEID
+func (c *ServicedCli) cmdServiceHealth(ctx *cli.Context) {
+	if len(ctx.Args()) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "health")
+		c.exit(1)
+		return
+	}
+
+	svc, _, err := c.searchForService(ctx.Args().First(), ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	var t *Table
+	if ctx.Bool("history") {
+		history, err := c.driver.GetServiceHealthHistory(svc.ID)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		t = NewTable("Name,Health Check,Started,Duration,Status,Flapping,Output")
+		var keys []health.HealthStatusKey
+		for instanceID, checks := range history {
+			for name := range checks {
+				keys = append(keys, health.HealthStatusKey{InstanceID: instanceID, HealthCheckName: name})
+			}
+		}
+		sortHealthCheckKeys(keys)
+		for _, key := range keys {
+			h := history[key.InstanceID][key.HealthCheckName]
+			for i, entry := range h.Entries {
+				row := map[string]interface{}{
+					"Name":         healthCheckInstanceName(svc, key.InstanceID),
+					"Health Check": key.HealthCheckName,
+					"Started":      entry.StartedAt.Local().Format("2006-01-02 15:04:05"),
+					"Duration":     entry.Duration.String(),
+					"Status":       entry.Status.Name(),
+					"Output":       healthCheckOutput(entry.Output),
+				}
+				// only the latest result says whether the check is flapping
+				if i == len(h.Entries)-1 && h.Flapping {
+					row["Flapping"] = "Y"
+				}
+				t.AddRow(row)
+			}
+		}
+	} else {
+		statuses, err := c.driver.GetServiceHealth(svc.ID)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		t = NewTable("Name,Health Check,Kind,Status,Flapping,Output")
+		for _, key := range sortedHealthStatusKeys(statuses) {
+			stat := statuses[key.InstanceID][key.HealthCheckName]
+			row := map[string]interface{}{
+				"Name":         healthCheckInstanceName(svc, key.InstanceID),
+				"Health Check": key.HealthCheckName,
+				"Kind":         stat.Kind,
+				"Status":       stat.Status.Name(),
+				"Output":       healthCheckOutput(stat.Output),
+			}
+			if stat.Flapping {
+				row["Flapping"] = "Y"
+			}
+			t.AddRow(row)
+		}
+	}
+	t.Padding = 3
+	t.Print()
+}
+
+// sortHealthCheckKeys orders health checks by instance and name
+func sortHealthCheckKeys(keys []health.HealthStatusKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].InstanceID != keys[j].InstanceID {
+			return keys[i].InstanceID < keys[j].InstanceID
+		}
+		return keys[i].HealthCheckName < keys[j].HealthCheckName
+	})
+}
+
+// healthCheckInstanceName returns the name of an instance of a service
+func healthCheckInstanceName(svc *service.ServiceDetails, instanceID int) string {
+	if svc.Instances > 1 {
+		return fmt.Sprintf("%s/%d", svc.Name, instanceID)
+	}
+	return svc.Name
+}
+
 // serviced service list [--verbose, -v] [SERVICEID]
 func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 	if len(ctx.Args()) > 0 {
@@ -1395,6 +1732,9 @@ func (c *ServicedCli) cmdServiceConfigList(ctx *cli.Context) {
 		return
 	}
 
+	if ctx.IsSet("instance") {
+		service.ApplyInstanceOverride(ctx.Int("instance"))
+	}
 	configs := service.ConfigFiles
 
 	if len(args) < 2 {
@@ -1491,6 +1831,196 @@ func (c *ServicedCli) cmdServiceConfigEdit(ctx *cli.Context) {
 	return
 }
 
+// serviced service config set SERVICEID CONFIGFILE [--instance N] [--from PATH]
+func (c *ServicedCli) cmdServiceConfigSet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set")
+		c.exit(1)
+		return
+	}
+
+	svcDetails, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	service, err := c.driver.GetService(svcDetails.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	filename := args[1]
+	configfile, found := service.ConfigFiles[filename]
+	if !found {
+		fmt.Printf("Config file %s not found.\n", filename)
+		c.exit(1)
+		return
+	}
+
+	var contents []byte
+	if from := ctx.String("from"); from != "" {
+		contents, err = ioutil.ReadFile(from)
+	} else {
+		contents, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	configfile.Content = string(contents)
+
+	if ctx.IsSet("instance") {
+		setInstanceConfigFile(service, ctx.Int("instance"), filename, configfile)
+	} else {
+		service.ConfigFiles[filename] = configfile
+	}
+
+	if service, err := c.driver.UpdateServiceObj(*service); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else if service == nil {
+		fmt.Fprintln(os.Stderr, "received nil service")
+		c.exit(1)
+	} else {
+		fmt.Println(service.ID)
+	}
+}
+
+// serviced service config unset SERVICEID CONFIGFILE --instance N
+func (c *ServicedCli) cmdServiceConfigUnset(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 || !ctx.IsSet("instance") {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "unset")
+		c.exit(1)
+		return
+	}
+
+	svcDetails, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	service, err := c.driver.GetService(svcDetails.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	filename := args[1]
+	instanceID := ctx.Int("instance")
+	if !unsetInstanceConfigFile(service, instanceID, filename) {
+		fmt.Printf("Config file %s not overridden for instance %d.\n", filename, instanceID)
+		c.exit(1)
+		return
+	}
+
+	if service, err := c.driver.UpdateServiceObj(*service); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else if service == nil {
+		fmt.Fprintln(os.Stderr, "received nil service")
+		c.exit(1)
+	} else {
+		fmt.Println(service.ID)
+	}
+}
+
+// updateInstanceOverride changes the override for an instance of a service,
+// and removes the override if nothing is left in it
+func updateInstanceOverride(svc *service.Service, instanceID int, update func(*service.InstanceOverride)) {
+	override := svc.InstanceOverrides[instanceID]
+	update(&override)
+	if override.IsEmpty() {
+		delete(svc.InstanceOverrides, instanceID)
+		return
+	}
+	if svc.InstanceOverrides == nil {
+		svc.InstanceOverrides = make(map[int]service.InstanceOverride)
+	}
+	svc.InstanceOverrides[instanceID] = override
+}
+
+func setInstanceVariable(svc *service.Service, instanceID int, key, value string) {
+	updateInstanceOverride(svc, instanceID, func(override *service.InstanceOverride) {
+		if override.Context == nil {
+			override.Context = make(map[string]interface{})
+		}
+		override.Context[key] = value
+	})
+}
+
+func unsetInstanceVariable(svc *service.Service, instanceID int, key string) (found bool) {
+	updateInstanceOverride(svc, instanceID, func(override *service.InstanceOverride) {
+		if _, found = override.Context[key]; found {
+			delete(override.Context, key)
+		}
+	})
+	return
+}
+
+func setInstanceConfigFile(svc *service.Service, instanceID int, filename string, configfile servicedefinition.ConfigFile) {
+	updateInstanceOverride(svc, instanceID, func(override *service.InstanceOverride) {
+		if override.ConfigFiles == nil {
+			override.ConfigFiles = make(map[string]servicedefinition.ConfigFile)
+		}
+		override.ConfigFiles[filename] = configfile
+	})
+}
+
+func unsetInstanceConfigFile(svc *service.Service, instanceID int, filename string) (found bool) {
+	updateInstanceOverride(svc, instanceID, func(override *service.InstanceOverride) {
+		if _, found = override.ConfigFiles[filename]; found {
+			delete(override.ConfigFiles, filename)
+		}
+	})
+	return
+}
+
+func setInstanceMemoryLimit(svc *service.Service, instanceID int, limit float64) {
+	updateInstanceOverride(svc, instanceID, func(override *service.InstanceOverride) {
+		override.MemoryLimit = limit
+	})
+}
+
+func setInstanceEnvironment(svc *service.Service, instanceID int, variable string) {
+	name := strings.SplitN(variable, "=", 2)[0]
+	updateInstanceOverride(svc, instanceID, func(override *service.InstanceOverride) {
+		env := []string{}
+		for _, v := range override.Environment {
+			if strings.SplitN(v, "=", 2)[0] != name {
+				env = append(env, v)
+			}
+		}
+		override.Environment = append(env, variable)
+	})
+}
+
+func unsetInstanceEnvironment(svc *service.Service, instanceID int, name string) (found bool) {
+	updateInstanceOverride(svc, instanceID, func(override *service.InstanceOverride) {
+		var env []string
+		for _, v := range override.Environment {
+			if strings.SplitN(v, "=", 2)[0] == name {
+				found = true
+			} else {
+				env = append(env, v)
+			}
+		}
+		override.Environment = env
+	})
+	return
+}
+
 // serviced service variables list SERVICEID
 func (c *ServicedCli) cmdServiceVariableList(ctx *cli.Context) {
 	args := ctx.Args()
@@ -1597,10 +2127,14 @@ func (c *ServicedCli) cmdServiceVariableSet(ctx *cli.Context) {
 
 	key := args[1]
 	value := args[2]
-	if service.Context == nil {
-		service.Context = make(map[string]interface{})
+	if ctx.IsSet("instance") {
+		setInstanceVariable(service, ctx.Int("instance"), key, value)
+	} else {
+		if service.Context == nil {
+			service.Context = make(map[string]interface{})
+		}
+		service.Context[key] = value
 	}
-	service.Context[key] = value
 	if service, err := c.driver.UpdateServiceObj(*service); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	} else if service == nil {
@@ -1632,13 +2166,16 @@ func (c *ServicedCli) cmdServiceVariableUnset(ctx *cli.Context) {
 	}
 
 	key := args[1]
-	if service.Context == nil {
+	if ctx.IsSet("instance") {
+		if !unsetInstanceVariable(service, ctx.Int("instance"), key) {
+			fmt.Fprintf(os.Stderr, "Variable %s not found for instance %d.\n", key, ctx.Int("instance"))
+			return
+		}
+	} else if service.Context == nil {
 		message := fmt.Sprintf("Variable %v not found.", key)
 		fmt.Fprintln(os.Stderr, message)
 		return
-	}
-
-	if _, ok := service.Context[key]; ok {
+	} else if _, ok := service.Context[key]; ok {
 		delete(service.Context, key)
 	} else {
 		message := fmt.Sprintf("Variable %s not found.", key)
@@ -2493,8 +3030,20 @@ func (c *ServicedCli) cmdServiceTune(ctx *cli.Context) {
 		return
 	}
 
+	// The memory limit and environment are only tuned for one instance
+	if ctx.IsSet("instance") {
+		c.tuneInstance(ctx, service)
+		return
+	} else if ctx.IsSet("memoryLimit") || ctx.IsSet("env") || ctx.IsSet("unsetEnv") {
+		fmt.Fprintln(os.Stderr, "--memoryLimit, --env and --unsetEnv require --instance")
+		c.exit(1)
+		return
+	}
+
 	// Check the arguments
-	if !(ctx.IsSet("instances") || ctx.IsSet("ramCommitment") || ctx.IsSet("ramThreshold") || ctx.IsSet("launchMode")) {
+	if !(ctx.IsSet("instances") || ctx.IsSet("ramCommitment") || ctx.IsSet("ramThreshold") || ctx.IsSet("launchMode") ||
+		ctx.IsSet("priority") || ctx.IsSet("preemptible") || ctx.IsSet("cpuQuota") || ctx.IsSet("cpuSet") ||
+		ctx.IsSet("blkioWeight") || ctx.IsSet("deviceReadBps") || ctx.IsSet("deviceWriteBps")) {
 		fmt.Printf("Incorrect Usage.\n\n")
 		cli.ShowCommandHelp(ctx, "tune")
 		return
@@ -2564,6 +3113,55 @@ func (c *ServicedCli) cmdServiceTune(ctx *cli.Context) {
 		}
 	}
 
+	if ctx.IsSet("priority") {
+		if newPriority := ctx.Int("priority"); service.Priority != newPriority {
+			service.Priority = newPriority
+			modified = true
+		}
+	}
+
+	if ctx.IsSet("preemptible") {
+		if nonPreemptible := !ctx.BoolT("preemptible"); service.NonPreemptible != nonPreemptible {
+			service.NonPreemptible = nonPreemptible
+			modified = true
+		}
+	}
+
+	limits := service.ResourceLimits
+	if ctx.IsSet("cpuQuota") {
+		limits.CPUQuota = ctx.Float64("cpuQuota")
+	}
+	if ctx.IsSet("cpuSet") {
+		limits.CPUSet = ctx.String("cpuSet")
+	}
+	if ctx.IsSet("blkioWeight") {
+		weight := ctx.Int("blkioWeight")
+		if weight != 0 && (weight < 10 || weight > 1000) {
+			fmt.Fprintf(os.Stderr, "blkioWeight %d must be between 10 and 1000, or 0 for the default\n", weight)
+			c.exit(1)
+			return
+		}
+		limits.BlkioWeight = uint16(weight)
+	}
+	if ctx.IsSet("deviceReadBps") {
+		if limits.BlkioDeviceReadBps, err = parseDeviceLimits(ctx.StringSlice("deviceReadBps")); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+	}
+	if ctx.IsSet("deviceWriteBps") {
+		if limits.BlkioDeviceWriteBps, err = parseDeviceLimits(ctx.StringSlice("deviceWriteBps")); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+	}
+	if !reflect.DeepEqual(limits, service.ResourceLimits) {
+		service.ResourceLimits = limits
+		modified = true
+	}
+
 	if modified {
 		if service, err := c.driver.UpdateServiceObj(*service); err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -2580,3 +3178,72 @@ func (c *ServicedCli) cmdServiceTune(ctx *cli.Context) {
 	}
 
 }
+
+// tuneInstance changes the memory limit and environment variables overridden
+// for one instance of a service
+func (c *ServicedCli) tuneInstance(ctx *cli.Context, svc *service.Service) {
+	if !(ctx.IsSet("memoryLimit") || ctx.IsSet("env") || ctx.IsSet("unsetEnv")) {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "tune")
+		return
+	}
+
+	instanceID := ctx.Int("instance")
+	if ctx.IsSet("memoryLimit") {
+		limit, err := utils.NewEngNotationFromString(ctx.String("memoryLimit"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		setInstanceMemoryLimit(svc, instanceID, float64(limit.Value))
+	}
+	for _, variable := range ctx.StringSlice("env") {
+		if strings.TrimSpace(variable) == "" {
+			continue
+		}
+		if strings.Index(variable, "=") < 1 {
+			fmt.Fprintf(os.Stderr, "environment variable %q is not of the form NAME=VALUE\n", variable)
+			c.exit(1)
+			return
+		}
+		setInstanceEnvironment(svc, instanceID, variable)
+	}
+	for _, name := range ctx.StringSlice("unsetEnv") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		if !unsetInstanceEnvironment(svc, instanceID, name) {
+			fmt.Fprintf(os.Stderr, "Environment variable %s not overridden for instance %d.\n", name, instanceID)
+			c.exit(1)
+			return
+		}
+	}
+
+	if svc, err := c.driver.UpdateServiceObj(*svc); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else if svc == nil {
+		fmt.Fprintln(os.Stderr, "received nil service")
+		c.exit(1)
+	} else {
+		fmt.Println(svc.ID)
+	}
+}
+
+// parseDeviceLimits parses device limits of the form PATH:RATE, skipping
+// empty values so that a limit can be cleared
+func parseDeviceLimits(values []string) ([]servicedefinition.DeviceLimit, error) {
+	var limits []servicedefinition.DeviceLimit
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		limit, err := servicedefinition.ParseDeviceLimit(value)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
@@ -20,9 +20,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	//	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/dao"
@@ -32,6 +35,7 @@ import (
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -231,6 +235,42 @@ func (t ServiceAPITest) GetEndpoints(serviceID string, reportImports, reportExpo
 	return []applicationendpoint.EndpointReport{}, nil
 }
 
+func (t ServiceAPITest) GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	if t.errs["GetServiceHealth"] != nil {
+		return nil, t.errs["GetServiceHealth"]
+	}
+	return map[int]map[string]health.HealthStatus{
+		0: {
+			"running": {Status: health.OK, Kind: health.LivenessCheck},
+			"answering": {
+				Status:   health.Failed,
+				Kind:     health.ReadinessCheck,
+				Output:   "connection\nrefused",
+				Flapping: true,
+			},
+		},
+	}, nil
+}
+
+func (t ServiceAPITest) GetServiceHealthHistory(serviceID string) (map[int]map[string]health.HealthHistory, error) {
+	if t.errs["GetServiceHealthHistory"] != nil {
+		return nil, t.errs["GetServiceHealthHistory"]
+	}
+	started := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+	return map[int]map[string]health.HealthHistory{
+		0: {
+			"answering": {
+				Entries: []health.HealthHistoryEntry{
+					{Status: health.Failed, StartedAt: started, Duration: 2 * time.Second, Output: "connection refused"},
+					{Status: health.OK, StartedAt: started.Add(time.Minute), Duration: time.Second},
+				},
+				Changes:  1,
+				Flapping: false,
+			},
+		},
+	}, nil
+}
+
 func (t ServiceAPITest) GetService(id string) (*service.Service, error) {
 	if t.errs["GetService"] != nil {
 		return nil, t.errs["GetService"]
@@ -744,9 +784,10 @@ func ExampleServicedCLI_CmdServiceConfigList_usage() {
 	//    command list [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service config list SERVICEID [FILENAME]
+	//    serviced service config list SERVICEID [FILENAME] [--instance N]
 	//
 	// OPTIONS:
+	//    --instance '0'		Instance of the service to show the config files of, including its overrides
 	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
 }
 
@@ -825,6 +866,55 @@ func ExampleServicedCLI_CmdServiceConfigEdit() {
 	InitServiceAPITest("serviced", "service", "config", "edit", "test-service-1", "/etc/test.conf")
 }
 
+// instanceOverride returns the override of a test service for one instance
+func instanceOverride(serviceID string, instanceID int) service.InstanceOverride {
+	svc, _ := DefaultServiceAPITest.GetService(serviceID)
+	return svc.InstanceOverrides[instanceID]
+}
+
+// clearInstanceOverrides removes the overrides of a test service
+func clearInstanceOverrides(serviceID string) {
+	svc, _ := DefaultServiceAPITest.GetService(serviceID)
+	svc.InstanceOverrides = nil
+}
+
+func ExampleServicedCLI_CmdServiceConfigSet_instance() {
+	defer clearInstanceOverrides("test-service-1")
+	from, _ := ioutil.TempFile("", "config")
+	defer os.Remove(from.Name())
+	from.WriteString("instance 2 config")
+	from.Close()
+
+	InitServiceAPITest("serviced", "service", "config", "set", "test-service-1", "/etc/test.conf", "--instance=2", "--from="+from.Name())
+	svc, _ := DefaultServiceAPITest.GetService("test-service-1")
+	fmt.Println(svc.ConfigFiles["/etc/test.conf"].Content == "instance 2 config")
+	fmt.Println(instanceOverride("test-service-1", 2).ConfigFiles["/etc/test.conf"].Content)
+
+	// Output:
+	// test-service-1
+	// false
+	// instance 2 config
+}
+
+func ExampleServicedCLI_CmdServiceConfigUnset_instance() {
+	defer clearInstanceOverrides("test-service-1")
+	svc, _ := DefaultServiceAPITest.GetService("test-service-1")
+	svc.InstanceOverrides = map[int]service.InstanceOverride{
+		2: {ConfigFiles: map[string]servicedefinition.ConfigFile{"/etc/test.conf": {Content: "instance 2 config"}}},
+	}
+
+	InitServiceAPITest("serviced", "service", "config", "unset", "test-service-1", "/etc/test.conf", "--instance=2")
+	fmt.Println(len(svc.InstanceOverrides))
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "config", "unset", "test-service-1", "/etc/test.conf", "--instance=2")
+	})
+
+	// Output:
+	// test-service-1
+	// 0
+	// Config file /etc/test.conf not overridden for instance 2.
+}
+
 func ExampleServicedCLI_CmdServiceAssignIPs() {
 	// Auto-assign
 	InitServiceAPITest("serviced", "service", "assign-ip", "test-service-1")
@@ -1468,6 +1558,31 @@ func ExampleServicedCLI_CmdServiceEndpoints_works() {
 	// Zope    test-service-2    endpointName2    import     hostID2    hostIP2    20          containerID2    containerIP2    200
 }
 
+func ExampleServicedCLI_CmdServiceHealth_err() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "health", "test-service-0") })
+
+	// Output:
+	// service not found
+}
+
+func ExampleServicedCLI_CmdServiceHealth_works() {
+	InitServiceAPITest("serviced", "service", "health", "test-service-2")
+
+	// Output:
+	// Name   Health Check   Kind        Status   Flapping   Output
+	// Zope   answering      readiness   failed   Y          connection refused
+	// Zope   running        liveness    passed
+}
+
+func ExampleServicedCLI_CmdServiceHealth_history() {
+	InitServiceAPITest("serviced", "service", "health", "--history", "test-service-2")
+
+	// Output:
+	// Name   Health Check   Started               Duration   Status   Flapping   Output
+	// Zope   answering      2026-10-01 12:00:00   2s         failed              connection refused
+	// Zope   answering      2026-10-01 12:01:00   1s         passed
+}
+
 func ExampleServicedCLI_CmdServiceClearEmergency_works() {
 	pipeStderr(func() { InitServiceAPITest("serviced", "service", "clear-emergency", "test-service-1") })
 
@@ -1516,20 +1631,31 @@ func ExampleServiceCLI_CmdServiceTune_usage() {
 	// Incorrect Usage.
 	//
 	// NAME:
-	//    tune - Adjust launch mode, instance count, RAM commitment, or RAM threshold for a service
+	//    tune - Adjust launch mode, instance count, RAM commitment, RAM threshold, priority, or resource limits for a service, or the memory limit and environment of one instance
 	//
 	// USAGE:
 	//    command tune [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service tune SERVICEID
+	//    serviced service tune SERVICEID [--instance N --memoryLimit SIZE --env NAME=VALUE --unsetEnv NAME]
 	//
 	// OPTIONS:
-	//    --launchMode 		Launch mode for this service (auto, manual)
-	//    --instances '0'		Instance count for this service
-	//    --ramCommitment 		RAM Commitment for this service
-	//    --ramThreshold 		RAM Threshold for this service
-	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
+	//    --launchMode 							Launch mode for this service (auto, manual)
+	//    --instances '0'							Instance count for this service
+	//    --ramCommitment 							RAM Commitment for this service
+	//    --ramThreshold 							RAM Threshold for this service
+	//    --priority '0'							Scheduling priority for this service; higher priorities may preempt lower ones
+	//    --preemptible							Whether instances of this service may be preempted
+	//    --cpuQuota '0'							Cores of CPU time each instance may use; 0 is unlimited
+	//    --cpuSet 								CPUs each instance may run on, e.g. 0-3,6
+	//    --blkioWeight '0'							Relative block I/O weight of each instance (10 to 1000)
+	//    --deviceReadBps '--deviceReadBps option --deviceReadBps option'	Read rate limit of each instance on a device, as PATH:RATE
+	//    --deviceWriteBps '--deviceWriteBps option --deviceWriteBps option'	Write rate limit of each instance on a device, as PATH:RATE
+	//    --instance '0'							Instance of the service to override the memory limit or environment for
+	//    --memoryLimit 							Memory limit of the instance; 0 removes the override
+	//    --env '--env option --env option'					Environment variable of the instance, as NAME=VALUE
+	//    --unsetEnv '--unsetEnv option --unsetEnv option'			Environment variable to remove from the overrides of the instance
+	//    --no-prefix-match, --np						Make SERVICEID matches on name strict 'ends with' matches
 }
 
 func ExampleServiceCLI_CmdServiceTune_noservice() {
@@ -1544,20 +1670,31 @@ func ExampleServiceCLI_CmdServiceTune_nokwargs() {
 	// Incorrect Usage.
 	//
 	// NAME:
-	//    tune - Adjust launch mode, instance count, RAM commitment, or RAM threshold for a service
+	//    tune - Adjust launch mode, instance count, RAM commitment, RAM threshold, priority, or resource limits for a service, or the memory limit and environment of one instance
 	//
 	// USAGE:
 	//    command tune [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service tune SERVICEID
+	//    serviced service tune SERVICEID [--instance N --memoryLimit SIZE --env NAME=VALUE --unsetEnv NAME]
 	//
 	// OPTIONS:
-	//    --launchMode 		Launch mode for this service (auto, manual)
-	//    --instances '0'		Instance count for this service
-	//    --ramCommitment 		RAM Commitment for this service
-	//    --ramThreshold 		RAM Threshold for this service
-	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
+	//    --launchMode 							Launch mode for this service (auto, manual)
+	//    --instances '0'							Instance count for this service
+	//    --ramCommitment 							RAM Commitment for this service
+	//    --ramThreshold 							RAM Threshold for this service
+	//    --priority '0'							Scheduling priority for this service; higher priorities may preempt lower ones
+	//    --preemptible							Whether instances of this service may be preempted
+	//    --cpuQuota '0'							Cores of CPU time each instance may use; 0 is unlimited
+	//    --cpuSet 								CPUs each instance may run on, e.g. 0-3,6
+	//    --blkioWeight '0'							Relative block I/O weight of each instance (10 to 1000)
+	//    --deviceReadBps '--deviceReadBps option --deviceReadBps option'	Read rate limit of each instance on a device, as PATH:RATE
+	//    --deviceWriteBps '--deviceWriteBps option --deviceWriteBps option'	Write rate limit of each instance on a device, as PATH:RATE
+	//    --instance '0'							Instance of the service to override the memory limit or environment for
+	//    --memoryLimit 							Memory limit of the instance; 0 removes the override
+	//    --env '--env option --env option'					Environment variable of the instance, as NAME=VALUE
+	//    --unsetEnv '--unsetEnv option --unsetEnv option'			Environment variable to remove from the overrides of the instance
+	//    --no-prefix-match, --np						Make SERVICEID matches on name strict 'ends with' matches
 }
 
 func ExampleServiceCLI_CmdServiceTune_nochanges() {
@@ -1572,26 +1709,70 @@ func ExampleServiceCLI_CmdServiceTune_toomany() {
 	// test-service-2
 }
 
+func ExampleServiceCLI_CmdServiceTune_priority() {
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--priority=5", "--preemptible=false")
+	})
+	// Output:
+	// test-service-2
+}
+
+func ExampleServiceCLI_CmdServiceTune_limits() {
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--cpuQuota=1.5", "--cpuSet=0-1",
+			"--deviceReadBps=/dev/sda:10M")
+	})
+	// Output:
+	// test-service-2
+}
+
+func ExampleServiceCLI_CmdServiceTune_invalidblkioweight() {
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--blkioWeight=5")
+	})
+	// Output:
+	// blkioWeight 5 must be between 10 and 1000, or 0 for the default
+}
+
+func ExampleServiceCLI_CmdServiceTune_invaliddevicelimit() {
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--deviceWriteBps=/dev/sda")
+	})
+	// Output:
+	// device limit "/dev/sda" is not of the form PATH:RATE
+}
+
 func ExampleServiceCLI_CmdServiceTune_invalidlaunchmode() {
 	pipeStderr(func() { InitServiceAPITest("serviced", "service", "tune", "test-service-1", "--launchMode=incorrect") })
 	// Output:
 	// Incorrect Usage.
 	//
 	// NAME:
-	//    tune - Adjust launch mode, instance count, RAM commitment, or RAM threshold for a service
+	//    tune - Adjust launch mode, instance count, RAM commitment, RAM threshold, priority, or resource limits for a service, or the memory limit and environment of one instance
 	//
 	// USAGE:
 	//    command tune [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service tune SERVICEID
+	//    serviced service tune SERVICEID [--instance N --memoryLimit SIZE --env NAME=VALUE --unsetEnv NAME]
 	//
 	// OPTIONS:
-	//    --launchMode 		Launch mode for this service (auto, manual)
-	//    --instances '0'		Instance count for this service
-	//    --ramCommitment 		RAM Commitment for this service
-	//    --ramThreshold 		RAM Threshold for this service
-	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
+	//    --launchMode 							Launch mode for this service (auto, manual)
+	//    --instances '0'							Instance count for this service
+	//    --ramCommitment 							RAM Commitment for this service
+	//    --ramThreshold 							RAM Threshold for this service
+	//    --priority '0'							Scheduling priority for this service; higher priorities may preempt lower ones
+	//    --preemptible							Whether instances of this service may be preempted
+	//    --cpuQuota '0'							Cores of CPU time each instance may use; 0 is unlimited
+	//    --cpuSet 								CPUs each instance may run on, e.g. 0-3,6
+	//    --blkioWeight '0'							Relative block I/O weight of each instance (10 to 1000)
+	//    --deviceReadBps '--deviceReadBps option --deviceReadBps option'	Read rate limit of each instance on a device, as PATH:RATE
+	//    --deviceWriteBps '--deviceWriteBps option --deviceWriteBps option'	Write rate limit of each instance on a device, as PATH:RATE
+	//    --instance '0'							Instance of the service to override the memory limit or environment for
+	//    --memoryLimit 							Memory limit of the instance; 0 removes the override
+	//    --env '--env option --env option'					Environment variable of the instance, as NAME=VALUE
+	//    --unsetEnv '--unsetEnv option --unsetEnv option'			Environment variable to remove from the overrides of the instance
+	//    --no-prefix-match, --np						Make SERVICEID matches on name strict 'ends with' matches
 }
 
 func ExampleServiceCLI_CmdServiceTune_launch() {
@@ -1607,6 +1788,43 @@ func ExampleServiceCLI_CmdServiceTune_threshold() {
 	InitServiceAPITest("serviced", "service", "tune", "test-service-1", "--ramThreshold='80%'")
 }
 
+func ExampleServiceCLI_CmdServiceTune_instance() {
+	defer clearInstanceOverrides("test-service-2")
+	InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--instance=1", "--memoryLimit=512M",
+		"--env=ZENHOME=/opt/zenoss", "--env=LOG_LEVEL=debug")
+	InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--instance=1", "--env=LOG_LEVEL=info",
+		"--unsetEnv=ZENHOME")
+	override := instanceOverride("test-service-2", 1)
+	fmt.Println(override.MemoryLimit, override.Environment)
+
+	// removing the last override removes the instance's overrides
+	InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--instance=1", "--memoryLimit=0",
+		"--unsetEnv=LOG_LEVEL")
+	svc, _ := DefaultServiceAPITest.GetService("test-service-2")
+	fmt.Println(len(svc.InstanceOverrides))
+
+	// Output:
+	// test-service-2
+	// test-service-2
+	// 5.36870912e+08 [LOG_LEVEL=info]
+	// test-service-2
+	// 0
+}
+
+func ExampleServiceCLI_CmdServiceTune_instanceerrors() {
+	defer clearInstanceOverrides("test-service-2")
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--memoryLimit=512M")
+		InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--instance=1", "--env=ZENHOME")
+		InitServiceAPITest("serviced", "service", "tune", "test-service-2", "--instance=1", "--unsetEnv=ZENHOME")
+	})
+
+	// Output:
+	// --memoryLimit, --env and --unsetEnv require --instance
+	// environment variable "ZENHOME" is not of the form NAME=VALUE
+	// Environment variable ZENHOME not overridden for instance 1.
+}
+
 func ExampleServiceCLI_CmdServiceVariableList_usage() {
 	pipeStderr(func() { InitServiceAPITest("serviced", "service", "variable", "list") })
 	// Output:
@@ -1688,9 +1906,10 @@ func ExampleServiceCLI_CmdServiceVariableSet_usage() {
 	//    command set [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service variable set SERVICEID VARIABLE VALUE
+	//    serviced service variable set SERVICEID VARIABLE VALUE [--instance N]
 	//
 	// OPTIONS:
+	//    --instance '0'		Instance of the service to set the variable for
 	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
 }
 
@@ -1714,9 +1933,10 @@ func ExampleServiceCLI_CmdServiceVariableSet_novariable() {
 	//    command set [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service variable set SERVICEID VARIABLE VALUE
+	//    serviced service variable set SERVICEID VARIABLE VALUE [--instance N]
 	//
 	// OPTIONS:
+	//    --instance '0'		Instance of the service to set the variable for
 	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
 }
 
@@ -1732,9 +1952,10 @@ func ExampleServiceCLI_CmdServiceVariableSet_novalue() {
 	//    command set [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service variable set SERVICEID VARIABLE VALUE
+	//    serviced service variable set SERVICEID VARIABLE VALUE [--instance N]
 	//
 	// OPTIONS:
+	//    --instance '0'		Instance of the service to set the variable for
 	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
 }
 
@@ -1744,6 +1965,17 @@ func ExampleServiceCLI_CmdServiceVariableSet() {
 	// test-service-2
 }
 
+func ExampleServiceCLI_CmdServiceVariableSet_instance() {
+	defer clearInstanceOverrides("test-service-2")
+	InitServiceAPITest("serviced", "service", "variable", "set", "test-service-2", "home.name", "Charlies", "--instance=1")
+	svc, _ := DefaultServiceAPITest.GetService("test-service-2")
+	fmt.Println(svc.Context["home.name"], instanceOverride("test-service-2", 1).Context["home.name"])
+
+	// Output:
+	// test-service-2
+	// Alphas Charlies
+}
+
 func ExampleServiceCLI_CmdServiceVariableUnset_usage() {
 	InitServiceAPITest("serviced", "service", "variable", "unset")
 	// Output:
@@ -1756,9 +1988,10 @@ func ExampleServiceCLI_CmdServiceVariableUnset_usage() {
 	//    command unset [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service variable unset SERVICEID VARIABLE
+	//    serviced service variable unset SERVICEID VARIABLE [--instance N]
 	//
 	// OPTIONS:
+	//    --instance '0'		Instance of the service to unset the variable for
 	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
 }
 
@@ -1780,9 +2013,10 @@ func ExampleServiceCLI_CmdServiceVariableUnset_novariable() {
 	//    command unset [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced service variable unset SERVICEID VARIABLE
+	//    serviced service variable unset SERVICEID VARIABLE [--instance N]
 	//
 	// OPTIONS:
+	//    --instance '0'		Instance of the service to unset the variable for
 	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
 }
 
@@ -1792,6 +2026,25 @@ func ExampleServiceCLI_CmdServiceVariableUnset_badvariable() {
 	// Variable mallets not found.
 }
 
+func ExampleServiceCLI_CmdServiceVariableUnset_instance() {
+	defer clearInstanceOverrides("test-service-2")
+	svc, _ := DefaultServiceAPITest.GetService("test-service-2")
+	svc.InstanceOverrides = map[int]service.InstanceOverride{
+		1: {Context: map[string]interface{}{"home.name": "Charlies", "away.name": "Deltas"}},
+	}
+
+	InitServiceAPITest("serviced", "service", "variable", "unset", "test-service-2", "home.name", "--instance=1")
+	fmt.Println(svc.Context["home.name"], instanceOverride("test-service-2", 1).Context)
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "variable", "unset", "test-service-2", "home.name", "--instance=1")
+	})
+
+	// Output:
+	// test-service-2
+	// Alphas map[away.name:Deltas]
+	// Variable home.name not found for instance 1.
+}
+
 func ExampleServiceCLI_CmdServiceVariableUnset() {
 	InitServiceAPITest("serviced", "service", "variable", "unset", "test-service-2", "home.name")
 	// Output:
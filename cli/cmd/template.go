@@ -86,6 +86,17 @@ func (c *ServicedCli) initTemplate() {
 						Usage: "Map a given image name to another (e.g. -map zenoss/zenoss5x:latest,quay.io/zenoss-core:alpha2)",
 					},
 				},
+			}, {
+				Name:        "lint",
+				Usage:       "Report every issue in a template or a directory of service definitions",
+				Description: "serviced template lint PATH",
+				Action:      c.cmdTemplateLint,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
 			},
 		},
 	})
@@ -339,3 +350,50 @@ func (c *ServicedCli) cmdTemplateCompile(ctx *cli.Context) {
 		}
 	}
 }
+
+// serviced template lint PATH
+func (c *ServicedCli) cmdTemplateLint(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "lint")
+		return
+	}
+
+	diagnostics, err := c.driver.LintServiceTemplate(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if diagnostics == nil {
+			diagnostics = template.Diagnostics{}
+		}
+		if jsonDiagnostics, err := json.MarshalIndent(diagnostics, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal diagnostics: %s\n", err)
+			c.exit(1)
+			return
+		} else {
+			fmt.Println(string(jsonDiagnostics))
+		}
+	} else if len(diagnostics) == 0 {
+		fmt.Fprintln(os.Stderr, "no issues found")
+	} else {
+		t := NewTable("Severity,Path,Message")
+		for _, diag := range diagnostics {
+			t.AddRow(map[string]interface{}{
+				"Severity": diag.Severity,
+				"Path":     diag.Path,
+				"Message":  diag.Message,
+			})
+		}
+		t.Padding = 6
+		t.Print()
+	}
+
+	if diagnostics.HasErrors() {
+		c.exit(1)
+	}
+}
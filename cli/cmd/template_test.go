@@ -120,6 +120,18 @@ func (t TemplateAPITest) CompileServiceTemplate(cfg api.CompileTemplateConfig) (
 	return &tpl, nil
 }
 
+func (t TemplateAPITest) LintServiceTemplate(path string) (template.Diagnostics, error) {
+	if t.fail {
+		return nil, ErrInvalidTemplate
+	} else if path == NilTemplate {
+		return nil, nil
+	}
+	return template.Diagnostics{
+		{Path: "Services[0].Endpoints[0].PortNumber", Severity: template.SeverityError, Message: "not in valid port range: 0"},
+		{Path: `Services[0].LogFilters["unused"]`, Severity: template.SeverityWarning, Message: "log filter is not used by any log config"},
+	}, nil
+}
+
 func (t TemplateAPITest) DeployServiceTemplate(cfg api.DeployTemplateConfig) ([]service.ServiceDetails, error) {
 	tpl, err := t.GetServiceTemplate(cfg.ID)
 	if err != nil {
@@ -355,3 +367,28 @@ func ExampleServicedCLI_CmdTemplateCompile_err() {
 	// Output:
 	// received nil template
 }
+
+func ExampleServicedCLI_CmdTemplateLint() {
+	InitTemplateAPITest("serviced", "template", "lint", "/path/to/template")
+
+	// Output:
+	// Severity      Path                                     Message
+	// error         Services[0].Endpoints[0].PortNumber      not in valid port range: 0
+	// warning       Services[0].LogFilters["unused"]         log filter is not used by any log config
+}
+
+func ExampleServicedCLI_CmdTemplateLint_clean() {
+	pipeStderr(func() { InitTemplateAPITest("serviced", "template", "lint", NilTemplate) })
+
+	// Output:
+	// no issues found
+}
+
+func ExampleServicedCLI_CmdTemplateLint_fail() {
+	DefaultTemplateAPITest.fail = true
+	defer func() { DefaultTemplateAPITest.fail = false }()
+	pipeStderr(func() { InitTemplateAPITest("serviced", "template", "lint", "/path/to/template") })
+
+	// Output:
+	// invalid template
+}
@@ -52,6 +52,11 @@ type Options struct {
 	Agent                      bool
 	MuxPort                    int
 	MuxDisableTLS              string //  Disable TLS for MUX connections, string val of bool
+	MuxCertVerify              string // Require mux connections to present certificates issued by the internal CA, string val of bool
+	MuxDialTimeout             int    // Seconds to wait for a proxied connection through the mux
+	MuxIdleTimeout             int    // Seconds before an idle proxied connection is closed; 0 never
+	MuxBreakerFailures         int    // Consecutive failed connections to a host that trip its circuit breaker; 0 never trips
+	MuxBreakerReset            int    // Seconds a tripped circuit breaker fails connections fast before it probes the host
 	KeyPEMFile                 string
 	CertPEMFile                string
 	HomePath                   string // serviced's root directory; e.g. /opt/serviced
@@ -144,6 +149,11 @@ type Options struct {
 	Auth0Scope                 string            // Auth0 Scope for request.
 	KeyProxyJsonServer         string            // Address of api-key-server endpoint for getting CC Access tokens
 	KeyProxyListenPort         string            // Port where api-key-proxy will listen
+	ACMEDirectory              string            // Directory url of the acme server that issues public endpoint certificates
+	ACMEEmail                  string            // Contact email of the acme account
+	ACMEAccountKey             string            // Path to the key of the acme account
+	ACMERenewDays              int               // Days before expiry to renew acme certificates
+	PublicEndpointAccessLog    string            // Whether to send access logs of the vhost and port public endpoints to logstash
 }
 
 // GetOptions returns a COPY of the global options struct
@@ -0,0 +1,209 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// BackendStats are the connection counts for one backend of a proxy
+type BackendStats struct {
+	Address    string // container IP:port of the backend
+	HostIP     string
+	ServiceID  string
+	InstanceID int
+	Healthy    bool
+	Active     int64 // open connections
+	Total      int64 // connections made since the backend was added
+	Failures   int64 // failed dials
+}
+
+type backend struct {
+	address  addressTuple
+	healthy  bool
+	active   int64
+	total    int64
+	failures int64
+}
+
+func (be *backend) key() string {
+	return be.address.host + "/" + be.address.containerAddr
+}
+
+// balancer chooses the backend for each connection to a proxy.  Backends that
+// are failing their health checks are skipped unless no healthy backend is
+// left.
+type balancer struct {
+	mu       sync.Mutex
+	mode     string
+	backends []*backend
+	next     int
+}
+
+func newBalancer(mode string) *balancer {
+	return &balancer{mode: mode}
+}
+
+// SetMode changes the load balancing mode
+func (b *balancer) SetMode(mode string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mode = mode
+}
+
+// SetBackends replaces the backends, keeping the counts and health of the
+// backends that are still present.
+func (b *balancer) SetBackends(addresses []addressTuple) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	current := make(map[string]*backend)
+	for _, be := range b.backends {
+		current[be.key()] = be
+	}
+	backends := make([]*backend, len(addresses))
+	for i, address := range addresses {
+		be := &backend{address: address, healthy: true}
+		if old, ok := current[be.key()]; ok {
+			old.address = address
+			be = old
+		}
+		backends[i] = be
+	}
+	b.backends = backends
+}
+
+// SetHealth updates whether each backend is healthy
+func (b *balancer) SetHealth(isHealthy func(addressTuple) bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, be := range b.backends {
+		be.healthy = isHealthy(be.address)
+	}
+}
+
+// Len returns the number of backends
+func (b *balancer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.backends)
+}
+
+// Addresses returns the addresses of all the backends
+func (b *balancer) Addresses() []addressTuple {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	addresses := make([]addressTuple, len(b.backends))
+	for i, be := range b.backends {
+		addresses[i] = be.address
+	}
+	return addresses
+}
+
+// Pick chooses a backend for a connection from clientIP, skipping the
+// backends that have already been tried.  The connection is counted against
+// the backend until it is released.  Returns nil if there is nothing left to
+// try.
+func (b *balancer) Pick(clientIP string, tried map[*backend]bool) *backend {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var healthy, untried []*backend
+	for _, be := range b.backends {
+		if tried[be] {
+			continue
+		}
+		untried = append(untried, be)
+		if be.healthy {
+			healthy = append(healthy, be)
+		}
+	}
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = untried
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	var be *backend
+	switch b.mode {
+	case servicedefinition.LoadBalanceLeastConn:
+		for _, c := range candidates {
+			if be == nil || c.active < be.active {
+				be = c
+			}
+		}
+	case servicedefinition.LoadBalanceHash:
+		// rendezvous hashing, so a client only moves when its backend goes
+		// away
+		var max uint32
+		for _, c := range candidates {
+			if score := hashScore(clientIP, c.key()); be == nil || score > max {
+				be, max = c, score
+			}
+		}
+	default:
+		b.next++
+		be = candidates[b.next%len(candidates)]
+	}
+	be.active++
+	be.total++
+	return be
+}
+
+// Release ends a connection that was counted against the backend
+func (b *balancer) Release(be *backend) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	be.active--
+}
+
+// Fail records a failed dial to the backend and releases the connection
+func (b *balancer) Fail(be *backend) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	be.active--
+	be.failures++
+}
+
+// Stats returns the connection counts of each backend
+func (b *balancer) Stats() []BackendStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := make([]BackendStats, len(b.backends))
+	for i, be := range b.backends {
+		stats[i] = BackendStats{
+			Address:    be.address.containerAddr,
+			HostIP:     be.address.host,
+			ServiceID:  be.address.serviceID,
+			InstanceID: be.address.instanceID,
+			Healthy:    be.healthy,
+			Active:     be.active,
+			Total:      be.total,
+			Failures:   be.failures,
+		}
+	}
+	return stats
+}
+
+func hashScore(clientIP, key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(clientIP))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return h.Sum32()
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package container
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+func testAddresses(n int) []addressTuple {
+	addresses := make([]addressTuple, n)
+	for i := range addresses {
+		addresses[i] = addressTuple{
+			host:          "10.0.0.1",
+			containerAddr: fmt.Sprintf("172.17.0.%d:8080", i+1),
+			serviceID:     "svc",
+			instanceID:    i,
+		}
+	}
+	return addresses
+}
+
+func TestBalancer_RoundRobin(t *testing.T) {
+	b := newBalancer("")
+	b.SetBackends(testAddresses(3))
+
+	counts := make(map[int]int)
+	for i := 0; i < 6; i++ {
+		be := b.Pick("10.0.0.5", nil)
+		counts[be.address.instanceID]++
+		b.Release(be)
+	}
+	for i := 0; i < 3; i++ {
+		if counts[i] != 2 {
+			t.Errorf("Expected instance %d to get 2 connections, got %d", i, counts[i])
+		}
+	}
+}
+
+func TestBalancer_SkipsUnhealthy(t *testing.T) {
+	b := newBalancer(servicedefinition.LoadBalanceRoundRobin)
+	b.SetBackends(testAddresses(3))
+	b.SetHealth(func(address addressTuple) bool { return address.instanceID != 1 })
+
+	for i := 0; i < 6; i++ {
+		if be := b.Pick("10.0.0.5", nil); be.address.instanceID == 1 {
+			t.Fatalf("Picked unhealthy instance")
+		}
+	}
+
+	// with nothing healthy, fall back to every backend
+	b.SetHealth(func(addressTuple) bool { return false })
+	if be := b.Pick("10.0.0.5", nil); be == nil {
+		t.Fatalf("Expected a backend when none are healthy")
+	}
+}
+
+func TestBalancer_Retry(t *testing.T) {
+	b := newBalancer(servicedefinition.LoadBalanceRoundRobin)
+	b.SetBackends(testAddresses(2))
+
+	tried := make(map[*backend]bool)
+	first := b.Pick("10.0.0.5", tried)
+	tried[first] = true
+	b.Fail(first)
+	second := b.Pick("10.0.0.5", tried)
+	if second == nil || second == first {
+		t.Fatalf("Expected a different backend on retry")
+	}
+	tried[second] = true
+	b.Fail(second)
+	if be := b.Pick("10.0.0.5", tried); be != nil {
+		t.Fatalf("Expected no backend left to try, got %v", be.address)
+	}
+
+	for _, stat := range b.Stats() {
+		if stat.Active != 0 || stat.Total != 1 || stat.Failures != 1 {
+			t.Errorf("Unexpected stats %+v", stat)
+		}
+	}
+}
+
+func TestBalancer_LeastConn(t *testing.T) {
+	b := newBalancer(servicedefinition.LoadBalanceLeastConn)
+	b.SetBackends(testAddresses(3))
+
+	first := b.Pick("10.0.0.5", nil)
+	second := b.Pick("10.0.0.5", nil)
+	if third := b.Pick("10.0.0.5", nil); third == first || third == second {
+		t.Fatalf("Expected an idle backend, got a busy one")
+	}
+
+	// the released backend is the only idle one left
+	b.Release(second)
+	if be := b.Pick("10.0.0.5", nil); be != second {
+		t.Fatalf("Expected the released backend")
+	}
+}
+
+func TestBalancer_Hash(t *testing.T) {
+	b := newBalancer(servicedefinition.LoadBalanceHash)
+	addresses := testAddresses(4)
+	b.SetBackends(addresses)
+
+	first := b.Pick("10.0.0.5", nil)
+	for i := 0; i < 5; i++ {
+		if be := b.Pick("10.0.0.5", nil); be != first {
+			t.Fatalf("Expected the same client to get the same backend")
+		}
+	}
+
+	// removing another backend does not move the client
+	var remaining []addressTuple
+	for _, address := range addresses {
+		if address.instanceID == first.address.instanceID || len(remaining) < 2 {
+			remaining = append(remaining, address)
+		}
+	}
+	b.SetBackends(remaining)
+	if be := b.Pick("10.0.0.5", nil); be != first {
+		t.Fatalf("Expected the client to stay on its backend")
+	}
+}
+
+func TestBalancer_SetBackendsKeepsCounts(t *testing.T) {
+	b := newBalancer("")
+	addresses := testAddresses(1)
+	b.SetBackends(addresses)
+	b.Pick("10.0.0.5", nil)
+	b.SetBackends(append(addresses, testAddresses(2)[1]))
+
+	stats := b.Stats()
+	if len(stats) != 2 || stats[0].Active != 1 || stats[1].Active != 0 {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/node"
+	svcproxy "github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/zzk"
@@ -43,6 +44,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -98,6 +100,11 @@ type ControllerOptions struct {
 		DisableTLS  bool   // True if TLS is disabled
 		KeyPEMFile  string // Path to the key file when TLS is used
 		CertPEMFile string // Path to the cert file when TLS is used
+
+		DialTimeout     time.Duration // How long to wait for a connection to an import
+		IdleTimeout     time.Duration // Close a connection to an import when it is idle this long, 0 never
+		BreakerFailures int           // Consecutive failed connections that trip the breaker of a host, 0 never
+		BreakerReset    time.Duration // How long a tripped breaker fails fast before it probes the host
 	}
 	Logforwarder LogforwarderOptions
 	Metric       struct {
@@ -128,6 +135,10 @@ type Controller struct {
 	exitStatus         int
 	endpoints          *ContainerEndpoints
 	healthChecks       map[string]health.HealthCheck
+	readiness          *readinessTracker
+	livenessFailed     chan string
+	serviceMu          sync.Mutex
+	serviceStarted     time.Time
 	ccApiProxy         *servicedApiProxy
 }
 
@@ -375,6 +386,7 @@ func NewController(options ControllerOptions) (*Controller, error) {
 	}
 
 	//build metric redirect url -- assumes 8444 is port mapped
+	var statsDestination string
 	metricRedirect := options.Metric.RemoteEndoint
 	if len(metricRedirect) == 0 {
 		glog.V(1).Infof("container.Controller does not have metric forwarding")
@@ -406,7 +418,7 @@ func NewController(options ControllerOptions) (*Controller, error) {
 		// setup network stats
 		destination := fmt.Sprintf("http://localhost%s/api/metrics/store", options.Metric.Address)
 		glog.Infof("pushing network stats to: %s", destination)
-		go statReporter(destination, time.Second*15)
+		statsDestination = destination
 	}
 
 	// Keep a copy of the service prerequisites in the Controller object.
@@ -438,13 +450,36 @@ func NewController(options ControllerOptions) (*Controller, error) {
 		IsShell:              os.Getenv("SERVICED_IS_SERVICE_SHELL") == "true",
 		TCPMuxPort:           uint16(options.Mux.Port),
 		UseTLS:               !options.Mux.DisableTLS,
+		MuxDialer: svcproxy.DialerOptions{
+			DialTimeout:      options.Mux.DialTimeout,
+			IdleTimeout:      options.Mux.IdleTimeout,
+			FailureThreshold: options.Mux.BreakerFailures,
+			ResetTimeout:     options.Mux.BreakerReset,
+		},
 		VirtualAddressSubnet: options.VirtualAddressSubnet,
+		ServicedEndpoint:     options.ServicedEndpoint,
 	}
 	c.endpoints, err = NewContainerEndpoints(service, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	// hold the exports back until the readiness checks pass
+	var readinessChecks []string
+	if !opts.IsShell {
+		for name, hc := range c.healthChecks {
+			if hc.Kind == health.ReadinessCheck {
+				readinessChecks = append(readinessChecks, name)
+			}
+		}
+	}
+	c.readiness = newReadinessTracker(readinessChecks, c.endpoints.SetReady)
+	c.livenessFailed = make(chan string, 1)
+	if statsDestination != "" {
+		go statReporter(statsDestination, time.Second*15, c.endpoints)
+		go proxyStatReporter(statsDestination, time.Second*15, c.endpoints)
+	}
+
 	// CC Rest API proxy
 	c.ccApiProxy = newServicedApiProxy()
 
@@ -637,6 +672,7 @@ func (c *Controller) Run() (err error) {
 		service, serviceExited, _ := subprocess.New(
 			time.Second*10, env, c.options.Service.RunAs, command, args...,
 		)
+		c.setServiceStarted(time.Now())
 		return service, serviceExited
 	}
 
@@ -697,8 +733,16 @@ func (c *Controller) Run() (err error) {
 
 	exited := false
 
+	// restarting is set while the service stops after failing a liveness
+	// check, which restarts it regardless of the restart policy
+	restarting := false
+	var backoff restartBackoff
+	var killAfter <-chan time.Time
+
 	var shutdownService = func(service *subprocess.Instance, sig os.Signal) {
 		c.options.Service.Autorestart = false
+		restarting = false
+		killAfter = nil
 		if sendSignal(service, sig) {
 			// nil out all other channels because we're shutting down
 			sigc = nil
@@ -738,8 +782,33 @@ func (c *Controller) Run() (err error) {
 			startAfter = time.After(time.Millisecond * 1)
 			prereqsPassed = nil
 
+		case name := <-c.livenessFailed:
+			if service == nil || restarting {
+				break
+			}
+			glog.Infof("Liveness check %s failed, stopping service process for service %s", name, c.options.Service.ID)
+			if sendSignal(service, syscall.SIGTERM) {
+				restarting = true
+				killAfter = time.After(time.Second * 30)
+				c.readiness.Suspend()
+			}
+
+		case <-killAfter:
+			glog.Infof("Killing unresponsive service process for service %s", c.options.Service.ID)
+			sendSignal(service, syscall.SIGKILL)
+			killAfter = nil
+
 		case exitError := <-serviceExited:
-			if !c.options.Service.Autorestart {
+			uptime := time.Since(c.getServiceStarted())
+			c.setServiceStarted(time.Time{})
+			if restarting {
+				restarting = false
+				killAfter = nil
+				delay := backoff.Next(uptime)
+				glog.Infof("Restarting service process for service %s in %s after a failed liveness check.", c.options.Service.ID, delay)
+				service = nil
+				startAfter = time.After(delay)
+			} else if !c.options.Service.Autorestart {
 				exitStatus, _ := utils.GetExitStatus(exitError)
 				if c.options.Logforwarder.Enabled {
 					time.Sleep(c.options.Logforwarder.SettleTime)
@@ -754,12 +823,14 @@ func (c *Controller) Run() (err error) {
 				glog.Infof("Restarting service process for service %s in 10 seconds.", c.options.Service.ID)
 				service = nil
 				startAfter = time.After(time.Second * 10)
+				c.readiness.Suspend()
 			}
 
 		case <-startAfter:
 			glog.Infof("Starting service process for service %s", c.options.Service.ID)
 			service, serviceExited = startService()
 			startAfter = nil
+			c.readiness.Resume()
 		case <-rpcDead:
 			glog.Infof("RPC Server has gone away, cleaning up service %s", c.options.Service.ID)
 			shutdownService(service, syscall.SIGTERM)
@@ -836,7 +907,7 @@ func (c *Controller) kickOffHealthChecks(healthExit chan struct{}) {
 	}
 	for name, hc := range c.healthChecks {
 		glog.Infof("Kicking off health check %s.", name)
-		glog.Infof("Setting up health check: %s", hc.Script)
+		glog.Infof("Setting up %s health check: %s", hc.GetType(), hc.Target())
 		key := health.HealthStatusKey{
 			ServiceID:       c.options.Service.ID,
 			InstanceID:      instanceID,
@@ -847,13 +918,34 @@ func (c *Controller) kickOffHealthChecks(healthExit chan struct{}) {
 	return
 }
 
+func (c *Controller) setServiceStarted(started time.Time) {
+	c.serviceMu.Lock()
+	defer c.serviceMu.Unlock()
+	c.serviceStarted = started
+}
+
+// getServiceStarted returns when the service process started, or the zero
+// time if it is not running.
+func (c *Controller) getServiceStarted() time.Time {
+	c.serviceMu.Lock()
+	defer c.serviceMu.Unlock()
+	return c.serviceStarted
+}
+
 func (c *Controller) doHealthCheck(cancel <-chan struct{}, key health.HealthStatusKey, hc health.HealthCheck) {
 	logger := plog.WithFields(log.Fields{
 		"service":     key.ServiceID,
 		"instance":    key.InstanceID,
 		"healthcheck": key.HealthCheckName,
+		"kind":        hc.Kind,
 	})
 	hc.Ping(cancel, key, func(stat health.HealthStatus) {
+		if stat.Status != health.OK && hc.InStartPeriod(c.getServiceStarted()) {
+			// failures do not count while the service is starting
+			stat.Status = health.Unknown
+			stat.KillFlag = false
+			hc.KillCounter = 0
+		}
 		req := master.HealthStatusRequest{
 			Key:     key,
 			Value:   stat,
@@ -866,9 +958,23 @@ func (c *Controller) doHealthCheck(cancel <-chan struct{}, key health.HealthStat
 		}
 		defer client.Close()
 		client.ReportHealthStatus(req, nil)
-		if stat.KillFlag {
-			logger.WithField("kill_count_limit", hc.KillCountLimit).Infof("KillFlag has been set. Shutting down the controller.")
-			c.shutdown()
+		switch hc.Kind {
+		case health.ReadinessCheck:
+			c.readiness.Report(key.HealthCheckName, stat.Status == health.OK)
+		case health.LivenessCheck:
+			if stat.KillFlag {
+				logger.WithField("kill_count_limit", hc.GetKillCountLimit()).Info("Liveness check failed. Restarting the service.")
+				hc.KillCounter = 0
+				select {
+				case c.livenessFailed <- key.HealthCheckName:
+				default:
+				}
+			}
+		default:
+			if stat.KillFlag {
+				logger.WithField("kill_count_limit", hc.KillCountLimit).Infof("KillFlag has been set. Shutting down the controller.")
+				c.shutdown()
+			}
 		}
 	})
 }
@@ -23,7 +23,11 @@ import (
 	"time"
 
 	log "github.com/Sirupsen/logrus"
+	coordclient "github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/node"
+	svcproxy "github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/zzk"
 	"github.com/control-center/serviced/zzk/registry"
 	zkservice "github.com/control-center/serviced/zzk/service"
@@ -37,7 +41,10 @@ type ContainerEndpointsOptions struct {
 	IsShell              bool
 	TCPMuxPort           uint16
 	UseTLS               bool
+	MuxDialer            svcproxy.DialerOptions // Timeouts and circuit breakers of connections to imports
 	VirtualAddressSubnet string
+	ServicedEndpoint     string        // Where to look up the health of imported instances
+	HealthInterval       time.Duration // How often to look up the health of imported instances
 }
 
 // ContainerEndpoints manages import and export bindings for the instance.
@@ -47,6 +54,7 @@ type ContainerEndpoints struct {
 	cache *proxyCache
 	ports map[uint16]struct{}
 	vifs  *VIFRegistry
+	ready *readinessGate
 }
 
 // NewContainerEndpoints loads the service state and manages port bindings
@@ -57,6 +65,7 @@ func NewContainerEndpoints(svc *service.Service, opts ContainerEndpointsOptions)
 		opts:  opts,
 		ports: make(map[uint16]struct{}),
 		vifs:  NewVIFRegistry(),
+		ready: newReadinessGate(true),
 	}
 
 	// load the state object
@@ -66,7 +75,7 @@ func NewContainerEndpoints(svc *service.Service, opts ContainerEndpointsOptions)
 	}
 
 	// set up the proxy cache
-	ce.cache = newProxyCache(opts.TenantID, opts.TCPMuxPort, opts.UseTLS, allowDirect)
+	ce.cache = newProxyCache(opts.TenantID, opts.TCPMuxPort, opts.UseTLS, allowDirect, svcproxy.NewDialer(opts.MuxDialer))
 
 	// set up virtual interface registry
 	if err := ce.vifs.SetSubnet(opts.VirtualAddressSubnet); err != nil {
@@ -118,6 +127,7 @@ func (ce *ContainerEndpoints) loadState(svc *service.Service) (bool, error) {
 					Purpose:        ep.Purpose,
 					PortNumber:     ep.PortNumber,
 					VirtualAddress: ep.VirtualAddress,
+					LoadBalancing:  ep.LoadBalancing,
 				})
 			}
 		}
@@ -195,9 +205,90 @@ func (ce *ContainerEndpoints) Run(cancel <-chan struct{}) {
 	// track all of the imports
 	// TODO: set up another tracker for cc exports
 	go ce.RunImportListener(cancel, ce.opts.TenantID, ce.state.Imports...)
+
+	// keep unhealthy instances out of the import proxies
+	if ce.opts.ServicedEndpoint != "" {
+		go ce.RunHealthMonitor(cancel)
+	}
+}
+
+// RunHealthMonitor periodically looks up the health of the instances behind
+// the import proxies, so that connections are not sent to instances that are
+// failing their health checks.
+func (ce *ContainerEndpoints) RunHealthMonitor(cancel <-chan struct{}) {
+	plog.Debug("Running import health monitor")
+	defer plog.Debug("Exited import health monitor")
+
+	interval := ce.opts.HealthInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ce.UpdateImportHealth()
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// UpdateImportHealth looks up the health of the instances behind the import
+// proxies and updates the proxies.
+func (ce *ContainerEndpoints) UpdateImportHealth() {
+	client, err := node.NewLBClient(ce.opts.ServicedEndpoint)
+	if err != nil {
+		plog.WithError(err).Debug("Could not connect to look up import health")
+		return
+	}
+	defer client.Close()
+
+	statuses := make(map[string]map[int]map[string]health.HealthStatus)
+	for _, serviceID := range ce.cache.ServiceIDs() {
+		var results map[int]map[string]health.HealthStatus
+		if err := client.GetServiceHealth(serviceID, &results); err != nil {
+			// without health data the instances are treated as healthy
+			plog.WithField("serviceid", serviceID).WithError(err).Debug("Could not look up service health")
+			continue
+		}
+		statuses[serviceID] = results
+	}
+
+	ce.cache.SetHealth(func(address addressTuple) bool {
+		return health.IsHealthy(statuses[address.serviceID][address.instanceID])
+	})
+}
+
+// ProxyStats returns the connection counts for each backend of each import
+// proxy, keyed by the proxy name.
+func (ce *ContainerEndpoints) ProxyStats() map[string][]BackendStats {
+	return ce.cache.Stats()
+}
+
+// DialStats returns the connection counts and circuit breaker state of each
+// address the import proxies dialed.
+func (ce *ContainerEndpoints) DialStats() []svcproxy.DialStats {
+	return ce.cache.dialer.Stats()
+}
+
+// Traffic returns the traffic counts from each import to each service
+// exporting the endpoint.
+func (ce *ContainerEndpoints) Traffic() []TrafficStats {
+	return ce.cache.Traffic()
+}
+
+// SetReady registers the exports of the instance when it is ready and
+// unregisters them when it is not, so that other services only connect to
+// ready instances.  Instances are ready unless set otherwise.
+func (ce *ContainerEndpoints) SetReady(ready bool) {
+	ce.ready.Set(ready)
 }
 
 // AddExport ensures that an export is registered for other services to bind
+// while the instance is ready
 func (ce *ContainerEndpoints) AddExport(cancel <-chan struct{}, bind zkservice.ExportBinding) {
 	logger := plog.WithFields(log.Fields{
 		"application": bind.Application,
@@ -210,6 +301,7 @@ func (ce *ContainerEndpoints) AddExport(cancel <-chan struct{}, bind zkservice.E
 		PrivateIP:     ce.state.PrivateIP,
 		HostIP:        ce.state.HostIP,
 		MuxPort:       ce.opts.TCPMuxPort,
+		ServiceID:     ce.state.ServiceID,
 		InstanceID:    ce.state.InstanceID,
 	}
 
@@ -217,12 +309,23 @@ func (ce *ContainerEndpoints) AddExport(cancel <-chan struct{}, bind zkservice.E
 	defer logger.Debug("Unregistered export")
 
 	for {
+		ready, changed := ce.ready.State()
+		if !ready {
+			logger.Debug("Waiting for the instance to be ready")
+			select {
+			case <-changed:
+				continue
+			case <-cancel:
+				return
+			}
+		}
+
 		select {
 		case conn := <-zzk.Connect("/", zzk.GetLocalConnection):
 			if conn != nil {
 
 				logger.Debug("Received coordinator connection")
-				registry.RegisterExport(cancel, conn, ce.opts.TenantID, exp)
+				ce.registerExport(cancel, changed, conn, exp)
 				select {
 				case <-cancel:
 					return
@@ -235,6 +338,24 @@ func (ce *ContainerEndpoints) AddExport(cancel <-chan struct{}, bind zkservice.E
 	}
 }
 
+// registerExport registers the export until the instance shuts down or its
+// readiness changes
+func (ce *ContainerEndpoints) registerExport(cancel, changed <-chan struct{}, conn coordclient.Connection, exp registry.ExportDetails) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-cancel:
+		case <-changed:
+		case <-done:
+			return
+		}
+		close(stop)
+	}()
+	registry.RegisterExport(stop, conn, ce.opts.TenantID, exp)
+}
+
 // RunImportListener keeps track of the state of all matching imports
 // TODO: isvcs imports should be stored under tenantID /net/export/cc
 func (ce *ContainerEndpoints) RunImportListener(cancel <-chan struct{}, tenantID string, binds ...zkservice.ImportBinding) {
@@ -377,7 +498,7 @@ func (ce *ContainerEndpoints) UpdateRemoteExports(bind zkservice.ImportBinding,
 			}
 
 			// update the proxy; returns a boolean if a new proxy was created.
-			isNew, err := ce.cache.Set(bind.Application, port, export)
+			isNew, err := ce.cache.Set(bind.Application, port, bind.LoadBalancing, export)
 			if err != nil {
 				exLogger.WithError(err).Error("Could not update proxy")
 				return
@@ -433,7 +554,7 @@ func (ce *ContainerEndpoints) UpdateRemoteExports(bind zkservice.ImportBinding,
 		}
 
 		// update the proxy
-		isNew, err := ce.cache.Set(bind.Application, port, exports...)
+		isNew, err := ce.cache.Set(bind.Application, port, bind.LoadBalancing, exports...)
 		if err != nil {
 			exLogger.WithError(err).Error("Could not update proxy")
 			return
@@ -476,9 +597,10 @@ type proxyCache struct {
 	tcpMuxPort  uint16
 	useTLS      bool
 	allowDirect bool
+	dialer      *svcproxy.Dialer // shared by the proxies so a failing host trips once
 }
 
-func newProxyCache(tenantID string, tcpMuxPort uint16, useTLS, allowDirect bool) *proxyCache {
+func newProxyCache(tenantID string, tcpMuxPort uint16, useTLS, allowDirect bool, dialer *svcproxy.Dialer) *proxyCache {
 	return &proxyCache{
 		mu:          &sync.Mutex{},
 		cache:       make(map[proxyKey]*proxy),
@@ -486,11 +608,12 @@ func newProxyCache(tenantID string, tcpMuxPort uint16, useTLS, allowDirect bool)
 		tcpMuxPort:  tcpMuxPort,
 		useTLS:      useTLS,
 		allowDirect: allowDirect,
+		dialer:      dialer,
 	}
 }
 
 // Set returns true if the key was created and an error
-func (c *proxyCache) Set(application string, portNumber uint16, exports ...registry.ExportDetails) (bool, error) {
+func (c *proxyCache) Set(application string, portNumber uint16, loadBalancing string, exports ...registry.ExportDetails) (bool, error) {
 	logger := plog.WithFields(log.Fields{
 		"application": application,
 		"portnumber":  portNumber,
@@ -527,6 +650,7 @@ func (c *proxyCache) Set(application string, portNumber uint16, exports ...regis
 			c.useTLS,
 			listener,
 			c.allowDirect,
+			c.dialer,
 		)
 		if err != nil {
 			logger.WithError(err).Debug("Could not start proxy")
@@ -544,11 +668,82 @@ func (c *proxyCache) Set(application string, portNumber uint16, exports ...regis
 		addresses[i] = addressTuple{
 			host:          export.HostIP,
 			containerAddr: fmt.Sprintf("%s:%d", export.PrivateIP, export.PortNumber),
+			serviceID:     export.ServiceID,
+			instanceID:    export.InstanceID,
 		}
 	}
+	prxy.SetLoadBalancing(loadBalancing)
 	prxy.SetNewAddresses(addresses)
 
 	logger.Debug("Set exports for proxy")
 
 	return !ok, nil
 }
+
+// ServiceIDs returns the ids of the services behind the proxies
+func (c *proxyCache) ServiceIDs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]struct{})
+	var serviceIDs []string
+	for _, prxy := range c.cache {
+		for _, address := range prxy.lb.Addresses() {
+			if _, ok := seen[address.serviceID]; !ok && address.serviceID != "" {
+				seen[address.serviceID] = struct{}{}
+				serviceIDs = append(serviceIDs, address.serviceID)
+			}
+		}
+	}
+	return serviceIDs
+}
+
+// SetHealth updates the health of the addresses of every proxy
+func (c *proxyCache) SetHealth(isHealthy func(addressTuple) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, prxy := range c.cache {
+		prxy.SetHealth(isHealthy)
+	}
+}
+
+// Stats returns the connection counts of every proxy, keyed by proxy name
+func (c *proxyCache) Stats() map[string][]BackendStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make(map[string][]BackendStats)
+	for _, prxy := range c.cache {
+		stats[prxy.Name()] = prxy.Stats()
+	}
+	return stats
+}
+
+// Traffic returns the traffic counts of every proxy, summed per application
+// and exporting service
+func (c *proxyCache) Traffic() []TrafficStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type trafficKey struct{ application, serviceID string }
+	byKey := make(map[trafficKey]*TrafficStats)
+	for key, prxy := range c.cache {
+		for _, s := range prxy.Traffic() {
+			k := trafficKey{key.Application, s.ServiceID}
+			if total, ok := byKey[k]; ok {
+				total.add(s)
+			} else {
+				total := s
+				total.Application = key.Application
+				byKey[k] = &total
+			}
+		}
+	}
+	traffic := make([]TrafficStats, 0, len(byKey))
+	for _, s := range byKey {
+		traffic = append(traffic, *s)
+	}
+	sortTraffic(traffic)
+	return traffic
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// minRestartBackoff is how long the controller waits to restart a service
+	// that failed a liveness check for the first time.
+	minRestartBackoff = 10 * time.Second
+
+	// maxRestartBackoff is the longest the controller waits to restart a
+	// service that keeps failing its liveness checks.
+	maxRestartBackoff = 5 * time.Minute
+)
+
+// readinessGate holds the exports of an instance back while it is not ready
+type readinessGate struct {
+	mu      sync.Mutex
+	ready   bool
+	changed chan struct{}
+}
+
+func newReadinessGate(ready bool) *readinessGate {
+	return &readinessGate{ready: ready, changed: make(chan struct{})}
+}
+
+// Set marks the instance as ready or not ready
+func (g *readinessGate) Set(ready bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.ready == ready {
+		return
+	}
+	g.ready = ready
+	close(g.changed)
+	g.changed = make(chan struct{})
+}
+
+// State returns whether the instance is ready and a channel that closes when
+// that changes.
+func (g *readinessGate) State() (bool, <-chan struct{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.ready, g.changed
+}
+
+// readinessTracker combines the results of the readiness checks of an
+// instance, which is ready once all of them pass.
+type readinessTracker struct {
+	mu        sync.Mutex
+	checks    map[string]bool
+	set       func(bool)
+	suspended bool // the process is restarting, so results are ignored
+}
+
+func newReadinessTracker(names []string, set func(bool)) *readinessTracker {
+	t := &readinessTracker{checks: make(map[string]bool), set: set}
+	for _, name := range names {
+		t.checks[name] = false
+	}
+	set(len(names) == 0)
+	return t
+}
+
+// Report records the result of a readiness check
+func (t *readinessTracker) Report(name string, passed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.suspended {
+		return
+	}
+	t.checks[name] = passed
+	ready := true
+	for _, ok := range t.checks {
+		ready = ready && ok
+	}
+	t.set(ready)
+}
+
+// Suspend marks the instance as not ready while its process restarts,
+// whether or not it has readiness checks
+func (t *readinessTracker) Suspend() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.suspended = true
+	t.set(false)
+}
+
+// Resume clears the results of a suspended tracker once the process starts
+// again, so the instance is ready when all of its readiness checks pass anew
+func (t *readinessTracker) Resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.suspended {
+		return
+	}
+	t.suspended = false
+	for name := range t.checks {
+		t.checks[name] = false
+	}
+	t.set(len(t.checks) == 0)
+}
+
+// restartBackoff is the delay before restarting a service that failed its
+// liveness checks, which doubles with each restart and resets once the
+// service stays up for longer than the maximum delay.
+type restartBackoff struct {
+	delay time.Duration
+}
+
+// Next returns the delay before the next restart of a service that ran for
+// the given time.
+func (b *restartBackoff) Next(uptime time.Duration) time.Duration {
+	switch {
+	case b.delay == 0 || uptime > maxRestartBackoff:
+		b.delay = minRestartBackoff
+	case b.delay*2 > maxRestartBackoff:
+		b.delay = maxRestartBackoff
+	default:
+		b.delay *= 2
+	}
+	return b.delay
+}
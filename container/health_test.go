@@ -0,0 +1,135 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package container
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadinessGate(t *testing.T) {
+	g := newReadinessGate(false)
+	ready, changed := g.State()
+	if ready {
+		t.Fatalf("Expected the gate to start closed")
+	}
+
+	g.Set(true)
+	select {
+	case <-changed:
+	default:
+		t.Fatalf("Expected the gate to signal the change")
+	}
+	ready, changed = g.State()
+	if !ready {
+		t.Fatalf("Expected the gate to be open")
+	}
+
+	// setting the same state is not a change
+	g.Set(true)
+	select {
+	case <-changed:
+		t.Fatalf("Did not expect a change")
+	default:
+	}
+}
+
+func TestReadinessTracker(t *testing.T) {
+	var ready []bool
+	set := func(r bool) { ready = append(ready, r) }
+
+	// instances without readiness checks are ready
+	newReadinessTracker(nil, set)
+	if len(ready) != 1 || !ready[0] {
+		t.Fatalf("Expected the instance to be ready, got %v", ready)
+	}
+
+	ready = nil
+	tracker := newReadinessTracker([]string{"db", "web"}, set)
+	tracker.Report("db", true)
+	tracker.Report("web", true)
+	tracker.Report("db", false)
+	expected := []bool{false, false, true, false}
+	if len(ready) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, ready)
+	}
+	for i := range expected {
+		if ready[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, ready)
+		}
+	}
+}
+
+func TestReadinessTracker_Restart(t *testing.T) {
+	var ready []bool
+	set := func(r bool) { ready = append(ready, r) }
+	expect := func(expected ...bool) {
+		t.Helper()
+		if len(ready) != len(expected) {
+			t.Fatalf("Expected %v, got %v", expected, ready)
+		}
+		for i := range expected {
+			if ready[i] != expected[i] {
+				t.Fatalf("Expected %v, got %v", expected, ready)
+			}
+		}
+		ready = nil
+	}
+
+	// an instance with only liveness checks is not ready while it restarts
+	tracker := newReadinessTracker(nil, set)
+	tracker.Suspend()
+	tracker.Resume()
+	expect(true, false, true)
+
+	// results while restarting are ignored, and the checks must pass again
+	// once the process starts
+	tracker = newReadinessTracker([]string{"web"}, set)
+	tracker.Report("web", true)
+	tracker.Suspend()
+	tracker.Report("web", true)
+	tracker.Resume()
+	tracker.Report("web", true)
+	expect(false, true, false, false, true)
+
+	// the first start does not clear the results
+	tracker = newReadinessTracker([]string{"web"}, set)
+	tracker.Report("web", true)
+	tracker.Resume()
+	expect(false, true)
+}
+
+func TestRestartBackoff(t *testing.T) {
+	var b restartBackoff
+	for _, expected := range []time.Duration{
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		80 * time.Second,
+		160 * time.Second,
+		maxRestartBackoff,
+		maxRestartBackoff,
+	} {
+		if actual := b.Next(time.Second); actual != expected {
+			t.Fatalf("Expected %s, got %s", expected, actual)
+		}
+	}
+
+	// a service that stayed up starts over
+	if actual := b.Next(maxRestartBackoff + time.Second); actual != minRestartBackoff {
+		t.Fatalf("Expected %s, got %s", minRestartBackoff, actual)
+	}
+}
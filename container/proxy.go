@@ -15,6 +15,7 @@ package container
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -24,6 +25,7 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/auth"
+	svcproxy "github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/glog"
 )
@@ -74,35 +76,42 @@ The netcat (nc) command is particularly useful for this:
 type addressTuple struct {
 	host          string // IP of the host on which the container is running
 	containerAddr string // Container IP:port of the remote service
+	serviceID     string // ID of the service exporting the endpoint
+	instanceID    int    // Instance of the service exporting the endpoint
 }
 
 type proxy struct {
-	name             string              // Name of the remote service
-	tenantEndpointID string              // Tenant endpoint ID
-	addresses        []addressTuple      // Public/container IP:Port of the remote service
-	tcpMuxPort       uint16              // the port to use for TCP Muxing, 0 is disabled
-	useTLS           bool                // use encryption over mux port
-	closing          chan chan error     // internal shutdown signal
-	newAddresses     chan []addressTuple // a stream of updates to the addresses
-	listener         net.Listener        // handle on the listening socket
-	allowDirectConn  bool                // allow container to container connections
+	name             string           // Name of the remote service
+	tenantEndpointID string           // Tenant endpoint ID
+	lb               *balancer        // Chooses the public/container IP:Port of the remote service
+	tcpMuxPort       uint16           // the port to use for TCP Muxing, 0 is disabled
+	useTLS           bool             // use encryption over mux port
+	closing          chan chan error  // internal shutdown signal
+	listener         net.Listener     // handle on the listening socket
+	allowDirectConn  bool             // allow container to container connections
+	dialer           *svcproxy.Dialer // dials the addresses with timeouts and circuit breakers
+	traffic          *trafficRecorder // counts the traffic to each exporting service
 }
 
 // Newproxy create a new proxy object. It starts listening on the prxy port asynchronously.
-func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, listener net.Listener, allowDirectConn bool) (p *proxy, err error) {
+func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, listener net.Listener, allowDirectConn bool, dialer *svcproxy.Dialer) (p *proxy, err error) {
 	if len(name) == 0 {
 		return nil, fmt.Errorf("prxy: name can not be empty")
 	}
+	if dialer == nil {
+		dialer = svcproxy.NewDialer(svcproxy.DefaultDialerOptions)
+	}
 	p = &proxy{
 		name:             name,
 		tenantEndpointID: tenantEndpointID,
-		addresses:        make([]addressTuple, 0),
+		lb:               newBalancer(""),
 		tcpMuxPort:       tcpMuxPort,
 		useTLS:           useTLS,
 		listener:         listener,
 		allowDirectConn:  allowDirectConn,
+		dialer:           dialer,
+		traffic:          newTrafficRecorder(),
 	}
-	p.newAddresses = make(chan []addressTuple, 2)
 	go p.listenAndproxy()
 	return p, nil
 }
@@ -114,7 +123,7 @@ func (p *proxy) Name() string {
 
 // String() pretty prints the proxy struct.
 func (p *proxy) String() string {
-	return fmt.Sprintf("proxy[%s; %s]=>%v", p.name, p.listener, p.addresses)
+	return fmt.Sprintf("proxy[%s; %s]=>%v", p.name, p.listener, p.lb.Addresses())
 }
 
 // TCPMuxPort() returns the tcp port use for muxing, 0 if not used.
@@ -135,7 +144,27 @@ func (p *proxy) SetNewAddresses(addresses []addressTuple) {
 	for i, v := range perm {
 		dest[v] = addresses[i]
 	}
-	p.newAddresses <- dest
+	p.lb.SetBackends(dest)
+}
+
+// SetLoadBalancing sets how connections are spread across the addresses
+func (p *proxy) SetLoadBalancing(mode string) {
+	p.lb.SetMode(mode)
+}
+
+// SetHealth marks which addresses are healthy
+func (p *proxy) SetHealth(isHealthy func(addressTuple) bool) {
+	p.lb.SetHealth(isHealthy)
+}
+
+// Stats returns the connection counts for each address
+func (p *proxy) Stats() []BackendStats {
+	return p.lb.Stats()
+}
+
+// Traffic returns the traffic counts to each exporting service
+func (p *proxy) Traffic() []TrafficStats {
+	return p.traffic.Stats()
 }
 
 // Close() terminates the prxy; it can not be restarted.
@@ -161,20 +190,15 @@ func (p *proxy) listenAndproxy() {
 		}
 	}(p.listener, connections)
 
-	i := 0
 	for {
 		select {
 		case conn := <-connections:
-			if len(p.addresses) == 0 {
+			if p.lb.Len() == 0 {
 				glog.Warningf("No remote services available for prxying %v", p)
 				conn.Close()
 				continue
 			}
-			i++
-			// round robin connections to list of addresses
-			glog.V(1).Infof("choosing address from %v", p.addresses)
-			go p.prxy(conn, p.addresses[i%len(p.addresses)])
-		case p.addresses = <-p.newAddresses:
+			go p.prxy(conn)
 		case errc := <-p.closing:
 			p.listener.Close()
 			errc <- nil
@@ -192,10 +216,68 @@ func getPort(addr string) (int, error) {
 	return strconv.Atoi(port)
 }
 
-// prxy takes an established local connection, Dials the remote address specified
-// by the proxy structure and then copies data to and from the resulting pair
-// of endpoints.
-func (p *proxy) prxy(local net.Conn, address addressTuple) {
+// prxy takes an established local connection, Dials a remote address chosen
+// by the load balancer and then copies data to and from the resulting pair of
+// endpoints.  If the dial fails, the next address is tried.
+func (p *proxy) prxy(local net.Conn) {
+	clientIP, _, _ := net.SplitHostPort(local.RemoteAddr().String())
+	tried := make(map[*backend]bool)
+
+	var (
+		remote net.Conn
+		be     *backend
+		err    error
+	)
+	for {
+		if be = p.lb.Pick(clientIP, tried); be == nil {
+			glog.Errorf("Could not connect to any remote service for %v", p)
+			local.Close()
+			return
+		}
+		tried[be] = true
+		start := time.Now()
+		if remote, err = p.dial(be.address); err == nil {
+			p.traffic.Connected(be.address.serviceID, time.Since(start))
+			break
+		}
+		glog.Warningf("Could not connect to %v, trying next address: %s", be.address, err)
+		p.lb.Fail(be)
+		p.traffic.Failed(be.address.serviceID)
+	}
+
+	address := be.address
+	local, remote = svcproxy.CloseIdle(local, remote, p.dialer.Options().IdleTimeout)
+	glog.V(2).Infof("Using hostAgent:%v to prxy %v<->%v<->%v<->%v",
+		remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
+
+	// release the backend once both directions are closed
+	done := make(chan struct{}, 2)
+	go func() {
+		<-done
+		<-done
+		p.lb.Release(be)
+	}()
+	go func(address string) {
+		defer func() { done <- struct{}{} }()
+		defer local.Close()
+		defer remote.Close()
+		io.Copy(&countingWriter{local, func(n int64) { p.traffic.Received(be.address.serviceID, n) }}, remote)
+		glog.V(2).Infof("Closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
+			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
+	}(address.containerAddr)
+	go func(address string) {
+		defer func() { done <- struct{}{} }()
+		defer local.Close()
+		defer remote.Close()
+		io.Copy(&countingWriter{remote, func(n int64) { p.traffic.Sent(be.address.serviceID, n) }}, local)
+		glog.V(2).Infof("closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
+			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
+	}(address.containerAddr)
+}
+
+// dial connects to the remote address, either directly to a local container
+// or through the mux port of the remote host.
+func (p *proxy) dial(address addressTuple) (net.Conn, error) {
 
 	var (
 		remote net.Conn
@@ -248,13 +330,13 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 		muxAddrPacked, err = utils.PackTCPAddressString(address.containerAddr)
 		if err != nil {
 			glog.Errorf("Container address is invalid. Can't create proxy: %s", address.containerAddr)
-			return
+			return nil, err
 		}
 		select {
 		case token = <-auth.AuthToken(nil):
 		case <-time.After(tokenTimeout):
 			glog.Error("Unable to retrieve authentication token with 30 seconds")
-			return
+			return nil, errors.New("timeout waiting for authentication token")
 		}
 	}
 
@@ -263,50 +345,42 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 	switch {
 	case isLocalContainer:
 		glog.V(2).Infof("dialing local addr=> %s", localAddr)
-		remote, err = net.Dial("tcp4", localAddr)
+		remote, err = p.dialer.Dial("tcp4", localAddr)
 		if err != nil {
 			glog.Errorf("Error Local (net.Dial): %s", err)
-			return
+			return nil, err
 		}
 	case p.useTLS:
 		glog.V(2).Infof("dialing remote tls => %s", muxAddr)
-		config := tls.Config{InsecureSkipVerify: true}
-		tlsConn, err := tls.Dial("tcp4", muxAddr, &config)
+		config := auth.MuxClientTLSConfig()
+		conn, err := p.dialer.DialFunc(muxAddr, func(timeout time.Duration) (net.Conn, error) {
+			return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp4", muxAddr, config)
+		})
 		if err != nil {
 			glog.Errorf("Error TLS (net.Dial): %s", err)
-			return
+			return nil, err
 		}
-		remote = tlsConn // cast it to the net.Conn interface
+		remote = conn
+		tlsConn := conn.(*tls.Conn)
 		cipher := tlsConn.ConnectionState().CipherSuite
 		glog.V(2).Infof("Proxy connected to mux with TLS cipher=%s (%d)", utils.GetCipherName(cipher), cipher)
 	default:
 		glog.V(2).Infof("dialing remote => %s", muxAddr)
-		remote, err = net.Dial("tcp4", muxAddr)
+		remote, err = p.dialer.Dial("tcp4", muxAddr)
 		if err != nil {
 			glog.Errorf("Error Remote (net.Dial): %s", err)
-			return
+			return nil, err
 		}
 	}
 
 	// If this is not a local container, write the mux header
 	if token != "" && len(muxAddrPacked) > 0 {
+		if timeout := p.dialer.Options().DialTimeout; timeout > 0 {
+			remote.SetWriteDeadline(time.Now().Add(timeout))
+			defer remote.SetWriteDeadline(time.Time{})
+		}
 		auth.AddSignedMuxHeader(remote, muxAddrPacked, token)
 	}
 
-	glog.V(2).Infof("Using hostAgent:%v to prxy %v<->%v<->%v<->%v",
-		remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	go func(address string) {
-		defer local.Close()
-		defer remote.Close()
-		io.Copy(local, remote)
-		glog.V(2).Infof("Closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
-			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	}(address.containerAddr)
-	go func(address string) {
-		defer local.Close()
-		defer remote.Close()
-		io.Copy(remote, local)
-		glog.V(2).Infof("closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
-			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	}(address.containerAddr)
+	return remote, nil
 }
@@ -57,12 +57,12 @@ func TestNoMux(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Could not bind to a port for test")
 	}
-	prxy, err := newProxy("foo", "endpointfoo", 0, false, local, false)
+	prxy, err := newProxy("foo", "endpointfoo", 0, false, local, false, nil)
 	if err != nil {
 		t.Fatalf("Could not create a prxy: %s", err)
 	}
 	host := strings.Split(remote.Addr().String(), ":")[0]
-	addresses := []addressTuple{addressTuple{host, remote.Addr().String()}}
+	addresses := []addressTuple{addressTuple{host: host, containerAddr: remote.Addr().String()}}
 	prxy.SetNewAddresses(addresses)
 	stringChan := stringAcceptor(remote)
 	conn, err := net.Dial("tcp4", local.Addr().String())
@@ -14,6 +14,7 @@
 package container
 
 import (
+	svcproxy "github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/stats"
 	"github.com/zenoss/glog"
 
@@ -28,13 +29,14 @@ import (
 
 // statReporter perically collects statistics at the given
 // interval until the closing channel closes
-func statReporter(statsUrl string, interval time.Duration) {
+func statReporter(statsUrl string, interval time.Duration, endpoints *ContainerEndpoints) {
 
 	tick := time.Tick(interval)
 	for {
 		select {
 		case t := <-tick:
 			collect(t, statsUrl)
+			collectTrafficStats(t, statsUrl, endpoints.Traffic())
 		}
 	}
 }
@@ -96,6 +98,115 @@ func collect(ts time.Time, statsUrl string) {
 	}
 }
 
+// proxyStatReporter periodically posts the connection counts of the import
+// proxies and the circuit breakers of the hosts they dial at the given
+// interval
+func proxyStatReporter(statsUrl string, interval time.Duration, endpoints *ContainerEndpoints) {
+	tick := time.Tick(interval)
+	for t := range tick {
+		collectProxyStats(t, statsUrl, endpoints.ProxyStats(), endpoints.DialStats())
+	}
+}
+
+func collectProxyStats(ts time.Time, statsUrl string, proxyStats map[string][]BackendStats, dialStats []svcproxy.DialStats) {
+	samples := []stats.Sample{}
+	now := ts.Unix()
+	for name, backends := range proxyStats {
+		for _, backend := range backends {
+			tags := map[string]string{
+				"proxy":      name,
+				"backend":    backend.Address,
+				"instanceid": strconv.Itoa(backend.InstanceID),
+			}
+			for metric, value := range map[string]int64{
+				"active":   backend.Active,
+				"total":    backend.Total,
+				"failures": backend.Failures,
+			} {
+				samples = append(samples, stats.Sample{
+					Metric:    "net.proxy.connections." + metric,
+					Value:     strconv.FormatInt(value, 10),
+					Timestamp: now,
+					Tags:      tags,
+				})
+			}
+		}
+	}
+	for _, dial := range dialStats {
+		tags := map[string]string{"address": dial.Address}
+		circuitOpen := int64(0)
+		if dial.State != svcproxy.CircuitClosed {
+			circuitOpen = 1
+		}
+		for metric, value := range map[string]int64{
+			"opened":      dial.Opened,
+			"failed":      dial.Failed,
+			"tripped":     dial.Tripped,
+			"circuitopen": circuitOpen,
+		} {
+			samples = append(samples, stats.Sample{
+				Metric:    "net.proxy.dial." + metric,
+				Value:     strconv.FormatInt(value, 10),
+				Timestamp: now,
+				Tags:      tags,
+			})
+		}
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	glog.V(4).Infof("posting proxy samples: %+v", samples)
+	if err := stats.Post(statsUrl, samples); err != nil {
+		glog.Errorf("could not post proxy stats: %s", err)
+	}
+}
+
+// collectTrafficStats posts the traffic counts of the imports, tagged with the
+// imported application and the service exporting it.  The metric forwarder
+// tags them with this service, so the master can map the traffic between
+// services.
+func collectTrafficStats(ts time.Time, statsUrl string, traffic []TrafficStats) {
+	samples := trafficSamples(ts, traffic)
+	if len(samples) == 0 {
+		return
+	}
+
+	glog.V(4).Infof("posting traffic samples: %+v", samples)
+	if err := stats.Post(statsUrl, samples); err != nil {
+		glog.Errorf("could not post traffic stats: %s", err)
+	}
+}
+
+func trafficSamples(ts time.Time, traffic []TrafficStats) []stats.Sample {
+	samples := []stats.Sample{}
+	now := ts.Unix()
+	for _, t := range traffic {
+		tags := map[string]string{
+			"application":     t.Application,
+			"exportserviceid": t.ServiceID,
+		}
+		for _, metric := range []struct {
+			name  string
+			value int64
+		}{
+			{"connections", t.Connections},
+			{"errors", t.Errors},
+			{"bytessent", t.BytesSent},
+			{"bytesreceived", t.BytesReceived},
+			{"connectlatency", int64(t.ConnectLatency / time.Millisecond)},
+		} {
+			samples = append(samples, stats.Sample{
+				Metric:    "net.traffic." + metric.name,
+				Value:     strconv.FormatInt(metric.value, 10),
+				Timestamp: now,
+				Tags:      tags,
+			})
+		}
+	}
+	return samples
+}
+
 // Read all the files in a directory that contain integers and return a
 // map of those values
 func readInt64Stats(dir string) (results map[string]int64, err error) {
@@ -17,6 +17,7 @@ package container
 
 import (
 	"testing"
+	"time"
 )
 
 func TestReadInt64Stats(t *testing.T) {
@@ -54,3 +55,28 @@ func TestGetOpenConnections(t *testing.T) {
 		t.Fatalf("expected 0 open connections, but got %d", conns)
 	}
 }
+
+func TestTrafficSamples(t *testing.T) {
+	ts := time.Unix(1500000000, 0)
+	samples := trafficSamples(ts, []TrafficStats{
+		{Application: "zodb", ServiceID: "mariadb", Connections: 4, Errors: 1, BytesSent: 100, BytesReceived: 2000, ConnectLatency: 1500 * time.Millisecond},
+	})
+	expected := map[string]string{
+		"net.traffic.connections":    "4",
+		"net.traffic.errors":         "1",
+		"net.traffic.bytessent":      "100",
+		"net.traffic.bytesreceived":  "2000",
+		"net.traffic.connectlatency": "1500",
+	}
+	if len(samples) != len(expected) {
+		t.Fatalf("expected %d samples, but got %d", len(expected), len(samples))
+	}
+	for _, sample := range samples {
+		if sample.Value != expected[sample.Metric] {
+			t.Errorf("expected %s for %s, but got %s", expected[sample.Metric], sample.Metric, sample.Value)
+		}
+		if sample.Timestamp != ts.Unix() || sample.Tags["application"] != "zodb" || sample.Tags["exportserviceid"] != "mariadb" {
+			t.Errorf("unexpected sample %+v", sample)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TrafficStats are the traffic counts from an import of this container to
+// one service exporting the endpoint, since the container started.
+type TrafficStats struct {
+	Application    string        // the imported endpoint
+	ServiceID      string        // the service exporting the endpoint
+	Connections    int64         // connections made
+	Errors         int64         // failed dials
+	BytesSent      int64         // bytes sent to the exporting service
+	BytesReceived  int64         // bytes received from the exporting service
+	ConnectLatency time.Duration // total time spent connecting
+}
+
+// add sums the counts of another import to the same service
+func (s *TrafficStats) add(other TrafficStats) {
+	s.Connections += other.Connections
+	s.Errors += other.Errors
+	s.BytesSent += other.BytesSent
+	s.BytesReceived += other.BytesReceived
+	s.ConnectLatency += other.ConnectLatency
+}
+
+// trafficRecorder counts the traffic of a proxy per exporting service
+type trafficRecorder struct {
+	mu    sync.Mutex
+	stats map[string]*TrafficStats
+}
+
+func newTrafficRecorder() *trafficRecorder {
+	return &trafficRecorder{stats: make(map[string]*TrafficStats)}
+}
+
+func (r *trafficRecorder) get(serviceID string) *TrafficStats {
+	s, ok := r.stats[serviceID]
+	if !ok {
+		s = &TrafficStats{ServiceID: serviceID}
+		r.stats[serviceID] = s
+	}
+	return s
+}
+
+// Connected records a connection to the service and how long it took
+func (r *trafficRecorder) Connected(serviceID string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(serviceID)
+	s.Connections++
+	s.ConnectLatency += latency
+}
+
+// Failed records a failed dial to the service
+func (r *trafficRecorder) Failed(serviceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.get(serviceID).Errors++
+}
+
+// Sent records bytes sent to the service
+func (r *trafficRecorder) Sent(serviceID string, n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.get(serviceID).BytesSent += n
+}
+
+// Received records bytes received from the service
+func (r *trafficRecorder) Received(serviceID string, n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.get(serviceID).BytesReceived += n
+}
+
+// Stats returns the traffic counts of each service
+func (r *trafficRecorder) Stats() []TrafficStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]TrafficStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		stats = append(stats, *s)
+	}
+	return stats
+}
+
+// countingWriter reports the bytes written through it as they are written, so
+// that long-lived connections are counted while they are open
+type countingWriter struct {
+	w     io.Writer
+	count func(int64)
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	if n > 0 {
+		c.count(int64(n))
+	}
+	return n, err
+}
+
+// sortTraffic orders traffic stats by application and service
+func sortTraffic(stats []TrafficStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Application != stats[j].Application {
+			return stats[i].Application < stats[j].Application
+		}
+		return stats[i].ServiceID < stats[j].ServiceID
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package container
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrafficRecorder(t *testing.T) {
+	r := newTrafficRecorder()
+	r.Failed("svcA")
+	r.Connected("svcA", 20*time.Millisecond)
+	r.Connected("svcA", 10*time.Millisecond)
+
+	var buf bytes.Buffer
+	w := &countingWriter{&buf, func(n int64) { r.Sent("svcA", n) }}
+	if _, err := io.Copy(w, strings.NewReader("hello")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	r.Received("svcA", 3)
+
+	stats := r.Stats()
+	expected := TrafficStats{
+		ServiceID:      "svcA",
+		Connections:    2,
+		Errors:         1,
+		BytesSent:      5,
+		BytesReceived:  3,
+		ConnectLatency: 30 * time.Millisecond,
+	}
+	if len(stats) != 1 || stats[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestProxyCacheTraffic(t *testing.T) {
+	newTestProxy := func(serviceID string, sent int64) *proxy {
+		p := &proxy{traffic: newTrafficRecorder()}
+		p.traffic.Connected(serviceID, time.Millisecond)
+		p.traffic.Sent(serviceID, sent)
+		return p
+	}
+	c := newProxyCache("tenant", 22250, true, false, nil)
+	c.cache[proxyKey{"zodb", 3306}] = newTestProxy("mariadb", 10)
+	c.cache[proxyKey{"zodb", 13306}] = newTestProxy("mariadb", 5)
+	c.cache[proxyKey{"redis", 6379}] = newTestProxy("redis", 1)
+
+	// imports of the same application to the same service are summed
+	traffic := c.Traffic()
+	expected := []TrafficStats{
+		{Application: "redis", ServiceID: "redis", Connections: 1, BytesSent: 1, ConnectLatency: time.Millisecond},
+		{Application: "zodb", ServiceID: "mariadb", Connections: 2, BytesSent: 15, ConnectLatency: 2 * time.Millisecond},
+	}
+	if len(traffic) != len(expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, traffic)
+	}
+	for i := range expected {
+		if traffic[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], traffic[i])
+		}
+	}
+}
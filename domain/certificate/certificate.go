@@ -0,0 +1,164 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+const (
+	// TypeVHost is a certificate served for a vhost public endpoint
+	TypeVHost = "vhost"
+	// TypePort is a certificate served for a port public endpoint
+	TypePort = "port"
+)
+
+const (
+	// RenewalPending means an acme certificate is waiting to be issued
+	RenewalPending = "pending"
+	// RenewalValid means an acme certificate was issued
+	RenewalValid = "valid"
+	// RenewalFailed means the last attempt to issue an acme certificate failed
+	RenewalFailed = "failed"
+)
+
+// ErrNoCertificate is returned when the pem data does not contain a certificate
+var ErrNoCertificate = errors.New("no certificate found in pem data")
+
+// Certificate is a tls certificate and key served for a public endpoint,
+// in place of the default certificate of the master.
+type Certificate struct {
+	ID          string
+	Type        string   // TypeVHost or TypePort
+	Name        string   // vhost name or port address
+	ServiceID   string   // service that owns the public endpoint
+	Application string   // endpoint that owns the public endpoint
+	CertPEM     string   // pem encoded certificate chain
+	KeyPEM      string   // pem encoded private key
+	Subjects    []string // names the leaf certificate is valid for
+	NotAfter    time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// Certificates issued and renewed with the acme protocol
+	ACME          bool
+	Domains       []string  `json:",omitempty"` // names requested from the acme server
+	RenewalStatus string    `json:",omitempty"`
+	RenewalError  string    `json:",omitempty"` // error of the last failed attempt
+	LastAttempt   time.Time // time of the last attempt to issue the certificate
+	datastore.VersionedEntity
+}
+
+// ID returns the id of the certificate for a public endpoint
+func ID(certType, name string) string {
+	return certType + "-" + name
+}
+
+// New parses the pem encoded certificate and key and returns a certificate
+// for the public endpoint.
+func New(certType, name, serviceID, application, certPEM, keyPEM string) (*Certificate, error) {
+	cert := &Certificate{
+		ID:          ID(certType, name),
+		Type:        certType,
+		Name:        name,
+		ServiceID:   serviceID,
+		Application: application,
+	}
+	if err := cert.SetKeyPair(certPEM, keyPEM); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// NewACME returns a certificate for the public endpoint that is issued
+// for the domains by an acme server.
+func NewACME(certType, name, serviceID, application string, domains []string) *Certificate {
+	return &Certificate{
+		ID:            ID(certType, name),
+		Type:          certType,
+		Name:          name,
+		ServiceID:     serviceID,
+		Application:   application,
+		ACME:          true,
+		Domains:       domains,
+		RenewalStatus: RenewalPending,
+	}
+}
+
+// SetKeyPair parses the pem encoded certificate and key and updates the
+// certificate with them.
+func (c *Certificate) SetKeyPair(certPEM, keyPEM string) error {
+	pair, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		return err
+	} else if len(pair.Certificate) == 0 {
+		return ErrNoCertificate
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	subjects := []string{}
+	if leaf.Subject.CommonName != "" {
+		subjects = append(subjects, leaf.Subject.CommonName)
+	}
+	for _, name := range leaf.DNSNames {
+		if name != leaf.Subject.CommonName {
+			subjects = append(subjects, name)
+		}
+	}
+
+	c.CertPEM = certPEM
+	c.KeyPEM = keyPEM
+	c.Subjects = subjects
+	c.NotAfter = leaf.NotAfter
+	return nil
+}
+
+// NeedsRenewal returns true if an acme certificate should be issued, because
+// it is pending or expires within renewBefore.  Failed attempts are retried
+// after retryAfter.
+func (c *Certificate) NeedsRenewal(now time.Time, renewBefore, retryAfter time.Duration) bool {
+	if !c.ACME {
+		return false
+	}
+	if c.RenewalStatus == RenewalFailed && now.Before(c.LastAttempt.Add(retryAfter)) {
+		return false
+	}
+	if c.RenewalStatus == RenewalPending || c.CertPEM == "" {
+		return true
+	}
+	return now.After(c.NotAfter.Add(-renewBefore))
+}
+
+// KeyPair returns the parsed certificate and key
+func (c *Certificate) KeyPair() (tls.Certificate, error) {
+	return tls.X509KeyPair([]byte(c.CertPEM), []byte(c.KeyPEM))
+}
+
+// GetType returns the kind of the entity
+func GetType() string {
+	return kind
+}
+
+// GetType returns the kind of the entity
+func (c *Certificate) GetType() string {
+	return GetType()
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit integration
+
+package certificate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/proxy"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type unitTestSuite struct{}
+
+var _ = Suite(&unitTestSuite{})
+
+func (s *unitTestSuite) Test_GetType(c *C) {
+	c.Assert(GetType(), Equals, kind)
+	c.Assert((&Certificate{}).GetType(), Equals, kind)
+}
+
+func (s *unitTestSuite) Test_New(c *C) {
+	cert, err := New(TypeVHost, "zproxy", "svc1", "zproxy", proxy.InsecureCertPEM, proxy.InsecureKeyPEM)
+	c.Assert(err, IsNil)
+	c.Assert(cert.ID, Equals, "vhost-zproxy")
+	c.Assert(cert.Subjects, DeepEquals, []string{"ControlCenter"})
+	c.Assert(cert.NotAfter.IsZero(), Equals, false)
+
+	pair, err := cert.KeyPair()
+	c.Assert(err, IsNil)
+	c.Assert(pair.Certificate, HasLen, 1)
+}
+
+func (s *unitTestSuite) Test_NewMismatchedKey(c *C) {
+	_, err := New(TypeVHost, "zproxy", "svc1", "zproxy", proxy.InsecureCertPEM, "")
+	c.Assert(err, NotNil)
+
+	_, err = New(TypePort, ":2222", "svc1", "zproxy", "", proxy.InsecureKeyPEM)
+	c.Assert(err, NotNil)
+}
+
+func (s *unitTestSuite) Test_NeedsRenewal(c *C) {
+	now := time.Now()
+	day := 24 * time.Hour
+
+	// certificates set by the user are never renewed
+	cert := &Certificate{NotAfter: now}
+	c.Assert(cert.NeedsRenewal(now, 30*day, time.Hour), Equals, false)
+
+	cert = NewACME(TypeVHost, "zproxy", "svc1", "zproxy", []string{"zproxy.example.com"})
+	c.Assert(cert.NeedsRenewal(now, 30*day, time.Hour), Equals, true)
+
+	cert.RenewalStatus = RenewalValid
+	cert.CertPEM = "cert"
+	cert.NotAfter = now.Add(60 * day)
+	c.Assert(cert.NeedsRenewal(now, 30*day, time.Hour), Equals, false)
+	c.Assert(cert.NeedsRenewal(now.Add(31*day), 30*day, time.Hour), Equals, true)
+
+	// failed attempts are retried later
+	cert.RenewalStatus = RenewalFailed
+	cert.LastAttempt = now
+	c.Assert(cert.NeedsRenewal(now.Add(31*day), 30*day, 32*day), Equals, false)
+	c.Assert(cert.NeedsRenewal(now.Add(31*day), 30*day, time.Hour), Equals, true)
+}
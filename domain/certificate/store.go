@@ -0,0 +1,101 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+)
+
+// Store is the database for public endpoint certificates
+type Store interface {
+	// Get a Certificate by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Certificate, error)
+
+	// Put adds or updates a Certificate
+	Put(ctx datastore.Context, cert *Certificate) error
+
+	// Delete removes a Certificate if it exists
+	Delete(ctx datastore.Context, id string) error
+
+	// GetCertificates returns all Certificates
+	GetCertificates(ctx datastore.Context) ([]Certificate, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore returns a new certificate store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.Get"))
+	val := &Certificate{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (s *storeImpl) Put(ctx datastore.Context, cert *Certificate) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.Put"))
+	return s.ds.Put(ctx, Key(cert.ID), cert)
+}
+
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.Delete"))
+	return s.ds.Delete(ctx, Key(id))
+}
+
+func (s *storeImpl) GetCertificates(ctx datastore.Context) ([]Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.GetCertificates"))
+	q := datastore.NewQuery(ctx)
+
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]string{"type": kind},
+		},
+	}
+
+	search, err := elastic.BuildSearchRequest(query, "controlplane")
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key returns the datastore key of a certificate
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]Certificate, error) {
+	certs := make([]Certificate, results.Len())
+	for idx := range certs {
+		if err := results.Get(idx, &certs[idx]); err != nil {
+			return []Certificate{}, err
+		}
+	}
+	return certs, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package certificate
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/proxy"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_CertificateCRUD(c *C) {
+	cert, err := New(TypeVHost, "zproxy", "svc1", "zproxy", proxy.InsecureCertPEM, proxy.InsecureKeyPEM)
+	c.Assert(err, IsNil)
+
+	_, err = s.store.Get(s.ctx, cert.ID)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, cert)
+	c.Assert(err, IsNil)
+
+	actual, err := s.store.Get(s.ctx, cert.ID)
+	c.Assert(err, IsNil)
+	c.Assert(actual.CertPEM, Equals, cert.CertPEM)
+	c.Assert(actual.Subjects, DeepEquals, cert.Subjects)
+
+	certs, err := s.store.GetCertificates(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(certs, HasLen, 1)
+	c.Assert(certs[0].Name, Equals, "zproxy")
+
+	err = s.store.Delete(s.ctx, cert.ID)
+	c.Assert(err, IsNil)
+	_, err = s.store.Get(s.ctx, cert.ID)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates the fields of a Certificate
+func (c *Certificate) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Certificate.ID", c.ID))
+	violations.Add(validation.StringsEqual(c.ID, strings.TrimSpace(c.ID), "leading and trailing spaces not allowed for Certificate ID"))
+	violations.Add(validation.StringIn(c.Type, TypeVHost, TypePort))
+	violations.Add(validation.NotEmpty("Certificate.Name", c.Name))
+	violations.Add(validation.StringsEqual(c.ID, ID(c.Type, c.Name), "Certificate ID does not match its type and name"))
+	if c.ACME {
+		// acme certificates are stored before they are issued
+		if len(c.Domains) == 0 {
+			violations.Add(fmt.Errorf("no domains set for acme certificate %s", c.ID))
+		}
+		violations.Add(validation.StringIn(c.RenewalStatus, RenewalPending, RenewalValid, RenewalFailed))
+	} else {
+		violations.Add(validation.NotEmpty("Certificate.CertPEM", c.CertPEM))
+		violations.Add(validation.NotEmpty("Certificate.KeyPEM", c.KeyPEM))
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
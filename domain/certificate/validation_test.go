@@ -0,0 +1,52 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package certificate
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type validationSuite struct{}
+
+var _ = Suite(&validationSuite{})
+
+func (s *validationSuite) TestCertificate_Success(c *C) {
+	cert := Certificate{ID: "port-:2222", Type: TypePort, Name: ":2222", CertPEM: "cert", KeyPEM: "key"}
+	c.Assert(cert.ValidEntity(), IsNil)
+}
+
+func (s *validationSuite) TestCertificate_Invalid(c *C) {
+	cert := Certificate{ID: "vhost-zproxy", Type: "other", Name: "zproxy", CertPEM: "cert", KeyPEM: "key"}
+	c.Assert(cert.ValidEntity(), NotNil)
+
+	cert = Certificate{ID: "vhost-other", Type: TypeVHost, Name: "zproxy", CertPEM: "cert", KeyPEM: "key"}
+	c.Assert(cert.ValidEntity(), NotNil)
+
+	cert = Certificate{ID: "vhost-zproxy", Type: TypeVHost, Name: "zproxy", CertPEM: "cert"}
+	c.Assert(cert.ValidEntity(), NotNil)
+}
+
+func (s *validationSuite) TestCertificate_ACME(c *C) {
+	cert := NewACME(TypeVHost, "zproxy", "svc1", "zproxy", []string{"zproxy.example.com"})
+	c.Assert(cert.ValidEntity(), IsNil)
+
+	cert.Domains = nil
+	c.Assert(cert.ValidEntity(), NotNil)
+
+	cert = NewACME(TypeVHost, "zproxy", "svc1", "zproxy", []string{"zproxy.example.com"})
+	cert.RenewalStatus = "unknown"
+	c.Assert(cert.ValidEntity(), NotNil)
+}
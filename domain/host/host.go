@@ -75,7 +75,8 @@ type Host struct {
 	}
 	MonitoringProfile domain.MonitorProfile
 	datastore.VersionedEntity
-	NatIP string
+	NatIP   string
+	Drained bool // Whether instances are moved off of the host and no new ones are scheduled on it
 }
 
 //ReadHost is a minimal representation of hosts.
@@ -90,6 +91,7 @@ type ReadHost struct {
 	KernelRelease string
 	ServiceD      ReadServiced
 	IPs           []HostIPResource
+	Drained       bool
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -172,6 +174,9 @@ func (a *Host) Equals(b *Host) bool {
 	if a.NatIP != b.NatIP {
 		return false
 	}
+	if a.Drained != b.Drained {
+		return false
+	}
 
 	return true
 }
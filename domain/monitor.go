@@ -32,6 +32,26 @@ func (profile *MonitorProfile) Equals(that *MonitorProfile) bool {
 	return reflect.DeepEqual(profile, that)
 }
 
+// HasMetric returns true if a metric config of the profile has a metric with
+// the given id
+func (profile *MonitorProfile) HasMetric(id string) bool {
+	_, ok := profile.GetMetric(id)
+	return ok
+}
+
+// GetMetric returns the metric with the given id from the metric configs of
+// the profile
+func (profile *MonitorProfile) GetMetric(id string) (Metric, bool) {
+	for _, config := range profile.MetricConfigs {
+		for _, metric := range config.Metrics {
+			if metric.ID == id {
+				return metric, true
+			}
+		}
+	}
+	return Metric{}, false
+}
+
 //ReBuild metrics, graphs and thresholds with the new tags, also set graphs units based on datapoints
 func (profile *MonitorProfile) ReBuild(timeSpan string, tags map[string][]string) (*MonitorProfile, error) {
 	newProfile := MonitorProfile{
@@ -36,6 +36,16 @@ type VirtualIP struct {
 
 type Permission uint
 
+const (
+	// PreemptNever leaves instances that exceed the quotas of the pool
+	// waiting for resources; this is the default.
+	PreemptNever = "never"
+	// PreemptLowerPriority stops preemptible instances of lower priority
+	// services to make room for instances that exceed the quotas of the pool,
+	// or the memory capacity of its hosts if the pool has no quota.
+	PreemptLowerPriority = "lower-priority"
+)
+
 const (
 	AdminAccess Permission = 1 << iota
 	DFSAccess
@@ -53,6 +63,7 @@ type ResourcePool struct {
 	MemoryCapacity    uint64      // Amount (bytes) of RAM available as a sum of all memory on all hosts in the pool
 	MemoryCommitment  uint64      // Amount (bytes) of RAM committed to services
 	ConnectionTimeout int         // Wait delay on service rescheduling when an outage is reported (milliseconds)
+	PreemptionPolicy  string      // Whether instances over quota may preempt lower priority instances, "" = never
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	MonitoringProfile domain.MonitorProfile
@@ -125,6 +136,9 @@ func (a *ResourcePool) Equals(b *ResourcePool) bool {
 	if a.MemoryCommitment != b.MemoryCommitment {
 		return false
 	}
+	if a.PreemptionPolicy != b.PreemptionPolicy {
+		return false
+	}
 	if a.CreatedAt.Unix() != b.CreatedAt.Unix() {
 		return false
 	}
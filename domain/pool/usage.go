@@ -0,0 +1,95 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pool
+
+import "fmt"
+
+// Usage describes the resources committed to the running services of a pool
+// against the quotas and capacity of the pool.
+type Usage struct {
+	PoolID           string
+	CoreLimit        int    // Quota on the number of cores, 0 = unlimited
+	CoreCommitment   int    // Number of cores committed to running instances
+	CoreCapacity     int    // Number of cores on all hosts in the pool
+	MemoryLimit      uint64 // Quota on the amount (bytes) of RAM, 0 = unlimited
+	MemoryCommitment uint64 // Amount (bytes) of RAM committed to running instances
+	MemoryCapacity   uint64 // Amount (bytes) of RAM on all hosts in the pool
+}
+
+// NewUsage returns the usage of a pool with no resources committed.
+func NewUsage(p *ResourcePool) *Usage {
+	return &Usage{
+		PoolID:         p.ID,
+		CoreLimit:      p.CoreLimit,
+		CoreCapacity:   p.CoreCapacity,
+		MemoryLimit:    p.MemoryLimit,
+		MemoryCapacity: p.MemoryCapacity,
+	}
+}
+
+// Commit adds the resources of instances to the commitment of the pool.
+func (u *Usage) Commit(cores int, memory uint64, instances int) {
+	u.CoreCommitment += cores * instances
+	u.MemoryCommitment += memory * uint64(instances)
+}
+
+// Release removes the resources of instances from the commitment of the pool.
+func (u *Usage) Release(cores int, memory uint64, instances int) {
+	u.CoreCommitment -= cores * instances
+	if u.CoreCommitment < 0 {
+		u.CoreCommitment = 0
+	}
+	if freed := memory * uint64(instances); freed < u.MemoryCommitment {
+		u.MemoryCommitment -= freed
+	} else {
+		u.MemoryCommitment = 0
+	}
+}
+
+// Check returns a QuotaError if committing additional cores and memory would
+// exceed the quotas of the pool.
+func (u Usage) Check(cores int, memory uint64) error {
+	if u.CoreLimit > 0 && cores > 0 && u.CoreCommitment+cores > u.CoreLimit {
+		return &QuotaError{
+			PoolID:    u.PoolID,
+			Resource:  "cores",
+			Requested: uint64(cores),
+			Committed: uint64(u.CoreCommitment),
+			Limit:     uint64(u.CoreLimit),
+		}
+	}
+	if u.MemoryLimit > 0 && memory > 0 && u.MemoryCommitment+memory > u.MemoryLimit {
+		return &QuotaError{
+			PoolID:    u.PoolID,
+			Resource:  "memory",
+			Requested: memory,
+			Committed: u.MemoryCommitment,
+			Limit:     u.MemoryLimit,
+		}
+	}
+	return nil
+}
+
+// QuotaError is returned when scheduling would exceed the quota of a pool.
+type QuotaError struct {
+	PoolID    string
+	Resource  string
+	Requested uint64
+	Committed uint64
+	Limit     uint64
+}
+
+func (err *QuotaError) Error() string {
+	return fmt.Sprintf("pool %s %s quota exceeded: %d requested, %d of %d committed", err.PoolID, err.Resource, err.Requested, err.Committed, err.Limit)
+}
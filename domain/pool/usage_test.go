@@ -0,0 +1,45 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package pool
+
+import "testing"
+
+func TestUsageCheck(t *testing.T) {
+	u := NewUsage(&ResourcePool{ID: "default", CoreLimit: 4, MemoryLimit: 1024})
+	u.Commit(1, 256, 2)
+	if u.CoreCommitment != 2 || u.MemoryCommitment != 512 {
+		t.Fatalf("unexpected commitment: %d cores, %d bytes", u.CoreCommitment, u.MemoryCommitment)
+	}
+	if err := u.Check(2, 512); err != nil {
+		t.Errorf("expected commitment to fit, got %s", err)
+	}
+	err := u.Check(3, 0)
+	if qerr, ok := err.(*QuotaError); !ok || qerr.Resource != "cores" {
+		t.Errorf("expected a cores quota error, got %v", err)
+	}
+	err = u.Check(0, 513)
+	if qerr, ok := err.(*QuotaError); !ok || qerr.Resource != "memory" {
+		t.Errorf("expected a memory quota error, got %v", err)
+	}
+}
+
+func TestUsageCheckUnlimited(t *testing.T) {
+	u := NewUsage(&ResourcePool{ID: "default"})
+	u.Commit(64, 1<<40, 10)
+	if err := u.Check(64, 1<<40); err != nil {
+		t.Errorf("expected no quota, got %s", err)
+	}
+}
@@ -37,6 +37,16 @@ func (p *ResourcePool) ValidEntity() error {
 		violations.Add(validation.NewViolation(fmt.Sprintf("connection timeout cannot be less than 0")))
 	}
 
+	if p.CoreLimit < 0 {
+		violations.Add(validation.NewViolation(fmt.Sprintf("core limit cannot be less than 0")))
+	}
+
+	switch p.PreemptionPolicy {
+	case "", PreemptNever, PreemptLowerPriority:
+	default:
+		violations.Add(validation.NewViolation(fmt.Sprintf("preemption policy must be %s or %s", PreemptNever, PreemptLowerPriority)))
+	}
+
 	if len(violations.Errors) > 0 {
 		return violations
 	}
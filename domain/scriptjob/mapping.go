@@ -0,0 +1,45 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scriptjob
+
+import (
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/logging"
+)
+
+var (
+	kind          = "scriptjob"
+	plog          = logging.PackageLogger()
+	mappingString = `
+	{
+	  "properties":{
+		"ID":        {"type": "keyword", "index":"true"},
+		"ServiceID": {"type": "keyword", "index":"true"},
+		"FileName":  {"type": "keyword", "index":"true"},
+		"Script":    {"type": "text", "index":"false"},
+		"Status":    {"type": "keyword", "index":"true"},
+		"Steps":     {"type": "object", "enabled": false},
+		"CreatedAt": {"type": "date", "format": "date_optional_time"},
+		"UpdatedAt": {"type": "date", "format": "date_optional_time"}
+	  }
+	}`
+	// MAPPING is the elastic mapping for a script job
+	MAPPING, mappingError = elastic.NewMapping(mappingString)
+)
+
+func init() {
+	if mappingError != nil {
+		plog.WithError(mappingError).Fatal("error creating mapping for the scriptjob object")
+	}
+}
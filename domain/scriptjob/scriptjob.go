@@ -0,0 +1,103 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scriptjob
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/script"
+)
+
+// Status is the status of a script job
+type Status string
+
+const (
+	// StatusRunning means that the script is being run on the master
+	StatusRunning Status = "running"
+	// StatusSucceeded means that every step of the script succeeded
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed means that a step failed and the script can be resumed
+	StatusFailed Status = "failed"
+	// StatusRolledBack means that a step failed and a snapshot was restored
+	StatusRolledBack Status = "rolledback"
+	// StatusInterrupted means that the master stopped while running the script
+	StatusInterrupted Status = "interrupted"
+)
+
+// Job is a service script run on the master, with the progress of each of
+// its top-level steps
+type Job struct {
+	ID               string
+	ServiceID        string        // service the script is run against
+	FileName         string        // name of the script file as submitted
+	Script           string        // content of the script
+	NoOp             bool          // run through the script without modifying the system
+	Status           Status
+	Steps            []script.Step // progress of each top-level step
+	Error            string        `json:",omitempty"`
+	RestoredSnapshot string        `json:",omitempty"` // snapshot restored after a failure
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	datastore.VersionedEntity
+}
+
+// Resumable returns true if the job can continue from its first incomplete step
+func (j *Job) Resumable() bool {
+	return j.Status == StatusFailed || j.Status == StatusInterrupted
+}
+
+// NextStep returns the index of the first step that has not succeeded
+func (j *Job) NextStep() int {
+	for i, step := range j.Steps {
+		if step.Status != script.StepSucceeded {
+			return i
+		}
+	}
+	return len(j.Steps)
+}
+
+// Snapshots returns the snapshots taken by the steps that succeeded before
+// the given step, in order
+func (j *Job) Snapshots(before int) []string {
+	snapshots := []string{}
+	for _, step := range j.Steps[:before] {
+		if step.SnapshotID != "" {
+			snapshots = append(snapshots, step.SnapshotID)
+		}
+	}
+	return snapshots
+}
+
+// ImageID returns the image resolved by the last USE step that succeeded
+// before the given step
+func (j *Job) ImageID(before int) string {
+	imageID := ""
+	for _, step := range j.Steps[:before] {
+		if step.ImageID != "" {
+			imageID = step.ImageID
+		}
+	}
+	return imageID
+}
+
+// GetType returns the kind of the entity
+func GetType() string {
+	return kind
+}
+
+// GetType returns the kind of the entity
+func (j *Job) GetType() string {
+	return GetType()
+}
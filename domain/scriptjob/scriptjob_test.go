@@ -0,0 +1,67 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit integration
+
+package scriptjob
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/script"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type unitTestSuite struct{}
+
+var _ = Suite(&unitTestSuite{})
+
+func (s *unitTestSuite) Test_GetType(c *C) {
+	c.Assert(GetType(), Equals, kind)
+	c.Assert((&Job{}).GetType(), Equals, kind)
+}
+
+func (s *unitTestSuite) Test_Progress(c *C) {
+	job := &Job{
+		Status: StatusFailed,
+		Steps: []script.Step{
+			{LineNum: 1, Status: script.StepSucceeded, ImageID: "zenoss/core:5.0.2"},
+			{LineNum: 2, Status: script.StepSucceeded, SnapshotID: "snap1"},
+			{LineNum: 3, Status: script.StepFailed},
+			{LineNum: 4, Status: script.StepPending},
+		},
+	}
+	c.Assert(job.Resumable(), Equals, true)
+	c.Assert(job.NextStep(), Equals, 2)
+	c.Assert(job.Snapshots(job.NextStep()), DeepEquals, []string{"snap1"})
+	c.Assert(job.Snapshots(1), DeepEquals, []string{})
+	c.Assert(job.ImageID(job.NextStep()), Equals, "zenoss/core:5.0.2")
+	c.Assert(job.ImageID(0), Equals, "")
+
+	for i := range job.Steps {
+		job.Steps[i].Status = script.StepSucceeded
+	}
+	job.Status = StatusSucceeded
+	c.Assert(job.Resumable(), Equals, false)
+	c.Assert(job.NextStep(), Equals, 4)
+
+	job.Status = StatusRolledBack
+	c.Assert(job.Resumable(), Equals, false)
+	job.Status = StatusInterrupted
+	c.Assert(job.Resumable(), Equals, true)
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scriptjob
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+)
+
+// Store is the database for script jobs
+type Store interface {
+	// Get a Job by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Job, error)
+
+	// Put adds or updates a Job
+	Put(ctx datastore.Context, job *Job) error
+
+	// Delete removes a Job if it exists
+	Delete(ctx datastore.Context, id string) error
+
+	// GetJobs returns all Jobs
+	GetJobs(ctx datastore.Context) ([]Job, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore returns a new script job store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Job, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScriptJobStore.Get"))
+	val := &Job{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (s *storeImpl) Put(ctx datastore.Context, job *Job) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScriptJobStore.Put"))
+	return s.ds.Put(ctx, Key(job.ID), job)
+}
+
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScriptJobStore.Delete"))
+	return s.ds.Delete(ctx, Key(id))
+}
+
+func (s *storeImpl) GetJobs(ctx datastore.Context) ([]Job, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScriptJobStore.GetJobs"))
+	q := datastore.NewQuery(ctx)
+
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]string{"type": kind},
+		},
+	}
+
+	search, err := elastic.BuildSearchRequest(query, "controlplane")
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key returns the datastore key of a script job
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]Job, error) {
+	jobs := make([]Job, results.Len())
+	for idx := range jobs {
+		if err := results.Get(idx, &jobs[idx]); err != nil {
+			return []Job{}, err
+		}
+	}
+	return jobs, nil
+}
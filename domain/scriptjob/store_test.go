@@ -0,0 +1,77 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package scriptjob
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/script"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_JobCRUD(c *C) {
+	job := &Job{
+		ID:        "job1",
+		ServiceID: "svc1",
+		Script:    "REQUIRE_SVC",
+		Status:    StatusRunning,
+		Steps:     []script.Step{{LineNum: 1, Line: "REQUIRE_SVC", Status: script.StepRunning}},
+	}
+
+	_, err := s.store.Get(s.ctx, job.ID)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, job)
+	c.Assert(err, IsNil)
+
+	actual, err := s.store.Get(s.ctx, job.ID)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Steps, DeepEquals, job.Steps)
+
+	actual.Status = StatusSucceeded
+	actual.Steps[0].Status = script.StepSucceeded
+	err = s.store.Put(s.ctx, actual)
+	c.Assert(err, IsNil)
+
+	jobs, err := s.store.GetJobs(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(jobs, HasLen, 1)
+	c.Assert(jobs[0].Status, Equals, StatusSucceeded)
+
+	err = s.store.Delete(s.ctx, job.ID)
+	c.Assert(err, IsNil)
+	_, err = s.store.Get(s.ctx, job.ID)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scriptjob
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates the fields of a Job
+func (j *Job) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Job.ID", j.ID))
+	violations.Add(validation.StringsEqual(j.ID, strings.TrimSpace(j.ID), "leading and trailing spaces not allowed for Job ID"))
+	violations.Add(validation.NotEmpty("Job.Script", j.Script))
+	violations.Add(validation.StringIn(string(j.Status), string(StatusRunning), string(StatusSucceeded),
+		string(StatusFailed), string(StatusRolledBack), string(StatusInterrupted)))
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package scriptjob
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type validationSuite struct{}
+
+var _ = Suite(&validationSuite{})
+
+func (s *validationSuite) TestJob_Success(c *C) {
+	job := Job{ID: "job1", Script: "REQUIRE_SVC", Status: StatusRunning}
+	c.Assert(job.ValidEntity(), IsNil)
+}
+
+func (s *validationSuite) TestJob_Invalid(c *C) {
+	job := Job{ID: " job1", Script: "REQUIRE_SVC", Status: StatusRunning}
+	c.Assert(job.ValidEntity(), NotNil)
+
+	job = Job{ID: "job1", Status: StatusRunning}
+	c.Assert(job.ValidEntity(), NotNil)
+
+	job = Job{ID: "job1", Script: "REQUIRE_SVC", Status: "unknown"}
+	c.Assert(job.ValidEntity(), NotNil)
+}
@@ -13,6 +13,10 @@
 
 package service
 
+import (
+	svcdef "github.com/control-center/serviced/domain/servicedefinition"
+)
+
 // AggregateService is a lighter service object for providing aggregate service
 // status information
 type AggregateService struct {
@@ -36,6 +40,7 @@ type PublicEndpoint struct {
 	VHostName   string `json:",omitempty"`
 	PortAddress string `json:",omitempty"`
 	Enabled     bool
+	Policy      *svcdef.AccessPolicy `json:",omitempty"`
 }
 
 // BaseIPAssignment is a minimal service object that describes a service endpoint
@@ -47,6 +47,10 @@ type ServiceEndpoint struct {
 
 	// PortList is the list of enabled/disabled ports to assign to this endpoint.
 	PortList []svcdef.Port
+
+	// LoadBalancing is how an import spreads connections across the
+	// instances of the export.
+	LoadBalancing string
 }
 
 // BuildServiceEndpoint build a ServiceEndpoint from a EndpointDefinition
@@ -64,6 +68,7 @@ func BuildServiceEndpoint(epd svcdef.EndpointDefinition) ServiceEndpoint {
 	sep.VHosts = epd.VHosts
 	sep.VHostList = epd.VHostList
 	sep.PortList = epd.PortList
+	sep.LoadBalancing = epd.LoadBalancing
 
 	// run public ports through scrubber to allow for "almost correct" port addresses
 	for index, port := range sep.PortList {
@@ -113,33 +113,45 @@ func contextFilter(gs GetService) func(s *runtimeContext, prefix string) (map[st
 
 // EvaluateActionsTemplate parses and evaluates the Actions string of a service.
 func (service *Service) EvaluateActionsTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
-	for key, value := range service.Actions {
-		err, result := service.evaluateTemplate(gs, fc, instanceID, value)
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateActions()
+}
+
+func (e *evaluator) evaluateActions() error {
+	for key, value := range e.service.Actions {
+		result, err := e.evaluate(fmt.Sprintf("Actions[%q]", key), value)
 		if err != nil {
 			return err
 		}
 
 		if result != "" {
-			service.Actions[key] = result
+			e.service.Actions[key] = result
 		}
 	}
-	return
+	return nil
 }
 
 // EvaluateHostnameTemplate parses and evaluates the Hostname string of a service.
 func (service *Service) EvaluateHostnameTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
-	err, result := service.evaluateTemplate(gs, fc, instanceID, service.Hostname)
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateHostname()
+}
+
+func (e *evaluator) evaluateHostname() error {
+	result, err := e.evaluate("Hostname", e.service.Hostname)
 	if err == nil {
-		service.Hostname = result
+		e.service.Hostname = result
 	}
-	return
+	return err
 }
 
 // EvaluateVolumesTemplate parses and evaluates the ResourcePath string in
 // volumes of a service
 func (service *Service) EvaluateVolumesTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
-	for i, vol := range service.Volumes {
-		err, result := service.evaluateTemplate(gs, fc, instanceID, vol.ResourcePath)
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateVolumes()
+}
+
+func (e *evaluator) evaluateVolumes() error {
+	for i, vol := range e.service.Volumes {
+		result, err := e.evaluate(fmt.Sprintf("Volumes[%d].ResourcePath", i), vol.ResourcePath)
 		if err != nil {
 			return err
 		}
@@ -147,48 +159,86 @@ func (service *Service) EvaluateVolumesTemplate(gs GetService, fc FindChildServi
 		if result != "" {
 			vol.ResourcePath = result
 		}
-		service.Volumes[i] = vol
+		e.service.Volumes[i] = vol
 	}
-	return
+	return nil
 }
 
 // EvaluateStartupTemplate parses and evaluates the StartUp string of a service.
 func (service *Service) EvaluateStartupTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
-	err, result := service.evaluateTemplate(gs, fc, instanceID, service.Startup)
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateStartup()
+}
+
+func (e *evaluator) evaluateStartup() error {
+	result, err := e.evaluate("Startup", e.service.Startup)
 	if err == nil && result != "" {
-		service.Startup = result
+		e.service.Startup = result
 	}
-	return
+	return err
 }
 
 // EvaluateRunsTemplate parses and evaluates the Runs string of a service.
 func (service *Service) EvaluateRunsTemplate(gs GetService, fc FindChildService) (err error) {
-	for key, value := range service.Runs {
-		err, result := service.evaluateTemplate(gs, fc, 0, value)
+	return newEvaluator(service, gs, fc, nil, 0).evaluateRuns()
+}
+
+func (e *evaluator) evaluateRuns() error {
+	for key, value := range e.service.Runs {
+		result, err := e.evaluate(fmt.Sprintf("Runs[%q]", key), value)
 		if err != nil {
 			return err
 		}
 		if result != "" {
-			service.Runs[key] = result
+			e.service.Runs[key] = result
 		}
 	}
-	for key, value := range service.Commands {
-		err, result := service.evaluateTemplate(gs, fc, 0, value.Command)
+	for key, value := range e.service.Commands {
+		result, err := e.evaluate(fmt.Sprintf("Commands[%q].Command", key), value.Command)
 		if err != nil {
 			return err
 		}
 		if result != "" {
 			value.Command = result
-			service.Commands[key] = value
+			e.service.Commands[key] = value
 		}
 	}
-	return
+	return nil
+}
+
+// ParseTemplate checks that a service definition template is well formed and
+// only calls functions that are available when it is evaluated.
+func ParseTemplate(serviceTemplate string) error {
+	e := newEvaluator(&Service{}, nil, nil, nil, 0)
+	_, err := template.New("ServiceDefinitionTemplate").Funcs(e.functions()).Parse(serviceTemplate)
+	return err
+}
+
+// evaluator evaluates the templated fields of a service instance
+type evaluator struct {
+	service    *Service
+	gs         GetService
+	fc         FindChildService
+	gh         GetInstanceHost
+	instanceID int
+	host       *HostFacts // cached result of gh
+}
+
+func newEvaluator(service *Service, gs GetService, fc FindChildService, gh GetInstanceHost, instanceID int) *evaluator {
+	return &evaluator{
+		service:    service,
+		gs:         gs,
+		fc:         fc,
+		gh:         gh,
+		instanceID: instanceID,
+	}
 }
 
-// evaluateTemplate takes a control center client and template string and evaluates
-// the template using the service as the context. If the template is invalid or there is an error
-// then an empty string is returned.
-func (service *Service) evaluateTemplate(gs GetService, fc FindChildService, instanceID int, serviceTemplate string) (err error, result string) {
+// evaluate takes a template string and evaluates it using the service as the
+// context. name identifies the templated field, e.g.
+// ConfigFiles["/etc/my.cnf"].Content, so that errors point to the template
+// that failed. If the template is invalid or there is an error then an empty
+// string is returned.
+func (e *evaluator) evaluate(name, serviceTemplate string) (result string, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			if _, ok := r.(runtime.Error); ok {
@@ -199,25 +249,12 @@ func (service *Service) evaluateTemplate(gs GetService, fc FindChildService, ins
 		}
 	}()
 
-	functions := template.FuncMap{
-		"parent":        parent(gs),
-		"child":         child(fc),
-		"context":       context(gs),
-		"getContext":    getContext(gs),
-		"contextFilter": contextFilter(gs),
-		"percentScale":  percentScale,
-		"bytesToMB":     bytesToMB,
-		"plus":          plus,
-		"uintToInt":     uintToInt,
-		"each":          each,
-	}
-
 	// parse the template
-	t := template.Must(template.New("ServiceDefinitionTemplate").Funcs(functions).Parse(serviceTemplate))
+	t := template.Must(template.New(name).Funcs(e.functions()).Parse(serviceTemplate))
 
 	// evaluate it
 	var buffer bytes.Buffer
-	ctx := newRuntimeContext(service, instanceID)
+	ctx := newRuntimeContext(e.service, e.instanceID)
 	err = t.Execute(&buffer, ctx)
 	if err == nil {
 		result = buffer.String()
@@ -225,65 +262,77 @@ func (service *Service) evaluateTemplate(gs GetService, fc FindChildService, ins
 	}
 
 	// something went wrong, warn them
-	log.WithField("template", serviceTemplate).WithError(err).Warning("Template evaluation produced an error")
+	log.WithFields(log.Fields{
+		"serviceid": e.service.ID,
+		"field":     name,
+		"template":  serviceTemplate,
+	}).WithError(err).Warning("Template evaluation produced an error")
 	return
 }
 
 // EvaluateLogConfigTemplate parses and evals the Path, Type and all the values for the tags of the log
 // configs. This happens for each LogConfig on the service.
 func (service *Service) EvaluateLogConfigTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateLogConfigs()
+}
+
+func (e *evaluator) evaluateLogConfigs() error {
 	log.WithFields(log.Fields{
-		"servicename": service.Name,
-		"serviceid": service.ID,
-		"instanceid": instanceID,
+		"servicename": e.service.Name,
+		"serviceid":   e.service.ID,
+		"instanceid":  e.instanceID,
 	}).Debug("Evaluating LogConfig Files")
 
 	// evaluate the template for the LogConfig as well as the tags
-	for i, logConfig := range service.LogConfigs {
+	for i, logConfig := range e.service.LogConfigs {
 		// Path
-		err, result := service.evaluateTemplate(gs, fc, instanceID, logConfig.Path)
+		result, err := e.evaluate(fmt.Sprintf("LogConfigs[%d].Path", i), logConfig.Path)
 		if err != nil {
 			return err
 		}
 		if result != "" {
-			service.LogConfigs[i].Path = result
+			e.service.LogConfigs[i].Path = result
 		}
 
 		// Type
-		err, result = service.evaluateTemplate(gs, fc, instanceID, logConfig.Type)
+		result, err = e.evaluate(fmt.Sprintf("LogConfigs[%d].Type", i), logConfig.Type)
 		if err != nil {
 			return err
 		}
 		if result != "" {
-			service.LogConfigs[i].Type = result
+			e.service.LogConfigs[i].Type = result
 		}
 
 		// Tags
 		for j, tag := range logConfig.LogTags {
-			err, result = service.evaluateTemplate(gs, fc, instanceID, tag.Value)
+			result, err = e.evaluate(fmt.Sprintf("LogConfigs[%d].LogTags[%d].Value", i, j), tag.Value)
 			if err != nil {
 				return err
 			}
 			if result != "" {
-				service.LogConfigs[i].LogTags[j].Value = result
+				e.service.LogConfigs[i].LogTags[j].Value = result
 			}
 		}
 	}
-	return
+	return nil
 }
 
 // EvaluateConfigFilesTemplate parses and evals the Filename and Content. This happens for each
 // ConfigFile on the service.
 func (service *Service) EvaluateConfigFilesTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateConfigFiles()
+}
+
+func (e *evaluator) evaluateConfigFiles() error {
 	log.WithFields(log.Fields{
-		"servicename": service.Name,
-		"serviceid": service.ID,
-		"instanceid": instanceID,
+		"servicename": e.service.Name,
+		"serviceid":   e.service.ID,
+		"instanceid":  e.instanceID,
 	}).Debug("Evaluating Config Files")
 
-	for key, configFile := range service.ConfigFiles {
+	for key, configFile := range e.service.ConfigFiles {
 		// Filename
-		err, result := service.evaluateTemplate(gs, fc, instanceID, configFile.Filename)
+		result, err := e.evaluate(fmt.Sprintf("ConfigFiles[%q].Filename", key), configFile.Filename)
 		if err != nil {
 			return err
 		}
@@ -291,58 +340,66 @@ func (service *Service) EvaluateConfigFilesTemplate(gs GetService, fc FindChildS
 			configFile.Filename = result
 		}
 		// Content
-		err, result = service.evaluateTemplate(gs, fc, instanceID, configFile.Content)
+		result, err = e.evaluate(fmt.Sprintf("ConfigFiles[%q].Content", key), configFile.Content)
 		if err != nil {
 			return err
 		}
 		if result != "" {
 			configFile.Content = result
 		}
-		service.ConfigFiles[key] = configFile
+		e.service.ConfigFiles[key] = configFile
 	}
-	return
+	return nil
 }
 
 // EvaluatePrereqsTemplate parses and evals the Script field for each Prereq.
 func (service *Service) EvaluatePrereqsTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluatePrereqs()
+}
+
+func (e *evaluator) evaluatePrereqs() error {
 	log.WithFields(log.Fields{
-		"servicename": service.Name,
-		"serviceid": service.ID,
-		"instanceid": instanceID,
+		"servicename": e.service.Name,
+		"serviceid":   e.service.ID,
+		"instanceid":  e.instanceID,
 	}).Debug("Evaluating Prereq scripts")
 
-	for i, prereq := range service.Prereqs {
-		err, result := service.evaluateTemplate(gs, fc, instanceID, prereq.Script)
+	for i, prereq := range e.service.Prereqs {
+		result, err := e.evaluate(fmt.Sprintf("Prereqs[%d].Script", i), prereq.Script)
 		if err != nil {
 			return err
 		}
 		if result != "" {
 			prereq.Script = result
-			service.Prereqs[i] = prereq
+			e.service.Prereqs[i] = prereq
 		}
 	}
-	return
+	return nil
 }
 
 // EvaluateHealthCheckTemplate parses and evals the Script field for each HealthCheck.
 func (service *Service) EvaluateHealthCheckTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateHealthChecks()
+}
+
+func (e *evaluator) evaluateHealthChecks() error {
 	log.WithFields(log.Fields{
-		"servicename": service.Name,
-		"serviceid": service.ID,
-		"instanceid": instanceID,
+		"servicename": e.service.Name,
+		"serviceid":   e.service.ID,
+		"instanceid":  e.instanceID,
 	}).Debug("Evaluating HealthCheck scripts")
 
-	for key, healthcheck := range service.HealthChecks {
-		err, result := service.evaluateTemplate(gs, fc, instanceID, healthcheck.Script)
+	for key, healthcheck := range e.service.HealthChecks {
+		result, err := e.evaluate(fmt.Sprintf("HealthChecks[%q].Script", key), healthcheck.Script)
 		if err != nil {
 			return err
 		}
 		if result != "" {
 			healthcheck.Script = result
-			service.HealthChecks[key] = healthcheck
+			e.service.HealthChecks[key] = healthcheck
 		}
 	}
-	return
+	return nil
 }
 
 func percentScale(x uint64, percentage float64) uint64 {
@@ -382,26 +439,30 @@ func Round(value float64) int64 {
 // EvaluateEndpointTemplates parses and evaluates the "ApplicationTemplate" property
 // of each of the service endpoints for this service.
 func (service *Service) EvaluateEndpointTemplates(gs GetService, fc FindChildService, instanceID int) (err error) {
-	for i, ep := range service.Endpoints {
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateEndpoints()
+}
+
+func (e *evaluator) evaluateEndpoints() error {
+	for i, ep := range e.service.Endpoints {
 		//cache the application template (assumes this is called after service creation from svc definition)
 		if ep.Application != "" && ep.ApplicationTemplate == "" {
 			ep.ApplicationTemplate = ep.Application
-			service.Endpoints[i].ApplicationTemplate = ep.Application
+			e.service.Endpoints[i].ApplicationTemplate = ep.Application
 		}
 
 		if ep.ApplicationTemplate != "" {
-			err, result := service.evaluateTemplate(gs, fc, instanceID, ep.ApplicationTemplate)
+			result, err := e.evaluate(fmt.Sprintf("Endpoints[%d].ApplicationTemplate", i), ep.ApplicationTemplate)
 			if err != nil {
 				return err
 			}
 			if result != "" {
-				service.Endpoints[i].Application = result
+				e.service.Endpoints[i].Application = result
 			}
 		}
 
 		// we only want to evaluate exports
 		if ep.PortTemplate != "" && ep.Purpose == "export" {
-			err, result := service.evaluateTemplate(gs, fc, instanceID, ep.PortTemplate)
+			result, err := e.evaluate(fmt.Sprintf("Endpoints[%d].PortTemplate", i), ep.PortTemplate)
 			if err != nil {
 				return err
 			}
@@ -413,26 +474,30 @@ func (service *Service) EvaluateEndpointTemplates(gs GetService, fc FindChildSer
 				if i < 0 {
 					return fmt.Errorf("For port template %q, the value %d is invalid: must be non-negative", ep.PortTemplate, portNumber)
 				}
-				service.Endpoints[i].PortNumber = uint16(portNumber)
+				e.service.Endpoints[i].PortNumber = uint16(portNumber)
 			}
 		}
 	}
-	return
+	return nil
 }
 
 // EvaluateEndpointTemplates parses and evaluates the "Environment" property of
 // this service.
 func (service *Service) EvaluateEnvironmentTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
-	for i, envvar := range service.Environment {
-		err, result := service.evaluateTemplate(gs, fc, instanceID, envvar)
+	return newEvaluator(service, gs, fc, nil, instanceID).evaluateEnvironment()
+}
+
+func (e *evaluator) evaluateEnvironment() error {
+	for i, envvar := range e.service.Environment {
+		result, err := e.evaluate(fmt.Sprintf("Environment[%d]", i), envvar)
 		if err != nil {
 			return err
 		}
 		if result != "" {
-			service.Environment[i] = result
+			e.service.Environment[i] = result
 		}
 	}
-	return
+	return nil
 }
 
 // runtimeContext wraps a service and adds extra fields for template evaluation.
@@ -459,49 +524,32 @@ func newRuntimeContext(svc *Service, instanceID int) *runtimeContext {
 // a runtimeContext with the current Service embedded, and adding instanceID
 // as an extra attribute.
 func (service *Service) Evaluate(getSvc GetService, findChild FindChildService, instanceID int) (err error) {
-	if err = service.EvaluateEndpointTemplates(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateLogConfigTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateConfigFilesTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateStartupTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateRunsTemplate(getSvc, findChild); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateActionsTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateHostnameTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateVolumesTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluatePrereqsTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateHealthCheckTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
-	}
-	if err = service.EvaluateEnvironmentTemplate(getSvc, findChild, instanceID); err != nil {
-		plog.WithError(err).Error()
-		return err
+	return service.EvaluateWithHost(getSvc, findChild, nil, instanceID)
+}
+
+// EvaluateWithHost evaluates all the fields of the Service like Evaluate, and
+// also makes facts about the host running the instance available to the
+// templates.
+func (service *Service) EvaluateWithHost(getSvc GetService, findChild FindChildService, getHost GetInstanceHost, instanceID int) (err error) {
+	e := newEvaluator(service, getSvc, findChild, getHost, instanceID)
+	for _, evaluate := range []func() error{
+		e.evaluateEndpoints,
+		e.evaluateLogConfigs,
+		e.evaluateConfigFiles,
+		e.evaluateStartup,
+		// commands are not specific to an instance
+		newEvaluator(service, getSvc, findChild, getHost, 0).evaluateRuns,
+		e.evaluateActions,
+		e.evaluateHostname,
+		e.evaluateVolumes,
+		e.evaluatePrereqs,
+		e.evaluateHealthChecks,
+		e.evaluateEnvironment,
+	} {
+		if err = evaluate(); err != nil {
+			plog.WithError(err).Error()
+			return err
+		}
 	}
 	return nil
 }
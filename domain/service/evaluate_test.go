@@ -40,3 +40,10 @@ func (s *ServiceDomainUnitTestSuite) TestRound(c *C) {
 		c.Assert(service.Round(test.value), Equals, test.expected)
 	}
 }
+
+func (s *ServiceDomainUnitTestSuite) TestParseTemplate(c *C) {
+	c.Assert(service.ParseTemplate(`{{(parent .).Name}} {{plus 1 .InstanceID}}`), IsNil)
+	c.Assert(service.ParseTemplate(`{{percentScale .RAMCommitment 0.5}}`), IsNil)
+	c.Assert(service.ParseTemplate(`{{shout .Name}}`), ErrorMatches, `.*function "shout" not defined`)
+	c.Assert(service.ParseTemplate(`{{.Name`), NotNil)
+}
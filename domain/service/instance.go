@@ -35,6 +35,7 @@ const (
 	StatePendingRestart    InstanceCurrentState = "pending_restart"
 	StateEmergencyStopping InstanceCurrentState = "emergency_stopping"
 	StateEmergencyStopped  InstanceCurrentState = "emergency_stopped"
+	StateBackoff           InstanceCurrentState = "backoff"
 )
 
 // Usage describes the current, max, and avg values of an instance
@@ -62,17 +63,21 @@ type Instance struct {
 	Scheduled     time.Time
 	Started       time.Time
 	Terminated    time.Time
+	Restarts      int // times the restart policy restarted the container after it exited
 }
 
 // StrategyInstance collects service strategy information about a service
 // instance.
 type StrategyInstance struct {
-	HostID        string
-	ServiceID     string
-	CPUCommitment int
-	RAMCommitment uint64
-	RAMThreshold  uint
-	HostPolicy    servicedefinition.HostPolicy
+	HostID         string
+	ServiceID      string
+	InstanceID     int
+	CPUCommitment  int
+	RAMCommitment  uint64
+	RAMThreshold   uint
+	HostPolicy     servicedefinition.HostPolicy
+	Priority       int
+	NonPreemptible bool
 }
 
 // LocationInstance collection location information about a service instance
@@ -0,0 +1,88 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+
+	svcdef "github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// InstanceOverride is configuration that applies to a single instance of a
+// service in place of the service's own configuration.
+type InstanceOverride struct {
+	Context     map[string]interface{}       // Merged over the service's context
+	ConfigFiles map[string]svcdef.ConfigFile // Replace the service's config files with the same name
+	Environment []string                     // Replace or add to the service's environment variables
+	MemoryLimit float64                      // Replaces the service's memory limit, if set
+}
+
+// IsEmpty returns true if the override does not change anything
+func (o InstanceOverride) IsEmpty() bool {
+	return len(o.Context) == 0 && len(o.ConfigFiles) == 0 && len(o.Environment) == 0 && o.MemoryLimit == 0
+}
+
+// ApplyInstanceOverride replaces the configuration of the service with the
+// overrides for the given instance, if there are any. It is applied before the
+// service is evaluated, so overridden values may themselves be templates.
+func (s *Service) ApplyInstanceOverride(instanceID int) {
+	override, ok := s.InstanceOverrides[instanceID]
+	if !ok {
+		return
+	}
+
+	if len(override.Context) > 0 {
+		ctx := make(map[string]interface{})
+		for k, v := range s.Context {
+			ctx[k] = v
+		}
+		for k, v := range override.Context {
+			ctx[k] = v
+		}
+		s.Context = ctx
+	}
+
+	if len(override.ConfigFiles) > 0 {
+		configFiles := make(map[string]svcdef.ConfigFile)
+		for k, v := range s.ConfigFiles {
+			configFiles[k] = v
+		}
+		for k, v := range override.ConfigFiles {
+			configFiles[k] = v
+		}
+		s.ConfigFiles = configFiles
+	}
+
+	if len(override.Environment) > 0 {
+		env := append([]string{}, s.Environment...)
+		for _, variable := range override.Environment {
+			name := strings.SplitN(variable, "=", 2)[0]
+			replaced := false
+			for i := range env {
+				if strings.SplitN(env[i], "=", 2)[0] == name {
+					env[i] = variable
+					replaced = true
+				}
+			}
+			if !replaced {
+				env = append(env, variable)
+			}
+		}
+		s.Environment = env
+	}
+
+	if override.MemoryLimit != 0 {
+		s.MemoryLimit = override.MemoryLimit
+	}
+}
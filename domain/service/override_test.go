@@ -0,0 +1,72 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) TestApplyInstanceOverride(c *C) {
+	newService := func() *service.Service {
+		return &service.Service{
+			Context:     map[string]interface{}{"role": "replica", "port": 8080},
+			Environment: []string{"ROLE=replica", "DEBUG=0"},
+			ConfigFiles: map[string]servicedefinition.ConfigFile{
+				"/etc/app.conf": {Filename: "/etc/app.conf", Content: "replica"},
+				"/etc/log.conf": {Filename: "/etc/log.conf", Content: "log"},
+			},
+			MemoryLimit: 1024,
+			InstanceOverrides: map[int]service.InstanceOverride{
+				0: {
+					Context:     map[string]interface{}{"role": "primary"},
+					Environment: []string{"ROLE=primary", "PRIMARY=1"},
+					ConfigFiles: map[string]servicedefinition.ConfigFile{
+						"/etc/app.conf": {Filename: "/etc/app.conf", Content: "primary"},
+					},
+					MemoryLimit: 4096,
+				},
+			},
+		}
+	}
+
+	svc := newService()
+	svc.ApplyInstanceOverride(0)
+	c.Assert(svc.Context, DeepEquals, map[string]interface{}{"role": "primary", "port": 8080})
+	c.Assert(svc.Environment, DeepEquals, []string{"ROLE=primary", "DEBUG=0", "PRIMARY=1"})
+	c.Assert(svc.ConfigFiles["/etc/app.conf"].Content, Equals, "primary")
+	c.Assert(svc.ConfigFiles["/etc/log.conf"].Content, Equals, "log")
+	c.Assert(svc.MemoryLimit, Equals, float64(4096))
+
+	// other instances keep the service's configuration
+	svc = newService()
+	svc.ApplyInstanceOverride(1)
+	c.Assert(svc, DeepEquals, newService())
+}
+
+func (s *ServiceDomainUnitTestSuite) TestApplyInstanceOverride_DoesNotShareMaps(c *C) {
+	context := map[string]interface{}{"role": "replica"}
+	svc := &service.Service{
+		Context: context,
+		InstanceOverrides: map[int]service.InstanceOverride{
+			2: {Context: map[string]interface{}{"role": "primary"}},
+		},
+	}
+	svc.ApplyInstanceOverride(2)
+	c.Assert(svc.Context["role"], Equals, "primary")
+	c.Assert(context["role"], Equals, "replica")
+}
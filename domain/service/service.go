@@ -262,12 +262,16 @@ type Service struct {
 	ConfigFiles     map[string]svcdef.ConfigFile
 	Instances       int
 	InstanceLimits  domain.MinMax
+	Autoscale       []svcdef.AutoscaleRule
 	ChangeOptions   []svcdef.ChangeOption
 	ImageID         string
 	PoolID          string
 	DesiredState    int
 	CurrentState    string
 	HostPolicy      svcdef.HostPolicy
+	RestartPolicy   svcdef.RestartPolicy
+	Priority        int
+	NonPreemptible  bool
 	Hostname        string
 	Privileged      bool
 	Launch          string
@@ -299,6 +303,9 @@ type Service struct {
 	OomScoreAdj       int64
 	PIDFile           string
 
+	// ResourceLimits are hard limits on the container of each instance
+	svcdef.ResourceLimits
+
 	// StartLevel represents the order in which services are started and stopped
 	// in normal operations.  All services of a given level start before any services
 	// at higher levels.  Stopping services occurs in the reverse order.  Services
@@ -317,6 +324,10 @@ type Service struct {
 	// EmergencyShutdown is a flag that indicates whether this service has been shutdown due
 	// to an emergency (low-storage) situation.  Services with this flag set can not be started
 	EmergencyShutdown bool
+
+	// InstanceOverrides replace parts of the service's configuration for
+	// specific instances, keyed by instance id.
+	InstanceOverrides map[int]InstanceOverride
 	datastore.VersionedEntity
 }
 
@@ -370,12 +381,16 @@ func BuildService(
 		svc.Instances = sd.Instances.Min
 	}
 	svc.InstanceLimits = sd.Instances
+	svc.Autoscale = sd.Autoscale
 	svc.ChangeOptions = sd.ChangeOptions
 	svc.ImageID = sd.ImageID
 	svc.PoolID = poolID
 	svc.DesiredState = desiredState
 	svc.Launch = sd.Launch
 	svc.HostPolicy = sd.HostPolicy
+	svc.RestartPolicy = sd.RestartPolicy
+	svc.Priority = sd.Priority
+	svc.NonPreemptible = sd.NonPreemptible
 	svc.Hostname = sd.Hostname
 	svc.Privileged = sd.Privileged
 	svc.OriginalConfigs = sd.ConfigFiles
@@ -417,6 +432,7 @@ func BuildService(
 	svc.MonitoringProfile = *profile
 	svc.MemoryLimit = sd.MemoryLimit
 	svc.CPUShares = sd.CPUShares
+	svc.ResourceLimits = sd.ResourceLimits
 
 	return &svc, nil
 }
@@ -709,6 +725,23 @@ func (s *Service) EnablePort(application string, portAddr string, enable bool) e
 	return nil
 }
 
+// SetPortPolicy sets the access policy of a port for given service
+func (s *Service) SetPortPolicy(application, portAddr string, policy svcdef.AccessPolicy) error {
+	for i := range s.Endpoints {
+		ep := &s.Endpoints[i]
+		if ep.Application == application && ep.Purpose == "export" {
+			for j := range ep.PortList {
+				if ep.PortList[j].PortAddr == portAddr {
+					ep.PortList[j].Policy = policy
+					return nil
+				}
+			}
+			return fmt.Errorf("port %s not found in service %s:%s", portAddr, s.ID, s.Name)
+		}
+	}
+	return fmt.Errorf("port %s not found; application %s not found in service %s:%s", portAddr, application, s.ID, s.Name)
+}
+
 // ScrubPortString makes a best effort to produce a valid port address.
 func ScrubPortString(port string) string {
 	// remove possible protocol at string beginning
@@ -756,6 +789,24 @@ func (s *Service) EnableVirtualHost(application, vhostName string, enable bool)
 	return nil
 }
 
+// SetVirtualHostPolicy sets the access policy of a virtual host for given
+// service
+func (s *Service) SetVirtualHostPolicy(application, vhostName string, policy svcdef.AccessPolicy) error {
+	for i := range s.Endpoints {
+		ep := &s.Endpoints[i]
+		if ep.Application == application && ep.Purpose == "export" {
+			for j := range ep.VHostList {
+				if strings.EqualFold(ep.VHostList[j].Name, vhostName) {
+					ep.VHostList[j].Policy = policy
+					return nil
+				}
+			}
+			return fmt.Errorf("vhost %s not found in service %s:%s", vhostName, s.ID, s.Name)
+		}
+	}
+	return fmt.Errorf("vhost %s not found; application %s not found in service %s:%s", vhostName, application, s.ID, s.Name)
+}
+
 // RemoveVirtualHost Remove a virtual host for given service
 func (s *Service) RemoveVirtualHost(application, vhostName string) error {
 	if s.Endpoints != nil {
@@ -882,9 +933,24 @@ func (s *Service) Equals(b *Service) bool {
 	if s.HostPolicy != b.HostPolicy {
 		return false
 	}
+	if s.RestartPolicy != b.RestartPolicy {
+		return false
+	}
+	if s.Priority != b.Priority {
+		return false
+	}
+	if s.NonPreemptible != b.NonPreemptible {
+		return false
+	}
+	if !reflect.DeepEqual(s.Autoscale, b.Autoscale) {
+		return false
+	}
 	if s.ParentServiceID != b.ParentServiceID {
 		return false
 	}
+	if !reflect.DeepEqual(s.InstanceOverrides, b.InstanceOverrides) {
+		return false
+	}
 	if s.CreatedAt.Unix() != b.CreatedAt.Unix() {
 		return false
 	}
@@ -170,6 +170,29 @@ func (s *S) TestRemovePort(t *C) {
 	t.Assert(err, NotNil)
 }
 
+func (s *S) TestSetPolicy(t *C) {
+	svc := Service{
+		Endpoints: []ServiceEndpoint{
+			BuildServiceEndpoint(
+				servicedefinition.EndpointDefinition{
+					Purpose:     "export",
+					Application: "server",
+					VHostList:   []servicedefinition.VHost{{Name: "server"}},
+					PortList:    []servicedefinition.Port{{PortAddr: ":1234"}},
+				}),
+		},
+	}
+	policy := servicedefinition.AccessPolicy{AllowCIDRs: []string{"10.0.0.0/8"}, MaxConnections: 10}
+
+	t.Assert(svc.SetPortPolicy("server", ":1234", policy), IsNil)
+	t.Assert(svc.Endpoints[0].PortList[0].Policy, DeepEquals, policy)
+	t.Assert(svc.SetVirtualHostPolicy("server", "SERVER", policy), IsNil)
+	t.Assert(svc.Endpoints[0].VHostList[0].Policy, DeepEquals, policy)
+
+	t.Assert(svc.SetPortPolicy("server", ":4321", policy), NotNil)
+	t.Assert(svc.SetVirtualHostPolicy("other", "server", policy), NotNil)
+}
+
 func (s *S) TestCloneService(t *C) {
 	svc := &Service{
 		ID:           "testserviceidwithatleasttwelvecharacters",
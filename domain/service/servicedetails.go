@@ -15,6 +15,9 @@ package service
 
 import (
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/control-center/serviced/datastore"
@@ -111,3 +114,52 @@ func (s *ServiceDetails) Equals(b *ServiceDetails) bool {
 	}
 	return true
 }
+
+// ServicePaths returns a map of the lowercase full path of each service to its id
+func ServicePaths(svcs []ServiceDetails) map[string]string {
+	svcMap := make(map[string]ServiceDetails)
+	for _, svc := range svcs {
+		svcMap[svc.ID] = svc
+	}
+
+	// recursively build full path for all services
+	pathmap := make(map[string]string) //path to service id
+	for _, svc := range svcs {
+		fullpath := svc.Name
+		parentServiceID := svc.ParentServiceID
+
+		for parentServiceID != "" {
+			fullpath = path.Join(svcMap[parentServiceID].Name, fullpath)
+			parentServiceID = svcMap[parentServiceID].ParentServiceID
+		}
+		pathmap[strings.ToLower(fullpath)] = svc.ID
+	}
+	return pathmap
+}
+
+// ServiceIDFromPath returns the id of the service at a full path, regardless
+// of case
+func ServiceIDFromPath(svcs []ServiceDetails, svcPath string) (string, error) {
+	svcID, found := ServicePaths(svcs)[strings.ToLower(svcPath)]
+	if !found {
+		return "", fmt.Errorf("did not find service %s", svcPath)
+	}
+	return svcID, nil
+}
+
+// ChildServicePaths returns the sorted paths of the children of the service
+// at a full path.  The paths are joined to the path of the parent as given.
+func ChildServicePaths(svcs []ServiceDetails, svcPath string) ([]string, error) {
+	svcID, err := ServiceIDFromPath(svcs, svcPath)
+	if err != nil {
+		return nil, err
+	}
+	children := []string{}
+	for _, svc := range svcs {
+		if svc.ParentServiceID == svcID {
+			children = append(children, path.Join(svcPath, svc.Name))
+		}
+	}
+	sort.Strings(children)
+	return children, nil
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/datastore/elastic"
+	svcdef "github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 )
 
@@ -449,6 +450,15 @@ func (s *storeImpl) fillDetailsVolatileInfo(d *ServiceDetails) {
 	}
 }
 
+// publicEndpointPolicy returns the access policy of a public endpoint, or nil
+// if the policy does not restrict anything
+func publicEndpointPolicy(policy svcdef.AccessPolicy) *svcdef.AccessPolicy {
+	if policy.IsEmpty() {
+		return nil
+	}
+	return &policy
+}
+
 func createPublicEndpoints(result EndpointQueryResult) []PublicEndpoint {
 	pubs := []PublicEndpoint{}
 
@@ -461,6 +471,7 @@ func createPublicEndpoints(result EndpointQueryResult) []PublicEndpoint {
 				Protocol:    "https",
 				VHostName:   vhost.Name,
 				Enabled:     vhost.Enabled,
+				Policy:      publicEndpointPolicy(vhost.Policy),
 			})
 		}
 
@@ -471,9 +482,11 @@ func createPublicEndpoints(result EndpointQueryResult) []PublicEndpoint {
 				Application: ep.Application,
 				PortAddress: port.PortAddr,
 				Enabled:     port.Enabled,
+				Policy:      publicEndpointPolicy(port.Policy),
 			}
 
-			if strings.HasPrefix(port.Protocol, "http") {
+			if strings.HasPrefix(port.Protocol, "http") || port.Protocol == svcdef.PortProtocolUDP ||
+				port.Protocol == svcdef.PortProtocolTLSPassthrough {
 				pub.Protocol = port.Protocol
 			} else if port.UseTLS {
 				pub.Protocol = "Other, secure (TLS)"
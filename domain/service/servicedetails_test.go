@@ -145,3 +145,33 @@ func (s *ServiceDomainUnitTestSuite) TestValidServiceDetailsWithZeroInstances(c
 
 	c.Assert(details.ValidEntity(), IsNil)
 }
+
+func (s *ServiceDomainUnitTestSuite) TestServicePaths(c *C) {
+	svcs := []service.ServiceDetails{
+		{ID: "zope", Name: "Zope", ParentServiceID: "core"},
+		{ID: "core", Name: "Zenoss.core"},
+		{ID: "mariadb", Name: "MariaDB", ParentServiceID: "infra"},
+		{ID: "infra", Name: "Infrastructure", ParentServiceID: "core"},
+		{ID: "redis", Name: "redis", ParentServiceID: "infra"},
+	}
+	c.Assert(service.ServicePaths(svcs), DeepEquals, map[string]string{
+		"zenoss.core":                        "core",
+		"zenoss.core/zope":                   "zope",
+		"zenoss.core/infrastructure":         "infra",
+		"zenoss.core/infrastructure/mariadb": "mariadb",
+		"zenoss.core/infrastructure/redis":   "redis",
+	})
+
+	svcID, err := service.ServiceIDFromPath(svcs, "Zenoss.core/infrastructure/MariaDB")
+	c.Assert(err, IsNil)
+	c.Assert(svcID, Equals, "mariadb")
+	_, err = service.ServiceIDFromPath(svcs, "Zenoss.core/Nope")
+	c.Assert(err, ErrorMatches, "did not find service Zenoss.core/Nope")
+
+	children, err := service.ChildServicePaths(svcs, "zenoss.core/Infrastructure")
+	c.Assert(err, IsNil)
+	c.Assert(children, DeepEquals, []string{"zenoss.core/Infrastructure/MariaDB", "zenoss.core/Infrastructure/redis"})
+	children, err = service.ChildServicePaths(svcs, "Zenoss.core/Zope")
+	c.Assert(err, IsNil)
+	c.Assert(children, DeepEquals, []string{})
+}
@@ -28,6 +28,7 @@ var (
 	"Name":            {"type": "keyword", "index":"true"},
 	"Startup":         {"type": "keyword", "index":"true"},
 	"Context":         {"type": "object", "enabled":"false"},
+	"InstanceOverrides": {"type": "object", "enabled":"false"},
 	"Description":     {"type": "keyword", "index":"true"},
 	"DeploymentID":    {"type": "keyword", "index":"true"},
 	"Environment":     {"type": "keyword", "index":"true"},
@@ -0,0 +1,276 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// HostFacts describes the host running a service instance
+type HostFacts struct {
+	ID     string
+	Name   string
+	IPAddr string
+	Cores  int
+	Memory uint64 // bytes
+}
+
+// GetInstanceHost looks up the host running an instance of a service
+type GetInstanceHost func(serviceID string, instanceID int) (HostFacts, error)
+
+// functions returns the functions available to service definition templates.
+//
+// Service tree:
+//
+//	parent .                     the parent service
+//	child . "name"               the child service with the given name
+//	context .                    the context of the service and its parents
+//	getContext . "key"           a single value from the context
+//	contextFilter . "prefix"     the context values whose keys have the prefix,
+//	                             with the prefix removed
+//
+// Host running the instance:
+//
+//	hostIP, hostName             the address and name of the host
+//	hostCores                    the number of cores available on the host
+//	hostMemory                   the bytes of memory available on the host
+//
+// Endpoints:
+//
+//	importAddress "application"  the address an imported application is
+//	                             reached at from inside the container
+//	importPort "application"     the port of an imported application
+//
+// Numbers:
+//
+//	percentScale x 0.5, bytesToMB x, plus a b, uintToInt x, each n
+//
+// Strings and lists (the string being operated on comes last, so that they
+// can be used in pipelines, e.g. {{.Name | replace "-" "_" | toUpper}}):
+//
+//	toUpper, toLower, trim, trimPrefix "p", trimSuffix "s",
+//	replace "old" "new", split "sep", join "sep", contains "sub",
+//	hasPrefix "p", hasSuffix "s", list a b c
+//
+// Encoding and defaults:
+//
+//	b64enc, b64dec               base64 encode and decode
+//	default "value" x            x, or "value" if x is empty
+//	empty x                      true if x is nil, zero or has no elements
+func (e *evaluator) functions() template.FuncMap {
+	return template.FuncMap{
+		"parent":        parent(e.gs),
+		"child":         child(e.fc),
+		"context":       context(e.gs),
+		"getContext":    getContext(e.gs),
+		"contextFilter": contextFilter(e.gs),
+		"percentScale":  percentScale,
+		"bytesToMB":     bytesToMB,
+		"plus":          plus,
+		"uintToInt":     uintToInt,
+		"each":          each,
+
+		"hostIP":     e.hostIP,
+		"hostName":   e.hostName,
+		"hostCores":  e.hostCores,
+		"hostMemory": e.hostMemory,
+
+		"importAddress": e.importAddress,
+		"importPort":    e.importPort,
+
+		"toUpper":    strings.ToUpper,
+		"toLower":    strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": trimPrefix,
+		"trimSuffix": trimSuffix,
+		"replace":    replace,
+		"split":      split,
+		"join":       join,
+		"contains":   containsString,
+		"hasPrefix":  hasPrefix,
+		"hasSuffix":  hasSuffix,
+		"list":       list,
+
+		"b64enc":  b64enc,
+		"b64dec":  b64dec,
+		"default": defaultValue,
+		"empty":   empty,
+	}
+}
+
+// hostFacts looks up the host running the instance being evaluated
+func (e *evaluator) hostFacts() (*HostFacts, error) {
+	if e.host != nil {
+		return e.host, nil
+	}
+	if e.gh == nil {
+		return nil, fmt.Errorf("host facts are not available for service %s instance %d", e.service.ID, e.instanceID)
+	}
+	host, err := e.gh(e.service.ID, e.instanceID)
+	if err != nil {
+		return nil, err
+	}
+	e.host = &host
+	return e.host, nil
+}
+
+func (e *evaluator) hostIP() (string, error) {
+	host, err := e.hostFacts()
+	if err != nil {
+		return "", err
+	}
+	return host.IPAddr, nil
+}
+
+func (e *evaluator) hostName() (string, error) {
+	host, err := e.hostFacts()
+	if err != nil {
+		return "", err
+	}
+	return host.Name, nil
+}
+
+func (e *evaluator) hostCores() (int, error) {
+	host, err := e.hostFacts()
+	if err != nil {
+		return 0, err
+	}
+	return host.Cores, nil
+}
+
+func (e *evaluator) hostMemory() (uint64, error) {
+	host, err := e.hostFacts()
+	if err != nil {
+		return 0, err
+	}
+	return host.Memory, nil
+}
+
+// importedEndpoint returns the endpoint the service imports the application
+// with
+func (e *evaluator) importedEndpoint(application string) (*ServiceEndpoint, error) {
+	for i, ep := range e.service.Endpoints {
+		if strings.HasPrefix(ep.Purpose, "import") && ep.Application == application {
+			return &e.service.Endpoints[i], nil
+		}
+	}
+	return nil, fmt.Errorf("service %s does not import application %s", e.service.Name, application)
+}
+
+func (e *evaluator) importAddress(application string) (string, error) {
+	ep, err := e.importedEndpoint(application)
+	if err != nil {
+		return "", err
+	}
+	if ep.VirtualAddress != "" {
+		return ep.VirtualAddress, nil
+	}
+	if ep.PortNumber == 0 {
+		return "", fmt.Errorf("import of application %s has no port", application)
+	}
+	return fmt.Sprintf("localhost:%d", ep.PortNumber), nil
+}
+
+func (e *evaluator) importPort(application string) (int, error) {
+	ep, err := e.importedEndpoint(application)
+	if err != nil {
+		return 0, err
+	}
+	if ep.PortNumber == 0 {
+		return 0, fmt.Errorf("import of application %s has no port", application)
+	}
+	return int(ep.PortNumber), nil
+}
+
+func trimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+func trimSuffix(suffix, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+func replace(old, new, s string) string {
+	return strings.Replace(s, old, new, -1)
+}
+
+func split(sep, s string) []string {
+	return strings.Split(s, sep)
+}
+
+func join(sep string, items interface{}) (string, error) {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("join expects a list, got %T", items)
+	}
+	strs := make([]string, v.Len())
+	for i := range strs {
+		strs[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(strs, sep), nil
+}
+
+func containsString(substr, s string) bool {
+	return strings.Contains(s, substr)
+}
+
+func hasPrefix(prefix, s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+func hasSuffix(suffix, s string) bool {
+	return strings.HasSuffix(s, suffix)
+}
+
+func list(items ...interface{}) []interface{} {
+	return items
+}
+
+func b64enc(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func b64dec(s string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func defaultValue(def, value interface{}) interface{} {
+	if empty(value) {
+		return def
+	}
+	return value
+}
+
+func empty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return reflect.DeepEqual(value, reflect.Zero(v.Type()).Interface())
+	}
+}
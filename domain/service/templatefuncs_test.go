@@ -0,0 +1,103 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) newTemplateService(content string) *service.Service {
+	return &service.Service{
+		ID:   "svc1",
+		Name: "zope",
+		Context: map[string]interface{}{
+			"user": "zenoss",
+		},
+		Endpoints: []service.ServiceEndpoint{
+			{Purpose: "import", Application: "zodb", PortNumber: 3306},
+			{Purpose: "import", Application: "redis", VirtualAddress: "redis:6379"},
+		},
+		ConfigFiles: map[string]servicedefinition.ConfigFile{
+			"/etc/zope.conf": {Filename: "/etc/zope.conf", Content: content},
+		},
+	}
+}
+
+func (s *ServiceDomainUnitTestSuite) evaluateTemplate(c *C, content string) (string, error) {
+	svc := s.newTemplateService(content)
+	getSvc := func(serviceID string) (service.Service, error) { return *svc, nil }
+	findChild := func(parentID, childName string) (service.Service, error) { return service.Service{}, nil }
+	getHost := func(serviceID string, instanceID int) (service.HostFacts, error) {
+		c.Assert(serviceID, Equals, "svc1")
+		c.Assert(instanceID, Equals, 2)
+		return service.HostFacts{ID: "host1", Name: "node1", IPAddr: "10.0.0.5", Cores: 8, Memory: 16 * 1024 * 1024 * 1024}, nil
+	}
+	err := svc.EvaluateWithHost(getSvc, findChild, getHost, 2)
+	return svc.ConfigFiles["/etc/zope.conf"].Content, err
+}
+
+func (s *ServiceDomainUnitTestSuite) TestTemplateFunctions(c *C) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{`{{hostIP}} {{hostName}} {{hostCores}} {{bytesToMB hostMemory}}`, "10.0.0.5 node1 8 16384"},
+		{`{{importAddress "zodb"}} {{importPort "zodb"}} {{importAddress "redis"}}`, "localhost:3306 3306 redis:6379"},
+		{`{{.Name | toUpper}} {{"A-B-C" | replace "-" "_" | toLower}} {{trim "  x  "}}`, "ZOPE a_b_c x"},
+		{`{{"zenoss-" | trimSuffix "-"}} {{"/opt/zenoss" | trimPrefix "/opt/"}}`, "zenoss zenoss"},
+		{`{{"a,b,c" | split "," | join ";"}} {{list 1 2 3 | join ","}}`, "a;b;c 1,2,3"},
+		{`{{contains "ope" .Name}} {{hasPrefix "zo" .Name}} {{hasSuffix "x" .Name}}`, "true true false"},
+		{`{{b64enc "admin:zenoss"}} {{b64dec "emVub3Nz"}}`, "YWRtaW46emVub3Nz zenoss"},
+		{`{{getContext . "user" | default "root"}} {{getContext . "missing" | default "root"}}`, "zenoss root"},
+		{`{{empty ""}} {{empty 0}} {{empty (list)}} {{empty "x"}}`, "true true true false"},
+	}
+	for _, test := range tests {
+		actual, err := s.evaluateTemplate(c, test.template)
+		c.Assert(err, IsNil, Commentf("template %s", test.template))
+		c.Check(actual, Equals, test.expected, Commentf("template %s", test.template))
+	}
+}
+
+func (s *ServiceDomainUnitTestSuite) TestTemplateFunctions_Errors(c *C) {
+	_, err := s.evaluateTemplate(c, `{{importAddress "mariadb"}}`)
+	c.Assert(err, ErrorMatches, `template: ConfigFiles\["/etc/zope.conf"\].Content:1:2: .*service zope does not import application mariadb`)
+
+	_, err = s.evaluateTemplate(c, `{{b64dec "!"}}`)
+	c.Assert(err, ErrorMatches, `template: ConfigFiles\["/etc/zope.conf"\].Content:1:2: .*illegal base64 data.*`)
+
+	_, err = s.evaluateTemplate(c, `{{join "," .Name}}`)
+	c.Assert(err, ErrorMatches, `.*join expects a list, got string`)
+
+	_, err = s.evaluateTemplate(c, `{{undefined}}`)
+	c.Assert(err, ErrorMatches, `template: ConfigFiles\["/etc/zope.conf"\].Content:1: function "undefined" not defined`)
+}
+
+func (s *ServiceDomainUnitTestSuite) TestTemplateFunctions_NoHost(c *C) {
+	svc := s.newTemplateService(`{{hostIP}}`)
+	err := svc.EvaluateConfigFilesTemplate(nil, nil, 1)
+	c.Assert(err, ErrorMatches, `.*host facts are not available for service svc1 instance 1`)
+
+	svc = s.newTemplateService(`{{hostIP}}`)
+	getHost := func(serviceID string, instanceID int) (service.HostFacts, error) {
+		return service.HostFacts{}, errors.New("instance not found")
+	}
+	err = svc.EvaluateWithHost(nil, nil, getHost, 1)
+	c.Assert(err, ErrorMatches, `.*instance not found`)
+}
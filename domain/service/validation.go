@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"github.com/control-center/serviced/commons"
+	svcdef "github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/validation"
 )
 
@@ -46,14 +47,29 @@ func (s *Service) ValidEntity() error {
 	// validate the monitoring profile
 	vErr.Add(s.MonitoringProfile.ValidEntity())
 
+	vErr.Add(svcdef.ValidAutoscaleRules(s.Autoscale, s.InstanceLimits, s.MonitoringProfile))
+
 	for _, ep := range s.Endpoints {
 		vErr.Add(ep.ValidEntity())
 	}
 
+	vErr.Add(s.RestartPolicy.ValidEntity())
+
+	vErr.Add(s.ResourceLimits.ValidEntity())
+
 	for _, hc := range s.HealthChecks {
 		vErr.Add(hc.ValidEntity())
 	}
 
+	for instanceID, override := range s.InstanceOverrides {
+		if instanceID < 0 {
+			vErr.Add(fmt.Errorf("Instance override for invalid instance %d", instanceID))
+		}
+		if override.MemoryLimit < 0 {
+			vErr.Add(fmt.Errorf("Instance override for instance %d has a negative memory limit", instanceID))
+		}
+	}
+
 	if vErr.HasError() {
 		return vErr
 	}
@@ -72,6 +88,7 @@ func (endpoint ServiceEndpoint) ValidEntity() error {
 	}
 
 	violations.Add(validation.NotEmpty("endpoint.Application", endpoint.Application))
+	violations.Add(validation.StringIn(endpoint.LoadBalancing, "", svcdef.LoadBalanceRoundRobin, svcdef.LoadBalanceLeastConn, svcdef.LoadBalanceHash))
 
 	if violations.HasError() {
 		return violations
@@ -16,7 +16,11 @@ package servicedefinition
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain"
@@ -40,13 +44,17 @@ type ServiceDefinition struct {
 	Environment []string // Environment variables to be injected, of the form NAME="value"
 	Tags        []string // Searchable service tags
 
-	ImageID       string         // Docker image hosting the service
-	Instances     domain.MinMax  // Constraints on the number of instances
-	ChangeOptions []ChangeOption // Control options for what happens when a running service is changed
-	Launch        string         // Must be "AUTO", the default, or "MANUAL"
-	HostPolicy    HostPolicy     // Policy for starting up instances
-	Hostname      string         // Optional hostname which should be set on run
-	Privileged    bool           // Whether to run the container with extended privileges
+	ImageID        string          // Docker image hosting the service
+	Instances      domain.MinMax   // Constraints on the number of instances
+	Autoscale      []AutoscaleRule // Rules for scaling instances within the constraints
+	ChangeOptions  []ChangeOption  // Control options for what happens when a running service is changed
+	Launch         string          // Must be "AUTO", the default, or "MANUAL"
+	HostPolicy     HostPolicy      // Policy for starting up instances
+	RestartPolicy  RestartPolicy   // Policy for restarting instances whose container exited
+	Priority       int             // Instances of higher priority services may preempt lower ones in a full pool
+	NonPreemptible bool            // Whether the instances of the service must never be preempted
+	Hostname       string          // Optional hostname which should be set on run
+	Privileged     bool            // Whether to run the container with extended privileges
 
 	ConfigFiles map[string]ConfigFile  // Config file templates
 	Context     map[string]interface{} // Context information for the service
@@ -76,6 +84,7 @@ type ServiceDefinition struct {
 	CPUShares         int64
 	OomKillDisable    bool  // Whether to disable OOM Killer for the container or not
 	OomScoreAdj       int64 // Tune containers OOM preferences (-1000 to 1000)
+	ResourceLimits          // Hard CPU, block I/O and process limits enforced on the container
 
 	PIDFile string // An optional path or command to generate a path for a PID file to which signals are relayed.
 
@@ -108,20 +117,78 @@ type EndpointDefinition struct {
 
 	VHostList []VHost // VHost is used to request named vhost(s) for this endpoint.
 	PortList  []Port
+
+	// LoadBalancing is how an import spreads connections across the
+	// instances of the export: roundrobin (default), leastconn or hash.
+	LoadBalancing string
 }
 
+// Session affinity modes for vhosts and public ports
+const (
+	AffinityCookie   = "cookie"   // pin each browser to an instance with a cookie
+	AffinitySourceIP = "sourceip" // pin each client IP to an instance
+)
+
+// Load balancing modes for import endpoints
+const (
+	LoadBalanceRoundRobin = "roundrobin" // cycle through the healthy instances
+	LoadBalanceLeastConn  = "leastconn"  // pick the instance with the fewest open connections
+	LoadBalanceHash       = "hash"       // pin each client IP to an instance
+)
+
+// Protocols of public ports besides http and https.  Ports with an empty
+// protocol forward tcp.
+const (
+	PortProtocolUDP            = "udp"             // forward datagrams
+	PortProtocolTLSPassthrough = "tls-passthrough" // forward tls to instances that terminate it, by server name
+)
+
 // VHost is the configuration for an application endpoint that wants an http VHost endpoint provided by Control Center
 type VHost struct {
-	Name    string // name of the vhost subdomain subdomain, i.e "myapplication"  not "myapplication.host.com
-	Enabled bool   // whether the vhost should be enabled or disabled.
+	Name             string  // name of the vhost subdomain subdomain, i.e "myapplication"  not "myapplication.host.com
+	Enabled          bool    // whether the vhost should be enabled or disabled.
+	Affinity         string  // pin clients to an instance by cookie or sourceip; empty to balance every request
+	DisableRetry     bool    // do not retry idempotent requests on another instance when a connection fails
+	DisableAccessLog bool    // do not write requests to the access log
+	AccessLogSample  float64 // fraction of requests written to the access log; 0 writes every request
+	Policy           AccessPolicy
 }
 
 // Port is the configuration for an application endpoint port.
 type Port struct {
-	PortAddr string // which port number to use for this endpoint
-	Enabled  bool   // whether the port should be enabled or disabled.
-	UseTLS   bool   // Does this port endpoint use tls.
-	Protocol string // What protocol (if any) does the endpoind use.
+	PortAddr     string // which port number to use for this endpoint
+	Enabled      bool   // whether the port should be enabled or disabled.
+	UseTLS       bool   // Does this port endpoint use tls.
+	Protocol     string // What protocol (if any) does the endpoind use.
+	Affinity     string // pin clients to an instance by cookie (http only) or sourceip
+	DisableRetry bool   // do not retry on another instance when a connection fails
+
+	// server names (SNI) a tls-passthrough port accepts, e.g. "db.example.com"
+	// or "*.example.com"; empty accepts every server name
+	ServerNames []string `json:",omitempty"`
+
+	// access log of http and https ports
+	DisableAccessLog bool    // do not write requests to the access log
+	AccessLogSample  float64 // fraction of requests written to the access log; 0 writes every request
+
+	Policy AccessPolicy
+}
+
+// AccessPolicy restricts which clients may use a vhost or public port, and
+// how hard they may use it.  Limits of 0 are not enforced.
+type AccessPolicy struct {
+	AllowCIDRs     []string `json:",omitempty"` // networks of the clients that may connect; empty allows every client
+	DenyCIDRs      []string `json:",omitempty"` // networks of the clients that are refused, even if allowed
+	ConnectionRate float64  `json:",omitempty"` // new connections per second from each client IP (ports only)
+	RequestRate    float64  `json:",omitempty"` // http requests per second from each client IP
+	RateBurst      int      `json:",omitempty"` // connections or requests a client may make at once; defaults to the rate
+	MaxConnections int      `json:",omitempty"` // open connections on a port, or requests in flight on a vhost
+}
+
+// IsEmpty returns true if the policy does not restrict anything
+func (p AccessPolicy) IsEmpty() bool {
+	return len(p.AllowCIDRs) == 0 && len(p.DenyCIDRs) == 0 && p.ConnectionRate == 0 &&
+		p.RequestRate == 0 && p.MaxConnections == 0
 }
 
 // Volume import defines a file system directory underneath an export directory
@@ -148,7 +215,7 @@ func GetConfigFileType() string {
 	return "serviceconfigurationfile"
 }
 
-//AddressResourceConfig defines an external facing port for a service definition
+// AddressResourceConfig defines an external facing port for a service definition
 type AddressResourceConfig struct {
 	Port     uint16
 	Protocol string
@@ -203,6 +270,243 @@ func (p *HostPolicy) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// Conditions on which the restart policy restarts an exited container
+const (
+	RestartAlways    = "always"     // restart every exited container (default)
+	RestartOnFailure = "on-failure" // restart containers that exited with a non-zero code
+	RestartNever     = "never"      // leave exited containers stopped
+)
+
+// DefaultMaxRestartBackoff caps the restart backoff if the policy does not
+const DefaultMaxRestartBackoff = 5 * time.Minute
+
+// RestartPolicy describes when the host agent restarts an instance whose
+// container exited, and how long it waits before doing so.  The zero value
+// restarts every container immediately.
+type RestartPolicy struct {
+	Condition   string        // always, on-failure or never
+	MaxRestarts int           // restarts allowed within the window; 0 is unlimited
+	Window      time.Duration // period over which restarts are counted; 0 counts every restart
+	Backoff     time.Duration // delay before the first restart, doubled on each consecutive one
+	MaxBackoff  time.Duration // cap on the delay; defaults to DefaultMaxRestartBackoff
+}
+
+// Restarts returns true if a container that exited with the given code
+// should be restarted.  Unknown exit codes are treated as failures.
+func (p RestartPolicy) Restarts(exitCode int) bool {
+	switch p.Condition {
+	case RestartNever:
+		return false
+	case RestartOnFailure:
+		return exitCode != 0
+	default:
+		return true
+	}
+}
+
+// MaxDelay returns the cap on the restart backoff.  A container that ran for
+// at least this long before exiting starts over at the initial backoff.
+func (p RestartPolicy) MaxDelay() time.Duration {
+	if p.MaxBackoff > 0 {
+		return p.MaxBackoff
+	}
+	return DefaultMaxRestartBackoff
+}
+
+// Delay returns how long to wait before restarting a container that has
+// already been restarted the given number of consecutive times.
+func (p RestartPolicy) Delay(failures int) time.Duration {
+	if p.Backoff <= 0 {
+		return 0
+	}
+	max := p.MaxDelay()
+	delay := p.Backoff
+	for i := 0; i < failures && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+type jsonRestartPolicy struct {
+	Condition   string  `json:",omitempty"`
+	MaxRestarts int     `json:",omitempty"`
+	Window      float64 `json:",omitempty"`
+	Backoff     float64 `json:",omitempty"`
+	MaxBackoff  float64 `json:",omitempty"`
+}
+
+// MarshalJSON implements json.Marshaller, writing durations in seconds
+func (p RestartPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonRestartPolicy{
+		Condition:   p.Condition,
+		MaxRestarts: p.MaxRestarts,
+		Window:      p.Window.Seconds(),
+		Backoff:     p.Backoff.Seconds(),
+		MaxBackoff:  p.MaxBackoff.Seconds(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaller, reading durations in seconds
+func (p *RestartPolicy) UnmarshalJSON(data []byte) error {
+	jp := jsonRestartPolicy{}
+	if err := json.Unmarshal(data, &jp); err != nil {
+		return err
+	}
+	*p = RestartPolicy{
+		Condition:   jp.Condition,
+		MaxRestarts: jp.MaxRestarts,
+		Window:      time.Duration(jp.Window * float64(time.Second)),
+		Backoff:     time.Duration(jp.Backoff * float64(time.Second)),
+		MaxBackoff:  time.Duration(jp.MaxBackoff * float64(time.Second)),
+	}
+	return nil
+}
+
+// ResourceLimits are hard limits the host agent enforces on the container
+// of each instance through its cgroups.  Zero values leave the resource
+// unlimited.
+type ResourceLimits struct {
+	CPUQuota            float64       // CPU time the container may use, in cores
+	CPUSet              string        // CPUs the container may run on, e.g. "0-3,6"
+	BlkioWeight         uint16        // Relative block I/O weight, from 10 to 1000
+	BlkioDeviceReadBps  []DeviceLimit // Bytes per second the container may read from each device
+	BlkioDeviceWriteBps []DeviceLimit // Bytes per second the container may write to each device
+}
+
+// DeviceLimit limits the rate of block I/O on a device
+type DeviceLimit struct {
+	Path string            // Path of the device on the host, e.g. /dev/sda
+	Rate utils.EngNotation // Bytes per second, e.g. 10M
+}
+
+// String returns the limit as PATH:RATE
+func (l DeviceLimit) String() string {
+	return fmt.Sprintf("%s:%d", l.Path, l.Rate.Value)
+}
+
+// ParseDeviceLimit parses a device limit of the form PATH:RATE
+func ParseDeviceLimit(in string) (DeviceLimit, error) {
+	i := strings.LastIndex(in, ":")
+	if i <= 0 {
+		return DeviceLimit{}, fmt.Errorf("device limit %q is not of the form PATH:RATE", in)
+	}
+	rate, err := utils.NewEngNotationFromString(in[i+1:])
+	if err != nil {
+		return DeviceLimit{}, fmt.Errorf("device limit %q has an invalid rate: %s", in, err)
+	}
+	return DeviceLimit{Path: in[:i], Rate: rate}, nil
+}
+
+// ParseCPUSet returns the CPUs in a list of CPU numbers and ranges, such as
+// "0-3,6".  The CPUs are returned in the order they are listed.
+func ParseCPUSet(in string) ([]int, error) {
+	var cpus []int
+	if strings.TrimSpace(in) == "" {
+		return cpus, nil
+	}
+	for _, part := range strings.Split(in, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("invalid cpu %q in cpu set %q", bounds[0], in)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return nil, fmt.Errorf("invalid cpu range %q in cpu set %q", part, in)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// Defaults of autoscale rules that do not set a window or cooldown
+const (
+	DefaultAutoscaleWindow   = 5 * time.Minute
+	DefaultAutoscaleCooldown = 5 * time.Minute
+)
+
+// AutoscaleTolerance is how far the metric may stray from the target, as a
+// fraction of the target, before the instances are scaled.
+const AutoscaleTolerance = 0.1
+
+// AutoscaleRule scales the instances of a service to keep the average value
+// of a metric per instance near a target.
+type AutoscaleRule struct {
+	Metric   string        // ID of a metric in the service's monitoring profile
+	Target   float64       // desired average value of the metric per instance
+	Window   time.Duration // period the metric is averaged over; defaults to DefaultAutoscaleWindow
+	Cooldown time.Duration // minimum time between scaling the service; defaults to DefaultAutoscaleCooldown
+}
+
+// GetWindow returns the period the metric is averaged over
+func (r AutoscaleRule) GetWindow() time.Duration {
+	if r.Window > 0 {
+		return r.Window
+	}
+	return DefaultAutoscaleWindow
+}
+
+// GetCooldown returns the minimum time between scaling the service
+func (r AutoscaleRule) GetCooldown() time.Duration {
+	if r.Cooldown > 0 {
+		return r.Cooldown
+	}
+	return DefaultAutoscaleCooldown
+}
+
+// Instances returns the number of instances that would bring the average of
+// the metric per instance to the target, given its average value across the
+// current instances.  The count is not bounded by the service's limits.
+func (r AutoscaleRule) Instances(current int, value float64) int {
+	if current <= 0 || r.Target <= 0 {
+		return current
+	}
+	ratio := value / r.Target
+	if math.Abs(ratio-1) <= AutoscaleTolerance {
+		return current
+	}
+	return int(math.Ceil(float64(current) * ratio))
+}
+
+type jsonAutoscaleRule struct {
+	Metric   string
+	Target   float64
+	Window   float64 `json:",omitempty"`
+	Cooldown float64 `json:",omitempty"`
+}
+
+// MarshalJSON implements json.Marshaller, writing durations in seconds
+func (r AutoscaleRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonAutoscaleRule{
+		Metric:   r.Metric,
+		Target:   r.Target,
+		Window:   r.Window.Seconds(),
+		Cooldown: r.Cooldown.Seconds(),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaller, reading durations in seconds
+func (r *AutoscaleRule) UnmarshalJSON(data []byte) error {
+	jr := jsonAutoscaleRule{}
+	if err := json.Unmarshal(data, &jr); err != nil {
+		return err
+	}
+	*r = AutoscaleRule{
+		Metric:   jr.Metric,
+		Target:   jr.Target,
+		Window:   time.Duration(jr.Window * float64(time.Second)),
+		Cooldown: time.Duration(jr.Cooldown * float64(time.Second)),
+	}
+	return nil
+}
+
 // ChangeOption is the policy for what happens in the scheduler Sync when the running services change
 type ChangeOption string
 
@@ -313,7 +617,7 @@ func (s ServiceDefinition) String() string {
 	return s.Name
 }
 
-//BuildFromPath given a path will create a ServiceDefintion
+// BuildFromPath given a path will create a ServiceDefintion
 func BuildFromPath(path string) (*ServiceDefinition, error) {
 	sd, err := getServiceDefinition(path)
 	if err != nil {
@@ -322,6 +626,11 @@ func BuildFromPath(path string) (*ServiceDefinition, error) {
 	return sd, sd.ValidEntity()
 }
 
+// ReadFromPath given a path will create a ServiceDefinition without validating it
+func ReadFromPath(path string) (*ServiceDefinition, error) {
+	return getServiceDefinition(path)
+}
+
 // GetType return the ServiceDefinition's type
 // It returns the type as a string
 func GetType() string {
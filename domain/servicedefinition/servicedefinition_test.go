@@ -0,0 +1,127 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package servicedefinition_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/control-center/serviced/domain/servicedefinition"
+)
+
+func TestRestartPolicy_Restarts(t *testing.T) {
+	for _, tc := range []struct {
+		condition string
+		exitCode  int
+		expected  bool
+	}{
+		{"", 0, true},
+		{RestartAlways, 1, true},
+		{RestartOnFailure, 0, false},
+		{RestartOnFailure, 1, true},
+		{RestartOnFailure, -1, true},
+		{RestartNever, 1, false},
+	} {
+		p := RestartPolicy{Condition: tc.condition}
+		if actual := p.Restarts(tc.exitCode); actual != tc.expected {
+			t.Errorf("Condition %q with exit code %d: expected %v, got %v", tc.condition, tc.exitCode, tc.expected, actual)
+		}
+	}
+}
+
+func TestRestartPolicy_Delay(t *testing.T) {
+	p := RestartPolicy{}
+	if delay := p.Delay(3); delay != 0 {
+		t.Errorf("Expected no delay without a backoff, got %s", delay)
+	}
+
+	p = RestartPolicy{Backoff: time.Second, MaxBackoff: 10 * time.Second}
+	for failures, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		if delay := p.Delay(failures); delay != expected {
+			t.Errorf("Expected delay %s after %d failures, got %s", expected, failures, delay)
+		}
+	}
+
+	p.MaxBackoff = 0
+	if delay := p.Delay(1000); delay != DefaultMaxRestartBackoff {
+		t.Errorf("Expected delay to be capped at %s, got %s", DefaultMaxRestartBackoff, delay)
+	}
+}
+
+func TestRestartPolicy_JSON(t *testing.T) {
+	p := RestartPolicy{
+		Condition:   RestartOnFailure,
+		MaxRestarts: 5,
+		Window:      10 * time.Minute,
+		Backoff:     500 * time.Millisecond,
+		MaxBackoff:  time.Minute,
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := `{"Condition":"on-failure","MaxRestarts":5,"Window":600,"Backoff":0.5,"MaxBackoff":60}`; string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	var actual RestartPolicy
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if actual != p {
+		t.Errorf("Expected %+v, got %+v", p, actual)
+	}
+}
+
+func TestAutoscaleRule_Instances(t *testing.T) {
+	rule := AutoscaleRule{Metric: "zope.requests", Target: 100}
+	for _, tc := range []struct {
+		current  int
+		value    float64
+		expected int
+	}{
+		{2, 100, 2},
+		{2, 109, 2},
+		{2, 91, 2},
+		{2, 250, 5},
+		{4, 30, 2},
+		{3, 0, 0},
+		{0, 500, 0},
+	} {
+		if actual := rule.Instances(tc.current, tc.value); actual != tc.expected {
+			t.Errorf("Expected %d instances from %d at %v, got %d", tc.expected, tc.current, tc.value, actual)
+		}
+	}
+}
+
+func TestAutoscaleRule_Defaults(t *testing.T) {
+	rule := AutoscaleRule{}
+	if rule.GetWindow() != DefaultAutoscaleWindow {
+		t.Errorf("Expected window %s, got %s", DefaultAutoscaleWindow, rule.GetWindow())
+	}
+	if rule.GetCooldown() != DefaultAutoscaleCooldown {
+		t.Errorf("Expected cooldown %s, got %s", DefaultAutoscaleCooldown, rule.GetCooldown())
+	}
+
+	data := []byte(`{"Metric":"zope.requests","Target":100,"Window":60,"Cooldown":600}`)
+	if err := json.Unmarshal(data, &rule); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if rule.GetWindow() != time.Minute || rule.GetCooldown() != 10*time.Minute {
+		t.Errorf("Unexpected rule %+v", rule)
+	}
+}
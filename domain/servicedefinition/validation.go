@@ -14,11 +14,14 @@
 package servicedefinition
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/validation"
 )
 
@@ -61,6 +64,25 @@ func (sd *ServiceDefinition) validate(context *validationContext) error {
 	}
 	//TODO: validate LogConfigs
 
+	if err := ValidAutoscaleRules(sd.Autoscale, sd.Instances, sd.MonitoringProfile); err != nil {
+		return fmt.Errorf("service definition %v: %v", sd.Name, err)
+	}
+
+	if err := sd.RestartPolicy.ValidEntity(); err != nil {
+		return fmt.Errorf("service definition %v: invalid restart policy: %s", sd.Name, err)
+	}
+
+	if err := sd.ResourceLimits.ValidEntity(); err != nil {
+		return fmt.Errorf("service definition %v: invalid resource limits: %s", sd.Name, err)
+	}
+
+	// validate health checks
+	for name, hc := range sd.HealthChecks {
+		if err := hc.ValidEntity(); err != nil {
+			return fmt.Errorf("service definition %v: invalid health check %s: %s", sd.Name, name, err)
+		}
+	}
+
 	// validate Monitoring Profile
 	if err := sd.MonitoringProfile.ValidEntity(); err != nil {
 		return fmt.Errorf("service definition %v: invalid monitoring profile %s", sd.Name, err)
@@ -121,6 +143,37 @@ func (se EndpointDefinition) ValidEntity() error {
 			return fmt.Errorf("endpoint '%s': %s", se.Name, err)
 		}
 	}
+	if err := validation.StringIn(se.LoadBalancing, "", LoadBalanceRoundRobin, LoadBalanceLeastConn, LoadBalanceHash); err != nil {
+		return fmt.Errorf("endpoint '%s': invalid load balancing: %s", se.Name, err)
+	}
+	for _, vhost := range se.VHostList {
+		if err := validation.StringIn(vhost.Affinity, "", AffinityCookie, AffinitySourceIP); err != nil {
+			return fmt.Errorf("endpoint '%s': vhost %s: invalid affinity: %s", se.Name, vhost.Name, err)
+		}
+		if vhost.AccessLogSample < 0 || vhost.AccessLogSample > 1 {
+			return fmt.Errorf("endpoint '%s': vhost %s: access log sample must be between 0 and 1", se.Name, vhost.Name)
+		}
+		if vhost.Policy.ConnectionRate != 0 {
+			return fmt.Errorf("endpoint '%s': vhost %s: connection rate is not supported on vhosts; use a request rate", se.Name, vhost.Name)
+		}
+		if err := vhost.Policy.ValidEntity(); err != nil {
+			return fmt.Errorf("endpoint '%s': vhost %s: %s", se.Name, vhost.Name, err)
+		}
+	}
+	for _, port := range se.PortList {
+		if err := validation.StringIn(port.Affinity, "", AffinityCookie, AffinitySourceIP); err != nil {
+			return fmt.Errorf("endpoint '%s': port %s: invalid affinity: %s", se.Name, port.PortAddr, err)
+		}
+		if port.AccessLogSample < 0 || port.AccessLogSample > 1 {
+			return fmt.Errorf("endpoint '%s': port %s: access log sample must be between 0 and 1", se.Name, port.PortAddr)
+		}
+		if err := port.Policy.ValidEntity(); err != nil {
+			return fmt.Errorf("endpoint '%s': port %s: %s", se.Name, port.PortAddr, err)
+		}
+		if err := port.ValidProtocol(); err != nil {
+			return fmt.Errorf("endpoint '%s': port %s: %s", se.Name, port.PortAddr, err)
+		}
+	}
 	return se.AddressConfig.ValidEntity()
 }
 
@@ -153,6 +206,122 @@ func (arc AddressResourceConfig) ValidEntity() error {
 	return nil
 }
 
+// ValidEntity makes sure the networks of the access policy parse and its
+// limits are not negative
+func (p AccessPolicy) ValidEntity() error {
+	for _, cidr := range append(append([]string{}, p.AllowCIDRs...), p.DenyCIDRs...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid cidr %s", cidr)
+		}
+	}
+	if p.ConnectionRate < 0 {
+		return errors.New("connection rate must not be negative")
+	}
+	if p.RequestRate < 0 {
+		return errors.New("request rate must not be negative")
+	}
+	if p.RateBurst < 0 {
+		return errors.New("rate burst must not be negative")
+	}
+	if p.MaxConnections < 0 {
+		return errors.New("max connections must not be negative")
+	}
+	return nil
+}
+
+// ValidAutoscaleRules makes sure the autoscale rules of a service scale a
+// bounded number of instances by metrics in its monitoring profile
+func ValidAutoscaleRules(rules []AutoscaleRule, instances domain.MinMax, profile domain.MonitorProfile) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	if instances.Max == 0 {
+		return errors.New("autoscaling requires a maximum number of instances")
+	}
+	for _, rule := range rules {
+		if !profile.HasMetric(rule.Metric) {
+			return fmt.Errorf("autoscale metric %s is not in the monitoring profile", rule.Metric)
+		}
+		if rule.Target <= 0 {
+			return fmt.Errorf("autoscale target of metric %s must be positive", rule.Metric)
+		}
+		if rule.Window < 0 || rule.Cooldown < 0 {
+			return fmt.Errorf("autoscale window and cooldown of metric %s must not be negative", rule.Metric)
+		}
+	}
+	return nil
+}
+
+// ValidEntity makes sure the restart policy has a known condition and that
+// its limits are not negative
+func (p RestartPolicy) ValidEntity() error {
+	if err := validation.StringIn(p.Condition, "", RestartAlways, RestartOnFailure, RestartNever); err != nil {
+		return fmt.Errorf("invalid condition: %s", err)
+	}
+	if p.MaxRestarts < 0 {
+		return errors.New("max restarts must not be negative")
+	}
+	if p.Window < 0 {
+		return errors.New("window must not be negative")
+	}
+	if p.Backoff < 0 {
+		return errors.New("backoff must not be negative")
+	}
+	if p.MaxBackoff < 0 {
+		return errors.New("max backoff must not be negative")
+	}
+	if p.MaxBackoff > 0 && p.MaxBackoff < p.Backoff {
+		return errors.New("max backoff must not be less than the backoff")
+	}
+	return nil
+}
+
+// ValidEntity makes sure the resource limits are within the ranges the
+// kernel accepts.  Whether the host can enforce them is checked by the host
+// agent when it starts the container.
+func (l ResourceLimits) ValidEntity() error {
+	if l.CPUQuota < 0 {
+		return errors.New("cpu quota must not be negative")
+	}
+	if _, err := ParseCPUSet(l.CPUSet); err != nil {
+		return err
+	}
+	if l.BlkioWeight != 0 && (l.BlkioWeight < 10 || l.BlkioWeight > 1000) {
+		return errors.New("block I/O weight must be between 10 and 1000")
+	}
+	for _, limits := range [][]DeviceLimit{l.BlkioDeviceReadBps, l.BlkioDeviceWriteBps} {
+		for _, limit := range limits {
+			if !strings.HasPrefix(limit.Path, "/dev/") {
+				return fmt.Errorf("device %q is not a path under /dev", limit.Path)
+			}
+			if limit.Rate.Value == 0 {
+				return fmt.Errorf("rate of device %s must be positive", limit.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidProtocol makes sure the port only terminates tls for protocols that
+// carry it, and only names servers when it passes tls through
+func (p Port) ValidProtocol() error {
+	switch p.Protocol {
+	case PortProtocolUDP, PortProtocolTLSPassthrough:
+		if p.UseTLS {
+			return fmt.Errorf("%s ports do not terminate tls", p.Protocol)
+		}
+	}
+	if len(p.ServerNames) > 0 && p.Protocol != PortProtocolTLSPassthrough {
+		return fmt.Errorf("server names are only supported on %s ports", PortProtocolTLSPassthrough)
+	}
+	for _, name := range p.ServerNames {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("server names must not be empty")
+		}
+	}
+	return nil
+}
+
 //Normalize adjusts attributes to be in an expected format, lowercases and trims certain fields
 func (arc *AddressResourceConfig) Normalize() {
 	testProto := strings.Trim(strings.ToLower(arc.Protocol), " ")
@@ -17,11 +17,14 @@ package servicedefinition_test
 
 import (
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain"
 	. "github.com/control-center/serviced/domain/servicedefinition"
 	. "github.com/control-center/serviced/domain/servicedefinition/testutils"
+	"github.com/control-center/serviced/utils"
 
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestServiceDefinitionValidate(t *testing.T) {
@@ -137,3 +140,195 @@ func TestServiceDefinitionEmptyEndpointName(t *testing.T) {
 		t.Errorf("Unexpected Error %v", err)
 	}
 }
+
+func TestServiceDefinitionInvalidAccessLogSample(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Endpoints[0].VHostList = []VHost{{Name: "zproxy", AccessLogSample: 1.5}}
+
+	err := sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "access log sample must be between 0 and 1") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	sd.Services[0].Endpoints[0].VHostList[0].AccessLogSample = 0.1
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected Error %v", err)
+	}
+}
+
+func TestServiceDefinitionInvalidAccessPolicy(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Endpoints[0].PortList = []Port{{PortAddr: ":22222", Policy: AccessPolicy{AllowCIDRs: []string{"10.0.0.0/33"}}}}
+
+	err := sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "invalid cidr 10.0.0.0/33") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	sd.Services[0].Endpoints[0].PortList[0].Policy = AccessPolicy{
+		AllowCIDRs:     []string{"10.0.0.0/8"},
+		DenyCIDRs:      []string{"10.0.5.0/24"},
+		ConnectionRate: 5,
+		MaxConnections: 100,
+	}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	sd.Services[0].Endpoints[0].VHostList = []VHost{{Name: "zproxy", Policy: AccessPolicy{ConnectionRate: 5}}}
+	err = sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "connection rate is not supported on vhosts") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+}
+
+func TestServiceDefinitionInvalidPortProtocol(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Endpoints[0].PortList = []Port{{PortAddr: ":514", Protocol: PortProtocolUDP, UseTLS: true}}
+
+	err := sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "udp ports do not terminate tls") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	sd.Services[0].Endpoints[0].PortList[0] = Port{PortAddr: ":5432", ServerNames: []string{"db.example.com"}}
+	err = sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "server names are only supported on tls-passthrough ports") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	sd.Services[0].Endpoints[0].PortList[0].Protocol = PortProtocolTLSPassthrough
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected Error %v", err)
+	}
+}
+
+func TestServiceDefinitionInvalidRestartPolicy(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].RestartPolicy = RestartPolicy{Condition: "sometimes"}
+
+	err := sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "invalid restart policy") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	sd.Services[0].RestartPolicy = RestartPolicy{Condition: RestartOnFailure, Backoff: time.Minute, MaxBackoff: time.Second}
+	err = sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "max backoff must not be less than the backoff") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	sd.Services[0].RestartPolicy.MaxBackoff = 0
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected Error %v", err)
+	}
+}
+
+func TestServiceDefinitionInvalidAutoscale(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	svc := &sd.Services[0]
+	svc.Autoscale = []AutoscaleRule{{Metric: "zope.requests", Target: 100}}
+	svc.Instances = domain.MinMax{Min: 1}
+
+	err := sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "autoscaling requires a maximum number of instances") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	svc.Instances.Max = 5
+	err = sd.ValidEntity()
+	if err == nil {
+		t.Error("Expected error")
+	} else if !strings.Contains(err.Error(), "autoscale metric zope.requests is not in the monitoring profile") {
+		t.Errorf("Unexpected Error %v", err)
+	}
+
+	svc.MonitoringProfile.MetricConfigs = append(svc.MonitoringProfile.MetricConfigs, domain.MetricConfig{
+		ID:      "zope",
+		Metrics: []domain.Metric{{ID: "zope.requests"}},
+	})
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected Error %v", err)
+	}
+}
+
+func TestServiceDefinitionInvalidResourceLimits(t *testing.T) {
+	rate, _ := utils.NewEngNotationFromString("10M")
+	for _, tc := range []struct {
+		limits ResourceLimits
+		err    string
+	}{
+		{ResourceLimits{CPUQuota: -1}, "cpu quota must not be negative"},
+		{ResourceLimits{CPUSet: "0-a"}, "invalid cpu range"},
+		{ResourceLimits{CPUSet: "3-1"}, "invalid cpu range"},
+		{ResourceLimits{BlkioWeight: 5}, "block I/O weight must be between 10 and 1000"},
+		{ResourceLimits{BlkioDeviceReadBps: []DeviceLimit{{Path: "sda", Rate: rate}}}, "is not a path under /dev"},
+		{ResourceLimits{BlkioDeviceWriteBps: []DeviceLimit{{Path: "/dev/sda"}}}, "rate of device /dev/sda must be positive"},
+	} {
+		sd := CreateValidServiceDefinition()
+		sd.Services[0].ResourceLimits = tc.limits
+		err := sd.ValidEntity()
+		if err == nil {
+			t.Errorf("Expected error for %+v", tc.limits)
+		} else if !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Unexpected Error %v", err)
+		}
+	}
+
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].ResourceLimits = ResourceLimits{
+		CPUQuota:            1.5,
+		CPUSet:              "0-1,3",
+		BlkioWeight:         500,
+		BlkioDeviceReadBps:  []DeviceLimit{{Path: "/dev/sda", Rate: rate}},
+		BlkioDeviceWriteBps: []DeviceLimit{{Path: "/dev/sda", Rate: rate}},
+	}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected Error %v", err)
+	}
+}
+
+func TestParseCPUSet(t *testing.T) {
+	cpus, err := ParseCPUSet("0-2, 5")
+	if err != nil {
+		t.Fatalf("Unexpected Error %v", err)
+	}
+	if len(cpus) != 4 || cpus[0] != 0 || cpus[2] != 2 || cpus[3] != 5 {
+		t.Errorf("Unexpected cpus %v", cpus)
+	}
+	if cpus, err := ParseCPUSet(""); err != nil || len(cpus) != 0 {
+		t.Errorf("Unexpected cpus %v, error %v", cpus, err)
+	}
+}
+
+func TestParseDeviceLimit(t *testing.T) {
+	limit, err := ParseDeviceLimit("/dev/sda:1M")
+	if err != nil {
+		t.Fatalf("Unexpected Error %v", err)
+	}
+	if limit.Path != "/dev/sda" || limit.Rate.Value != 1<<20 {
+		t.Errorf("Unexpected limit %+v", limit)
+	}
+	if _, err := ParseDeviceLimit("/dev/sda"); err == nil {
+		t.Error("Expected error")
+	}
+	if _, err := ParseDeviceLimit("/dev/sda:fast"); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicetemplate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/validation"
+)
+
+// Severity describes how serious a lint diagnostic is
+type Severity string
+
+const (
+	// SeverityError is an issue that will prevent the template from
+	// deploying or running correctly
+	SeverityError Severity = "error"
+	// SeverityWarning is an issue that is likely a mistake, but does not
+	// prevent the template from deploying
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single issue found while linting a template
+type Diagnostic struct {
+	Path     string // Path to the field with the issue, e.g. Services[2].Endpoints[0].PortNumber
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Severity, d.Path, d.Message)
+}
+
+// Diagnostics is the list of issues found while linting a template
+type Diagnostics []Diagnostic
+
+// HasErrors returns true if any of the diagnostics is an error
+func (d Diagnostics) HasErrors() bool {
+	for _, diag := range d {
+		if diag.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks the template and reports every issue it finds, rather than
+// stopping at the first one like ValidEntity.
+func (st *ServiceTemplate) Lint() Diagnostics {
+	l := &linter{
+		vhosts:      make(map[string]string),
+		publicPorts: make(map[string]string),
+		exports:     []string{},
+	}
+	for name, cf := range st.ConfigFiles {
+		l.lintTemplate(fmt.Sprintf("ConfigFiles[%q].Content", name), cf.Content)
+	}
+	for i := range st.Services {
+		l.lintService(fmt.Sprintf("Services[%d]", i), &st.Services[i], nil)
+	}
+	l.lintImports()
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		return l.diagnostics[i].Path < l.diagnostics[j].Path
+	})
+	return l.diagnostics
+}
+
+// endpointImport is an imported endpoint waiting to be matched to an export
+type endpointImport struct {
+	path        string
+	application string
+}
+
+// linter keeps track of the state that spans service definitions
+type linter struct {
+	diagnostics Diagnostics
+	vhosts      map[string]string // vhost name to the path where it is defined
+	publicPorts map[string]string // public port address to the path where it is defined
+	exports     []string
+	imports     []endpointImport
+}
+
+func (l *linter) errorf(path, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{Path: path, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) warnf(path, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{Path: path, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+// lintTemplate reports templates that do not parse or that call functions
+// that are not available when the service is evaluated
+func (l *linter) lintTemplate(path, value string) {
+	if !strings.Contains(value, "{{") {
+		return
+	}
+	if err := service.ParseTemplate(value); err != nil {
+		l.errorf(path, "invalid template: %s", err)
+	}
+}
+
+// lintService checks a service definition and its children. logFilters are
+// the filters defined by its ancestors.  It returns the names of the log
+// filters used by the service and its children.
+func (l *linter) lintService(path string, sd *servicedefinition.ServiceDefinition, logFilters map[string]struct{}) map[string]struct{} {
+	if err := sd.Instances.Validate(); err != nil {
+		l.errorf(path+".Instances", "%s", err)
+	}
+	launch := strings.ToLower(strings.TrimSpace(sd.Launch))
+	if launch != "" {
+		if err := validation.StringIn(launch, commons.AUTO, commons.MANUAL); err != nil {
+			l.errorf(path+".Launch", "invalid launch setting: %s", err)
+		}
+	}
+	if err := sd.MonitoringProfile.ValidEntity(); err != nil {
+		l.errorf(path+".MonitoringProfile", "invalid monitoring profile: %s", err)
+	}
+
+	l.lintTemplate(path+".Command", sd.Command)
+	l.lintTemplate(path+".Hostname", sd.Hostname)
+	for i, env := range sd.Environment {
+		l.lintTemplate(fmt.Sprintf("%s.Environment[%d]", path, i), env)
+	}
+	for name, action := range sd.Actions {
+		l.lintTemplate(fmt.Sprintf("%s.Actions[%q]", path, name), action)
+	}
+	for name, cmd := range sd.Commands {
+		l.lintTemplate(fmt.Sprintf("%s.Commands[%q].Command", path, name), cmd.Command)
+	}
+	for name, cf := range sd.ConfigFiles {
+		l.lintTemplate(fmt.Sprintf("%s.ConfigFiles[%q].Content", path, name), cf.Content)
+	}
+	for i, vol := range sd.Volumes {
+		l.lintTemplate(fmt.Sprintf("%s.Volumes[%d].ResourcePath", path, i), vol.ResourcePath)
+	}
+	for i, prereq := range sd.Prereqs {
+		l.lintTemplate(fmt.Sprintf("%s.Prereqs[%d].Script", path, i), prereq.Script)
+	}
+	for name, hc := range sd.HealthChecks {
+		hcPath := fmt.Sprintf("%s.HealthChecks[%q]", path, name)
+		l.lintTemplate(hcPath+".Script", hc.Script)
+		if hc.Interval <= 0 {
+			l.warnf(hcPath+".Interval", "health check has no interval and will use the default")
+		}
+	}
+
+	names := make(map[string]string)
+	for i := range sd.Endpoints {
+		epPath := fmt.Sprintf("%s.Endpoints[%d]", path, i)
+		if prev, ok := names[strings.TrimSpace(sd.Endpoints[i].Name)]; ok {
+			l.errorf(epPath+".Name", "endpoint name %q is already used by %s", sd.Endpoints[i].Name, prev)
+		} else {
+			names[strings.TrimSpace(sd.Endpoints[i].Name)] = epPath
+		}
+		l.lintEndpoint(epPath, &sd.Endpoints[i])
+	}
+
+	// log filters are visible to the service and all of its children
+	filters := make(map[string]struct{})
+	for name := range logFilters {
+		filters[name] = struct{}{}
+	}
+	for name := range sd.LogFilters {
+		filters[name] = struct{}{}
+	}
+	used := make(map[string]struct{})
+	for i, lc := range sd.LogConfigs {
+		lcPath := fmt.Sprintf("%s.LogConfigs[%d]", path, i)
+		l.lintTemplate(lcPath+".Path", lc.Path)
+		l.lintTemplate(lcPath+".Type", lc.Type)
+		for j, name := range lc.Filters {
+			if _, ok := filters[name]; !ok {
+				l.errorf(fmt.Sprintf("%s.Filters[%d]", lcPath, j), "log filter %q is not defined by the service or its parents", name)
+			}
+			used[name] = struct{}{}
+		}
+	}
+	for i := range sd.Services {
+		for name := range l.lintService(fmt.Sprintf("%s.Services[%d]", path, i), &sd.Services[i], filters) {
+			used[name] = struct{}{}
+		}
+	}
+	for name := range sd.LogFilters {
+		if _, ok := used[name]; !ok {
+			l.warnf(fmt.Sprintf("%s.LogFilters[%q]", path, name), "log filter is not used by any log config")
+		}
+	}
+	return used
+}
+
+// lintEndpoint checks an endpoint and records its vhosts, public ports,
+// imports and exports
+func (l *linter) lintEndpoint(path string, ep *servicedefinition.EndpointDefinition) {
+	if strings.TrimSpace(ep.Name) == "" {
+		l.errorf(path+".Name", "endpoint must have a name")
+	}
+	l.lintTemplate(path+".ApplicationTemplate", ep.ApplicationTemplate)
+	l.lintTemplate(path+".PortTemplate", ep.PortTemplate)
+
+	templated := strings.Contains(ep.Application, "{{")
+	if templated {
+		l.lintTemplate(path+".Application", ep.Application)
+	} else if err := applicationValidation(ep.Application); err != nil {
+		l.errorf(path+".Application", "%s", err)
+	}
+
+	if strings.HasPrefix(ep.Purpose, "import") {
+		if !templated {
+			l.imports = append(l.imports, endpointImport{path: path + ".Application", application: ep.Application})
+		}
+	} else {
+		if ep.PortTemplate == "" {
+			if err := validation.ValidPort(int(ep.PortNumber)); err != nil {
+				l.errorf(path+".PortNumber", "%s", err)
+			}
+		}
+		if ep.Purpose == "export" && !templated {
+			l.exports = append(l.exports, ep.Application)
+		}
+	}
+
+	if err := ep.AddressConfig.ValidEntity(); err != nil {
+		l.errorf(path+".AddressConfig", "%s", err)
+	}
+	for i, vhost := range ep.VHostList {
+		vhPath := fmt.Sprintf("%s.VHostList[%d].Name", path, i)
+		if prev, ok := l.vhosts[vhost.Name]; ok {
+			l.errorf(vhPath, "vhost %q is already defined by %s", vhost.Name, prev)
+		} else {
+			l.vhosts[vhost.Name] = vhPath
+		}
+	}
+	for i, port := range ep.PortList {
+		portPath := fmt.Sprintf("%s.PortList[%d].PortAddr", path, i)
+		if prev, ok := l.publicPorts[port.PortAddr]; ok {
+			l.errorf(portPath, "public port %q is already defined by %s", port.PortAddr, prev)
+		} else {
+			l.publicPorts[port.PortAddr] = portPath
+		}
+	}
+}
+
+// lintImports reports imported endpoints that no exported endpoint in the
+// template matches. They may be satisfied by another application, so these
+// are only warnings.
+func (l *linter) lintImports() {
+	for _, imp := range l.imports {
+		rgx, err := regexp.Compile(fmt.Sprintf("^%s$", imp.application))
+		if err != nil {
+			// already reported as an invalid application
+			continue
+		}
+		found := false
+		for _, exp := range l.exports {
+			if rgx.MatchString(exp) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			l.warnf(imp.path, "no endpoint in the template exports an application matching %q", imp.application)
+		}
+	}
+}
+
+// applicationValidation makes sure the application is a valid regular
+// expression
+func applicationValidation(application string) error {
+	if _, err := regexp.Compile(application); err != nil {
+		return fmt.Errorf("illegal application regexp %s", err)
+	}
+	return nil
+}
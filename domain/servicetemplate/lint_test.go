@@ -0,0 +1,96 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package servicetemplate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "github.com/control-center/serviced/domain/servicedefinition/testutils"
+	"github.com/control-center/serviced/health"
+)
+
+func TestServiceTemplateLintValid(t *testing.T) {
+	template := ServiceTemplate{}
+	template.Services = []servicedefinition.ServiceDefinition{*CreateValidServiceDefinition()}
+	for _, diag := range template.Lint() {
+		if diag.Severity == SeverityError {
+			t.Errorf("Unexpected error: %s", diag)
+		}
+	}
+}
+
+func TestServiceTemplateLint(t *testing.T) {
+	template := ServiceTemplate{
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name:       "app",
+				Launch:     "auto",
+				Command:    "{{shout .Name}}",
+				LogFilters: map[string]string{"unused": "filter {}", "used": "filter {}"},
+				Endpoints: []servicedefinition.EndpointDefinition{
+					{Name: "web", Purpose: "export", Application: "web", PortNumber: 8080, PortList: []servicedefinition.Port{{PortAddr: ":443"}}},
+					{Name: "web", Purpose: "export", Application: "web2", PortNumber: 0},
+				},
+				Services: []servicedefinition.ServiceDefinition{
+					{
+						Name:       "child",
+						LogConfigs: []servicedefinition.LogConfig{{Path: "/var/log/child.log", Filters: []string{"used", "missing"}}},
+						Endpoints: []servicedefinition.EndpointDefinition{
+							{Name: "db", Purpose: "import", Application: "mariadb"},
+							{Name: "web", Purpose: "import", Application: "web.*"},
+							{Name: "admin", Purpose: "export", Application: "admin", PortNumber: 9090, PortList: []servicedefinition.Port{{PortAddr: ":443"}}},
+						},
+						HealthChecks: map[string]health.HealthCheck{
+							"answering": {Script: "curl localhost", Interval: 0},
+							"running":   {Script: "pgrep app", Interval: 5 * time.Second},
+						},
+						Instances: domain.MinMax{Min: 2, Max: 1},
+					},
+				},
+			},
+		},
+	}
+
+	expected := Diagnostics{
+		{Path: "Services[0].Command", Severity: SeverityError},
+		{Path: "Services[0].Endpoints[1].Name", Severity: SeverityError},
+		{Path: "Services[0].Endpoints[1].PortNumber", Severity: SeverityError},
+		{Path: `Services[0].LogFilters["unused"]`, Severity: SeverityWarning},
+		{Path: `Services[0].Services[0].Endpoints[0].Application`, Severity: SeverityWarning},
+		{Path: `Services[0].Services[0].Endpoints[2].PortList[0].PortAddr`, Severity: SeverityError},
+		{Path: `Services[0].Services[0].HealthChecks["answering"].Interval`, Severity: SeverityWarning},
+		{Path: `Services[0].Services[0].Instances`, Severity: SeverityError},
+		{Path: `Services[0].Services[0].LogConfigs[0].Filters[1]`, Severity: SeverityError},
+	}
+	actual := template.Lint()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(expected), len(actual), actual)
+	}
+	for i, diag := range actual {
+		if diag.Path != expected[i].Path || diag.Severity != expected[i].Severity {
+			t.Errorf("Expected %s %s, got %s", expected[i].Severity, expected[i].Path, diag)
+		}
+		if diag.Message == "" {
+			t.Errorf("Expected a message for %s", diag.Path)
+		}
+	}
+	if !actual.HasErrors() {
+		t.Errorf("Expected lint to report errors")
+	}
+}
@@ -123,13 +123,26 @@ func BuildFromPath(path string) (*ServiceTemplate, error) {
 	if err != nil {
 		return nil, err
 	}
-	st := ServiceTemplate{
+	return newFromServiceDefinition(sd), nil
+}
+
+// ReadFromPath creates a template from a directory of service definitions
+// without validating it, so that it can be linted.
+func ReadFromPath(path string) (*ServiceTemplate, error) {
+	sd, err := servicedefinition.ReadFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return newFromServiceDefinition(sd), nil
+}
+
+func newFromServiceDefinition(sd *servicedefinition.ServiceDefinition) *ServiceTemplate {
+	return &ServiceTemplate{
 		Services:    []servicedefinition.ServiceDefinition{*sd},
 		Name:        sd.Name,
 		Version:     sd.Version,
 		Description: sd.Description,
 	}
-	return &st, nil
 }
 
 // GetType return the serviceTemplate's type
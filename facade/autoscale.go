@@ -0,0 +1,203 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// AutoscaleServices evaluates the autoscale rules of the running services in
+// a pool, and updates the instance counts of the services whose metrics have
+// strayed from their targets.  Instances are only added while the pool has
+// the memory to commit to them.
+func (f *Facade) AutoscaleServices(ctx datastore.Context, poolID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AutoscaleServices"))
+	logger := plog.WithField("poolid", poolID)
+
+	svcs, err := f.serviceStore.GetServicesByPool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up services in pool")
+		return err
+	}
+	p, err := f.GetResourcePool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up pool")
+		return err
+	} else if p == nil {
+		return fmt.Errorf("pool %s not found", poolID)
+	}
+	available, limited := poolMemoryAvailable(p, svcs)
+
+	for _, svc := range svcs {
+		if len(svc.Autoscale) == 0 || svc.DesiredState != int(service.SVCRun) || svc.EmergencyShutdown {
+			continue
+		}
+		svclogger := logger.WithFields(log.Fields{
+			"serviceid":   svc.ID,
+			"servicename": svc.Name,
+		})
+		instances, rule, value, ok := f.autoscaleInstances(svc)
+		if !ok || instances == svc.Instances {
+			continue
+		}
+
+		// only commit the memory the pool has left to new instances
+		if added := instances - svc.Instances; added > 0 && limited && svc.RAMCommitment.Value > 0 {
+			if fits := int(available / svc.RAMCommitment.Value); fits < added {
+				svclogger.WithFields(log.Fields{
+					"instances": instances,
+					"fits":      fits,
+				}).Warn("Pool does not have the memory to autoscale service")
+				instances = svc.Instances + fits
+				if instances == svc.Instances {
+					continue
+				}
+			}
+		}
+
+		if err := f.autoscaleService(ctx, svc.ID, instances, rule.Metric, value, rule.Target); err != nil {
+			svclogger.WithError(err).Warn("Could not autoscale service")
+			continue
+		}
+		if instances > svc.Instances {
+			available -= uint64(instances-svc.Instances) * svc.RAMCommitment.Value
+		}
+	}
+	return nil
+}
+
+// autoscaleInstances returns the number of instances the autoscale rules of
+// the service call for, within its instance limits, and the rule and metric
+// value that called for it.  Returns false if no rule is out of its cooldown
+// or none of the metrics could be looked up.
+func (f *Facade) autoscaleInstances(svc service.Service) (int, servicedefinition.AutoscaleRule, float64, bool) {
+	logger := plog.WithFields(log.Fields{
+		"serviceid":   svc.ID,
+		"servicename": svc.Name,
+	})
+
+	f.autoscaleLock.Lock()
+	scaledAt := f.autoscaledAt[svc.ID]
+	f.autoscaleLock.Unlock()
+
+	var (
+		instances int
+		scaledBy  servicedefinition.AutoscaleRule
+		value     float64
+		ok        bool
+	)
+	for _, rule := range svc.Autoscale {
+		if time.Since(scaledAt) < rule.GetCooldown() {
+			continue
+		}
+		metric, found := svc.MonitoringProfile.GetMetric(rule.Metric)
+		if !found {
+			logger.WithField("metric", rule.Metric).Debug("Autoscale metric is not in the monitoring profile")
+			continue
+		}
+		avg, err := f.metricsClient.GetServiceMetricAverage(rule.GetWindow(), svc.ID, metric.ID, metric.Counter)
+		if err != nil {
+			logger.WithField("metric", rule.Metric).WithError(err).Debug("Could not look up autoscale metric")
+			continue
+		}
+
+		// scale to the rule calling for the most instances
+		if count := rule.Instances(svc.Instances, avg); !ok || count > instances {
+			instances, scaledBy, value, ok = count, rule, avg, true
+		}
+	}
+	if !ok {
+		return 0, scaledBy, 0, false
+	}
+
+	if instances < svc.InstanceLimits.Min {
+		instances = svc.InstanceLimits.Min
+	}
+	if svc.InstanceLimits.Max > 0 && instances > svc.InstanceLimits.Max {
+		instances = svc.InstanceLimits.Max
+	}
+	return instances, scaledBy, value, true
+}
+
+// autoscaleService sets the number of instances of a service, recording the
+// decision in the audit log.
+func (f *Facade) autoscaleService(ctx datastore.Context, serviceID string, instances int, metric string, value, target float64) error {
+	svc, err := f.GetService(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	alog := f.auditLogger.Action(audit.Scale).Message(ctx, "Autoscale Service").WithField("servicename", svc.Name).Entity(svc).
+		WithFields(log.Fields{
+			"from":   strconv.Itoa(svc.Instances),
+			"to":     strconv.Itoa(instances),
+			"metric": metric,
+			"value":  strconv.FormatFloat(value, 'f', -1, 64),
+			"target": strconv.FormatFloat(target, 'f', -1, 64),
+		})
+	tenantID, err := f.GetTenantID(ctx, svc.ID)
+	if err != nil {
+		return alog.Error(err)
+	}
+	mutex := getTenantLock(tenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	svc.Instances = instances
+	if err := f.updateService(ctx, tenantID, *svc, false, false); err != nil {
+		return alog.Error(err)
+	}
+
+	f.autoscaleLock.Lock()
+	if f.autoscaledAt == nil {
+		f.autoscaledAt = make(map[string]time.Time)
+	}
+	f.autoscaledAt[svc.ID] = time.Now()
+	f.autoscaleLock.Unlock()
+
+	plog.WithFields(log.Fields{
+		"serviceid":   svc.ID,
+		"servicename": svc.Name,
+		"instances":   instances,
+		"metric":      metric,
+	}).Info("Autoscaled service")
+	alog.Succeeded()
+	return nil
+}
+
+// poolMemoryAvailable returns how much memory the pool has left to commit to
+// the instances of its running services.  Returns false if neither the
+// pool's limit nor the capacity of its hosts is known.
+func poolMemoryAvailable(p *pool.ResourcePool, svcs []service.Service) (uint64, bool) {
+	capacity := p.MemoryLimit
+	if capacity == 0 {
+		capacity = p.MemoryCapacity
+	}
+	if capacity == 0 {
+		return 0, false
+	}
+	committed := poolUsage(p, svcs, nil).MemoryCommitment
+	if committed >= capacity {
+		return 0, true
+	}
+	return capacity - committed, true
+}
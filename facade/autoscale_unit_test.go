@@ -0,0 +1,215 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	auditmocks "github.com/control-center/serviced/audit/mocks"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/utils"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupPoolServices(c *C, p pool.ResourcePool, svcs ...service.Service) {
+	ft.serviceStore.On("GetServicesByPool", ft.ctx, p.ID).Return(svcs, nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key(p.ID), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*pool.ResourcePool) = p
+		})
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, p.ID).Return(nil, nil)
+}
+
+func getAutoscaleService(instances int) service.Service {
+	return service.Service{
+		ID:             "zope",
+		Name:           "Zope",
+		PoolID:         "default",
+		DesiredState:   int(service.SVCRun),
+		Instances:      instances,
+		InstanceLimits: domain.MinMax{Min: 1, Max: 10},
+		RAMCommitment:  utils.EngNotation{Value: 1024},
+		Autoscale: []servicedefinition.AutoscaleRule{
+			{Metric: "zope.requests", Target: 100},
+		},
+		MonitoringProfile: domain.MonitorProfile{
+			MetricConfigs: []domain.MetricConfig{
+				{ID: "zope", Metrics: []domain.Metric{{ID: "zope.requests", Counter: true}}},
+			},
+		},
+	}
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_WithinTarget(c *C) {
+	svc := getAutoscaleService(2)
+	ft.setupPoolServices(c, pool.ResourcePool{ID: "default"}, svc)
+	ft.metricsClient.On("GetServiceMetricAverage", servicedefinition.DefaultAutoscaleWindow, "zope", "zope.requests", true).Return(105.0, nil)
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	ft.serviceStore.AssertNotCalled(c, "Get", ft.ctx, "zope")
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_NoPoolMemory(c *C) {
+	svc := getAutoscaleService(2)
+	ft.setupPoolServices(c, pool.ResourcePool{ID: "default", MemoryLimit: 2048}, svc)
+	ft.metricsClient.On("GetServiceMetricAverage", servicedefinition.DefaultAutoscaleWindow, "zope", "zope.requests", true).Return(300.0, nil)
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	ft.serviceStore.AssertNotCalled(c, "Get", ft.ctx, "zope")
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_Stopped(c *C) {
+	svc := getAutoscaleService(2)
+	svc.DesiredState = int(service.SVCStop)
+	ft.setupPoolServices(c, pool.ResourcePool{ID: "default"}, svc)
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	ft.metricsClient.AssertNotCalled(c, "GetServiceMetricAverage", servicedefinition.DefaultAutoscaleWindow, "zope", "zope.requests", true)
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_NoSuchPool(c *C) {
+	ft.serviceStore.On("GetServicesByPool", ft.ctx, "default").Return([]service.Service{}, nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key("default"), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(datastore.ErrNoSuchEntity{})
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, NotNil)
+}
+
+// setupAutoscaleUpdate mocks the calls made to update the instances of an
+// autoscaled service, and returns the instance counts written to the store
+// and the audit logger that records them.
+func (ft *FacadeUnitTest) setupAutoscaleUpdate(c *C, svc service.Service) (*[]int, *auditmocks.Logger) {
+	ft.setupMockDFSLocking()
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, svc.ID).Return(&service.ServiceDetails{ID: svc.ID, Name: svc.Name}, nil)
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(func(datastore.Context, string) *service.Service {
+		s := svc
+		return &s
+	}, nil)
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).
+		Return([]service.ServiceDetails{}, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+	ft.zzk.On("UpdateService", ft.ctx, svc.ID, mock.AnythingOfType("*service.Service"), false, false).Return(nil)
+
+	instances := []int{}
+	ft.serviceStore.On("Put", ft.ctx, mock.AnythingOfType("*service.Service")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			instances = append(instances, args.Get(1).(*service.Service).Instances)
+		})
+
+	alog := &auditmocks.Logger{}
+	alog.On("Message", ft.ctx, mock.AnythingOfType("string")).Return(alog)
+	alog.On("Action", mock.AnythingOfType("string")).Return(alog)
+	alog.On("Entity", mock.AnythingOfType("*service.Service")).Return(alog)
+	alog.On("WithField", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(alog)
+	alog.On("WithFields", mock.AnythingOfType("logrus.Fields")).Return(alog)
+	alog.On("Error", mock.Anything)
+	alog.On("Succeeded")
+	ft.Facade.SetAuditLogger(alog)
+	return &instances, alog
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_ScaleUp(c *C) {
+	svc := getAutoscaleService(2)
+	svc.ID = "zope-scaleup"
+	ft.setupPoolServices(c, pool.ResourcePool{ID: "default"}, svc)
+	ft.metricsClient.On("GetServiceMetricAverage", servicedefinition.DefaultAutoscaleWindow, svc.ID, "zope.requests", true).Return(300.0, nil)
+	instances, alog := ft.setupAutoscaleUpdate(c, svc)
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	c.Assert(*instances, DeepEquals, []int{6})
+	ft.zzk.AssertCalled(c, "UpdateService", ft.ctx, svc.ID, mock.AnythingOfType("*service.Service"), false, false)
+	alog.AssertCalled(c, "Action", audit.Scale)
+	alog.AssertCalled(c, "WithFields", log.Fields{
+		"from":   "2",
+		"to":     "6",
+		"metric": "zope.requests",
+		"value":  "300",
+		"target": "100",
+	})
+	alog.AssertCalled(c, "Succeeded")
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_ScaleUpToMax(c *C) {
+	svc := getAutoscaleService(4)
+	svc.ID = "zope-scaleuptomax"
+	ft.setupPoolServices(c, pool.ResourcePool{ID: "default"}, svc)
+	ft.metricsClient.On("GetServiceMetricAverage", servicedefinition.DefaultAutoscaleWindow, svc.ID, "zope.requests", true).Return(500.0, nil)
+	instances, alog := ft.setupAutoscaleUpdate(c, svc)
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	c.Assert(*instances, DeepEquals, []int{10})
+	alog.AssertCalled(c, "Succeeded")
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_ScaleDown(c *C) {
+	svc := getAutoscaleService(6)
+	svc.ID = "zope-scaledown"
+	ft.setupPoolServices(c, pool.ResourcePool{ID: "default"}, svc)
+	ft.metricsClient.On("GetServiceMetricAverage", servicedefinition.DefaultAutoscaleWindow, svc.ID, "zope.requests", true).Return(50.0, nil)
+	instances, alog := ft.setupAutoscaleUpdate(c, svc)
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	c.Assert(*instances, DeepEquals, []int{3})
+	alog.AssertCalled(c, "Action", audit.Scale)
+	alog.AssertCalled(c, "Succeeded")
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_ScaleDownToMin(c *C) {
+	svc := getAutoscaleService(4)
+	svc.ID = "zope-scaledowntomin"
+	ft.setupPoolServices(c, pool.ResourcePool{ID: "default"}, svc)
+	ft.metricsClient.On("GetServiceMetricAverage", servicedefinition.DefaultAutoscaleWindow, svc.ID, "zope.requests", true).Return(0.0, nil)
+	instances, alog := ft.setupAutoscaleUpdate(c, svc)
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	c.Assert(*instances, DeepEquals, []int{1})
+	alog.AssertCalled(c, "Succeeded")
+}
+
+func (ft *FacadeUnitTest) Test_AutoscaleServices_Cooldown(c *C) {
+	svc := getAutoscaleService(2)
+	svc.ID = "zope-cooldown"
+	ft.setupPoolServices(c, pool.ResourcePool{ID: "default"}, svc)
+	ft.metricsClient.On("GetServiceMetricAverage", servicedefinition.DefaultAutoscaleWindow, svc.ID, "zope.requests", true).Return(300.0, nil)
+	instances, _ := ft.setupAutoscaleUpdate(c, svc)
+
+	err := ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	c.Assert(*instances, DeepEquals, []int{6})
+
+	// the service is not scaled again until the cooldown has passed
+	err = ft.Facade.AutoscaleServices(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	c.Assert(*instances, DeepEquals, []int{6})
+	ft.metricsClient.AssertNumberOfCalls(c, "GetServiceMetricAverage", 1)
+}
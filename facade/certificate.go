@@ -0,0 +1,228 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// SetPublicEndpointCertificate stores the certificate and key served for a
+// vhost or port public endpoint of a service, replacing any certificate
+// already set for the endpoint.
+func (f *Facade) SetPublicEndpointCertificate(ctx datastore.Context, serviceID, endpointName, certType, name, certPEM, keyPEM string) (*certificate.Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetPublicEndpointCertificate"))
+	alog := f.auditLogger.Message(ctx, "Setting Public Endpoint Certificate").Action(audit.Update).
+		Type(certificate.GetType()).ID(certificate.ID(certType, name)).
+		WithFields(logrus.Fields{
+			"serviceid":    serviceID,
+			"endpointname": endpointName,
+			"type":         certType,
+			"name":         name,
+		})
+
+	if certType == certificate.TypePort {
+		name = service.ScrubPortString(name)
+	}
+	if err := f.checkPublicEndpoint(ctx, serviceID, endpointName, certType, name); err != nil {
+		return nil, alog.Error(err)
+	}
+
+	cert, err := certificate.New(certType, name, serviceID, endpointName, certPEM, keyPEM)
+	if err != nil {
+		return nil, alog.Error(fmt.Errorf("Invalid certificate for %s %s: %s", certType, name, err))
+	}
+
+	now := time.Now()
+	cert.CreatedAt, cert.UpdatedAt = now, now
+	if existing, err := f.certStore.Get(ctx, cert.ID); err == nil {
+		cert.CreatedAt = existing.CreatedAt
+		cert.VersionedEntity = existing.VersionedEntity
+	} else if !datastore.IsErrNoSuchEntity(err) {
+		return nil, alog.Error(err)
+	}
+
+	if err := cert.ValidEntity(); err != nil {
+		return nil, alog.Error(err)
+	}
+	if err := f.certStore.Put(ctx, cert); err != nil {
+		return nil, alog.Error(err)
+	}
+	plog.WithFields(logrus.Fields{
+		"type":     certType,
+		"name":     name,
+		"notafter": cert.NotAfter,
+	}).Info("Set public endpoint certificate")
+	alog.Succeeded()
+	return cert, nil
+}
+
+// SetPublicEndpointACME requests a certificate for the domains of a vhost or
+// port public endpoint from the acme server of the master, which issues and
+// renews it in the background.  The current certificate of the endpoint is
+// served until the new one is issued.
+func (f *Facade) SetPublicEndpointACME(ctx datastore.Context, serviceID, endpointName, certType, name string, domains []string) (*certificate.Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetPublicEndpointACME"))
+	alog := f.auditLogger.Message(ctx, "Requesting Public Endpoint ACME Certificate").Action(audit.Update).
+		Type(certificate.GetType()).ID(certificate.ID(certType, name)).
+		WithFields(logrus.Fields{
+			"serviceid":    serviceID,
+			"endpointname": endpointName,
+			"type":         certType,
+			"name":         name,
+			"domains":      strings.Join(domains, ","),
+		})
+
+	if certType == certificate.TypePort {
+		name = service.ScrubPortString(name)
+	}
+	if err := f.checkPublicEndpoint(ctx, serviceID, endpointName, certType, name); err != nil {
+		return nil, alog.Error(err)
+	}
+	if len(domains) == 0 {
+		return nil, alog.Error(fmt.Errorf("No domains set for acme certificate of %s %s", certType, name))
+	}
+	for _, domain := range domains {
+		if !vhostNameRegex.MatchString(domain) || !strings.Contains(domain, ".") {
+			return nil, alog.Error(fmt.Errorf("Invalid domain name %s", domain))
+		}
+	}
+
+	cert := certificate.NewACME(certType, name, serviceID, endpointName, domains)
+	now := time.Now()
+	cert.CreatedAt, cert.UpdatedAt = now, now
+	if existing, err := f.certStore.Get(ctx, cert.ID); err == nil {
+		cert.CreatedAt = existing.CreatedAt
+		cert.VersionedEntity = existing.VersionedEntity
+		// keep serving the existing certificate until the new one is issued
+		if existing.CertPEM != "" {
+			cert.SetKeyPair(existing.CertPEM, existing.KeyPEM)
+		}
+	} else if !datastore.IsErrNoSuchEntity(err) {
+		return nil, alog.Error(err)
+	}
+
+	if err := cert.ValidEntity(); err != nil {
+		return nil, alog.Error(err)
+	}
+	if err := f.certStore.Put(ctx, cert); err != nil {
+		return nil, alog.Error(err)
+	}
+	alog.Succeeded()
+	return cert, nil
+}
+
+// UpdatePublicEndpointCertificate saves the result of an attempt to issue an
+// acme certificate.  Failed attempts are saved and audited as errors.
+func (f *Facade) UpdatePublicEndpointCertificate(ctx datastore.Context, cert *certificate.Certificate) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UpdatePublicEndpointCertificate"))
+	alog := f.auditLogger.Message(ctx, "Renewing Public Endpoint Certificate").Action(audit.Update).
+		Type(certificate.GetType()).ID(cert.ID).
+		WithFields(logrus.Fields{
+			"domains":  strings.Join(cert.Domains, ","),
+			"notafter": cert.NotAfter.Format(time.RFC3339),
+		})
+
+	// the endpoint may have been removed or changed while the certificate
+	// was being issued
+	existing, err := f.certStore.Get(ctx, cert.ID)
+	if err != nil {
+		return alog.Error(err)
+	} else if !existing.ACME || strings.Join(existing.Domains, ",") != strings.Join(cert.Domains, ",") {
+		return alog.Error(fmt.Errorf("Certificate %s was changed during renewal", cert.ID))
+	}
+	cert.VersionedEntity = existing.VersionedEntity
+	cert.UpdatedAt = time.Now()
+
+	if err := cert.ValidEntity(); err != nil {
+		return alog.Error(err)
+	}
+	if err := f.certStore.Put(ctx, cert); err != nil {
+		return alog.Error(err)
+	}
+	if cert.RenewalStatus == certificate.RenewalFailed {
+		alog.Error(fmt.Errorf("Could not renew certificate for %s %s: %s", cert.Type, cert.Name, cert.RenewalError))
+		return nil
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// RemovePublicEndpointCertificate removes the certificate of a vhost or
+// port public endpoint, which is then served with the default certificate.
+func (f *Facade) RemovePublicEndpointCertificate(ctx datastore.Context, certType, name string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemovePublicEndpointCertificate"))
+	if certType == certificate.TypePort {
+		name = service.ScrubPortString(name)
+	}
+	alog := f.auditLogger.Message(ctx, "Removing Public Endpoint Certificate").Action(audit.Remove).
+		Type(certificate.GetType()).ID(certificate.ID(certType, name))
+
+	if _, err := f.certStore.Get(ctx, certificate.ID(certType, name)); err != nil {
+		return alog.Error(err)
+	}
+	if err := f.certStore.Delete(ctx, certificate.ID(certType, name)); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// GetPublicEndpointCertificates returns the certificates of all public
+// endpoints.
+func (f *Facade) GetPublicEndpointCertificates(ctx datastore.Context) ([]certificate.Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetPublicEndpointCertificates"))
+	return f.certStore.GetCertificates(ctx)
+}
+
+// deletePublicEndpointCertificate removes the certificate of a public
+// endpoint that was removed from its service, if it has one.
+func (f *Facade) deletePublicEndpointCertificate(ctx datastore.Context, certType, name string) {
+	id := certificate.ID(certType, name)
+	if _, err := f.certStore.Get(ctx, id); err != nil {
+		return
+	}
+	if err := f.certStore.Delete(ctx, id); err != nil {
+		plog.WithError(err).WithField("certificateid", id).Warn("Could not remove public endpoint certificate")
+	}
+}
+
+// checkPublicEndpoint returns an error if the service does not have the
+// vhost or port public endpoint.
+func (f *Facade) checkPublicEndpoint(ctx datastore.Context, serviceID, endpointName, certType, name string) error {
+	svc, err := f.GetService(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("Could not find service %s: %s", serviceID, err)
+	}
+	switch certType {
+	case certificate.TypeVHost:
+		if svc.GetVirtualHost(endpointName, name) == nil {
+			return fmt.Errorf("VHost %s not found in service %s:%s", name, svc.ID, svc.Name)
+		}
+	case certificate.TypePort:
+		if svc.GetPort(endpointName, name) == nil {
+			return fmt.Errorf("Port %s not found in service %s:%s", name, svc.ID, svc.Name)
+		}
+	default:
+		return fmt.Errorf("Invalid public endpoint type %s", certType)
+	}
+	return nil
+}
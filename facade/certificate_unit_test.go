@@ -0,0 +1,146 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/proxy"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupPublicEndpointService(serviceID string) {
+	svc := service.Service{
+		ID:   serviceID,
+		Name: "Zenoss",
+		Endpoints: []service.ServiceEndpoint{
+			{
+				Application: "zproxy",
+				Purpose:     "export",
+				VHostList:   []servicedefinition.VHost{{Name: "zenoss5", Enabled: true}},
+				PortList:    []servicedefinition.Port{{PortAddr: ":22222", Enabled: true, UseTLS: true}},
+			},
+		},
+	}
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID}, nil)
+	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(&svc, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+}
+
+func (ft *FacadeUnitTest) Test_SetPublicEndpointCertificate(c *C) {
+	ft.setupPublicEndpointService("svc1")
+	ft.certStore.On("Get", ft.ctx, "vhost-zenoss5").Return(nil, datastore.ErrNoSuchEntity{Key: certificate.Key("vhost-zenoss5")})
+	ft.certStore.On("Put", ft.ctx, mock.AnythingOfType("*certificate.Certificate")).Return(nil)
+
+	cert, err := ft.Facade.SetPublicEndpointCertificate(ft.ctx, "svc1", "zproxy", certificate.TypeVHost, "zenoss5", proxy.InsecureCertPEM, proxy.InsecureKeyPEM)
+	c.Assert(err, IsNil)
+	c.Assert(cert.ID, Equals, "vhost-zenoss5")
+	c.Assert(cert.ServiceID, Equals, "svc1")
+	c.Assert(cert.Subjects, DeepEquals, []string{"ControlCenter"})
+	c.Assert(cert.CreatedAt.IsZero(), Equals, false)
+	ft.certStore.AssertNumberOfCalls(c, "Put", 1)
+}
+
+func (ft *FacadeUnitTest) Test_SetPublicEndpointCertificate_Replace(c *C) {
+	ft.setupPublicEndpointService("svc1")
+	created := time.Now().Add(-24 * time.Hour)
+	existing := &certificate.Certificate{ID: "port-:22222", CreatedAt: created}
+	ft.certStore.On("Get", ft.ctx, "port-:22222").Return(existing, nil)
+	ft.certStore.On("Put", ft.ctx, mock.AnythingOfType("*certificate.Certificate")).Return(nil)
+
+	cert, err := ft.Facade.SetPublicEndpointCertificate(ft.ctx, "svc1", "zproxy", certificate.TypePort, "22222", proxy.InsecureCertPEM, proxy.InsecureKeyPEM)
+	c.Assert(err, IsNil)
+	c.Assert(cert.Name, Equals, ":22222")
+	c.Assert(cert.CreatedAt, Equals, created)
+	c.Assert(cert.UpdatedAt.After(created), Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_SetPublicEndpointCertificate_NoEndpoint(c *C) {
+	ft.setupPublicEndpointService("svc1")
+
+	_, err := ft.Facade.SetPublicEndpointCertificate(ft.ctx, "svc1", "zproxy", certificate.TypeVHost, "other", proxy.InsecureCertPEM, proxy.InsecureKeyPEM)
+	c.Assert(err, NotNil)
+	_, err = ft.Facade.SetPublicEndpointCertificate(ft.ctx, "svc1", "zproxy", certificate.TypePort, ":1234", proxy.InsecureCertPEM, proxy.InsecureKeyPEM)
+	c.Assert(err, NotNil)
+	ft.certStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_SetPublicEndpointCertificate_BadKeyPair(c *C) {
+	ft.setupPublicEndpointService("svc1")
+
+	_, err := ft.Facade.SetPublicEndpointCertificate(ft.ctx, "svc1", "zproxy", certificate.TypeVHost, "zenoss5", proxy.InsecureCertPEM, "not a key")
+	c.Assert(err, ErrorMatches, "Invalid certificate for vhost zenoss5: .*")
+	ft.certStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_RemovePublicEndpointCertificate(c *C) {
+	ft.certStore.On("Get", ft.ctx, "vhost-zenoss5").Return(&certificate.Certificate{ID: "vhost-zenoss5"}, nil)
+	ft.certStore.On("Delete", ft.ctx, "vhost-zenoss5").Return(nil)
+	ft.certStore.On("Get", ft.ctx, "vhost-other").Return(nil, datastore.ErrNoSuchEntity{Key: certificate.Key("vhost-other")})
+
+	c.Assert(ft.Facade.RemovePublicEndpointCertificate(ft.ctx, certificate.TypeVHost, "zenoss5"), IsNil)
+	c.Assert(ft.Facade.RemovePublicEndpointCertificate(ft.ctx, certificate.TypeVHost, "other"), NotNil)
+	ft.certStore.AssertNumberOfCalls(c, "Delete", 1)
+}
+
+func (ft *FacadeUnitTest) Test_SetPublicEndpointACME(c *C) {
+	ft.setupPublicEndpointService("svc1")
+	existing, err := certificate.New(certificate.TypeVHost, "zenoss5", "svc1", "zproxy", proxy.InsecureCertPEM, proxy.InsecureKeyPEM)
+	c.Assert(err, IsNil)
+	ft.certStore.On("Get", ft.ctx, "vhost-zenoss5").Return(existing, nil)
+	ft.certStore.On("Put", ft.ctx, mock.AnythingOfType("*certificate.Certificate")).Return(nil)
+
+	cert, err := ft.Facade.SetPublicEndpointACME(ft.ctx, "svc1", "zproxy", certificate.TypeVHost, "zenoss5", []string{"zenoss5.example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(cert.ACME, Equals, true)
+	c.Assert(cert.RenewalStatus, Equals, certificate.RenewalPending)
+	c.Assert(cert.Domains, DeepEquals, []string{"zenoss5.example.com"})
+	// the existing certificate is served until the new one is issued
+	c.Assert(cert.CertPEM, Equals, proxy.InsecureCertPEM)
+}
+
+func (ft *FacadeUnitTest) Test_SetPublicEndpointACME_InvalidDomain(c *C) {
+	ft.setupPublicEndpointService("svc1")
+
+	_, err := ft.Facade.SetPublicEndpointACME(ft.ctx, "svc1", "zproxy", certificate.TypeVHost, "zenoss5", []string{"zenoss5"})
+	c.Assert(err, ErrorMatches, "Invalid domain name zenoss5")
+	_, err = ft.Facade.SetPublicEndpointACME(ft.ctx, "svc1", "zproxy", certificate.TypeVHost, "zenoss5", nil)
+	c.Assert(err, NotNil)
+	ft.certStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_UpdatePublicEndpointCertificate(c *C) {
+	existing := certificate.NewACME(certificate.TypeVHost, "zenoss5", "svc1", "zproxy", []string{"zenoss5.example.com"})
+	ft.certStore.On("Get", ft.ctx, "vhost-zenoss5").Return(existing, nil)
+	ft.certStore.On("Put", ft.ctx, mock.AnythingOfType("*certificate.Certificate")).Return(nil)
+
+	failed := *existing
+	failed.RenewalStatus = certificate.RenewalFailed
+	failed.RenewalError = "challenge failed"
+	c.Assert(ft.Facade.UpdatePublicEndpointCertificate(ft.ctx, &failed), IsNil)
+
+	// the domains were changed while the certificate was being issued
+	changed := *existing
+	changed.Domains = []string{"other.example.com"}
+	c.Assert(ft.Facade.UpdatePublicEndpointCertificate(ft.ctx, &changed), NotNil)
+	ft.certStore.AssertNumberOfCalls(c, "Put", 1)
+}
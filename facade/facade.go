@@ -14,15 +14,18 @@
 package facade
 
 import (
+	"sync"
 	"time"
 
 	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/hostkey"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/scriptjob"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
@@ -37,6 +40,8 @@ import (
 type MetricsClient interface {
 	GetInstanceMemoryStats(time.Time, ...metrics.ServiceInstance) ([]metrics.MemoryUsageStats, error)
 	GetAvailableStorage(time.Duration, string, ...string) (*metrics.StorageMetrics, error)
+	GetServiceTraffic(time.Duration, string) ([]metrics.TrafficStats, error)
+	GetServiceMetricAverage(time.Duration, string, string, bool) (float64, error)
 }
 
 // instantiate the package logger
@@ -58,6 +63,9 @@ func New() *Facade {
 		templateStore:  servicetemplate.NewStore(),
 		logFilterStore: logfilter.NewStore(),
 		userStore:      user.NewStore(),
+		scriptJobStore: scriptjob.NewStore(),
+		certStore:      certificate.NewStore(),
+		scriptJobs:     make(map[string]struct{}),
 		serviceCache:   NewServiceCache(),
 		poolCache:      NewPoolCache(),
 		hostRegistry:   auth.NewHostExpirationRegistry(),
@@ -77,6 +85,8 @@ type Facade struct {
 	serviceStore   service.Store
 	configStore    serviceconfigfile.Store
 	userStore      user.Store
+	scriptJobStore scriptjob.Store
+	certStore      certificate.Store
 
 	auditLogger   audit.Logger
 	zzk           ZZK
@@ -91,6 +101,13 @@ type Facade struct {
 	isvcsPath     string
 
 	rollingRestartTimeout time.Duration
+
+	scriptConfigurer ScriptConfigurer
+	scriptJobs       map[string]struct{} // ids of the script jobs run by this facade
+	scriptJobLock    sync.Mutex
+
+	autoscaledAt  map[string]time.Time // when the autoscaler last scaled each service, by id
+	autoscaleLock sync.Mutex
 }
 
 func (f *Facade) SetAuditLogger(logger audit.Logger) { f.auditLogger = logger }
@@ -124,6 +141,12 @@ func (f *Facade) SetConfigStore(store serviceconfigfile.Store) { f.configStore =
 
 func (f *Facade) SetUserStore(store user.Store) { f.userStore = store }
 
+func (f *Facade) SetScriptJobStore(store scriptjob.Store) { f.scriptJobStore = store }
+
+func (f *Facade) SetCertificateStore(store certificate.Store) { f.certStore = store }
+
+func (f *Facade) SetScriptConfigurer(configurer ScriptConfigurer) { f.scriptConfigurer = configurer }
+
 func (f *Facade) SetTemplateStore(store servicetemplate.Store) { f.templateStore = store }
 
 func (f *Facade) SetLogFilterStore(store logfilter.Store) { f.logFilterStore = store }
@@ -31,6 +31,8 @@ import (
 	configmocks "github.com/control-center/serviced/domain/serviceconfigfile/mocks"
 	templatemocks "github.com/control-center/serviced/domain/servicetemplate/mocks"
 	logfiltermocks "github.com/control-center/serviced/domain/logfilter/mocks"
+	scriptjobmocks "github.com/control-center/serviced/domain/scriptjob/mocks"
+	certificatemocks "github.com/control-center/serviced/domain/certificate/mocks"
 	"github.com/control-center/serviced/facade"
 	zzkmocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/control-center/serviced/metrics"
@@ -53,6 +55,8 @@ type FacadeUnitTest struct {
 	configStore      *configmocks.Store
 	templateStore    *templatemocks.Store
 	logFilterStore   *logfiltermocks.Store
+	scriptJobStore   *scriptjobmocks.Store
+	certStore        *certificatemocks.Store
 	metricsClient    *zzkmocks.MetricsClient
 	hostauthregistry *authmocks.HostExpirationRegistryInterface
 }
@@ -63,6 +67,10 @@ func (ft *FacadeUnitTest) SetUpSuite(c *C) {
 	// Create a master key pair
 	pub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
 	auth.LoadMasterKeysFromPEM(pub, priv)
+
+	// Create the internal certificate authority
+	ca, _ := auth.GenerateCAPEM()
+	auth.LoadCAFromPEM(ca)
 }
 
 func (ft *FacadeUnitTest) SetUpTest(c *C) {
@@ -112,6 +120,13 @@ func (ft *FacadeUnitTest) SetUpTest(c *C) {
 	ft.logFilterStore = &logfiltermocks.Store{}
 	ft.Facade.SetLogFilterStore(ft.logFilterStore)
 
+	ft.scriptJobStore = &scriptjobmocks.Store{}
+	ft.Facade.SetScriptJobStore(ft.scriptJobStore)
+	ft.Facade.SetScriptConfigurer(nil)
+
+	ft.certStore = &certificatemocks.Store{}
+	ft.Facade.SetCertificateStore(ft.certStore)
+
 	ft.zzk = &zzkmocks.ZZK{}
 	ft.Facade.SetZZK(ft.zzk)
 
@@ -65,6 +65,33 @@ func (f *Facade) GetServiceHealth(ctx datastore.Context, serviceID string) (map[
 	return f.getServiceHealth(ctx, *sh)
 }
 
+// GetServiceHealthHistory returns the recent results of the health checks of
+// the instances of a service.
+func (f *Facade) GetServiceHealthHistory(ctx datastore.Context, serviceID string) (map[int]map[string]health.HealthHistory, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceHealthHistory"))
+	sh, err := f.serviceStore.GetServiceHealth(ctx, serviceID)
+	if err != nil {
+		glog.Errorf("Could not look up service %s: %s", serviceID, err)
+		return nil, err
+	}
+	history := make(map[int]map[string]health.HealthHistory)
+	for i := 0; i < sh.Instances; i++ {
+		checks := make(map[string]health.HealthHistory)
+		for name := range sh.HealthChecks {
+			key := health.HealthStatusKey{
+				ServiceID:       sh.ID,
+				InstanceID:      i,
+				HealthCheckName: name,
+			}
+			if h, ok := f.hcache.History(key); ok {
+				checks[name] = h
+			}
+		}
+		history[i] = checks
+	}
+	return history, nil
+}
+
 func (f *Facade) getServiceHealth(ctx datastore.Context, sh service.ServiceHealth) (map[int]map[string]health.HealthStatus, error) {
 	states, err := f.zzk.GetServiceStates(ctx, sh.PoolID, sh.ID)
 	if err != nil {
@@ -208,8 +208,15 @@ func (f *Facade) generateDelegateKey(ctx datastore.Context, entity *host.Host) (
 	// Reset the host's "authenticated" status
 	f.RemoveHostExpiration(ctx, entity.ID)
 
+	// Issue the host's mux certificate
+	certPEM, err := hostCertificatePEM(entity, publicPEM)
+	if err != nil {
+		return nil, err
+	}
+
 	// Concatenate and return keys
 	delegatePEMBlock := append(privatePEM, masterPEM...)
+	delegatePEMBlock = append(delegatePEMBlock, certPEM...)
 	return delegatePEMBlock, nil
 }
 
@@ -245,11 +252,40 @@ func (f *Facade) useCommonKey(ctx datastore.Context, entity *host.Host) ([]byte,
 	// Reset the host's "authenticated" status
 	f.RemoveHostExpiration(ctx, entity.ID)
 
+	// Issue the host's mux certificate
+	certPEM, err := hostCertificatePEM(entity, publicPEM)
+	if err != nil {
+		return nil, err
+	}
+
 	// Concatenate and return keys
 	delegatePEMBlock := append(privatePEM, masterPEM...)
+	delegatePEMBlock = append(delegatePEMBlock, certPEM...)
 	return delegatePEMBlock, nil
 }
 
+// hostCertificatePEM issues a mux certificate for the delegate key of the
+// host, followed by the certificates of the authorities the host trusts.
+// Returns nothing if the internal certificate authority is not loaded.
+func hostCertificatePEM(entity *host.Host, publicPEM []byte) ([]byte, error) {
+	caPEM, err := auth.GetCACertificatesPEM()
+	if err == auth.ErrNoCA {
+		glog.Warningf("Not issuing a mux certificate for host %s: %s", entity.ID, err)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	public, err := auth.RSAPublicKeyFromPEM(publicPEM)
+	if err != nil {
+		return nil, err
+	}
+	certPEM, err := auth.IssueHostCertificate(entity.ID, entity.IPAddr, public)
+	if err != nil {
+		return nil, err
+	}
+	return append(certPEM, caPEM...), nil
+}
+
 // UpdateHost information for a registered host
 func (f *Facade) UpdateHost(ctx datastore.Context, entity *host.Host) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UpdateHost"))
@@ -578,6 +614,7 @@ func toReadHost(h host.Host) host.ReadHost {
 			Release: h.ServiceD.Release,
 		},
 		IPs:       h.IPs,
+		Drained:   h.Drained,
 		CreatedAt: h.CreatedAt,
 		UpdatedAt: h.UpdatedAt,
 	}
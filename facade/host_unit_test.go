@@ -16,6 +16,8 @@
 package facade_test
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"time"
 
@@ -163,6 +165,19 @@ func (ft *FacadeUnitTest) Test_AddHost_HappyPath(c *C) {
 	_, _, err = auth.LoadRSAKeyPairPackage(result)
 	c.Assert(err, IsNil)
 
+	// followed by the host's mux certificate and the certificate authority
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(result); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			c.Assert(err, IsNil)
+			certs = append(certs, cert)
+		}
+	}
+	c.Assert(certs, HasLen, 2)
+	c.Assert(certs[0].Subject.CommonName, Equals, h.ID)
+	c.Assert(certs[0].CheckSignatureFrom(certs[1]), IsNil)
+
 	// Make sure we reset the auth registry
 	ft.hostauthregistry.AssertCalled(c, "Remove", h.ID)
 }
@@ -16,10 +16,12 @@ package facade
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/dfs/docker"
@@ -254,6 +256,7 @@ func (f *Facade) getInstance(ctx datastore.Context, hst host.Host, svc service.S
 		Scheduled:     state.Scheduled,
 		Started:       state.Started,
 		Terminated:    state.Terminated,
+		Restarts:      state.Restart.Restarts,
 	}
 	logger.Debug("Loaded service instance")
 
@@ -438,15 +441,18 @@ func (f *Facade) GetHostStrategyInstances(ctx datastore.Context, hosts []host.Ho
 					return nil, err
 				}
 				inst = service.StrategyInstance{
-					ServiceID:     s.ID,
-					CPUCommitment: int(s.CPUCommitment),
-					RAMCommitment: s.RAMCommitment.Value,
-					HostPolicy:    s.HostPolicy,
+					ServiceID:      s.ID,
+					CPUCommitment:  int(s.CPUCommitment),
+					RAMCommitment:  s.RAMCommitment.Value,
+					HostPolicy:     s.HostPolicy,
+					Priority:       s.Priority,
+					NonPreemptible: s.NonPreemptible,
 				}
 				svcMap[state.ServiceID] = inst
 			}
 
 			inst.HostID = state.HostID
+			inst.InstanceID = state.InstanceID
 			insts = append(insts, &inst)
 		}
 
@@ -479,6 +485,48 @@ func (f *Facade) StopServiceInstance(ctx datastore.Context, serviceID string, in
 	return nil
 }
 
+// PreemptServiceInstance stops a service instance to make room in its pool for
+// an instance of a higher priority service, recording the decision in the
+// audit log.
+func (f *Facade) PreemptServiceInstance(ctx datastore.Context, serviceID string, instanceID int, preemptorID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PreemptServiceInstance"))
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	preemptor, err := f.serviceStore.Get(ctx, preemptorID)
+	if err != nil {
+		return err
+	}
+	alog := f.auditLogger.Action(audit.Preempt).Message(ctx, "Preempt Service Instance").WithField("servicename", svc.Name).Entity(svc).
+		WithFields(log.Fields{
+			"instanceid":        strconv.Itoa(instanceID),
+			"priority":          strconv.Itoa(svc.Priority),
+			"preemptorid":       preemptor.ID,
+			"preemptorname":     preemptor.Name,
+			"preemptorpriority": strconv.Itoa(preemptor.Priority),
+		})
+	if svc.NonPreemptible {
+		return alog.Error(ErrServiceNonPreemptible)
+	} else if svc.Priority >= preemptor.Priority {
+		return alog.Error(ErrServicePriority)
+	}
+
+	if err := f.zzk.StopServiceInstance(svc.PoolID, svc.ID, instanceID); err != nil {
+		return alog.Error(err)
+	}
+
+	plog.WithFields(log.Fields{
+		"serviceid":     svc.ID,
+		"servicename":   svc.Name,
+		"instanceid":    instanceID,
+		"preemptorid":   preemptor.ID,
+		"preemptorname": preemptor.Name,
+	}).Info("Preempted service instance")
+	alog.Succeeded()
+	return nil
+}
+
 // LocateServiceInstance returns host and container information about a service
 // instance
 func (f *Facade) LocateServiceInstance(ctx datastore.Context, serviceID string, instanceID int) (*service.LocationInstance, error) {
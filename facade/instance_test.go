@@ -432,6 +432,7 @@ func (ft *FacadeUnitTest) TestGetHostStrategyInstances(c *C) {
 		hst1.ID: {
 			HostID:        hst1.ID,
 			ServiceID:     svc.ID,
+			InstanceID:    1,
 			CPUCommitment: int(svc.CPUCommitment),
 			RAMCommitment: svc.RAMCommitment.Value,
 			HostPolicy:    svc.HostPolicy,
@@ -439,6 +440,7 @@ func (ft *FacadeUnitTest) TestGetHostStrategyInstances(c *C) {
 		hst2.ID: {
 			HostID:        hst2.ID,
 			ServiceID:     svc.ID,
+			InstanceID:    2,
 			CPUCommitment: int(svc.CPUCommitment),
 			RAMCommitment: svc.RAMCommitment.Value,
 			HostPolicy:    svc.HostPolicy,
@@ -20,8 +20,10 @@ import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/metrics"
 
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
@@ -114,6 +116,8 @@ type FacadeInterface interface {
 
 	GetPoolIPs(ctx datastore.Context, poolID string) (*pool.PoolIPs, error)
 
+	GetPoolUsage(ctx datastore.Context, poolID string) (*pool.Usage, error)
+
 	HasIP(ctx datastore.Context, poolID string, ipAddr string) (bool, error)
 
 	RemoveResourcePool(ctx datastore.Context, id string) error
@@ -134,12 +138,32 @@ type FacadeInterface interface {
 
 	EnablePublicEndpointVHost(ctx datastore.Context, serviceid, endpointName, vhost string, isEnabled bool) error
 
+	SetPublicEndpointPortPolicy(ctx datastore.Context, serviceid, endpointName, portAddr string, policy servicedefinition.AccessPolicy) error
+
+	SetPublicEndpointVHostPolicy(ctx datastore.Context, serviceid, endpointName, vhost string, policy servicedefinition.AccessPolicy) error
+
+	SetPublicEndpointCertificate(ctx datastore.Context, serviceID, endpointName, certType, name, certPEM, keyPEM string) (*certificate.Certificate, error)
+
+	RemovePublicEndpointCertificate(ctx datastore.Context, certType, name string) error
+
+	GetPublicEndpointCertificates(ctx datastore.Context) ([]certificate.Certificate, error)
+
+	SetPublicEndpointACME(ctx datastore.Context, serviceID, endpointName, certType, name string, domains []string) (*certificate.Certificate, error)
+
+	UpdatePublicEndpointCertificate(ctx datastore.Context, cert *certificate.Certificate) error
+
 	GetHostInstances(ctx datastore.Context, since time.Time, hostid string) ([]service.Instance, error)
 
 	ListTenants(datastore.Context) ([]string, error)
 
 	GetServiceInstances(ctx datastore.Context, since time.Time, serviceid string) ([]service.Instance, error)
 
+	GetHostStrategyInstances(ctx datastore.Context, hosts []host.Host) ([]*service.StrategyInstance, error)
+
+	StopServiceInstance(ctx datastore.Context, serviceID string, instanceID int) error
+
+	PreemptServiceInstance(ctx datastore.Context, serviceID string, instanceID int, preemptorID string) error
+
 	GetAggregateServices(ctx datastore.Context, since time.Time, serviceids []string) ([]service.AggregateService, error)
 
 	GetReadPools(ctx datastore.Context) ([]pool.ReadPool, error)
@@ -166,6 +190,8 @@ type FacadeInterface interface {
 
 	GetServiceExportedEndpoints(ctx datastore.Context, serviceID string, children bool) ([]service.ExportedEndpoint, error)
 
+	GetServiceTraffic(ctx datastore.Context, serviceID string, window time.Duration) (*metrics.ServiceTraffic, error)
+
 	AddUser(ctx datastore.Context, newUser user.User) error
 
 	GetUser(ctx datastore.Context, userName string) (user.User, error)
@@ -180,6 +206,10 @@ type FacadeInterface interface {
 
 	GetServicesHealth(ctx datastore.Context) (map[string]map[int]map[string]health.HealthStatus, error)
 
+	GetServiceHealth(ctx datastore.Context, serviceID string) (map[int]map[string]health.HealthStatus, error)
+
+	GetServiceHealthHistory(ctx datastore.Context, serviceID string) (map[int]map[string]health.HealthHistory, error)
+
 	ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration)
 
 	ReportInstanceDead(serviceID string, instanceID int)
@@ -219,4 +249,6 @@ type FacadeInterface interface {
 	StopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error)
 
 	PauseService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error)
+
+	AutoscaleServices(ctx datastore.Context, poolID string) error
 }
@@ -6,6 +6,8 @@ import datastore "github.com/control-center/serviced/datastore"
 import domain "github.com/control-center/serviced/domain"
 
 import health "github.com/control-center/serviced/health"
+import metrics "github.com/control-center/serviced/metrics"
+import certificate "github.com/control-center/serviced/domain/certificate"
 import host "github.com/control-center/serviced/domain/host"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
@@ -204,6 +206,121 @@ func (_m *FacadeInterface) AssignIPs(ctx datastore.Context, assignmentRequest ad
 	return r0
 }
 
+// GetPoolUsage provides a mock function with given fields: ctx, poolID
+func (_m *FacadeInterface) GetPoolUsage(ctx datastore.Context, poolID string) (*pool.Usage, error) {
+	ret := _m.Called(ctx, poolID)
+
+	var r0 *pool.Usage
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *pool.Usage); ok {
+		r0 = rf(ctx, poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pool.Usage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicEndpointCertificates provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetPublicEndpointCertificates(ctx datastore.Context) ([]certificate.Certificate, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []certificate.Certificate
+	if rf, ok := ret.Get(0).(func(datastore.Context) []certificate.Certificate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceHealth provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceHealth(ctx datastore.Context, serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 map[int]map[string]health.HealthStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) map[int]map[string]health.HealthStatus); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]map[string]health.HealthStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceHealthHistory provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceHealthHistory(ctx datastore.Context, serviceID string) (map[int]map[string]health.HealthHistory, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 map[int]map[string]health.HealthHistory
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) map[int]map[string]health.HealthHistory); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]map[string]health.HealthHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceTraffic provides a mock function with given fields: ctx, serviceID, window
+func (_m *FacadeInterface) GetServiceTraffic(ctx datastore.Context, serviceID string, window time.Duration) (*metrics.ServiceTraffic, error) {
+	ret := _m.Called(ctx, serviceID, window)
+
+	var r0 *metrics.ServiceTraffic
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, time.Duration) *metrics.ServiceTraffic); ok {
+		r0 = rf(ctx, serviceID, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metrics.ServiceTraffic)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, serviceID, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveIPs provides a mock function with given fields: ctx, []string
 func (_m *FacadeInterface) RemoveIPs(ctx datastore.Context, args []string) error {
 	ret := _m.Called(ctx, args)
@@ -1027,6 +1144,57 @@ func (_m *FacadeInterface) GetServiceInstances(ctx datastore.Context, since time
 	return r0, r1
 }
 
+// GetHostStrategyInstances provides a mock function with given fields: ctx, hosts
+func (_m *FacadeInterface) GetHostStrategyInstances(ctx datastore.Context, hosts []host.Host) ([]*service.StrategyInstance, error) {
+	ret := _m.Called(ctx, hosts)
+
+	var r0 []*service.StrategyInstance
+	if rf, ok := ret.Get(0).(func(datastore.Context, []host.Host) []*service.StrategyInstance); ok {
+		r0 = rf(ctx, hosts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*service.StrategyInstance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, []host.Host) error); ok {
+		r1 = rf(ctx, hosts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StopServiceInstance provides a mock function with given fields: ctx, serviceID, instanceID
+func (_m *FacadeInterface) StopServiceInstance(ctx datastore.Context, serviceID string, instanceID int) error {
+	ret := _m.Called(ctx, serviceID, instanceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, int) error); ok {
+		r0 = rf(ctx, serviceID, instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PreemptServiceInstance provides a mock function with given fields: ctx, serviceID, instanceID, preemptorID
+func (_m *FacadeInterface) PreemptServiceInstance(ctx datastore.Context, serviceID string, instanceID int, preemptorID string) error {
+	ret := _m.Called(ctx, serviceID, instanceID, preemptorID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, int, string) error); ok {
+		r0 = rf(ctx, serviceID, instanceID, preemptorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetServiceMonitoringProfile provides a mock function with given fields: ctx, serviceID
 func (_m *FacadeInterface) GetServiceMonitoringProfile(ctx datastore.Context, serviceID string) (*domain.MonitorProfile, error) {
 	ret := _m.Called(ctx, serviceID)
@@ -1377,6 +1545,20 @@ func (_m *FacadeInterface) RemoveHostExpiration(ctx datastore.Context, hostID st
 	_m.Called(ctx, hostID)
 }
 
+// RemovePublicEndpointCertificate provides a mock function with given fields: ctx, certType, name
+func (_m *FacadeInterface) RemovePublicEndpointCertificate(ctx datastore.Context, certType string, name string) error {
+	ret := _m.Called(ctx, certType, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, certType, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemovePublicEndpointPort provides a mock function with given fields: ctx, serviceid, endpointName, portAddr
 func (_m *FacadeInterface) RemovePublicEndpointPort(ctx datastore.Context, serviceid string, endpointName string, portAddr string) error {
 	ret := _m.Called(ctx, serviceid, endpointName, portAddr)
@@ -1608,6 +1790,80 @@ func (_m *FacadeInterface) RestartService(ctx datastore.Context, request dao.Sch
 	return r0, r1
 }
 
+// SetPublicEndpointACME provides a mock function with given fields: ctx, serviceID, endpointName, certType, name, domains
+func (_m *FacadeInterface) SetPublicEndpointACME(ctx datastore.Context, serviceID string, endpointName string, certType string, name string, domains []string) (*certificate.Certificate, error) {
+	ret := _m.Called(ctx, serviceID, endpointName, certType, name, domains)
+
+	var r0 *certificate.Certificate
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, string, []string) *certificate.Certificate); ok {
+		r0 = rf(ctx, serviceID, endpointName, certType, name, domains)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, string, string, []string) error); ok {
+		r1 = rf(ctx, serviceID, endpointName, certType, name, domains)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPublicEndpointCertificate provides a mock function with given fields: ctx, serviceID, endpointName, certType, name, certPEM, keyPEM
+func (_m *FacadeInterface) SetPublicEndpointCertificate(ctx datastore.Context, serviceID string, endpointName string, certType string, name string, certPEM string, keyPEM string) (*certificate.Certificate, error) {
+	ret := _m.Called(ctx, serviceID, endpointName, certType, name, certPEM, keyPEM)
+
+	var r0 *certificate.Certificate
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, string, string, string) *certificate.Certificate); ok {
+		r0 = rf(ctx, serviceID, endpointName, certType, name, certPEM, keyPEM)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, string, string, string, string) error); ok {
+		r1 = rf(ctx, serviceID, endpointName, certType, name, certPEM, keyPEM)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPublicEndpointPortPolicy provides a mock function with given fields: ctx, serviceid, endpointName, portAddr, policy
+func (_m *FacadeInterface) SetPublicEndpointPortPolicy(ctx datastore.Context, serviceid string, endpointName string, portAddr string, policy servicedefinition.AccessPolicy) error {
+	ret := _m.Called(ctx, serviceid, endpointName, portAddr, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(ctx, serviceid, endpointName, portAddr, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPublicEndpointVHostPolicy provides a mock function with given fields: ctx, serviceid, endpointName, vhost, policy
+func (_m *FacadeInterface) SetPublicEndpointVHostPolicy(ctx datastore.Context, serviceid string, endpointName string, vhost string, policy servicedefinition.AccessPolicy) error {
+	ret := _m.Called(ctx, serviceid, endpointName, vhost, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(ctx, serviceid, endpointName, vhost, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SyncServiceRegistry provides a mock function with given fields: ctx, svc
 func (_m *FacadeInterface) SyncServiceRegistry(ctx datastore.Context, svc *service.Service) error {
 	ret := _m.Called(ctx, svc)
@@ -1636,6 +1892,20 @@ func (_m *FacadeInterface) UpdateHost(ctx datastore.Context, entity *host.Host)
 	return r0
 }
 
+// UpdatePublicEndpointCertificate provides a mock function with given fields: ctx, cert
+func (_m *FacadeInterface) UpdatePublicEndpointCertificate(ctx datastore.Context, cert *certificate.Certificate) error {
+	ret := _m.Called(ctx, cert)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *certificate.Certificate) error); ok {
+		r0 = rf(ctx, cert)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateResourcePool provides a mock function with given fields: ctx, entity
 func (_m *FacadeInterface) UpdateResourcePool(ctx datastore.Context, entity *pool.ResourcePool) error {
 	ret := _m.Called(ctx, entity)
@@ -1807,3 +2077,17 @@ func (_m *FacadeInterface) GetServiceNamePath(ctx datastore.Context, serviceID s
 
 	return r0, r1, r2
 }
+
+// AutoscaleServices provides a mock function with given fields: ctx, poolID
+func (_m *FacadeInterface) AutoscaleServices(ctx datastore.Context, poolID string) error {
+	ret := _m.Called(ctx, poolID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -54,3 +54,47 @@ func (_m *MetricsClient) GetInstanceMemoryStats(_a0 time.Time, _a1 ...metrics.Se
 
 	return r0, r1
 }
+
+// GetServiceMetricAverage provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *MetricsClient) GetServiceMetricAverage(_a0 time.Duration, _a1 string, _a2 string, _a3 bool) (float64, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(time.Duration, string, string, bool) float64); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Duration, string, string, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceTraffic provides a mock function with given fields: _a0, _a1
+func (_m *MetricsClient) GetServiceTraffic(_a0 time.Duration, _a1 string) ([]metrics.TrafficStats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []metrics.TrafficStats
+	if rf, ok := ret.Get(0).(func(time.Duration, string) []metrics.TrafficStats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]metrics.TrafficStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Duration, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupPreemption(victim, preemptor service.Service) {
+	ft.serviceStore.On("Get", ft.ctx, victim.ID).Return(&victim, nil)
+	ft.serviceStore.On("Get", ft.ctx, preemptor.ID).Return(&preemptor, nil)
+}
+
+func (ft *FacadeUnitTest) Test_PreemptServiceInstance(c *C) {
+	victim := service.Service{ID: "batch", Name: "Batch", PoolID: "default", Priority: 1}
+	preemptor := service.Service{ID: "web", Name: "Web", PoolID: "default", Priority: 10}
+	ft.setupPreemption(victim, preemptor)
+	ft.zzk.On("StopServiceInstance", "default", "batch", 2).Return(nil)
+
+	err := ft.Facade.PreemptServiceInstance(ft.ctx, "batch", 2, "web")
+	c.Assert(err, IsNil)
+	ft.zzk.AssertCalled(c, "StopServiceInstance", "default", "batch", 2)
+}
+
+func (ft *FacadeUnitTest) Test_PreemptServiceInstance_NonPreemptible(c *C) {
+	victim := service.Service{ID: "batch", Name: "Batch", PoolID: "default", Priority: 1, NonPreemptible: true}
+	preemptor := service.Service{ID: "web", Name: "Web", PoolID: "default", Priority: 10}
+	ft.setupPreemption(victim, preemptor)
+
+	err := ft.Facade.PreemptServiceInstance(ft.ctx, "batch", 2, "web")
+	c.Assert(err, Equals, facade.ErrServiceNonPreemptible)
+	ft.zzk.AssertNotCalled(c, "StopServiceInstance", "default", "batch", 2)
+}
+
+func (ft *FacadeUnitTest) Test_PreemptServiceInstance_SamePriority(c *C) {
+	victim := service.Service{ID: "batch", Name: "Batch", PoolID: "default", Priority: 10}
+	preemptor := service.Service{ID: "web", Name: "Web", PoolID: "default", Priority: 10}
+	ft.setupPreemption(victim, preemptor)
+
+	err := ft.Facade.PreemptServiceInstance(ft.ctx, "batch", 2, "web")
+	c.Assert(err, Equals, facade.ErrServicePriority)
+	ft.zzk.AssertNotCalled(c, "StopServiceInstance", "default", "batch", 2)
+}
@@ -15,6 +15,7 @@ package facade
 
 import (
 	"fmt"
+	"io"
 	"net"
 	"regexp"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/zenoss/glog"
@@ -58,10 +60,15 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 		return nil, alog.Error(err)
 	}
 
+	if err := (servicedefinition.Port{UseTLS: usetls, Protocol: protocol}).ValidProtocol(); err != nil {
+		glog.Error(err)
+		return nil, alog.Error(err)
+	}
+
 	// Check to make sure the port is available.  Don't allow adding a port if it's already being used.
 	// This has the added benefit of validating the port address before it gets added to the service
 	// definition.
-	if err := checkPort("tcp", fmt.Sprintf("%s", portAddr)); err != nil {
+	if err := checkPort(portNetwork(protocol), fmt.Sprintf("%s", portAddr)); err != nil {
 		glog.Error(err)
 		return nil, alog.Error(err)
 	}
@@ -131,7 +138,13 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 // Try to open the port.  If the port opens, we're good. Otherwise return the error.
 func checkPort(network string, laddr string) error {
 	glog.V(2).Infof("Checking %s port %s", network, laddr)
-	listener, err := net.Listen(network, laddr)
+	var listener io.Closer
+	var err error
+	if network == "udp" {
+		listener, err = net.ListenPacket(network, laddr)
+	} else {
+		listener, err = net.Listen(network, laddr)
+	}
 	if err != nil {
 		// Port isn't available.
 		glog.V(2).Infof("Port Listen failed; something else is using this port.")
@@ -144,6 +157,14 @@ func checkPort(network string, laddr string) error {
 	return nil
 }
 
+// portNetwork returns the network a public port with the protocol listens on
+func portNetwork(protocol string) string {
+	if protocol == servicedefinition.PortProtocolUDP {
+		return "udp"
+	}
+	return "tcp"
+}
+
 // Remove the port public endpoint from a service.
 func (f *Facade) RemovePublicEndpointPort(ctx datastore.Context, serviceid, endpointName, portAddr string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemovePublicEndpointPort"))
@@ -179,6 +200,7 @@ func (f *Facade) RemovePublicEndpointPort(ctx datastore.Context, serviceid, endp
 	}
 
 	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	f.deletePublicEndpointCertificate(ctx, certificate.TypePort, portAddr)
 	alog.Succeeded()
 	return nil
 }
@@ -270,7 +292,7 @@ func (f *Facade) EnablePublicEndpointPort(ctx datastore.Context, serviceid, endp
 			return alog.Error(err)
 		}
 
-		if err = checkPort("tcp", fmt.Sprintf("%s", portAddr)); err != nil {
+		if err = checkPort(portNetwork(port.Protocol), fmt.Sprintf("%s", portAddr)); err != nil {
 			glog.Error(err)
 			return alog.Error(err)
 		}
@@ -405,6 +427,7 @@ func (f *Facade) RemovePublicEndpointVHost(ctx datastore.Context, serviceid, end
 	}
 
 	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	f.deletePublicEndpointCertificate(ctx, certificate.TypeVHost, vhost)
 	alog.Succeeded()
 	return nil
 }
@@ -467,6 +490,99 @@ func (f *Facade) EnablePublicEndpointVHost(ctx datastore.Context, serviceid, end
 	return nil
 }
 
+// SetPublicEndpointPortPolicy sets which clients may connect to a port public
+// endpoint and how often.  An empty policy admits every client.
+func (f *Facade) SetPublicEndpointPortPolicy(ctx datastore.Context, serviceid, endpointName, portAddr string, policy servicedefinition.AccessPolicy) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetPublicEndpointPortPolicy"))
+	alog := f.auditLogger.Message(ctx, "Setting Public Endpoint Port Policy").Action(audit.Update).ID(serviceid).
+		WithFields(publicEndpointPolicyFields(policy, logrus.Fields{
+			"endpointname": endpointName,
+			"portaddr":     portAddr,
+		}))
+	portAddr = service.ScrubPortString(portAddr)
+
+	if err := policy.ValidEntity(); err != nil {
+		err = fmt.Errorf("Invalid policy for port %s: %s", portAddr, err)
+		return alog.Error(err)
+	}
+
+	svc, err := f.GetService(ctx, serviceid)
+	if err != nil {
+		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
+		glog.Error(err)
+		return alog.Error(err)
+	}
+	alog = alog.Entity(svc)
+
+	if err = svc.SetPortPolicy(endpointName, portAddr, policy); err != nil {
+		glog.Error(err)
+		return alog.Error(err)
+	}
+
+	if err = f.UpdateService(ctx, *svc); err != nil {
+		glog.Error(err)
+		return alog.Error(err)
+	}
+
+	glog.V(2).Infof("Port (%s) policy set for service (%s)", portAddr, svc.Name)
+	alog.Succeeded()
+	return nil
+}
+
+// SetPublicEndpointVHostPolicy sets which clients may send requests to a
+// vhost public endpoint and how often.  An empty policy admits every client.
+func (f *Facade) SetPublicEndpointVHostPolicy(ctx datastore.Context, serviceid, endpointName, vhost string, policy servicedefinition.AccessPolicy) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetPublicEndpointVHostPolicy"))
+	alog := f.auditLogger.Message(ctx, "Setting Public Endpoint VHost Policy").Action(audit.Update).ID(serviceid).
+		WithFields(publicEndpointPolicyFields(policy, logrus.Fields{
+			"endpointname": endpointName,
+			"vhost":        vhost,
+		}))
+
+	if policy.ConnectionRate != 0 {
+		err := fmt.Errorf("Invalid policy for vhost %s: connection rate is not supported on vhosts; use a request rate", vhost)
+		return alog.Error(err)
+	}
+	if err := policy.ValidEntity(); err != nil {
+		err = fmt.Errorf("Invalid policy for vhost %s: %s", vhost, err)
+		return alog.Error(err)
+	}
+
+	svc, err := f.GetService(ctx, serviceid)
+	if err != nil {
+		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
+		glog.Error(err)
+		return alog.Error(err)
+	}
+	alog = alog.Entity(svc)
+
+	if err = svc.SetVirtualHostPolicy(endpointName, vhost, policy); err != nil {
+		glog.Error(err)
+		return alog.Error(err)
+	}
+
+	if err = f.UpdateService(ctx, *svc); err != nil {
+		glog.Error(err)
+		return alog.Error(err)
+	}
+
+	glog.V(2).Infof("VHost (%s) policy set for service (%s)", vhost, svc.Name)
+	alog.Succeeded()
+	return nil
+}
+
+// publicEndpointPolicyFields adds the settings of an access policy to the
+// fields of an audit log message
+func publicEndpointPolicyFields(policy servicedefinition.AccessPolicy, fields logrus.Fields) logrus.Fields {
+	fields["allow"] = strings.Join(policy.AllowCIDRs, ",")
+	fields["deny"] = strings.Join(policy.DenyCIDRs, ",")
+	fields["connectionrate"] = policy.ConnectionRate
+	fields["requestrate"] = policy.RequestRate
+	fields["burst"] = policy.RateBurst
+	fields["maxconnections"] = policy.MaxConnections
+	return fields
+}
+
 // GetAllPublicEndpoints returns all the public endpoints in the system
 func (f *Facade) GetAllPublicEndpoints(ctx datastore.Context) ([]service.PublicEndpoint, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetAllPublicEndpoints"))
@@ -139,6 +139,26 @@ func (ft *FacadeIntegrationTest) Test_PublicEndpoint_PortAdd_VerifyEnabledFlag(c
 	fmt.Println(" ##### Test_PublicEndpoint_PortAdd_VerifyEnabledFlag: PASSED")
 }
 
+func (ft *FacadeIntegrationTest) Test_PublicEndpoint_PortAdd_UDP(c *C) {
+	fmt.Println(" ##### Test_PublicEndpoint_PortAdd_UDP: starting")
+
+	// Add a service so we can test our public endpoint.
+	_, svcB := ft.setupServiceWithPublicEndpoints(c)
+
+	// Add mock calls.
+	ft.zzk.On("GetPublicPort", ":12514").Return("", "", nil)
+
+	// udp ports do not terminate tls
+	_, err := ft.Facade.AddPublicEndpointPort(ft.CTX, svcB.ID, "service2", ":12514", true, servicedefinition.PortProtocolUDP, true, false)
+	c.Assert(err, NotNil)
+
+	port, err := ft.Facade.AddPublicEndpointPort(ft.CTX, svcB.ID, "service2", ":12514", false, servicedefinition.PortProtocolUDP, true, false)
+	c.Assert(err, IsNil)
+	c.Assert(port.Protocol, Equals, servicedefinition.PortProtocolUDP)
+
+	fmt.Println(" ##### Test_PublicEndpoint_PortAdd_UDP: PASSED")
+}
+
 func (ft *FacadeIntegrationTest) Test_PublicEndpoint_PortAdd_DuplicatePort(c *C) {
 	fmt.Println(" ##### Test_PublicEndpoint_PortAdd_DuplicatePort: starting")
 
@@ -682,6 +702,46 @@ func (ft *FacadeIntegrationTest) Test_PublicEndpointVHost_InvalidVHost(c *C) {
 	fmt.Println(" ##### Test_PublicEndpointVHost_InvalidVHost: PASSED")
 }
 
+func (ft *FacadeIntegrationTest) Test_PublicEndpoint_PortPolicy(c *C) {
+	svcA, _ := ft.setupServiceWithPublicEndpoints(c)
+
+	policy := servicedefinition.AccessPolicy{
+		AllowCIDRs:     []string{"10.0.0.0/8"},
+		ConnectionRate: 10,
+		MaxConnections: 100,
+	}
+	ft.zzk.On("GetVHost", "zproxy").Return(svcA.ID, "zproxy", nil).Once()
+	ft.zzk.On("GetPublicPort", ":22222").Return(svcA.ID, "zproxy", nil).Once()
+	err := ft.Facade.SetPublicEndpointPortPolicy(ft.CTX, svcA.ID, "zproxy", "22222", policy)
+	c.Assert(err, IsNil)
+	svc, err := ft.Facade.GetService(ft.CTX, svcA.ID)
+	c.Assert(err, IsNil)
+	c.Assert(svc.Endpoints[0].PortList[0].Policy, DeepEquals, policy)
+
+	// invalid policies and unknown ports are refused
+	err = ft.Facade.SetPublicEndpointPortPolicy(ft.CTX, svcA.ID, "zproxy", ":22222", servicedefinition.AccessPolicy{DenyCIDRs: []string{"10.0.0.1"}})
+	c.Assert(err, NotNil)
+	err = ft.Facade.SetPublicEndpointPortPolicy(ft.CTX, svcA.ID, "zproxy", ":33333", policy)
+	c.Assert(err, NotNil)
+}
+
+func (ft *FacadeIntegrationTest) Test_PublicEndpoint_VHostPolicy(c *C) {
+	svcA, _ := ft.setupServiceWithPublicEndpoints(c)
+
+	policy := servicedefinition.AccessPolicy{DenyCIDRs: []string{"192.168.0.0/16"}, RequestRate: 50}
+	ft.zzk.On("GetVHost", "zproxy").Return(svcA.ID, "zproxy", nil).Once()
+	ft.zzk.On("GetPublicPort", ":22222").Return(svcA.ID, "zproxy", nil).Once()
+	err := ft.Facade.SetPublicEndpointVHostPolicy(ft.CTX, svcA.ID, "zproxy", "zproxy", policy)
+	c.Assert(err, IsNil)
+	svc, err := ft.Facade.GetService(ft.CTX, svcA.ID)
+	c.Assert(err, IsNil)
+	c.Assert(svc.Endpoints[0].VHostList[0].Policy, DeepEquals, policy)
+
+	// vhosts limit requests, not connections
+	err = ft.Facade.SetPublicEndpointVHostPolicy(ft.CTX, svcA.ID, "zproxy", "zproxy", servicedefinition.AccessPolicy{ConnectionRate: 5})
+	c.Assert(err, NotNil)
+}
+
 func (ft *FacadeIntegrationTest) Test_PublicEndpoint_SetAddressConfig(c *C) {
 	fmt.Println(" ##### Test_PublicEndpoint_SetAddressConfig: starting")
 
@@ -0,0 +1,127 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// GetPoolUsage returns the cores and memory committed to the running services
+// of a pool against the quotas and capacity of the pool.
+func (f *Facade) GetPoolUsage(ctx datastore.Context, poolID string) (*pool.Usage, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetPoolUsage"))
+	logger := plog.WithField("poolid", poolID)
+
+	p, err := f.GetResourcePool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up pool")
+		return nil, err
+	} else if p == nil {
+		return nil, ErrPoolNotExists
+	}
+	svcs, err := f.serviceStore.GetServicesByPool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up services in pool")
+		return nil, err
+	}
+	return poolUsage(p, svcs, nil), nil
+}
+
+// checkPoolQuota returns a pool.QuotaError if running the instances of the
+// given services would exceed the core or memory quota of their pools.  In
+// pools that preempt lower priority instances, the commitment of the
+// instances a service may preempt is left to the scheduler.
+func (f *Facade) checkPoolQuota(ctx datastore.Context, svcs []*service.Service) error {
+	byPool := make(map[string][]*service.Service)
+	for _, svc := range svcs {
+		byPool[svc.PoolID] = append(byPool[svc.PoolID], svc)
+	}
+
+	for poolID, psvcs := range byPool {
+		logger := plog.WithField("poolid", poolID)
+
+		var p pool.ResourcePool
+		if err := f.poolStore.Get(ctx, pool.Key(poolID), &p); err != nil {
+			logger.WithError(err).Debug("Could not look up pool")
+			return err
+		}
+		if p.CoreLimit == 0 && p.MemoryLimit == 0 {
+			continue
+		}
+		current, err := f.serviceStore.GetServicesByPool(ctx, poolID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up services in pool")
+			return err
+		}
+
+		for i, svc := range psvcs {
+			// the services being checked replace their stored commitment,
+			// and the instances the service may preempt do not count
+			exclude := make(map[string]struct{})
+			for _, other := range psvcs {
+				exclude[other.ID] = struct{}{}
+			}
+			for j := range current {
+				if canPreempt(&p, svc, &current[j]) {
+					exclude[current[j].ID] = struct{}{}
+				}
+			}
+			usage := poolUsage(&p, current, exclude)
+			for _, other := range psvcs[:i] {
+				if !canPreempt(&p, svc, other) {
+					usage.Commit(int(other.CPUCommitment), other.RAMCommitment.Value, other.Instances)
+				}
+			}
+
+			cores := int(svc.CPUCommitment) * svc.Instances
+			memory := svc.RAMCommitment.Value * uint64(svc.Instances)
+			if err := usage.Check(cores, memory); err != nil {
+				logger.WithError(err).WithField("serviceid", svc.ID).Debug("Service exceeds the quota of its pool")
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// canPreempt returns true if the instances of svc may preempt the instances
+// of other in pool p.
+func canPreempt(p *pool.ResourcePool, svc, other *service.Service) bool {
+	return p.PreemptionPolicy == pool.PreemptLowerPriority && !other.NonPreemptible && other.Priority < svc.Priority
+}
+
+// commitmentIncreased returns true if an update to a running service commits
+// more resources to its pool.
+func commitmentIncreased(cursvc, svc *service.Service) bool {
+	return cursvc.DesiredState != int(service.SVCRun) || cursvc.PoolID != svc.PoolID ||
+		svc.Instances > cursvc.Instances || svc.CPUCommitment > cursvc.CPUCommitment ||
+		svc.RAMCommitment.Value > cursvc.RAMCommitment.Value
+}
+
+// poolUsage returns the commitment of the running services of a pool, leaving
+// out the services in exclude.
+func poolUsage(p *pool.ResourcePool, svcs []service.Service, exclude map[string]struct{}) *pool.Usage {
+	usage := pool.NewUsage(p)
+	for _, svc := range svcs {
+		if _, ok := exclude[svc.ID]; ok {
+			continue
+		}
+		if svc.DesiredState == int(service.SVCRun) {
+			usage.Commit(int(svc.CPUCommitment), svc.RAMCommitment.Value, svc.Instances)
+		}
+	}
+	return usage
+}
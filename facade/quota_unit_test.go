@@ -0,0 +1,147 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	ssmmocks "github.com/control-center/serviced/scheduler/servicestatemanager/mocks"
+	"github.com/control-center/serviced/utils"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_GetPoolUsage(c *C) {
+	p := pool.ResourcePool{ID: "default", CoreLimit: 8, MemoryLimit: 4096}
+	svcs := []service.Service{
+		{
+			ID:            "running",
+			PoolID:        "default",
+			DesiredState:  int(service.SVCRun),
+			Instances:     2,
+			CPUCommitment: 1,
+			RAMCommitment: utils.EngNotation{Value: 1024},
+		}, {
+			ID:            "stopped",
+			PoolID:        "default",
+			DesiredState:  int(service.SVCStop),
+			Instances:     1,
+			CPUCommitment: 4,
+			RAMCommitment: utils.EngNotation{Value: 2048},
+		},
+	}
+	ft.setupPoolServices(c, p, svcs...)
+
+	usage, err := ft.Facade.GetPoolUsage(ft.ctx, "default")
+	c.Assert(err, IsNil)
+	c.Assert(*usage, DeepEquals, pool.Usage{
+		PoolID:           "default",
+		CoreLimit:        8,
+		CoreCommitment:   2,
+		MemoryLimit:      4096,
+		MemoryCommitment: 2048,
+	})
+}
+
+func (ft *FacadeUnitTest) Test_GetPoolUsage_NoSuchPool(c *C) {
+	ft.poolStore.On("Get", ft.ctx, pool.Key("default"), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(datastore.ErrNoSuchEntity{})
+
+	_, err := ft.Facade.GetPoolUsage(ft.ctx, "default")
+	c.Assert(err, Equals, facade.ErrPoolNotExists)
+}
+
+// setupFullPool fills the memory quota of a pool with the instances of a
+// running service of priority 0 and returns a stopped service of priority
+// 10 that needs half of the quota.  Scheduled services are sent to
+// scheduled.
+func (ft *FacadeUnitTest) setupFullPool(c *C, policy string, nonPreemptible bool, scheduled chan<- []*service.Service) service.Service {
+	p := pool.ResourcePool{ID: "default", MemoryLimit: 1024, PreemptionPolicy: policy}
+	running := service.Service{
+		ID:             "batch",
+		PoolID:         "default",
+		DesiredState:   int(service.SVCRun),
+		Instances:      2,
+		RAMCommitment:  utils.EngNotation{Value: 512},
+		NonPreemptible: nonPreemptible,
+	}
+	svc := service.Service{
+		ID:            "zope",
+		Name:          "Zope",
+		PoolID:        "default",
+		Launch:        "auto",
+		DesiredState:  int(service.SVCStop),
+		Instances:     1,
+		RAMCommitment: utils.EngNotation{Value: 512},
+		Priority:      10,
+	}
+	ft.setupPoolServices(c, p, running, svc)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, svc.ID).Return(&service.ServiceDetails{ID: svc.ID, Name: svc.Name}, nil)
+	ft.serviceStore.On("Get", ft.ctx, svc.ID).Return(&svc, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, svc.ID).Return([]service.Service{}, nil)
+
+	ssm := &ssmmocks.ServiceStateManager{}
+	ssm.On("ScheduleServices", mock.AnythingOfType("[]*service.Service"), svc.ID, service.SVCRun, false).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			scheduled <- args.Get(0).([]*service.Service)
+		})
+	ssm.On("WaitScheduled", svc.ID, []string{svc.ID}).Return()
+	ft.Facade.SetServiceStateManager(ssm)
+	return svc
+}
+
+func (ft *FacadeUnitTest) Test_StartService_PreemptInFullPool(c *C) {
+	scheduled := make(chan []*service.Service, 1)
+	svc := ft.setupFullPool(c, pool.PreemptLowerPriority, false, scheduled)
+	defer ft.Facade.SetServiceStateManager(nil)
+
+	// the scheduler preempts the lower priority instances to make room
+	count, err := ft.Facade.StartService(ft.ctx, dao.ScheduleServiceRequest{ServiceIDs: []string{svc.ID}, AutoLaunch: true, Synchronous: true})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 1)
+	svcs := <-scheduled
+	c.Assert(svcs, HasLen, 1)
+	c.Assert(svcs[0].ID, Equals, svc.ID)
+}
+
+func (ft *FacadeUnitTest) Test_StartService_FullPool(c *C) {
+	scheduled := make(chan []*service.Service, 1)
+	svc := ft.setupFullPool(c, pool.PreemptNever, false, scheduled)
+	defer ft.Facade.SetServiceStateManager(nil)
+
+	_, err := ft.Facade.StartService(ft.ctx, dao.ScheduleServiceRequest{ServiceIDs: []string{svc.ID}, AutoLaunch: true, Synchronous: true})
+	qerr, ok := err.(*pool.QuotaError)
+	c.Assert(ok, Equals, true)
+	c.Assert(qerr.Resource, Equals, "memory")
+	c.Assert(scheduled, HasLen, 0)
+}
+
+func (ft *FacadeUnitTest) Test_StartService_FullPoolNonPreemptible(c *C) {
+	scheduled := make(chan []*service.Service, 1)
+	svc := ft.setupFullPool(c, pool.PreemptLowerPriority, true, scheduled)
+	defer ft.Facade.SetServiceStateManager(nil)
+
+	// the running instances cannot make room
+	_, err := ft.Facade.StartService(ft.ctx, dao.ScheduleServiceRequest{ServiceIDs: []string{svc.ID}, AutoLaunch: true, Synchronous: true})
+	qerr, ok := err.(*pool.QuotaError)
+	c.Assert(ok, Equals, true)
+	c.Assert(qerr.Resource, Equals, "memory")
+	c.Assert(scheduled, HasLen, 0)
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"math"
+	"time"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/script"
+)
+
+// NewScriptConfigurer returns a configurer that lets script jobs act on the
+// system through the facade, the same way the cli does through the master.
+func (f *Facade) NewScriptConfigurer(snapshotSpacePercent int) ScriptConfigurer {
+	return func(config *script.Config) {
+		config.Snapshot = func(serviceID, message, tag string) (string, error) {
+			tags := []string{}
+			if tag != "" {
+				tags = []string{tag}
+			}
+			ctx := datastore.Get()
+			dfslocker := f.DFSLock(ctx)
+			dfslocker.Lock("snapshot")
+			defer dfslocker.Unlock()
+			return f.Snapshot(ctx, serviceID, message, tags, snapshotSpacePercent)
+		}
+		config.Restore = func(snapshotID string, forceRestart bool) error {
+			ctx := datastore.Get()
+			dfslocker := f.DFSLock(ctx)
+			dfslocker.Lock("rollback")
+			defer dfslocker.Unlock()
+			return f.Rollback(ctx, snapshotID, forceRestart)
+		}
+		config.Commit = func(containerID string) (string, error) {
+			ctx := datastore.Get()
+			dfslocker := f.DFSLock(ctx)
+			dfslocker.Lock("snapshot")
+			defer dfslocker.Unlock()
+			return f.Commit(ctx, containerID, "", []string{}, snapshotSpacePercent)
+		}
+		config.TenantLookup = func(serviceID string) (string, error) {
+			return f.GetTenantID(datastore.Get(), serviceID)
+		}
+		config.SvcIDFromPath = func(tenantID, svcPath string) (string, error) {
+			svcs, err := f.GetServiceDetailsByTenantID(datastore.Get(), tenantID)
+			if err != nil {
+				return "", err
+			}
+			return service.ServiceIDFromPath(svcs, svcPath)
+		}
+		config.SvcStart = f.scriptServiceControl(f.StartService)
+		config.SvcStop = f.scriptServiceControl(f.StopService)
+		config.SvcRestart = f.scriptServiceControl(f.RestartService)
+		config.SvcWait = func(serviceIDs []string, state script.ServiceState, timeout uint32, recursive bool) error {
+			if timeout == 0 {
+				timeout = math.MaxUint32
+			}
+			desiredState, err := script.ScriptStateToDesiredState(state)
+			if err != nil {
+				return err
+			}
+			return f.WaitService(datastore.Get(), desiredState, time.Duration(timeout)*time.Second, recursive, serviceIDs...)
+		}
+		config.SvcUse = func(tenantID, serviceID, imageID, registry string, replaceImgs []string, noOp bool) (string, error) {
+			return "", f.ServiceUse(datastore.Get(), tenantID, serviceID, imageID, registry, replaceImgs, noOp)
+		}
+		config.SvcState = func(serviceID string) (script.ServiceState, error) {
+			svc, err := f.GetServiceDetails(datastore.Get(), serviceID)
+			if err != nil {
+				return "", err
+			}
+			return script.DesiredStateToScriptState(service.DesiredState(svc.DesiredState))
+		}
+		config.SvcImage = func(serviceID string) (string, error) {
+			svc, err := f.GetServiceDetails(datastore.Get(), serviceID)
+			if err != nil {
+				return "", err
+			}
+			return svc.ImageID, nil
+		}
+		config.SvcChildren = func(tenantID, svcPath string) ([]string, error) {
+			svcs, err := f.GetServiceDetailsByTenantID(datastore.Get(), tenantID)
+			if err != nil {
+				return nil, err
+			}
+			return service.ChildServicePaths(svcs, svcPath)
+		}
+	}
+}
+
+// scriptServiceControl adapts a facade method that schedules services to the
+// signature used by scripts
+func (f *Facade) scriptServiceControl(control func(datastore.Context, dao.ScheduleServiceRequest) (int, error)) script.ServiceControl {
+	return func(serviceID string, recursive bool) error {
+		_, err := control(datastore.Get(), dao.ScheduleServiceRequest{ServiceIDs: []string{serviceID}, AutoLaunch: recursive})
+		return err
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/scriptjob"
+	"github.com/control-center/serviced/script"
+	"github.com/control-center/serviced/utils"
+)
+
+// ErrNoScriptConfigurer is returned when script jobs cannot be run on this host
+var ErrNoScriptConfigurer = errors.New("script jobs are not supported on this host")
+
+// ScriptConfigurer sets up the functions a script runner uses to act on the system
+type ScriptConfigurer func(config *script.Config)
+
+// RunScriptJob validates the script of a job, saves the job and runs the
+// script in the background. It returns the id of the new job.
+func (f *Facade) RunScriptJob(ctx datastore.Context, job *scriptjob.Job) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RunScriptJob"))
+	if f.scriptConfigurer == nil {
+		return "", ErrNoScriptConfigurer
+	}
+	var err error
+	if job.ID, err = utils.NewUUID36(); err != nil {
+		return "", err
+	}
+	alog := f.auditLogger.Message(ctx, "Running Script Job").Action(audit.Start).
+		Type(scriptjob.GetType()).ID(job.ID).
+		WithFields(logrus.Fields{
+			"serviceid": job.ServiceID,
+			"filename":  job.FileName,
+		})
+
+	job.Status = scriptjob.StatusRunning
+	job.CreatedAt = time.Now()
+	runner, err := f.newScriptJobRunner(job, 0)
+	if err != nil {
+		return "", alog.Error(err)
+	}
+	job.Steps = runner.Steps()
+	f.setScriptJobRunning(job.ID, true)
+	if err := f.saveScriptJob(ctx, job); err != nil {
+		f.setScriptJobRunning(job.ID, false)
+		return "", alog.Error(err)
+	}
+	f.startScriptJob(job, runner)
+	alog.Succeeded()
+	return job.ID, nil
+}
+
+// ResumeScriptJob runs a failed or interrupted script job again, starting
+// from its first step that did not succeed.
+func (f *Facade) ResumeScriptJob(ctx datastore.Context, jobID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.ResumeScriptJob"))
+	if f.scriptConfigurer == nil {
+		return ErrNoScriptConfigurer
+	}
+	alog := f.auditLogger.Message(ctx, "Resuming Script Job").Action(audit.Start).
+		Type(scriptjob.GetType()).ID(jobID)
+	job, err := f.GetScriptJob(ctx, jobID)
+	if err != nil {
+		return alog.Error(err)
+	}
+	if !job.Resumable() {
+		if job.Status == scriptjob.StatusRolledBack {
+			return alog.Error(fmt.Errorf("job %s was rolled back to snapshot %s and must be run again", jobID, job.RestoredSnapshot))
+		}
+		return alog.Error(fmt.Errorf("job %s is %s and cannot be resumed", jobID, job.Status))
+	}
+
+	next := job.NextStep()
+	if next < len(job.Steps) && job.Steps[next].NestedSucceeded > 0 {
+		// progress is only recorded for top-level steps, so resuming would
+		// run the steps inside the block that already succeeded again
+		step := job.Steps[next]
+		return alog.Error(fmt.Errorf("job %s failed inside the block at line %d after %d of its steps succeeded and cannot be resumed", jobID, step.LineNum, step.NestedSucceeded))
+	}
+	runner, err := f.newScriptJobRunner(job, next)
+	if err != nil {
+		return alog.Error(err)
+	}
+
+	// another resume of the same job may have started since it was looked up
+	if !f.claimScriptJob(job.ID) {
+		return alog.Error(fmt.Errorf("job %s is already running", jobID))
+	}
+	job.Status = scriptjob.StatusRunning
+	job.Error = ""
+	if err := f.saveScriptJob(ctx, job); err != nil {
+		f.setScriptJobRunning(job.ID, false)
+		return alog.Error(err)
+	}
+	plog.WithFields(logrus.Fields{
+		"jobid": jobID,
+		"step":  next,
+	}).Info("Resuming script job")
+	f.startScriptJob(job, runner)
+	alog.Succeeded()
+	return nil
+}
+
+// GetScriptJob returns a script job and the progress of its steps
+func (f *Facade) GetScriptJob(ctx datastore.Context, jobID string) (*scriptjob.Job, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetScriptJob"))
+	job, err := f.scriptJobStore.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	f.checkScriptJobInterrupted(ctx, job)
+	return job, nil
+}
+
+// GetScriptJobs returns all script jobs
+func (f *Facade) GetScriptJobs(ctx datastore.Context) ([]scriptjob.Job, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetScriptJobs"))
+	jobs, err := f.scriptJobStore.GetJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		f.checkScriptJobInterrupted(ctx, &jobs[i])
+	}
+	return jobs, nil
+}
+
+// checkScriptJobInterrupted marks a job as interrupted if it is recorded as
+// running but is not being run by this master, e.g. because the master was
+// restarted.
+func (f *Facade) checkScriptJobInterrupted(ctx datastore.Context, job *scriptjob.Job) {
+	if job.Status != scriptjob.StatusRunning || f.isScriptJobRunning(job.ID) {
+		return
+	}
+	job.Status = scriptjob.StatusInterrupted
+	for i, step := range job.Steps {
+		if step.Status == script.StepRunning {
+			job.Steps[i].Status = script.StepFailed
+			job.Steps[i].Error = "interrupted"
+		}
+	}
+	if err := f.saveScriptJob(ctx, job); err != nil {
+		plog.WithError(err).WithField("jobid", job.ID).Warn("Unable to mark script job as interrupted")
+	}
+}
+
+// newScriptJobRunner builds the runner for a job, starting at the given step
+func (f *Facade) newScriptJobRunner(job *scriptjob.Job, resumeAt int) (script.Runner, error) {
+	config := &script.Config{
+		ServiceID: job.ServiceID,
+		NoOp:      job.NoOp,
+	}
+	f.scriptConfigurer(config)
+	if restore := config.Restore; restore != nil {
+		config.Restore = func(snapshotID string, forceRestart bool) error {
+			job.RestoredSnapshot = snapshotID
+			return restore(snapshotID, forceRestart)
+		}
+	}
+	config.ResumeAt = resumeAt
+	if resumeAt > 0 {
+		config.Snapshots = job.Snapshots(resumeAt)
+		config.ImageID = job.ImageID(resumeAt)
+	}
+	config.OnStep = func(i int, step script.Step) {
+		job.Steps[i] = step
+		if err := f.saveScriptJob(datastore.Get(), job); err != nil {
+			plog.WithError(err).WithField("jobid", job.ID).Warn("Unable to save progress of script job")
+		}
+	}
+	// report every parse error, not just that parsing failed
+	if err := script.Parse(strings.NewReader(job.Script)); err != nil {
+		return nil, err
+	}
+	return script.NewRunner(strings.NewReader(job.Script), config)
+}
+
+// startScriptJob runs a job in the background and saves its final status
+func (f *Facade) startScriptJob(job *scriptjob.Job, runner script.Runner) {
+	go func() {
+		defer f.setScriptJobRunning(job.ID, false)
+		logger := plog.WithField("jobid", job.ID)
+		job.RestoredSnapshot = ""
+		err := runner.Run(make(chan struct{}))
+		if err != nil {
+			logger.WithError(err).Warn("Script job failed")
+			job.Error = err.Error()
+			if job.RestoredSnapshot != "" {
+				job.Status = scriptjob.StatusRolledBack
+			} else {
+				job.Status = scriptjob.StatusFailed
+			}
+		} else {
+			logger.Info("Script job succeeded")
+			job.Status = scriptjob.StatusSucceeded
+		}
+		if err := f.saveScriptJob(datastore.Get(), job); err != nil {
+			logger.WithError(err).Error("Unable to save status of script job")
+		}
+	}()
+}
+
+func (f *Facade) setScriptJobRunning(jobID string, running bool) {
+	f.scriptJobLock.Lock()
+	defer f.scriptJobLock.Unlock()
+	if f.scriptJobs == nil {
+		f.scriptJobs = make(map[string]struct{})
+	}
+	if running {
+		f.scriptJobs[jobID] = struct{}{}
+	} else {
+		delete(f.scriptJobs, jobID)
+	}
+}
+
+// claimScriptJob marks a job as running on this master.  Returns false if it
+// already is.
+func (f *Facade) claimScriptJob(jobID string) bool {
+	f.scriptJobLock.Lock()
+	defer f.scriptJobLock.Unlock()
+	if f.scriptJobs == nil {
+		f.scriptJobs = make(map[string]struct{})
+	}
+	if _, ok := f.scriptJobs[jobID]; ok {
+		return false
+	}
+	f.scriptJobs[jobID] = struct{}{}
+	return true
+}
+
+func (f *Facade) isScriptJobRunning(jobID string) bool {
+	f.scriptJobLock.Lock()
+	defer f.scriptJobLock.Unlock()
+	_, ok := f.scriptJobs[jobID]
+	return ok
+}
+
+// saveScriptJob adds or updates a job, on top of whatever version is stored
+func (f *Facade) saveScriptJob(ctx datastore.Context, job *scriptjob.Job) error {
+	job.UpdatedAt = time.Now()
+	if current, err := f.scriptJobStore.Get(ctx, job.ID); err == nil {
+		job.IfSeqNo, job.IfPrimaryTerm = current.IfSeqNo, current.IfPrimaryTerm
+	} else if !datastore.IsErrNoSuchEntity(err) {
+		return err
+	}
+	return f.scriptJobStore.Put(ctx, job)
+}
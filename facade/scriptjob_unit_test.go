@@ -0,0 +1,242 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/scriptjob"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/script"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+// scriptJobRecorder keeps the last version of each script job that was saved
+type scriptJobRecorder struct {
+	sync.Mutex
+	jobs map[string]scriptjob.Job
+}
+
+func (ft *FacadeUnitTest) recordScriptJobs() *scriptJobRecorder {
+	rec := &scriptJobRecorder{jobs: make(map[string]scriptjob.Job)}
+	ft.scriptJobStore.On("Get", mock.Anything, mock.AnythingOfType("string")).
+		Return(nil, datastore.ErrNoSuchEntity{Key: scriptjob.Key("")})
+	ft.scriptJobStore.On("Put", mock.Anything, mock.AnythingOfType("*scriptjob.Job")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			job := args.Get(1).(*scriptjob.Job)
+			rec.Lock()
+			defer rec.Unlock()
+			saved := *job
+			saved.Steps = append([]script.Step{}, job.Steps...)
+			rec.jobs[job.ID] = saved
+		})
+	return rec
+}
+
+func (rec *scriptJobRecorder) waitForStatus(c *C, jobID string, status scriptjob.Status) scriptjob.Job {
+	timeout := time.After(5 * time.Second)
+	for {
+		rec.Lock()
+		job := rec.jobs[jobID]
+		rec.Unlock()
+		if job.Status == status {
+			return job
+		}
+		select {
+		case <-timeout:
+			c.Fatalf("job %s did not reach status %s, last status %s", jobID, status, job.Status)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func scriptJobConfigurer(startErr error) facade.ScriptConfigurer {
+	return func(config *script.Config) {
+		config.TenantLookup = func(service string) (string, error) { return service, nil }
+		config.SvcIDFromPath = func(tenantID, path string) (string, error) { return path, nil }
+		config.Snapshot = func(serviceID, description, tag string) (string, error) { return "snap-" + tag, nil }
+		config.Restore = func(snapshotID string, forceRestart bool) error { return nil }
+		config.SvcStop = func(serviceID string, recursive bool) error { return nil }
+		config.SvcStart = func(serviceID string, recursive bool) error { return startErr }
+	}
+}
+
+func (ft *FacadeUnitTest) TestRunScriptJob_NoConfigurer(c *C) {
+	_, err := ft.Facade.RunScriptJob(ft.ctx, &scriptjob.Job{ServiceID: "svc", Script: "REQUIRE_SVC"})
+	c.Assert(err, Equals, facade.ErrNoScriptConfigurer)
+}
+
+func (ft *FacadeUnitTest) TestRunScriptJob_ParseError(c *C) {
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(nil))
+	_, err := ft.Facade.RunScriptJob(ft.ctx, &scriptjob.Job{ServiceID: "svc", Script: "REQUIRE_SVC\nIF VAR X 1\n"})
+	c.Assert(err, ErrorMatches, "line 2: IF is not closed by END_IF")
+}
+
+func (ft *FacadeUnitTest) TestRunScriptJob_Success(c *C) {
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(nil))
+	rec := ft.recordScriptJobs()
+
+	jobID, err := ft.Facade.RunScriptJob(ft.ctx, &scriptjob.Job{
+		ServiceID: "svc",
+		Script:    "REQUIRE_SVC\nSNAPSHOT first\nSVC_START Zenoss.core\n",
+	})
+	c.Assert(err, IsNil)
+	job := rec.waitForStatus(c, jobID, scriptjob.StatusSucceeded)
+	c.Assert(job.Steps, HasLen, 3)
+	for _, step := range job.Steps {
+		c.Assert(step.Status, Equals, script.StepSucceeded)
+	}
+	c.Assert(job.Steps[1].SnapshotID, Equals, "snap-first")
+}
+
+func (ft *FacadeUnitTest) TestRunScriptJob_FailAndResume(c *C) {
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(errors.New("start failed")))
+	rec := ft.recordScriptJobs()
+
+	jobID, err := ft.Facade.RunScriptJob(ft.ctx, &scriptjob.Job{
+		ServiceID: "svc",
+		Script:    "REQUIRE_SVC\nON_FAILURE ABORT\nSNAPSHOT first\nSVC_START Zenoss.core\n",
+	})
+	c.Assert(err, IsNil)
+	job := rec.waitForStatus(c, jobID, scriptjob.StatusFailed)
+	c.Assert(job.Error, Equals, "start failed")
+	c.Assert(job.Steps[2].Status, Equals, script.StepFailed)
+	c.Assert(job.NextStep(), Equals, 2)
+
+	// resume the job once the service can be started
+	ft.SetUpTest(c)
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(nil))
+	ft.scriptJobStore.On("Get", ft.ctx, jobID).Return(&job, nil)
+	rec = ft.recordScriptJobs()
+	err = ft.Facade.ResumeScriptJob(ft.ctx, jobID)
+	c.Assert(err, IsNil)
+	job = rec.waitForStatus(c, jobID, scriptjob.StatusSucceeded)
+	c.Assert(job.Steps[2].Status, Equals, script.StepSucceeded)
+	c.Assert(job.Steps[1].SnapshotID, Equals, "snap-first")
+}
+
+func (ft *FacadeUnitTest) TestResumeScriptJob_AlreadyRunning(c *C) {
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(errors.New("start failed")))
+	rec := ft.recordScriptJobs()
+
+	jobID, err := ft.Facade.RunScriptJob(ft.ctx, &scriptjob.Job{
+		ServiceID: "svc",
+		Script:    "REQUIRE_SVC\nON_FAILURE ABORT\nSVC_START Zenoss.core\n",
+	})
+	c.Assert(err, IsNil)
+	failed := rec.waitForStatus(c, jobID, scriptjob.StatusFailed)
+
+	// the first resume blocks starting the service
+	started, release := make(chan struct{}), make(chan struct{})
+	defer func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	}()
+	ft.SetUpTest(c)
+	ft.Facade.SetScriptConfigurer(func(config *script.Config) {
+		scriptJobConfigurer(nil)(config)
+		config.SvcStart = func(serviceID string, recursive bool) error {
+			close(started)
+			<-release
+			return nil
+		}
+	})
+	ft.scriptJobStore.On("Get", ft.ctx, jobID).Return(func(datastore.Context, string) *scriptjob.Job {
+		job := failed
+		job.Steps = append([]script.Step{}, failed.Steps...)
+		return &job
+	}, nil)
+	rec = ft.recordScriptJobs()
+	err = ft.Facade.ResumeScriptJob(ft.ctx, jobID)
+	c.Assert(err, IsNil)
+	<-started
+
+	// a second resume of the job that was looked up before the first one was
+	// saved is refused
+	err = ft.Facade.ResumeScriptJob(ft.ctx, jobID)
+	c.Assert(err, ErrorMatches, "job .* is already running")
+
+	close(release)
+	rec.waitForStatus(c, jobID, scriptjob.StatusSucceeded)
+}
+
+func (ft *FacadeUnitTest) TestRunScriptJob_FailInsideBlock(c *C) {
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(errors.New("start failed")))
+	rec := ft.recordScriptJobs()
+
+	jobID, err := ft.Facade.RunScriptJob(ft.ctx, &scriptjob.Job{
+		ServiceID: "svc",
+		Script:    "REQUIRE_SVC\nON_FAILURE ABORT\nSET X 1\nIF VAR X 1\nSVC_STOP Zenoss.core\nSVC_START Zenoss.core\nEND_IF\n",
+	})
+	c.Assert(err, IsNil)
+	job := rec.waitForStatus(c, jobID, scriptjob.StatusFailed)
+	c.Assert(job.Steps[2].Status, Equals, script.StepFailed)
+	c.Assert(job.Steps[2].NestedSucceeded, Equals, 1)
+
+	// the stop inside the block would be run again, so the job is not resumed
+	ft.SetUpTest(c)
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(nil))
+	ft.scriptJobStore.On("Get", ft.ctx, jobID).Return(&job, nil)
+	err = ft.Facade.ResumeScriptJob(ft.ctx, jobID)
+	c.Assert(err, ErrorMatches, "job .* failed inside the block at line 4 after 1 of its steps succeeded and cannot be resumed")
+}
+
+func (ft *FacadeUnitTest) TestRunScriptJob_RolledBack(c *C) {
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(errors.New("start failed")))
+	rec := ft.recordScriptJobs()
+
+	jobID, err := ft.Facade.RunScriptJob(ft.ctx, &scriptjob.Job{
+		ServiceID: "svc",
+		Script:    "REQUIRE_SVC\nSNAPSHOT first\nSVC_START Zenoss.core\n",
+	})
+	c.Assert(err, IsNil)
+	job := rec.waitForStatus(c, jobID, scriptjob.StatusRolledBack)
+	c.Assert(job.RestoredSnapshot, Equals, "snap-first")
+
+	ft.SetUpTest(c)
+	ft.Facade.SetScriptConfigurer(scriptJobConfigurer(nil))
+	ft.scriptJobStore.On("Get", ft.ctx, jobID).Return(&job, nil)
+	err = ft.Facade.ResumeScriptJob(ft.ctx, jobID)
+	c.Assert(err, ErrorMatches, "job .* was rolled back to snapshot snap-first and must be run again")
+}
+
+func (ft *FacadeUnitTest) TestGetScriptJob_Interrupted(c *C) {
+	job := &scriptjob.Job{
+		ID:     "job1",
+		Script: "REQUIRE_SVC\nSVC_START Zenoss.core\n",
+		Status: scriptjob.StatusRunning,
+		Steps: []script.Step{
+			{LineNum: 1, Status: script.StepSucceeded},
+			{LineNum: 2, Status: script.StepRunning},
+		},
+	}
+	ft.scriptJobStore.On("Get", ft.ctx, "job1").Return(job, nil)
+	ft.scriptJobStore.On("Put", ft.ctx, job).Return(nil)
+
+	actual, err := ft.Facade.GetScriptJob(ft.ctx, "job1")
+	c.Assert(err, IsNil)
+	c.Assert(actual.Status, Equals, scriptjob.StatusInterrupted)
+	c.Assert(actual.Steps[1].Status, Equals, script.StepFailed)
+	c.Assert(actual.Resumable(), Equals, true)
+}
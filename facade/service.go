@@ -62,6 +62,8 @@ var (
 	ErrServiceMissingAssignment = errors.New("facade: service is missing an address assignment")
 	ErrServiceDuplicateEndpoint = errors.New("facade: duplicate endpoint found")
 	ErrEmergencyShutdownNoOp    = errors.New("Cannot perform operation; Service has Emergency Shutdown flag set")
+	ErrServiceNonPreemptible    = errors.New("facade: service is not preemptible")
+	ErrServicePriority          = errors.New("facade: service priority is not lower than the preempting service")
 )
 
 // A type for invalid service options; the details are specified when creating the error.
@@ -500,6 +502,14 @@ func (f *Facade) validateServiceUpdate(ctx datastore.Context, svc *service.Servi
 			svc.DesiredState = int(service.SVCStop)
 		}
 	}
+
+	// verify that the pool can run any additional instances
+	if svc.DesiredState == int(service.SVCRun) && commitmentIncreased(cursvc, svc) {
+		if err := f.checkPoolQuota(ctx, []*service.Service{svc}); err != nil {
+			logger.WithError(err).Warning("Service exceeds the resource quota of its pool")
+			return nil, err
+		}
+	}
 	return cursvc, nil
 }
 
@@ -1122,6 +1132,8 @@ func (f *Facade) GetEvaluatedService(ctx datastore.Context, serviceID string, in
 		return nil, err
 	}
 
+	// overrides may be templates, so apply them before evaluating
+	svc.ApplyInstanceOverride(instanceID)
 	if err := f.evaluateService(ctx, svc, instanceID); err != nil {
 		return nil, err
 	}
@@ -1151,7 +1163,28 @@ func (f *Facade) evaluateService(ctx datastore.Context, svc *service.Service, in
 		}
 		return svc, err
 	}
-	return svc.Evaluate(getService, getServiceChild, instanceID)
+
+	// host lookup for the instance
+	getHost := func(serviceID string, instanceID int) (service.HostFacts, error) {
+		location, err := f.LocateServiceInstance(ctx, serviceID, instanceID)
+		if err != nil {
+			return service.HostFacts{}, err
+		}
+		hst, err := f.GetHost(ctx, location.HostID)
+		if err != nil {
+			return service.HostFacts{}, err
+		} else if hst == nil {
+			return service.HostFacts{}, fmt.Errorf("host %s not found", location.HostID)
+		}
+		return service.HostFacts{
+			ID:     hst.ID,
+			Name:   hst.Name,
+			IPAddr: hst.IPAddr,
+			Cores:  hst.Cores,
+			Memory: hst.Memory,
+		}, nil
+	}
+	return svc.EvaluateWithHost(getService, getServiceChild, getHost, instanceID)
 }
 
 // GetServices looks up all services. Allows filtering by tenant ID, name (regular expression), and/or update time.
@@ -1655,6 +1688,14 @@ func (f *Facade) scheduleServiceParents(ctx datastore.Context, tenantID string,
 		}
 	}
 
+	// Verify that the pools can run the instances of the services
+	if desiredState == service.SVCRun {
+		if err := f.checkPoolQuota(ctx, svcs); err != nil {
+			logger.WithError(err).Warn("Services exceed the resource quota of their pool")
+			return 0, err
+		}
+	}
+
 	var err error
 	affected := len(svcs)
 	if synchronous {
@@ -2882,6 +2923,56 @@ func (f *Facade) GetServiceExportedEndpoints(ctx datastore.Context, serviceID st
 	return result, nil
 }
 
+// GetServiceTraffic returns the dependency map of a service: the traffic
+// between the service and the services it imports endpoints from or exports
+// endpoints to, averaged over the window.
+func (f *Facade) GetServiceTraffic(ctx datastore.Context, serviceID string, window time.Duration) (*metrics.ServiceTraffic, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceTraffic"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	svc, err := f.serviceStore.GetServiceDetails(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := f.metricsClient.GetServiceTraffic(window, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up traffic metrics for service")
+		return nil, err
+	}
+
+	// services that were removed since keep their id only
+	names := map[string]string{serviceID: svc.Name}
+	getName := func(id string) string {
+		if name, ok := names[id]; ok {
+			return name
+		}
+		if details, err := f.serviceStore.GetServiceDetails(ctx, id); err == nil {
+			names[id] = details.Name
+		} else {
+			names[id] = ""
+		}
+		return names[id]
+	}
+
+	traffic := &metrics.ServiceTraffic{
+		ServiceID: serviceID,
+		Window:    window.String(),
+		Imports:   []metrics.TrafficStats{},
+		Exports:   []metrics.TrafficStats{},
+	}
+	for _, s := range stats {
+		s.ImportServiceName = getName(s.ImportServiceID)
+		s.ExportServiceName = getName(s.ExportServiceID)
+		if s.ImportServiceID == serviceID {
+			traffic.Imports = append(traffic.Imports, s)
+		}
+		if s.ExportServiceID == serviceID {
+			traffic.Exports = append(traffic.Exports, s)
+		}
+	}
+	return traffic, nil
+}
+
 // GetServicePublicEndpoints returns all the endpoints for a service and its
 // children if enabled.
 func (f *Facade) GetServicePublicEndpoints(ctx datastore.Context, serviceID string, children bool) ([]service.PublicEndpoint, error) {
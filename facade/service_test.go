@@ -34,6 +34,7 @@ import (
 	zzkmocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/control-center/serviced/health"
 	ssmmocks "github.com/control-center/serviced/scheduler/servicestatemanager/mocks"
+	"github.com/control-center/serviced/utils"
 	zks "github.com/control-center/serviced/zzk/service"
 
 	"github.com/control-center/serviced/domain/logfilter"
@@ -2911,6 +2912,57 @@ func (ft *FacadeIntegrationTest) TestFacade_StartService_InvalidMounts(c *C) {
 	}
 }
 
+func (ft *FacadeIntegrationTest) TestFacade_StartService_PoolQuota(c *C) {
+	rp := pool.ResourcePool{ID: "default", CoreLimit: 4, MemoryLimit: 1024}
+	err := ft.Facade.AddResourcePool(ft.CTX, &rp)
+	c.Assert(err, IsNil)
+
+	running := service.Service{
+		ID:            "RunningServiceID",
+		Name:          "RunningService",
+		PoolID:        "default",
+		DeploymentID:  "deployment_id",
+		Launch:        "auto",
+		Instances:     1,
+		DesiredState:  int(service.SVCRun),
+		CPUCommitment: 1,
+		RAMCommitment: utils.EngNotation{Value: 512},
+	}
+	err = ft.Facade.AddService(ft.CTX, running)
+	c.Assert(err, IsNil)
+
+	svc := service.Service{
+		ID:            "StoppedServiceID",
+		Name:          "StoppedService",
+		PoolID:        "default",
+		DeploymentID:  "deployment_id",
+		Launch:        "auto",
+		Instances:     2,
+		DesiredState:  int(service.SVCStop),
+		CPUCommitment: 1,
+		RAMCommitment: utils.EngNotation{Value: 256},
+	}
+	err = ft.Facade.AddService(ft.CTX, svc)
+	c.Assert(err, IsNil)
+
+	// fits exactly within the memory quota
+	err = ft.Facade.checkPoolQuota(ft.CTX, []*service.Service{&svc})
+	c.Assert(err, IsNil)
+
+	// one more instance exceeds it
+	svc.Instances = 3
+	err = ft.Facade.checkPoolQuota(ft.CTX, []*service.Service{&svc})
+	qerr, ok := err.(*pool.QuotaError)
+	c.Assert(ok, Equals, true)
+	c.Assert(qerr.Resource, Equals, "memory")
+	c.Assert(qerr.Committed, Equals, uint64(512))
+
+	usage, err := ft.Facade.GetPoolUsage(ft.CTX, "default")
+	c.Assert(err, IsNil)
+	c.Assert(usage.CoreCommitment, Equals, 1)
+	c.Assert(usage.MemoryCommitment, Equals, uint64(512))
+}
+
 func (ft *FacadeIntegrationTest) TestFacade_ClearEmergencyStopFlag(c *C) {
 	// add a service with 2 subservices and set EmergencyShutdown to true for all 3
 	svc := service.Service{
@@ -18,10 +18,13 @@ package facade_test
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/utils"
 	. "gopkg.in/check.v1"
 )
@@ -197,6 +200,38 @@ func (ft *FacadeUnitTest) Test_GetEvaluatedServiceSimple(c *C) {
 	c.Assert(result.Actions["instanceID"], Equals, fmt.Sprintf("%d", instanceID))
 }
 
+// Test that GetEvaluatedService applies the overrides for the instance before evaluating it
+func (ft *FacadeUnitTest) Test_GetEvaluatedServiceInstanceOverride(c *C) {
+	serviceID := "0"
+	svc := service.Service{
+		ID:          serviceID,
+		Name:        "service0",
+		Context:     map[string]interface{}{"role": "replica"},
+		Actions:     map[string]string{"role": "{{getContext . \"role\"}}"},
+		MemoryLimit: 1024,
+		InstanceOverrides: map[int]service.InstanceOverride{
+			0: {Context: map[string]interface{}{"role": "primary"}, MemoryLimit: 4096},
+		},
+	}
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID}, nil)
+	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(func(datastore.Context, string) *service.Service {
+		copy := svc
+		return &copy
+	}, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+
+	result, err := ft.Facade.GetEvaluatedService(ft.ctx, serviceID, 0)
+	c.Assert(err, IsNil)
+	c.Assert(result.Actions["role"], Equals, "primary")
+	c.Assert(result.MemoryLimit, Equals, float64(4096))
+
+	svc.Actions = map[string]string{"role": "{{getContext . \"role\"}}"}
+	result, err = ft.Facade.GetEvaluatedService(ft.ctx, serviceID, 1)
+	c.Assert(err, IsNil)
+	c.Assert(result.Actions["role"], Equals, "replica")
+	c.Assert(result.MemoryLimit, Equals, float64(1024))
+}
+
 // Test that the 'getService' function defined by facade.evaluateService() works properly on success
 func (ft *FacadeUnitTest) Test_GetEvaluatedServiceUsesParent(c *C) {
 	parentID := "parentServiceID"
@@ -380,6 +415,78 @@ func getRandomServiceID(c *C) string {
 	return serviceID
 }
 
+func (ft *FacadeUnitTest) Test_GetServiceTraffic(c *C) {
+	window := time.Hour
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "zope").Return(&service.ServiceDetails{ID: "zope", Name: "Zope"}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "mariadb").Return(&service.ServiceDetails{ID: "mariadb", Name: "MariaDB"}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "zproxy").Return(nil, datastore.ErrNoSuchEntity{})
+	ft.metricsClient.On("GetServiceTraffic", window, "zope").Return([]metrics.TrafficStats{
+		{ImportServiceID: "zope", ExportServiceID: "mariadb", Application: "zodb", Connections: 2, BytesSent: 100},
+		{ImportServiceID: "zproxy", ExportServiceID: "zope", Application: "zope", Connections: 1, Errors: 0.5},
+	}, nil)
+
+	traffic, err := ft.Facade.GetServiceTraffic(ft.ctx, "zope", window)
+	c.Assert(err, IsNil)
+	c.Assert(traffic, DeepEquals, &metrics.ServiceTraffic{
+		ServiceID: "zope",
+		Window:    "1h0m0s",
+		Imports: []metrics.TrafficStats{
+			{ImportServiceID: "zope", ImportServiceName: "Zope", ExportServiceID: "mariadb", ExportServiceName: "MariaDB", Application: "zodb", Connections: 2, BytesSent: 100},
+		},
+		Exports: []metrics.TrafficStats{
+			{ImportServiceID: "zproxy", ExportServiceID: "zope", ExportServiceName: "Zope", Application: "zope", Connections: 1, Errors: 0.5},
+		},
+	})
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceTrafficNoSuchService(c *C) {
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "zope").Return(nil, datastore.ErrNoSuchEntity{})
+
+	traffic, err := ft.Facade.GetServiceTraffic(ft.ctx, "zope", time.Hour)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+	c.Assert(traffic, IsNil)
+	ft.metricsClient.AssertNotCalled(c, "GetServiceTraffic", time.Hour, "zope")
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceHealthHistory(c *C) {
+	hcache := health.New()
+	ft.Facade.SetHealthCache(hcache)
+	ft.serviceStore.On("GetServiceHealth", ft.ctx, "zope").Return(&service.ServiceHealth{
+		ID:        "zope",
+		Instances: 2,
+		HealthChecks: map[string]health.HealthCheck{
+			"answering": {Kind: health.ReadinessCheck},
+		},
+	}, nil)
+	started := time.Now()
+	hcache.Set(health.HealthStatusKey{ServiceID: "zope", InstanceID: 1, HealthCheckName: "answering"}, health.HealthStatus{
+		Status:    health.Failed,
+		StartedAt: started,
+		Output:    "connection refused",
+	}, time.Minute)
+
+	history, err := ft.Facade.GetServiceHealthHistory(ft.ctx, "zope")
+	c.Assert(err, IsNil)
+	c.Assert(history, DeepEquals, map[int]map[string]health.HealthHistory{
+		0: {},
+		1: {
+			"answering": {
+				Entries: []health.HealthHistoryEntry{
+					{Status: health.Failed, StartedAt: started, Output: "connection refused"},
+				},
+			},
+		},
+	})
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceHealthHistoryNoSuchService(c *C) {
+	ft.serviceStore.On("GetServiceHealth", ft.ctx, "zope").Return(nil, datastore.ErrNoSuchEntity{})
+
+	history, err := ft.Facade.GetServiceHealthHistory(ft.ctx, "zope")
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+	c.Assert(history, IsNil)
+}
+
 //service store returned not-found
 //service store returned other error
 //service store return err=nil and svc=nil
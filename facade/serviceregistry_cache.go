@@ -91,6 +91,14 @@ func (sc *serviceRegistryCache) BuildSyncRequest(tenantID string, svc *service.S
 					ServiceID:   svc.ID,
 					Protocol:    p.Protocol,
 					UseTLS:      p.UseTLS,
+					Routing: zkr.Routing{
+						Affinity:         p.Affinity,
+						DisableRetry:     p.DisableRetry,
+						DisableAccessLog: p.DisableAccessLog,
+						AccessLogSample:  p.AccessLogSample,
+						Policy:           p.Policy,
+						ServerNames:      p.ServerNames,
+					},
 				}
 				request.PortsToPublish[key] = pub
 			}
@@ -107,6 +115,13 @@ func (sc *serviceRegistryCache) BuildSyncRequest(tenantID string, svc *service.S
 					TenantID:    tenantID,
 					Application: ep.Application,
 					ServiceID:   svc.ID,
+					Routing: zkr.Routing{
+						Affinity:         v.Affinity,
+						DisableRetry:     v.DisableRetry,
+						DisableAccessLog: v.DisableAccessLog,
+						AccessLogSample:  v.AccessLogSample,
+						Policy:           v.Policy,
+					},
 				}
 				request.VHostsToPublish[key] = vh
 			}
@@ -445,7 +445,7 @@ func (t *ServiceRegistryCacheTest) assertPortMapsEqual(c *C, actual, expected ma
 	for key, value := range expected {
 		actualValue, ok := actual[key]
 		c.Assert(ok, Equals, true)
-		c.Assert(actualValue, Equals, value)
+		c.Assert(actualValue, DeepEquals, value)
 	}
 }
 
@@ -454,7 +454,7 @@ func (t *ServiceRegistryCacheTest) assertVHostMapsEqual(c *C, actual, expected m
 	for key, value := range expected {
 		actualValue, ok := actual[key]
 		c.Assert(ok, Equals, true)
-		c.Assert(actualValue, Equals, value)
+		c.Assert(actualValue, DeepEquals, value)
 	}
 }
 
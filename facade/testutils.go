@@ -25,9 +25,11 @@ import (
 	"github.com/control-center/serviced/datastore/elastic"
 	dfsmocks "github.com/control-center/serviced/dfs/mocks"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/scriptjob"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
@@ -66,6 +68,8 @@ func (ft *FacadeIntegrationTest) SetUpSuite(c *gocheck.C) {
 	ft.Mappings = append(ft.Mappings, serviceconfigfile.MAPPING)
 	ft.Mappings = append(ft.Mappings, user.MAPPING)
 	ft.Mappings = append(ft.Mappings, registry.MAPPING)
+	ft.Mappings = append(ft.Mappings, scriptjob.MAPPING)
+	ft.Mappings = append(ft.Mappings, certificate.MAPPING)
 
 	ft.ElasticTest.SetUpSuite(c)
 	datastore.Register(ft.Driver())
@@ -43,18 +43,20 @@ func (item *HealthStatusItem) Expired() bool {
 
 // HealthStatusCache keeps track of the health status items in memory.
 type HealthStatusCache struct {
-	mu   *sync.Mutex
-	data map[HealthStatusKey]HealthStatusItem
-	stop chan struct{}
-	wg   *sync.WaitGroup
+	mu      *sync.Mutex
+	data    map[HealthStatusKey]HealthStatusItem
+	history map[HealthStatusKey]*HealthHistory
+	stop    chan struct{}
+	wg      *sync.WaitGroup
 }
 
 // New returns a new HealthStatusCache instance
 func New() *HealthStatusCache {
 	cache := &HealthStatusCache{
-		mu:   &sync.Mutex{},
-		data: make(map[HealthStatusKey]HealthStatusItem),
-		wg:   &sync.WaitGroup{},
+		mu:      &sync.Mutex{},
+		data:    make(map[HealthStatusKey]HealthStatusItem),
+		history: make(map[HealthStatusKey]*HealthHistory),
+		wg:      &sync.WaitGroup{},
 	}
 	return cache
 }
@@ -123,13 +125,27 @@ func (cache *HealthStatusCache) get(key HealthStatusKey) (item HealthStatusItem,
 	return
 }
 
-// Set sets an item into the cache.
+// Set sets an item into the cache and adds it to the history of the health
+// check, which marks whether the check is flapping.
 func (cache *HealthStatusCache) Set(key HealthStatusKey, value HealthStatus, expire time.Duration) {
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
+	value.Flapping = cache.record(key, value)
 	cache.set(key, value, time.Now().Add(expire))
 }
 
+// History returns the recent results of a health check.  The history outlives
+// the cached status, so that flapping is detected across instance restarts.
+func (cache *HealthStatusCache) History(key HealthStatusKey) (HealthHistory, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	h, ok := cache.history[key]
+	if !ok {
+		return HealthHistory{}, false
+	}
+	return h.copy(), true
+}
+
 // set is non thread-safe
 func (cache *HealthStatusCache) set(key HealthStatusKey, value HealthStatus, expires time.Time) {
 	cache.data[key] = HealthStatusItem{value: value, expires: expires}
@@ -144,7 +160,8 @@ func (cache *HealthStatusCache) Delete(key HealthStatusKey) {
 	}
 }
 
-// DeleteExpired removes all expired items from the cache.
+// DeleteExpired removes all expired items from the cache, and the history of
+// health checks that stopped reporting.
 func (cache *HealthStatusCache) DeleteExpired() {
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
@@ -153,6 +170,11 @@ func (cache *HealthStatusCache) DeleteExpired() {
 			cache.delete(key)
 		}
 	}
+	for key, h := range cache.history {
+		if time.Since(h.last()) > historyRetention {
+			delete(cache.history, key)
+		}
+	}
 }
 
 // DeleteInstance removes all health checks per instance.
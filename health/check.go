@@ -57,6 +57,15 @@ const (
 	NotRunning = 4
 )
 
+// Name returns the name a status is marshalled as
+func (s Status) Name() string {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return "invalid"
+	}
+	return string(data[1 : len(data)-1])
+}
+
 func (s Status) MarshalJSON() ([]byte, error) {
 	switch s {
 	case OK:
@@ -103,10 +112,49 @@ type HealthStatus struct {
 	StartedAt time.Time
 	Duration  time.Duration
 	KillFlag  bool
+	Kind      string // the kind of the health check
+	Output    string // why the health check failed, if known
+	Flapping  bool   // whether the status keeps changing
 }
 
+// IsHealthy returns false if any of the health checks of an instance are
+// failing.  Checks that have not reported yet do not count against the
+// instance, nor do liveness checks, which restart the instance instead.
+func IsHealthy(checks map[string]HealthStatus) bool {
+	for _, stat := range checks {
+		if stat.Kind == LivenessCheck {
+			continue
+		}
+		switch stat.Status {
+		case Failed, Timeout, NotRunning:
+			return false
+		}
+	}
+	return true
+}
+
+// Health check types.  Script checks run a shell command in the container;
+// the others are run natively by the container controller.
+const (
+	ScriptCheck = "script"
+	HTTPCheck   = "http"
+	TCPCheck    = "tcp"
+	GRPCCheck   = "grpc"
+)
+
+// Health check kinds.  Readiness checks hold the exports of an instance back
+// until they pass, and liveness checks restart the service of an instance
+// when they fail.  Checks without a kind only report the health of the
+// instance, which keeps unhealthy instances from receiving traffic.
+const (
+	ReadinessCheck = "readiness"
+	LivenessCheck  = "liveness"
+)
+
 // HealthCheck is the health check object.
 type HealthCheck struct {
+	Type      string // script (the default), http, tcp or grpc
+	Kind      string // readiness, liveness or empty
 	Script    string
 	Timeout   time.Duration
 	Interval  time.Duration
@@ -117,52 +165,150 @@ type HealthCheck struct {
 	KillExitCodes  []int
 	KillCountLimit int
 	KillCounter    int
+
+	// StartPeriod is how long after the service starts failures do not
+	// count, so that slow services are not restarted or reported unhealthy
+	// while they are starting up.
+	StartPeriod time.Duration
+
+	// URL is the address of an http check.  Any 2xx or 3xx status passes
+	// unless an ExpectedStatus is set, and the body must match the
+	// ExpectedBody regular expression if one is set.
+	URL            string
+	Headers        map[string]string
+	ExpectedStatus int
+	ExpectedBody   string
+
+	// Host and Port are the address of a tcp or grpc check.  The host
+	// defaults to localhost.
+	Host string
+	Port int
+
+	// GRPCService is the service name sent in the grpc health check request;
+	// empty checks the health of the server as a whole.
+	GRPCService string
+
+	// TLS configures the connection of https urls; tcp and grpc checks
+	// connect with TLS if it is set.
+	TLS *HealthCheckTLS
+}
+
+// HealthCheckTLS are the TLS options of a native health check
+type HealthCheckTLS struct {
+	InsecureSkipVerify bool   `json:",omitempty"`
+	ServerName         string `json:",omitempty"`
+	CAFile             string `json:",omitempty"` // PEM certificates to verify the server with
+}
+
+type jsonHealthCheck struct {
+	Type           string `json:",omitempty"`
+	Kind           string `json:",omitempty"`
+	Script         string
+	Timeout        float64
+	Interval       float64
+	Tolerance      int
+	KillExitCodes  []int             `json:",omitempty"`
+	KillCountLimit int               `json:",omitempty"`
+	StartPeriod    float64           `json:",omitempty"`
+	URL            string            `json:",omitempty"`
+	Headers        map[string]string `json:",omitempty"`
+	ExpectedStatus int               `json:",omitempty"`
+	ExpectedBody   string            `json:",omitempty"`
+	Host           string            `json:",omitempty"`
+	Port           int               `json:",omitempty"`
+	GRPCService    string            `json:",omitempty"`
+	TLS            *HealthCheckTLS   `json:",omitempty"`
 }
 
 // MarshalJSON implements json.Marshaller
 func (hc HealthCheck) MarshalJSON() ([]byte, error) {
-	jhc := struct {
-		Script         string
-		Timeout        float64
-		Interval       float64
-		Tolerance      int
-		KillExitCodes  []int `json:",omitempty"`
-		KillCountLimit int   `json:",omitempty"`
-	}{
+	jhc := jsonHealthCheck{
+		Type:           hc.Type,
+		Kind:           hc.Kind,
 		Script:         hc.Script,
 		Timeout:        hc.Timeout.Seconds(),
 		Interval:       hc.Interval.Seconds(),
 		Tolerance:      hc.Tolerance,
 		KillCountLimit: hc.KillCountLimit,
 		KillExitCodes:  hc.KillExitCodes,
+		StartPeriod:    hc.StartPeriod.Seconds(),
+		URL:            hc.URL,
+		Headers:        hc.Headers,
+		ExpectedStatus: hc.ExpectedStatus,
+		ExpectedBody:   hc.ExpectedBody,
+		Host:           hc.Host,
+		Port:           hc.Port,
+		GRPCService:    hc.GRPCService,
+		TLS:            hc.TLS,
 	}
 	return json.Marshal(jhc)
 }
 
 // UnmarshalJSON implements json.Unmarshaller
 func (hc *HealthCheck) UnmarshalJSON(data []byte) error {
-	jhc := struct {
-		Script         string
-		Timeout        float64
-		Interval       float64
-		Tolerance      int
-		KillExitCodes  []int `json:",omitempty"`
-		KillCountLimit int   `json:",omitempty"`
-	}{}
+	jhc := jsonHealthCheck{}
 	if err := json.Unmarshal(data, &jhc); err != nil {
 		return err
 	}
 	*hc = HealthCheck{
+		Type:           jhc.Type,
+		Kind:           jhc.Kind,
 		Script:         jhc.Script,
 		Timeout:        time.Duration(jhc.Timeout) * time.Second,
 		Interval:       time.Duration(jhc.Interval) * time.Second,
 		Tolerance:      jhc.Tolerance,
 		KillCountLimit: jhc.KillCountLimit,
 		KillExitCodes:  jhc.KillExitCodes,
+		StartPeriod:    time.Duration(jhc.StartPeriod) * time.Second,
+		URL:            jhc.URL,
+		Headers:        jhc.Headers,
+		ExpectedStatus: jhc.ExpectedStatus,
+		ExpectedBody:   jhc.ExpectedBody,
+		Host:           jhc.Host,
+		Port:           jhc.Port,
+		GRPCService:    jhc.GRPCService,
+		TLS:            jhc.TLS,
 	}
 	return nil
 }
 
+// GetType returns the type of the health check.
+func (hc *HealthCheck) GetType() string {
+	if hc.Type == "" {
+		return ScriptCheck
+	}
+	return hc.Type
+}
+
+// Target describes what the health check runs or connects to.
+func (hc *HealthCheck) Target() string {
+	switch hc.GetType() {
+	case HTTPCheck:
+		return hc.URL
+	case TCPCheck, GRPCCheck:
+		return hc.address()
+	default:
+		return hc.Script
+	}
+}
+
+// GetKillCountLimit returns how many failures count toward killing the
+// instance.  Liveness checks restart the service on the first failure unless
+// a limit is set.
+func (hc *HealthCheck) GetKillCountLimit() int {
+	if hc.KillCountLimit <= 0 && hc.Kind == LivenessCheck {
+		return 1
+	}
+	return hc.KillCountLimit
+}
+
+// InStartPeriod returns true if failures do not count yet for a service that
+// started at the given time.  A zero time means the service has not started.
+// Checks without a start period always count.
+func (hc *HealthCheck) InStartPeriod(started time.Time) bool {
+	return hc.StartPeriod > 0 && (started.IsZero() || time.Since(started) < hc.StartPeriod)
+}
+
 // GetTimeout returns the timeout duration.
 func (hc *HealthCheck) GetTimeout() time.Duration {
 	timeout := hc.Timeout
@@ -187,6 +333,7 @@ func (hc *HealthCheck) NotRunning() HealthStatus {
 	return HealthStatus{
 		Status:    NotRunning,
 		StartedAt: time.Now(),
+		Kind:      hc.Kind,
 	}
 }
 
@@ -196,12 +343,21 @@ func (hc *HealthCheck) Unknown() HealthStatus {
 	return HealthStatus{
 		Status:    Unknown,
 		StartedAt: time.Now(),
+		Kind:      hc.Kind,
+	}
+}
+
+// Run returns the health status as a result of running the health check.
+func (hc *HealthCheck) Run(key HealthStatusKey) HealthStatus {
+	if hc.GetType() == ScriptCheck {
+		return hc.runScript(key)
 	}
+	return hc.runNative(key)
 }
 
-// Run returns the health status as a result of running the health check
-// script.
-func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
+// runScript returns the health status as a result of running the health
+// check script.
+func (hc *HealthCheck) runScript(key HealthStatusKey) (stat HealthStatus) {
 	logger := plog.WithFields(log.Fields{
 		"service":     key.ServiceID,
 		"instance":    key.InstanceID,
@@ -209,6 +365,13 @@ func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 	})
 	stat.StartedAt = time.Now()
 	cmd := exec.Command("sh", "-c", hc.Script)
+	output, err := newScriptOutput()
+	if err != nil {
+		logger.WithError(err).Debug("Unable to capture the health check output")
+	} else {
+		defer output.Close()
+		cmd.Stdout, cmd.Stderr = output.f, output.f
+	}
 	cmd.Start()
 	timer := time.NewTimer(hc.GetTimeout())
 	errC := make(chan error)
@@ -220,7 +383,7 @@ func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 			// If the command gives an error, the healthcheck status is Failed (curl command failed, connection
 			// refused, or any other error message including one that might contribute to the kill count)
 			stat.Status = Failed
-			if hc.KillCountLimit > 0 {
+			if hc.GetKillCountLimit() > 0 {
 				logger.Debug("Healthcheck has a KillCount.. checking the exit code")
 
 				if len(hc.KillExitCodes) == 0 {
@@ -266,6 +429,13 @@ func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 		stat.Status = Timeout
 	}
 	stat.Duration = time.Since(stat.StartedAt)
+
+	// report why the check did not pass
+	if stat.Status != OK && output != nil {
+		if stat.Output, err = output.Tail(MaxOutputSize); err != nil {
+			logger.WithError(err).Warn("Unable to read the health check output")
+		}
+	}
 	return
 }
 
@@ -277,7 +447,8 @@ func (hc *HealthCheck) Ping(cancel <-chan struct{}, key HealthStatusKey, report
 		select {
 		case <-timer.C:
 			stat := hc.Run(key)
-			stat.KillFlag = hc.KillCountLimit > 0 && hc.KillCounter >= hc.KillCountLimit
+			stat.Kind = hc.Kind
+			stat.KillFlag = hc.GetKillCountLimit() > 0 && hc.KillCounter >= hc.GetKillCountLimit()
 			timer.Reset(hc.Interval)
 			report(stat)
 		case <-cancel:
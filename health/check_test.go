@@ -17,6 +17,7 @@ package health_test
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	. "github.com/control-center/serviced/health"
@@ -41,6 +42,42 @@ var (
 
 type HealthCheckTestSuite struct{}
 
+func (s *HealthCheckTestSuite) TestIsHealthy(c *C) {
+	c.Check(IsHealthy(nil), Equals, true)
+
+	checks := map[string]HealthStatus{
+		"running": {Status: OK},
+		"ready":   {Status: Unknown},
+	}
+	c.Check(IsHealthy(checks), Equals, true)
+
+	checks["answering"] = HealthStatus{Status: Failed}
+	c.Check(IsHealthy(checks), Equals, false)
+}
+
+func (s *HealthCheckTestSuite) TestIsHealthy_Kind(c *C) {
+	// failing liveness checks restart the instance instead
+	checks := map[string]HealthStatus{
+		"ready": {Status: OK, Kind: ReadinessCheck},
+		"alive": {Status: Failed, Kind: LivenessCheck},
+	}
+	c.Check(IsHealthy(checks), Equals, true)
+
+	checks["ready"] = HealthStatus{Status: Timeout, Kind: ReadinessCheck}
+	c.Check(IsHealthy(checks), Equals, false)
+}
+
+func (s *HealthCheckTestSuite) TestInStartPeriod(c *C) {
+	check := HealthCheck{}
+	c.Check(check.InStartPeriod(time.Time{}), Equals, false)
+	c.Check(check.InStartPeriod(time.Now()), Equals, false)
+
+	check.StartPeriod = time.Minute
+	c.Check(check.InStartPeriod(time.Time{}), Equals, true)
+	c.Check(check.InStartPeriod(time.Now()), Equals, true)
+	c.Check(check.InStartPeriod(time.Now().Add(-2*time.Minute)), Equals, false)
+}
+
 func (s *HealthCheckTestSuite) TestMarshalJSON(c *C) {
 	// Verify the marshaller
 	check := HealthCheck{
@@ -207,6 +244,7 @@ func (s *HealthCheckTestSuite) TestRun_Passed(c *C) {
 	stat := check.Run(hcKey)
 	c.Check(stat.Status, Equals, OK)
 	c.Check(stat.Duration > 0, Equals, true)
+	c.Check(stat.Output, Equals, "")
 }
 
 func (s *HealthCheckTestSuite) TestRun_Timeout(c *C) {
@@ -221,6 +259,13 @@ func (s *HealthCheckTestSuite) TestRun_Timeout(c *C) {
 	c.Check(int(stat.Status), Equals, Timeout)
 	c.Check(stat.Duration >= check.Timeout, Equals, true)
 	c.Check(stat.Duration < 5*time.Second, Equals, true)
+
+	// processes left running by the script do not hold the check open
+	check.Script = "echo waiting; sleep 5 & exit 1"
+	stat = check.Run(hcKey)
+	c.Check(int(stat.Status), Equals, Failed)
+	c.Check(stat.Duration < time.Second, Equals, true)
+	c.Check(stat.Output, Equals, "waiting")
 }
 
 func (s *HealthCheckTestSuite) TestRun_Failed(c *C) {
@@ -234,6 +279,20 @@ func (s *HealthCheckTestSuite) TestRun_Failed(c *C) {
 	stat := check.Run(hcKey)
 	c.Check(int(stat.Status), Equals, Failed)
 	c.Check(stat.Duration > 0, Equals, true)
+	c.Check(stat.Output, Equals, "failure")
+}
+
+func (s *HealthCheckTestSuite) TestRun_Output(c *C) {
+	// Verify that the end of the output of a failing check is reported
+	check := HealthCheck{
+		Script:  "echo checking; head -c 4096 /dev/zero | tr '\\0' x; echo; echo connection refused >&2; exit 7",
+		Timeout: time.Second,
+	}
+	stat := check.Run(hcKey)
+	c.Check(int(stat.Status), Equals, Failed)
+	c.Check(len(stat.Output) <= MaxOutputSize, Equals, true)
+	c.Check(strings.HasSuffix(stat.Output, "xx\nconnection refused"), Equals, true)
+	c.Check(strings.Contains(stat.Output, "checking"), Equals, false)
 }
 
 func (s *HealthCheckTestSuite) TestPing(c *C) {
@@ -292,6 +351,37 @@ func (s *HealthCheckTestSuite) TestKillScript_MatchingSingleExitCode(c *C) {
 	})
 }
 
+func (s *HealthCheckTestSuite) TestKillScript_Liveness(c *C) {
+	// Verify that liveness checks set the kill flag on the first failure
+	// unless a kill limit is set
+	hc := HealthCheck{
+		Kind:     LivenessCheck,
+		Script:   "exit 1",
+		Timeout:  time.Second,
+		Interval: 500 * time.Millisecond,
+	}
+	cancel := make(chan struct{})
+	hc.Ping(cancel, hcKey, func(status HealthStatus) {
+		c.Check(hc.KillCounter, Equals, 1)
+		c.Check(status.KillFlag, Equals, true)
+		c.Check(status.Kind, Equals, LivenessCheck)
+		close(cancel)
+	})
+
+	hc.KillCounter = 0
+	hc.KillCountLimit = 2
+	cancel = make(chan struct{})
+	interval := 0
+	hc.Ping(cancel, hcKey, func(status HealthStatus) {
+		interval++
+		c.Check(hc.KillCounter, Equals, interval)
+		c.Check(status.KillFlag, Equals, interval == 2)
+		if interval == 2 {
+			close(cancel)
+		}
+	})
+}
+
 func (s *HealthCheckTestSuite) TestKillScript_MatchingMultipleExitCodes(c *C) {
 	// Verify that if we fail a healthcheck 3 times that the result has the kill flag when
 	// our exit code matches one of the multiple exit codes in our list.
@@ -0,0 +1,167 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// The grpc check calls grpc.health.v1.Health/Check over HTTP/2, in cleartext
+// or over TLS, without a grpc client.  A service is healthy if the call
+// succeeds with grpc status OK and the response message says it is serving.
+
+const (
+	grpcHealthPath = "/grpc.health.v1.Health/Check"
+	grpcServing    = 1
+	grpcStatusOK   = "0"
+)
+
+var (
+	// ErrNotServing is returned when a grpc service is not serving
+	ErrNotServing = errors.New("grpc service is not serving")
+
+	// ErrGRPCResponse is returned when a grpc health check response cannot
+	// be read
+	ErrGRPCResponse = errors.New("invalid grpc health check response")
+)
+
+// checkGRPC calls the grpc health service of the check address
+func (hc *HealthCheck) checkGRPC(deadline time.Time) error {
+	scheme := "http"
+	if hc.TLS != nil {
+		scheme = "https"
+	}
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(string, string, *tls.Config) (net.Conn, error) {
+			return hc.dial(deadline, []string{http2.NextProtoTLS})
+		},
+	}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequest("POST", scheme+"://"+hc.address()+grpcHealthPath,
+		bytes.NewReader(grpcHealthRequest(hc.GRPCService)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	resp, err := transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected http status %d (expected %d)", resp.StatusCode, http.StatusOK)
+	}
+	// calls that fail without a response message only send headers
+	code, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if code == "" {
+		code, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if code == "" {
+		return ErrGRPCResponse
+	} else if code != grpcStatusOK {
+		return fmt.Errorf("grpc status %s: %s", code, message)
+	}
+
+	status, err := parseGRPCHealthResponse(data)
+	if err != nil {
+		return err
+	}
+	if status != grpcServing {
+		return ErrNotServing
+	}
+	return nil
+}
+
+// grpcHealthRequest returns a HealthCheckRequest{service} message, behind
+// the grpc message prefix
+func grpcHealthRequest(service string) []byte {
+	var msg []byte
+	if service != "" {
+		msg = append([]byte{0x0a}, appendVarint(nil, uint64(len(service)))...)
+		msg = append(msg, service...)
+	}
+	data := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(data[1:], uint32(len(msg)))
+	return append(data, msg...)
+}
+
+// parseGRPCHealthResponse returns the status of a HealthCheckResponse
+// message
+func parseGRPCHealthResponse(data []byte) (int, error) {
+	if len(data) < 5 || data[0] != 0 {
+		return 0, ErrGRPCResponse
+	}
+	size := binary.BigEndian.Uint32(data[1:5])
+	msg := data[5:]
+	if uint32(len(msg)) < size {
+		return 0, ErrGRPCResponse
+	}
+	msg = msg[:size]
+
+	status := 0
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return 0, ErrGRPCResponse
+		}
+		msg = msg[n:]
+		switch tag & 0x7 {
+		case 0:
+			value, n := binary.Uvarint(msg)
+			if n <= 0 {
+				return 0, ErrGRPCResponse
+			}
+			msg = msg[n:]
+			if tag>>3 == 1 {
+				status = int(value)
+			}
+		case 2:
+			size, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < size {
+				return 0, ErrGRPCResponse
+			}
+			msg = msg[n+int(size):]
+		default:
+			return 0, ErrGRPCResponse
+		}
+	}
+	return status, nil
+}
+
+func appendVarint(b []byte, i uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(b, buf[:binary.PutUvarint(buf, i)]...)
+}
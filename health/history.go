@@ -0,0 +1,142 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const (
+	// MaxHistory is how many results of each health check are kept
+	MaxHistory = 20
+
+	// FlapStartThreshold is how many status changes within the history mark
+	// a health check as flapping
+	FlapStartThreshold = 6
+
+	// FlapStopThreshold is how few status changes within the history clear
+	// the flapping mark of a health check
+	FlapStopThreshold = 2
+
+	// maxHistoryOutput is how much output of each result is kept
+	maxHistoryOutput = 256
+
+	// historyRetention is how long the history of a health check that stopped
+	// reporting is kept
+	historyRetention = time.Hour
+)
+
+// HealthHistoryEntry is a past result of a health check
+type HealthHistoryEntry struct {
+	Status    Status
+	StartedAt time.Time
+	Duration  time.Duration
+	Output    string `json:",omitempty"`
+}
+
+// HealthHistory is the recent results of the health check of an instance,
+// oldest first.
+type HealthHistory struct {
+	Entries  []HealthHistoryEntry
+	Changes  int  // status changes within the entries
+	Flapping bool // whether the status keeps changing
+}
+
+// add records a result and returns whether the status changed and whether
+// the check started or stopped flapping.
+func (h *HealthHistory) add(stat HealthStatus) (changed, flapChanged bool) {
+	entry := HealthHistoryEntry{
+		Status:    stat.Status,
+		StartedAt: stat.StartedAt,
+		Duration:  stat.Duration,
+		Output:    stat.Output,
+	}
+	if len(entry.Output) > maxHistoryOutput {
+		entry.Output = entry.Output[len(entry.Output)-maxHistoryOutput:]
+	}
+	if n := len(h.Entries); n > 0 {
+		changed = h.Entries[n-1].Status != entry.Status
+	}
+	h.Entries = append(h.Entries, entry)
+	if len(h.Entries) > MaxHistory {
+		h.Entries = h.Entries[len(h.Entries)-MaxHistory:]
+	}
+
+	h.Changes = 0
+	for i := 1; i < len(h.Entries); i++ {
+		if h.Entries[i].Status != h.Entries[i-1].Status {
+			h.Changes++
+		}
+	}
+	wasFlapping := h.Flapping
+	if h.Changes >= FlapStartThreshold {
+		h.Flapping = true
+	} else if h.Changes <= FlapStopThreshold {
+		h.Flapping = false
+	}
+	return changed, h.Flapping != wasFlapping
+}
+
+// last returns when the health check last reported
+func (h *HealthHistory) last() time.Time {
+	if len(h.Entries) == 0 {
+		return time.Time{}
+	}
+	return h.Entries[len(h.Entries)-1].StartedAt
+}
+
+// copy returns a copy of the history that does not share its entries
+func (h *HealthHistory) copy() HealthHistory {
+	c := *h
+	c.Entries = make([]HealthHistoryEntry, len(h.Entries))
+	copy(c.Entries, h.Entries)
+	return c
+}
+
+// record adds a result to the history of a health check and logs changes to
+// its status.  Changes are not logged while the check is flapping, so that
+// a check that keeps passing and failing does not flood the log.
+func (cache *HealthStatusCache) record(key HealthStatusKey, stat HealthStatus) bool {
+	h, ok := cache.history[key]
+	if !ok {
+		h = &HealthHistory{}
+		cache.history[key] = h
+	}
+	previous := Status(Unknown)
+	if len(h.Entries) > 0 {
+		previous = h.Entries[len(h.Entries)-1].Status
+	}
+	changed, flapChanged := h.add(stat)
+
+	logger := plog.WithFields(log.Fields{
+		"serviceid":   key.ServiceID,
+		"instanceid":  key.InstanceID,
+		"healthcheck": key.HealthCheckName,
+		"changes":     h.Changes,
+	})
+	switch {
+	case flapChanged && h.Flapping:
+		logger.Warn("Health check is flapping, suppressing status changes")
+	case flapChanged:
+		logger.WithField("status", stat.Status.Name()).Info("Health check stopped flapping")
+	case changed && !h.Flapping:
+		logger.WithFields(log.Fields{
+			"previous": previous.Name(),
+			"status":   stat.Status.Name(),
+		}).Info("Health check status changed")
+	}
+	return h.Flapping
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package health_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/control-center/serviced/health"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&HealthHistoryTestSuite{})
+
+type HealthHistoryTestSuite struct{}
+
+func (s *HealthHistoryTestSuite) TestHistory(c *C) {
+	cache := New()
+	key := HealthStatusKey{
+		ServiceID:       "test-service",
+		InstanceID:      0,
+		HealthCheckName: "test-health-0",
+	}
+	_, ok := cache.History(key)
+	c.Assert(ok, Equals, false)
+
+	started := time.Now()
+	for i := 0; i < MaxHistory+5; i++ {
+		cache.Set(key, HealthStatus{
+			Status:    OK,
+			StartedAt: started.Add(time.Duration(i) * time.Second),
+			Duration:  time.Second,
+			Output:    strings.Repeat("x", 1000),
+		}, time.Minute)
+	}
+	history, ok := cache.History(key)
+	c.Assert(ok, Equals, true)
+	c.Assert(history.Entries, HasLen, MaxHistory)
+	c.Assert(history.Entries[0].StartedAt, Equals, started.Add(5*time.Second))
+	c.Assert(len(history.Entries[0].Output) < 1000, Equals, true)
+	c.Assert(history.Changes, Equals, 0)
+	c.Assert(history.Flapping, Equals, false)
+
+	// the history outlives the cached status
+	cache.DeleteInstance("test-service", 0)
+	_, ok = cache.History(key)
+	c.Assert(ok, Equals, true)
+}
+
+func (s *HealthHistoryTestSuite) TestFlapping(c *C) {
+	cache := New()
+	key := HealthStatusKey{
+		ServiceID:       "test-service",
+		InstanceID:      0,
+		HealthCheckName: "test-health-0",
+	}
+	set := func(status Status) HealthStatus {
+		cache.Set(key, HealthStatus{Status: status, StartedAt: time.Now()}, time.Minute)
+		stat, ok := cache.Get(key)
+		c.Assert(ok, Equals, true)
+		return stat
+	}
+
+	// passing and failing marks the check as flapping
+	status := Status(OK)
+	for i := 0; i < FlapStartThreshold; i++ {
+		c.Assert(set(status).Flapping, Equals, false)
+		if status == OK {
+			status = Failed
+		} else {
+			status = OK
+		}
+	}
+	c.Assert(set(status).Flapping, Equals, true)
+	history, _ := cache.History(key)
+	c.Assert(history.Changes, Equals, FlapStartThreshold)
+	c.Assert(history.Flapping, Equals, true)
+
+	// it stays flapping until the changes fall out of the history
+	for i := 0; i < MaxHistory-FlapStopThreshold-2; i++ {
+		c.Assert(set(status).Flapping, Equals, true)
+	}
+	c.Assert(set(status).Flapping, Equals, false)
+	history, _ = cache.History(key)
+	c.Assert(history.Changes, Equals, FlapStopThreshold)
+}
+
+func (s *HealthHistoryTestSuite) TestStatusName(c *C) {
+	c.Assert(Status(OK).Name(), Equals, "passed")
+	c.Assert(Status(NotRunning).Name(), Equals, "not_running")
+	c.Assert(Status(42).Name(), Equals, "invalid")
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// maxBodySize is how much of an http response is matched against the
+// expected body
+const maxBodySize = 1 << 20
+
+var (
+	// ErrUnexpectedBody is returned when the body of an http check does not
+	// match the expected body
+	ErrUnexpectedBody = errors.New("http response does not match the expected body")
+
+	// ErrBadCAFile is returned when the CA file of a check has no certificates
+	ErrBadCAFile = errors.New("no certificates found in CA file")
+)
+
+// runNative returns the health status of an http, tcp or grpc check.
+// Failures count toward the kill count if no exit codes are listed.
+func (hc *HealthCheck) runNative(key HealthStatusKey) (stat HealthStatus) {
+	logger := plog.WithFields(log.Fields{
+		"service":     key.ServiceID,
+		"instance":    key.InstanceID,
+		"healthcheck": key.HealthCheckName,
+		"type":        hc.GetType(),
+		"target":      hc.Target(),
+	})
+	stat.StartedAt = time.Now()
+	deadline := stat.StartedAt.Add(hc.GetTimeout())
+
+	var err error
+	switch hc.GetType() {
+	case HTTPCheck:
+		err = hc.checkHTTP(deadline)
+	case TCPCheck:
+		var conn net.Conn
+		if conn, err = hc.dial(deadline, nil); err == nil {
+			conn.Close()
+		}
+	case GRPCCheck:
+		err = hc.checkGRPC(deadline)
+	default:
+		err = fmt.Errorf("unknown health check type %q", hc.Type)
+	}
+	stat.Duration = time.Since(stat.StartedAt)
+
+	if err == nil {
+		if hc.KillCounter > 0 {
+			logger.Infof("Resetting KillCounter. KillCounter was %d", hc.KillCounter)
+			hc.KillCounter = 0
+		}
+		stat.Status = OK
+		return
+	}
+
+	logger.WithError(err).Debug("Health check did not pass")
+	stat.Output = err.Error()
+	if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+		stat.Status = Timeout
+	} else {
+		stat.Status = Failed
+	}
+	if hc.GetKillCountLimit() > 0 && len(hc.KillExitCodes) == 0 {
+		hc.KillCounter++
+		logger.Debugf("KillCounter is now %d", hc.KillCounter)
+	}
+	return
+}
+
+// address returns the host:port of a tcp or grpc check
+func (hc *HealthCheck) address() string {
+	host := hc.Host
+	if host == "" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(hc.Port))
+}
+
+// tlsConfig returns the tls config of the check for the given server
+func (hc *HealthCheck) tlsConfig(serverName string) (*tls.Config, error) {
+	config := &tls.Config{ServerName: serverName}
+	if hc.TLS == nil {
+		return config, nil
+	}
+	config.InsecureSkipVerify = hc.TLS.InsecureSkipVerify
+	if hc.TLS.ServerName != "" {
+		config.ServerName = hc.TLS.ServerName
+	}
+	if hc.TLS.CAFile != "" {
+		data, err := ioutil.ReadFile(hc.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(data) {
+			return nil, ErrBadCAFile
+		}
+	}
+	return config, nil
+}
+
+// dial connects to the address of a tcp or grpc check, with TLS if the check
+// has TLS options.
+func (hc *HealthCheck) dial(deadline time.Time, nextProtos []string) (net.Conn, error) {
+	address := hc.address()
+	dialer := &net.Dialer{Deadline: deadline}
+	if hc.TLS == nil {
+		return dialer.Dial("tcp", address)
+	}
+	host, _, _ := net.SplitHostPort(address)
+	config, err := hc.tlsConfig(host)
+	if err != nil {
+		return nil, err
+	}
+	config.NextProtos = nextProtos
+	return tls.DialWithDialer(dialer, "tcp", address, config)
+}
+
+// checkHTTP requests the url of the check and verifies the response
+func (hc *HealthCheck) checkHTTP(deadline time.Time) error {
+	req, err := http.NewRequest("GET", hc.URL, nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range hc.Headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+		} else {
+			req.Header.Set(name, value)
+		}
+	}
+	config, err := hc.tlsConfig(req.URL.Hostname())
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		Timeout: time.Until(deadline),
+		Transport: &http.Transport{
+			TLSClientConfig:   config,
+			DisableKeepAlives: true,
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if hc.ExpectedStatus > 0 {
+		if resp.StatusCode != hc.ExpectedStatus {
+			return fmt.Errorf("unexpected http status %d (expected %d)", resp.StatusCode, hc.ExpectedStatus)
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("unexpected http status %d (expected 2xx or 3xx)", resp.StatusCode)
+	}
+
+	if hc.ExpectedBody != "" {
+		expected, err := regexp.Compile(hc.ExpectedBody)
+		if err != nil {
+			return err
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return err
+		}
+		if !expected.Match(body) {
+			return ErrUnexpectedBody
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package health_test
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"time"
+
+	. "github.com/control-center/serviced/health"
+	"golang.org/x/net/http2"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&NativeCheckTestSuite{})
+
+type NativeCheckTestSuite struct{}
+
+// hostPort returns the host and port of a test server
+func hostPort(c *C, address string) (string, int) {
+	host, port, err := net.SplitHostPort(address)
+	c.Assert(err, IsNil)
+	p, err := strconv.Atoi(port)
+	c.Assert(err, IsNil)
+	return host, p
+}
+
+func (s *NativeCheckTestSuite) TestMarshalJSON_RoundTrip(c *C) {
+	check := HealthCheck{
+		Type:           HTTPCheck,
+		Kind:           ReadinessCheck,
+		Timeout:        5 * time.Second,
+		Interval:       10 * time.Second,
+		URL:            "https://localhost:8443/health",
+		Headers:        map[string]string{"Accept": "application/json"},
+		ExpectedStatus: 200,
+		ExpectedBody:   `"status":\s*"ok"`,
+		TLS:            &HealthCheckTLS{InsecureSkipVerify: true, ServerName: "zope"},
+		StartPeriod:    time.Minute,
+	}
+	data, err := json.Marshal(check)
+	c.Assert(err, IsNil)
+	var actual HealthCheck
+	err = json.Unmarshal(data, &actual)
+	c.Assert(err, IsNil)
+	c.Assert(actual, DeepEquals, check)
+
+	check = HealthCheck{Type: GRPCCheck, Host: "127.0.0.1", Port: 50051, GRPCService: "zenoss.Query"}
+	data, err = json.Marshal(check)
+	c.Assert(err, IsNil)
+	actual = HealthCheck{}
+	err = json.Unmarshal(data, &actual)
+	c.Assert(err, IsNil)
+	c.Assert(actual, DeepEquals, check)
+}
+
+func (s *NativeCheckTestSuite) TestRunHTTP(c *C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if r.Header.Get("X-Check") != "serviced" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"status": "ok"}`))
+		case "/slow":
+			time.Sleep(time.Second)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	check := HealthCheck{
+		Type:         HTTPCheck,
+		Timeout:      time.Second,
+		URL:          ts.URL + "/ok",
+		Headers:      map[string]string{"X-Check": "serviced"},
+		ExpectedBody: `"status":\s*"ok"`,
+	}
+	c.Check(check.Run(hcKey).Status, Equals, OK)
+
+	// the body must match
+	check.ExpectedBody = `"status":\s*"degraded"`
+	c.Check(check.Run(hcKey).Status, Equals, Status(Failed))
+
+	// the status must match, or be 2xx or 3xx
+	check.ExpectedBody = ""
+	check.ExpectedStatus = http.StatusNoContent
+	stat := check.Run(hcKey)
+	c.Check(stat.Status, Equals, Status(Failed))
+	c.Check(stat.Output, Equals, "unexpected http status 200 (expected 204)")
+	check.ExpectedStatus = 0
+	check.URL = ts.URL + "/down"
+	stat = check.Run(hcKey)
+	c.Check(stat.Status, Equals, Status(Failed))
+	c.Check(stat.Output, Equals, "unexpected http status 503 (expected 2xx or 3xx)")
+	check.ExpectedStatus = http.StatusServiceUnavailable
+	c.Check(check.Run(hcKey).Status, Equals, OK)
+
+	// slow responses time out
+	check.ExpectedStatus = 0
+	check.URL = ts.URL + "/slow"
+	check.Timeout = 100 * time.Millisecond
+	c.Check(check.Run(hcKey).Status, Equals, Status(Timeout))
+}
+
+func (s *NativeCheckTestSuite) TestRunHTTPS(c *C) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	// the self-signed certificate is not trusted unless verification is
+	// skipped
+	check := HealthCheck{Type: HTTPCheck, Timeout: time.Second, URL: ts.URL}
+	c.Check(check.Run(hcKey).Status, Equals, Status(Failed))
+	check.TLS = &HealthCheckTLS{InsecureSkipVerify: true}
+	c.Check(check.Run(hcKey).Status, Equals, OK)
+}
+
+func (s *NativeCheckTestSuite) TestRunTCP(c *C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	host, port := hostPort(c, listener.Addr().String())
+
+	check := HealthCheck{Type: TCPCheck, Timeout: time.Second, Host: host, Port: port}
+	c.Check(check.Run(hcKey).Status, Equals, OK)
+
+	// failures count toward the kill count
+	listener.Close()
+	check.KillCountLimit = 2
+	c.Check(check.Run(hcKey).Status, Equals, Status(Failed))
+	c.Check(check.KillCounter, Equals, 1)
+}
+
+// grpcHealthHandler answers grpc health checks with the serving status, or
+// with just the grpc status if it is not OK
+func grpcHealthHandler(status *byte, code *string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/grpc.health.v1.Health/Check" || r.ProtoMajor != 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/grpc")
+		if *code != "0" {
+			w.Header().Set("Grpc-Status", *code)
+			w.Header().Set("Grpc-Message", "unknown service")
+			return
+		}
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.Write([]byte{0, 0, 0, 0, 2, 0x08, *status})
+		w.Header().Set("Grpc-Status", "0")
+	})
+}
+
+func (s *NativeCheckTestSuite) TestRunGRPC(c *C) {
+	status, code := byte(1), "0"
+	ts := httptest.NewUnstartedServer(grpcHealthHandler(&status, &code))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	c.Assert(err, IsNil)
+	host, port := hostPort(c, u.Host)
+	check := HealthCheck{
+		Type:        GRPCCheck,
+		Timeout:     time.Second,
+		Host:        host,
+		Port:        port,
+		GRPCService: "zenoss.Query",
+		TLS:         &HealthCheckTLS{InsecureSkipVerify: true},
+	}
+	c.Check(check.Run(hcKey).Status, Equals, OK)
+
+	// NOT_SERVING
+	status = 2
+	c.Check(check.Run(hcKey).Status, Equals, Status(Failed))
+
+	// NOT_FOUND
+	status, code = 1, "5"
+	stat := check.Run(hcKey)
+	c.Check(stat.Status, Equals, Status(Failed))
+	c.Check(stat.Output, Equals, "grpc status 5: unknown service")
+}
+
+func (s *NativeCheckTestSuite) TestRunGRPCCleartext(c *C) {
+	status, code := byte(1), "0"
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer listener.Close()
+	go func() {
+		server := &http2.Server{}
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.ServeConn(conn, &http2.ServeConnOpts{Handler: grpcHealthHandler(&status, &code)})
+		}
+	}()
+
+	host, port := hostPort(c, listener.Addr().String())
+	check := HealthCheck{Type: GRPCCheck, Timeout: time.Second, Host: host, Port: port}
+	c.Check(check.Run(hcKey).Status, Equals, OK)
+
+	// NOT_SERVING
+	status = 2
+	c.Check(check.Run(hcKey).Status, Equals, Status(Failed))
+}
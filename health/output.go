@@ -0,0 +1,62 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// MaxOutputSize is how many bytes at the end of the output of a failing
+// health check script are reported with its status.
+const MaxOutputSize = 1024
+
+// scriptOutput collects the stdout and stderr of a health check script in a
+// temporary file rather than a pipe, so that processes left running by the
+// script cannot keep the check from finishing.
+type scriptOutput struct {
+	f *os.File
+}
+
+func newScriptOutput() (*scriptOutput, error) {
+	f, err := ioutil.TempFile("", "healthcheck-")
+	if err != nil {
+		return nil, err
+	}
+	return &scriptOutput{f: f}, nil
+}
+
+// Tail returns up to the last n bytes of output
+func (o *scriptOutput) Tail(n int) (string, error) {
+	info, err := o.f.Stat()
+	if err != nil {
+		return "", err
+	}
+	offset := info.Size() - int64(n)
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, info.Size()-offset)
+	if _, err := o.f.ReadAt(buf, offset); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.ToValidUTF8(string(buf), "")), nil
+}
+
+// Close removes the output
+func (o *scriptOutput) Close() {
+	o.f.Close()
+	os.Remove(o.f.Name())
+}
@@ -15,6 +15,9 @@ package health
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
+
 	"github.com/control-center/serviced/validation"
 )
 
@@ -24,6 +27,41 @@ func (hc HealthCheck) ValidEntity() error {
 		violations.Add(fmt.Errorf("the KillCountLimit must be set if KillExitCodes are specified"))
 	}
 
+	switch hc.GetType() {
+	case ScriptCheck:
+	case HTTPCheck:
+		if u, err := url.Parse(hc.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			violations.Add(fmt.Errorf("http health checks need an http or https URL, not %q", hc.URL))
+		}
+		if hc.ExpectedStatus != 0 && (hc.ExpectedStatus < 100 || hc.ExpectedStatus > 599) {
+			violations.Add(fmt.Errorf("invalid ExpectedStatus %d", hc.ExpectedStatus))
+		}
+		if _, err := regexp.Compile(hc.ExpectedBody); err != nil {
+			violations.Add(fmt.Errorf("invalid ExpectedBody: %s", err))
+		}
+	case TCPCheck, GRPCCheck:
+		if hc.Port < 1 || hc.Port > 65535 {
+			violations.Add(fmt.Errorf("%s health checks need a Port between 1 and 65535", hc.Type))
+		}
+	default:
+		violations.Add(fmt.Errorf("unknown health check type %q", hc.Type))
+	}
+	switch hc.Kind {
+	case "", LivenessCheck:
+	case ReadinessCheck:
+		if hc.KillCountLimit > 0 {
+			violations.Add(fmt.Errorf("readiness health checks cannot kill the instance"))
+		}
+	default:
+		violations.Add(fmt.Errorf("unknown health check kind %q", hc.Kind))
+	}
+	if hc.StartPeriod < 0 {
+		violations.Add(fmt.Errorf("the StartPeriod cannot be negative"))
+	}
+	if hc.GetType() != ScriptCheck && len(hc.KillExitCodes) > 0 {
+		violations.Add(fmt.Errorf("KillExitCodes only apply to script health checks"))
+	}
+
 	if violations.HasError() {
 		return violations
 	}
@@ -16,6 +16,8 @@
 package health_test
 
 import (
+	"time"
+
 	. "github.com/control-center/serviced/health"
 	. "gopkg.in/check.v1"
 )
@@ -37,3 +39,46 @@ func (vs *ValidationSuite) Test_Validation_Invalid_HealthCheck(c *C) {
 	err = hc.ValidEntity()
 	c.Assert(err, IsNil)
 }
+
+func (vs *ValidationSuite) Test_Validation_NativeHealthCheck(c *C) {
+	hc := HealthCheck{Type: HTTPCheck, URL: "http://localhost:8080/health"}
+	c.Assert(hc.ValidEntity(), IsNil)
+
+	hc.URL = "localhost:8080"
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{Type: HTTPCheck, URL: "http://localhost:8080/health", ExpectedBody: "("}
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{Type: TCPCheck, Port: 3306}
+	c.Assert(hc.ValidEntity(), IsNil)
+
+	// kill exit codes only apply to scripts
+	hc.KillExitCodes = []int{28}
+	hc.KillCountLimit = 3
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{Type: GRPCCheck}
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{Type: "udp", Port: 53}
+	c.Assert(hc.ValidEntity(), NotNil)
+}
+
+func (vs *ValidationSuite) Test_Validation_HealthCheckKind(c *C) {
+	hc := HealthCheck{Kind: LivenessCheck, StartPeriod: time.Minute}
+	c.Assert(hc.ValidEntity(), IsNil)
+
+	hc = HealthCheck{Kind: ReadinessCheck}
+	c.Assert(hc.ValidEntity(), IsNil)
+
+	// readiness checks cannot kill the instance
+	hc.KillCountLimit = 3
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{Kind: "startup"}
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{StartPeriod: -time.Second}
+	c.Assert(hc.ValidEntity(), NotNil)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// GetServiceMetricAverage returns the average value of a metric per instance
+// of a service over the window.  Counters are averaged by their rate.
+func (c *Client) GetServiceMetricAverage(window time.Duration, serviceID, metric string, counter bool) (float64, error) {
+	log.WithFields(logrus.Fields{
+		"serviceid": serviceID,
+		"metric":    metric,
+	}).Debug("Requesting metric average for service")
+	secs := int(window.Seconds())
+	options := V2PerformanceOptions{
+		Start:     fmt.Sprintf("%ds-ago", secs),
+		End:       "now",
+		Returnset: "exact",
+		Metrics: []V2MetricOptions{
+			{
+				Metric:      metric,
+				Aggregator:  "avg",
+				Rate:        counter,
+				RateOptions: V2RateOptions{Counter: counter},
+				Tags: map[string][]string{
+					"controlplane_service_id":  []string{serviceID},
+					"controlplane_instance_id": []string{"*"},
+				},
+				Downsample: fmt.Sprintf("%ds-avg", secs),
+			},
+		},
+	}
+	result, err := c.v2performanceQuery(options)
+	if err != nil {
+		return 0, err
+	}
+	return averageV2Instances(result)
+}
+
+// averageV2Instances averages the datapoints of each instance's series, and
+// then the instances
+func averageV2Instances(data *V2PerformanceData) (float64, error) {
+	var sum float64
+	count := 0
+	for _, series := range data.Series {
+		if len(series.Datapoints) == 0 {
+			continue
+		}
+		var total float64
+		for _, dp := range series.Datapoints {
+			total += dp.Value()
+		}
+		sum += total / float64(len(series.Datapoints))
+		count++
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("no data found")
+	}
+	return sum / float64(count), nil
+}
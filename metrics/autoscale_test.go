@@ -0,0 +1,48 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package metrics
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAverageV2Instances(t *testing.T) {
+	testData := []byte(`
+	{ "series" : [
+		{ "datapoints" : [ [ 1453835068, 2 ], [ 1453835128, 4 ] ], "metric" : "zope.requests", "tags" : { "controlplane_service_id" : "zope", "controlplane_instance_id" : "0" } },
+		{ "datapoints" : [ [ 1453835068, 9 ] ], "metric" : "zope.requests", "tags" : { "controlplane_service_id" : "zope", "controlplane_instance_id" : "1" } },
+		{ "datapoints" : [ ], "metric" : "zope.requests", "tags" : { "controlplane_service_id" : "zope", "controlplane_instance_id" : "2" } }
+	], "statuses" : [ { "message" : "", "status" : "SUCCESS" } ] }
+	`)
+
+	var perfdata V2PerformanceData
+	if err := json.Unmarshal(testData, &perfdata); err != nil {
+		t.Fatalf("Could not unmarshal testData: %s", err)
+	}
+
+	actual, err := averageV2Instances(&perfdata)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if actual != 6 {
+		t.Errorf("Expected 6, got %v", actual)
+	}
+
+	if _, err := averageV2Instances(&V2PerformanceData{}); err == nil {
+		t.Errorf("Expected an error without data")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trafficMetrics are the counters the container proxies post for each
+// import, tagged with the application and the exporting service
+var trafficMetrics = []string{
+	"net.traffic.connections",
+	"net.traffic.errors",
+	"net.traffic.bytessent",
+	"net.traffic.bytesreceived",
+	"net.traffic.connectlatency",
+}
+
+// TrafficStats is the average traffic from a service that imports an
+// endpoint to a service that exports it, over a window of time.
+type TrafficStats struct {
+	ImportServiceID   string
+	ImportServiceName string
+	ExportServiceID   string
+	ExportServiceName string
+	Application       string
+	Connections       float64 // connections per second
+	Errors            float64 // failed dials per second
+	BytesSent         float64 // bytes per second to the exporting service
+	BytesReceived     float64 // bytes per second from the exporting service
+	ConnectLatency    float64 // average milliseconds to connect
+}
+
+// ServiceTraffic is the dependency map of a service: the traffic to the
+// services whose endpoints it imports and from the services importing its
+// endpoints.
+type ServiceTraffic struct {
+	ServiceID string
+	Window    string
+	Imports   []TrafficStats
+	Exports   []TrafficStats
+}
+
+// GetServiceTraffic returns the traffic between a service and the services
+// it imports endpoints from or exports endpoints to, averaged over the
+// window.
+func (c *Client) GetServiceTraffic(window time.Duration, serviceID string) ([]TrafficStats, error) {
+	log.WithField("serviceid", serviceID).Debug("Requesting traffic metrics for service")
+	secs := int(window.Seconds())
+	options := V2PerformanceOptions{
+		Start:     fmt.Sprintf("%ds-ago", secs),
+		End:       "now",
+		Returnset: "exact",
+	}
+
+	// traffic the service sends to the services it imports, and traffic
+	// sent to the service by services importing its endpoints
+	data := &V2PerformanceData{}
+	for i, tags := range []map[string][]string{
+		{
+			"controlplane_service_id": []string{serviceID},
+			"exportserviceid":         []string{"*"},
+			"application":             []string{"*"},
+		}, {
+			"controlplane_service_id": []string{"*"},
+			"exportserviceid":         []string{serviceID},
+			"application":             []string{"*"},
+		},
+	} {
+		options.Metrics = make([]V2MetricOptions, len(trafficMetrics))
+		for j, metric := range trafficMetrics {
+			options.Metrics[j] = V2MetricOptions{
+				Metric:      metric,
+				Aggregator:  "sum",
+				Rate:        true,
+				RateOptions: V2RateOptions{Counter: true},
+				Tags:        tags,
+				Downsample:  fmt.Sprintf("%ds-avg", secs),
+			}
+		}
+		result, err := c.v2performanceQuery(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, series := range result.Series {
+			// a service importing its own endpoint is in both results
+			if i > 0 && series.Tags["controlplane_service_id"] == serviceID {
+				continue
+			}
+			data.Series = append(data.Series, series)
+		}
+	}
+	return convertV2Traffic(data), nil
+}
+
+// convertV2Traffic sums the rates of the traffic counters per import and
+// export pair
+func convertV2Traffic(data *V2PerformanceData) []TrafficStats {
+	type trafficKey struct{ importID, exportID, application string }
+	byKey := make(map[trafficKey]*TrafficStats)
+	latency := make(map[trafficKey]float64)
+	for _, series := range data.Series {
+		key := trafficKey{
+			importID:    series.Tags["controlplane_service_id"],
+			exportID:    series.Tags["exportserviceid"],
+			application: series.Tags["application"],
+		}
+		if key.importID == "" || key.exportID == "" {
+			continue
+		}
+		s, ok := byKey[key]
+		if !ok {
+			s = &TrafficStats{
+				ImportServiceID: key.importID,
+				ExportServiceID: key.exportID,
+				Application:     key.application,
+			}
+			byKey[key] = s
+		}
+
+		var sum float64
+		for _, dp := range series.Datapoints {
+			sum += dp.Value()
+		}
+		rate := 0.0
+		if len(series.Datapoints) > 0 {
+			rate = sum / float64(len(series.Datapoints))
+		}
+		switch strings.TrimPrefix(series.Metric, "net.traffic.") {
+		case "connections":
+			s.Connections += rate
+		case "errors":
+			s.Errors += rate
+		case "bytessent":
+			s.BytesSent += rate
+		case "bytesreceived":
+			s.BytesReceived += rate
+		case "connectlatency":
+			latency[key] += rate
+		}
+	}
+
+	stats := make([]TrafficStats, 0, len(byKey))
+	for key, s := range byKey {
+		// milliseconds spent connecting per second, per connection
+		if s.Connections > 0 {
+			s.ConnectLatency = latency[key] / s.Connections
+		}
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.ImportServiceID != b.ImportServiceID {
+			return a.ImportServiceID < b.ImportServiceID
+		}
+		if a.ExportServiceID != b.ExportServiceID {
+			return a.ExportServiceID < b.ExportServiceID
+		}
+		return a.Application < b.Application
+	})
+	return stats
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package metrics
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConvertV2Traffic(t *testing.T) {
+	testData := []byte(`
+	{ "series" : [
+		{ "datapoints" : [ [ 1453835068, 2 ], [ 1453835128, 4 ] ], "metric" : "net.traffic.connections", "tags" : { "controlplane_service_id" : "zope", "exportserviceid" : "mariadb", "application" : "zodb" } },
+		{ "datapoints" : [ [ 1453835068, 30 ], [ 1453835128, 90 ] ], "metric" : "net.traffic.connectlatency", "tags" : { "controlplane_service_id" : "zope", "exportserviceid" : "mariadb", "application" : "zodb" } },
+		{ "datapoints" : [ [ 1453835068, 1000 ] ], "metric" : "net.traffic.bytessent", "tags" : { "controlplane_service_id" : "zope", "exportserviceid" : "mariadb", "application" : "zodb" } },
+		{ "datapoints" : [ [ 1453835068, 8000 ] ], "metric" : "net.traffic.bytesreceived", "tags" : { "controlplane_service_id" : "zope", "exportserviceid" : "mariadb", "application" : "zodb" } },
+		{ "datapoints" : [ [ 1453835068, 0.5 ] ], "metric" : "net.traffic.errors", "tags" : { "controlplane_service_id" : "zope", "exportserviceid" : "redis", "application" : "redis" } },
+		{ "datapoints" : [ [ 1453835068, 1 ] ], "metric" : "net.traffic.connections", "tags" : { "controlplane_service_id" : "zope" } }
+	], "statuses" : [ { "message" : "", "status" : "SUCCESS" } ] }
+	`)
+
+	var perfdata V2PerformanceData
+	if err := json.Unmarshal(testData, &perfdata); err != nil {
+		t.Fatalf("Could not unmarshal testData: %s", err)
+	}
+
+	actual := convertV2Traffic(&perfdata)
+	expected := []TrafficStats{
+		{
+			ImportServiceID: "zope",
+			ExportServiceID: "mariadb",
+			Application:     "zodb",
+			Connections:     3,
+			BytesSent:       1000,
+			BytesReceived:   8000,
+			ConnectLatency:  20,
+		}, {
+			ImportServiceID: "zope",
+			ExportServiceID: "redis",
+			Application:     "redis",
+			Errors:          0.5,
+		},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, actual)
+	}
+}
@@ -76,8 +76,9 @@ type HostAgent struct {
 	mount                []string             // each element is in the form: dockerImage,hostPath,containerPath
 	currentServices      map[string]*exec.Cmd // the current running services
 	mux                  *proxy.TCPMux
-	muxport              string // the mux port to serviced (default is 22250)
-	useTLS               bool   // true if TLS should be enabled for MUX
+	muxport              string              // the mux port to serviced (default is 22250)
+	useTLS               bool                // true if TLS should be enabled for MUX
+	muxDialer            proxy.DialerOptions // timeouts and circuit breakers of containers' mux connections
 	proxyRegistry        proxy.ProxyRegistry
 	zkClient             *coordclient.Client
 	maxContainerAge      time.Duration   // maximum age for a stopped container before it is removed
@@ -94,6 +95,8 @@ type HostAgent struct {
 	conntrackFlush       bool
 	serviceCache         *ServiceCache
 	vip                  VIP
+	exitMu               sync.Mutex
+	exitCodes            map[string]int // exit codes of the last containers that exited, by container name
 }
 
 func getZkDSN(zookeepers []string,
@@ -131,6 +134,7 @@ type AgentOptions struct {
 	Mux                  *proxy.TCPMux
 	MuxPort              string
 	UseTLS               bool
+	MuxDialer            proxy.DialerOptions
 	DockerRegistry       string
 	MaxContainerAge      time.Duration // Maximum container age for a stopped container before being removed
 	VirtualAddressSubnet string
@@ -163,6 +167,7 @@ func NewHostAgent(options AgentOptions, reg registry.Registry) (*HostAgent, erro
 	agent.mux = options.Mux
 	agent.muxport = options.MuxPort
 	agent.useTLS = options.UseTLS
+	agent.muxDialer = options.MuxDialer
 	agent.maxContainerAge = options.MaxContainerAge
 	agent.virtualAddressSubnet = options.VirtualAddressSubnet
 	agent.servicedChain = iptables.NewChain("SERVICED")
@@ -194,6 +199,7 @@ func NewHostAgent(options AgentOptions, reg registry.Registry) (*HostAgent, erro
 		panic("Could not get hostid")
 	}
 	agent.currentServices = make(map[string]*exec.Cmd)
+	agent.exitCodes = make(map[string]int)
 	agent.proxyRegistry = proxy.NewDefaultProxyRegistry()
 	agent.pullreg = reg
 	agent.vip = NewVirtualIPManager("cc")
@@ -27,6 +27,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/zenoss/glog"
 )
@@ -112,6 +113,18 @@ func (a *HostAgent) ReportHealthStatus(req master.HealthStatusRequest, unused *i
 	return masterClient.ReportHealthStatus(req.Key, req.Value, req.Expires)
 }
 
+// GetServiceHealth proxies GetServiceHealth to the master server.
+func (a *HostAgent) GetServiceHealth(serviceID string, results *map[int]map[string]health.HealthStatus) error {
+	masterClient, err := master.NewClient(a.master)
+	if err != nil {
+		glog.Errorf("Could not start Control Center client: %s", err)
+		return err
+	}
+	defer masterClient.Close()
+	*results, err = masterClient.GetServiceHealth(serviceID)
+	return err
+}
+
 // ReportInstanceDead proxies ReportInstanceDead to the master server.
 func (a *HostAgent) ReportInstanceDead(req master.ServiceInstanceRequest, unused *int) error {
 	masterClient, err := master.NewClient(a.master)
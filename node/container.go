@@ -19,6 +19,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -189,6 +190,7 @@ func (a *HostAgent) StartContainer(cancel <-chan interface{}, serviceID string,
 	state.HostIP = a.ipaddress
 	state.PrivateIP = ctr.NetworkSettings.IPAddress
 	state.Started = dctr.State.StartedAt
+	state.RestartPolicy = evaluatedService.RestartPolicy
 
 	go a.exposeAssignedIPs(state, ctr)
 	a.setInstanceState(serviceID, instanceID, service.StateRunning)
@@ -459,6 +461,7 @@ func (a *HostAgent) monitorContainer(logger *log.Entry, ctr *docker.Container) <
 			"terminated": dctr.State.FinishedAt,
 			"exitcode":   dctr.State.ExitCode,
 		}).Debug("Container exited")
+		a.setExitCode(ctr.Name, dctr.State.ExitCode)
 
 		if dctr.State.ExitCode != 0 || log.GetLevel() == log.DebugLevel {
 			dockerLogsToFile(ctr.ID, 1000)
@@ -479,6 +482,25 @@ func (a *HostAgent) monitorContainer(logger *log.Entry, ctr *docker.Container) <
 	return ev
 }
 
+// setExitCode remembers the exit code of the container that exited
+func (a *HostAgent) setExitCode(ctrName string, exitCode int) {
+	a.exitMu.Lock()
+	defer a.exitMu.Unlock()
+	if a.exitCodes == nil {
+		a.exitCodes = make(map[string]int)
+	}
+	a.exitCodes[strings.TrimPrefix(ctrName, "/")] = exitCode
+}
+
+// ExitCode returns the exit code of the last container that exited for the
+// service instance, or false if it is not known.
+func (a *HostAgent) ExitCode(serviceID string, instanceID int) (int, bool) {
+	a.exitMu.Lock()
+	defer a.exitMu.Unlock()
+	exitCode, ok := a.exitCodes[fmt.Sprintf("%s-%d", serviceID, instanceID)]
+	return exitCode, ok
+}
+
 // exposeAssignedIPs sets up iptables forwarding rules for endpoints with
 // assigned ips.
 func (a *HostAgent) exposeAssignedIPs(state *zkservice.ServiceState, ctr *docker.Container) {
@@ -646,6 +668,7 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 					PortNumber:     endpoint.PortNumber,
 					PortTemplate:   endpoint.PortTemplate,
 					VirtualAddress: endpoint.VirtualAddress,
+					LoadBalancing:  endpoint.LoadBalancing,
 				})
 			}
 		}
@@ -786,6 +809,11 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 		fmt.Sprintf("SERVICED_SERVICE_IMAGE=%s", svc.ImageID),
 		fmt.Sprintf("SERVICED_MAX_RPC_CLIENTS=1"),
 		fmt.Sprintf("SERVICED_MUX_PORT=%s", a.muxport),
+		fmt.Sprintf("SERVICED_MUX_CERT_VERIFY=%s", config.GetOptions().MuxCertVerify),
+		fmt.Sprintf("SERVICED_MUX_DIAL_TIMEOUT=%d", int(a.muxDialer.DialTimeout/time.Second)),
+		fmt.Sprintf("SERVICED_MUX_IDLE_TIMEOUT=%d", int(a.muxDialer.IdleTimeout/time.Second)),
+		fmt.Sprintf("SERVICED_MUX_BREAKER_FAILURES=%d", a.muxDialer.FailureThreshold),
+		fmt.Sprintf("SERVICED_MUX_BREAKER_RESET=%d", int(a.muxDialer.ResetTimeout/time.Second)),
 		fmt.Sprintf("SERVICED_RPC_PORT=%s", a.rpcport),
 		fmt.Sprintf("SERVICED_LOG_ADDRESS=%s", a.logstashURL),
 		fmt.Sprintf("SERVICED_ZOOKEEPER_ACL_USER=%s", config.GetOptions().ZkAclUser),
@@ -851,6 +879,12 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 		cfg.CPUShares = svc.CPUShares
 	}
 
+	if err := checkResourceLimits(svc.ResourceLimits, runtime.NumCPU()); err != nil {
+		logger.WithError(err).Error("Host cannot enforce the resource limits of the service")
+		return nil, nil, nil, err
+	}
+	setResourceLimits(hcfg, svc.ResourceLimits)
+
 	hcfg.LogConfig.Type = a.dockerLogDriver
 	hcfg.LogConfig.Config = a.dockerLogConfig
 
@@ -0,0 +1,123 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	svcdef "github.com/control-center/serviced/domain/servicedefinition"
+	dockerclient "github.com/fsouza/go-dockerclient"
+)
+
+// cgroupRoot is where the cgroup hierarchies of the host are mounted
+var cgroupRoot = "/sys/fs/cgroup"
+
+// cpuPeriod is the length of the CFS period, in microseconds, over which the
+// CPU quota of a container is enforced
+const cpuPeriod = 100000
+
+// cgroupV2Controllers maps cgroup v1 controllers to their v2 counterparts
+var cgroupV2Controllers = map[string]string{
+	"blkio": "io",
+}
+
+// hasCgroupController returns true if the host has mounted the cgroup
+// controller, either as a v1 hierarchy or in the unified v2 hierarchy.
+func hasCgroupController(name string) bool {
+	if data, err := ioutil.ReadFile(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		if v2, ok := cgroupV2Controllers[name]; ok {
+			name = v2
+		}
+		for _, controller := range strings.Fields(string(data)) {
+			if controller == name {
+				return true
+			}
+		}
+		return false
+	}
+	_, err := os.Stat(filepath.Join(cgroupRoot, name))
+	return err == nil
+}
+
+// checkResourceLimits returns an error if the host cannot enforce the
+// resource limits of a service on a host with the given number of cpus.
+func checkResourceLimits(limits svcdef.ResourceLimits, numCPU int) error {
+	if limits.CPUQuota > 0 {
+		if limits.CPUQuota > float64(numCPU) {
+			return fmt.Errorf("cpu quota of %g cores exceeds the %d cores of the host", limits.CPUQuota, numCPU)
+		}
+		if !hasCgroupController("cpu") {
+			return fmt.Errorf("host does not support cpu quotas")
+		}
+	}
+	if limits.CPUSet != "" {
+		cpus, err := svcdef.ParseCPUSet(limits.CPUSet)
+		if err != nil {
+			return err
+		}
+		for _, cpu := range cpus {
+			if cpu >= numCPU {
+				return fmt.Errorf("cpu %d of cpu set %q is not on the host, which has %d cores", cpu, limits.CPUSet, numCPU)
+			}
+		}
+		if !hasCgroupController("cpuset") {
+			return fmt.Errorf("host does not support cpu sets")
+		}
+	}
+	devices := append(append([]svcdef.DeviceLimit{}, limits.BlkioDeviceReadBps...), limits.BlkioDeviceWriteBps...)
+	if limits.BlkioWeight > 0 || len(devices) > 0 {
+		if !hasCgroupController("blkio") {
+			return fmt.Errorf("host does not support block I/O limits")
+		}
+	}
+	for _, device := range devices {
+		fi, err := os.Stat(device.Path)
+		if err != nil {
+			return fmt.Errorf("device %s is not on the host: %s", device.Path, err)
+		}
+		if fi.Mode()&os.ModeDevice == 0 {
+			return fmt.Errorf("%s is not a device", device.Path)
+		}
+	}
+	return nil
+}
+
+// setResourceLimits sets the resource limits of a service on the host config
+// of its container.
+func setResourceLimits(hcfg *dockerclient.HostConfig, limits svcdef.ResourceLimits) {
+	if limits.CPUQuota > 0 {
+		hcfg.CPUPeriod = cpuPeriod
+		hcfg.CPUQuota = int64(limits.CPUQuota * cpuPeriod)
+	}
+	hcfg.CPUSetCPUs = limits.CPUSet
+	hcfg.BlkioWeight = int64(limits.BlkioWeight)
+	hcfg.BlkioDeviceReadBps = blockLimits(limits.BlkioDeviceReadBps)
+	hcfg.BlkioDeviceWriteBps = blockLimits(limits.BlkioDeviceWriteBps)
+}
+
+func blockLimits(limits []svcdef.DeviceLimit) []dockerclient.BlockLimit {
+	var blimits []dockerclient.BlockLimit
+	for _, limit := range limits {
+		blimits = append(blimits, dockerclient.BlockLimit{
+			Path: limit.Path,
+			Rate: strconv.FormatUint(limit.Rate.Value, 10),
+		})
+	}
+	return blimits
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	svcdef "github.com/control-center/serviced/domain/servicedefinition"
+	dockerclient "github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCgroupRoot(t *testing.T, controllers ...string) func() {
+	dir, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, controller := range controllers {
+		os.MkdirAll(filepath.Join(dir, controller), 0755)
+	}
+	root := cgroupRoot
+	cgroupRoot = dir
+	return func() {
+		cgroupRoot = root
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCheckResourceLimits(t *testing.T) {
+	assert := assert.New(t)
+	defer setupCgroupRoot(t, "cpu", "cpuset")()
+
+	assert.NoError(checkResourceLimits(svcdef.ResourceLimits{}, 1))
+	assert.NoError(checkResourceLimits(svcdef.ResourceLimits{CPUQuota: 2, CPUSet: "0,3"}, 4))
+	assert.Error(checkResourceLimits(svcdef.ResourceLimits{CPUQuota: 4.5}, 4))
+	assert.Error(checkResourceLimits(svcdef.ResourceLimits{CPUSet: "2-4"}, 4))
+
+	// controllers that are not mounted
+	assert.Error(checkResourceLimits(svcdef.ResourceLimits{BlkioWeight: 100}, 4))
+}
+
+func TestCheckResourceLimits_CgroupV2(t *testing.T) {
+	assert := assert.New(t)
+	defer setupCgroupRoot(t)()
+	ioutil.WriteFile(filepath.Join(cgroupRoot, "cgroup.controllers"), []byte("cpuset cpu io memory\n"), 0644)
+
+	assert.NoError(checkResourceLimits(svcdef.ResourceLimits{CPUQuota: 1, BlkioWeight: 100}, 4))
+
+	// devices must exist on the host
+	limits := svcdef.ResourceLimits{BlkioDeviceReadBps: []svcdef.DeviceLimit{{Path: "/dev/nosuchdevice"}}}
+	assert.Error(checkResourceLimits(limits, 4))
+}
+
+func TestSetResourceLimits(t *testing.T) {
+	assert := assert.New(t)
+	limit, err := svcdef.ParseDeviceLimit("/dev/sda:1M")
+	assert.NoError(err)
+
+	hcfg := &dockerclient.HostConfig{}
+	setResourceLimits(hcfg, svcdef.ResourceLimits{
+		CPUQuota:            1.5,
+		CPUSet:              "0-1",
+		BlkioWeight:         300,
+		BlkioDeviceWriteBps: []svcdef.DeviceLimit{limit},
+	})
+	assert.Equal(int64(100000), hcfg.CPUPeriod)
+	assert.Equal(int64(150000), hcfg.CPUQuota)
+	assert.Equal("0-1", hcfg.CPUSetCPUs)
+	assert.Equal(int64(300), hcfg.BlkioWeight)
+	assert.Empty(hcfg.BlkioDeviceReadBps)
+	assert.Equal([]dockerclient.BlockLimit{{Path: "/dev/sda", Rate: "1048576"}}, hcfg.BlkioDeviceWriteBps)
+
+	// no limits leave the container unlimited
+	hcfg = &dockerclient.HostConfig{}
+	setResourceLimits(hcfg, svcdef.ResourceLimits{})
+	assert.Equal(dockerclient.HostConfig{}, *hcfg)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MaxDatagramSize is the largest udp payload
+const MaxDatagramSize = 65535
+
+// DatagramIdleTimeout is how long a udp session lasts without a datagram in
+// either direction
+var DatagramIdleTimeout = 2 * time.Minute
+
+// ErrDatagramTooLarge is returned when a datagram does not fit in a udp packet
+var ErrDatagramTooLarge = errors.New("datagram is too large")
+
+// datagramConn frames the datagrams it writes to a stream with their length,
+// so that udp can cross the mux one datagram at a time
+type datagramConn struct {
+	net.Conn
+	reader *bufio.Reader
+	mu     *sync.Mutex
+}
+
+// NewDatagramConn returns a connection that writes each buffer to the stream
+// as one datagram and reads back one datagram at a time
+func NewDatagramConn(stream net.Conn) net.Conn {
+	return &datagramConn{
+		Conn:   stream,
+		reader: bufio.NewReaderSize(stream, 2+MaxDatagramSize),
+		mu:     &sync.Mutex{},
+	}
+}
+
+// Read implements net.Conn.  Like udp, the rest of a datagram that does not
+// fit in the buffer is discarded.  A datagram is only consumed once all of
+// it has arrived, so a read that times out can be retried.
+func (c *datagramConn) Read(b []byte) (int, error) {
+	header, err := c.reader.Peek(2)
+	if err != nil {
+		return 0, err
+	}
+	size := 2 + int(binary.BigEndian.Uint16(header))
+	frame, err := c.reader.Peek(size)
+	if err != nil {
+		return 0, err
+	}
+	n := copy(b, frame[2:])
+	c.reader.Discard(size)
+	return n, nil
+}
+
+// Write implements net.Conn
+func (c *datagramConn) Write(b []byte) (int, error) {
+	if len(b) > MaxDatagramSize {
+		return 0, ErrDatagramTooLarge
+	}
+	frame := make([]byte, 2+len(b))
+	binary.BigEndian.PutUint16(frame, uint16(len(b)))
+	copy(frame[2:], b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.Conn.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// DatagramLoop relays datagrams between the client and the backend until
+// either side fails, no datagram crosses for the idle timeout, or quit is
+// closed.
+func DatagramLoop(client net.Conn, backend net.Conn, idle time.Duration, quit chan bool) {
+	last := time.Now().UnixNano()
+	done := make(chan struct{})
+	var relay = func(to, from net.Conn) {
+		defer func() { done <- struct{}{} }()
+		buf := make([]byte, MaxDatagramSize)
+		for {
+			from.SetReadDeadline(time.Now().Add(idle))
+			n, err := from.Read(buf)
+			if err != nil {
+				// keep waiting while datagrams cross the other way
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					if time.Since(time.Unix(0, atomic.LoadInt64(&last))) < idle {
+						continue
+					}
+				}
+				return
+			}
+			atomic.StoreInt64(&last, time.Now().UnixNano())
+			if _, err := to.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+	}
+
+	go relay(client, backend)
+	go relay(backend, client)
+
+	running := 2
+	select {
+	case <-done:
+		running--
+	case <-quit:
+	}
+	client.Close()
+	backend.Close()
+	for ; running > 0; running-- {
+		<-done
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package proxy
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDatagramConn(t *testing.T) {
+	left, right := net.Pipe()
+	sender, receiver := NewDatagramConn(left), NewDatagramConn(right)
+	defer sender.Close()
+	defer receiver.Close()
+
+	go func() {
+		sender.Write([]byte("<13>first message"))
+		sender.Write([]byte{})
+		sender.Write([]byte("a datagram that is too long for the buffer"))
+		sender.Write([]byte("last"))
+	}()
+
+	buf := make([]byte, 32)
+	for _, expected := range []string{"<13>first message", "", "a datagram that is too long for ", "last"} {
+		n, err := receiver.Read(buf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if actual := string(buf[:n]); actual != expected {
+			t.Errorf("Expected %q, got %q", expected, actual)
+		}
+	}
+
+	if _, err := sender.Write(make([]byte, MaxDatagramSize+1)); err != ErrDatagramTooLarge {
+		t.Errorf("Expected %s, got %v", ErrDatagramTooLarge, err)
+	}
+}
+
+func TestDatagramLoop(t *testing.T) {
+	backend, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Could not listen: %s", err)
+	}
+	defer backend.Close()
+
+	// echo every datagram in upper case
+	go func() {
+		buf := make([]byte, MaxDatagramSize)
+		for {
+			n, addr, err := backend.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			for i := 0; i < n; i++ {
+				if buf[i] >= 'a' && buf[i] <= 'z' {
+					buf[i] -= 'a' - 'A'
+				}
+			}
+			backend.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	svc, err := net.Dial("udp4", backend.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Could not dial: %s", err)
+	}
+	local, remote := net.Pipe()
+	client := NewDatagramConn(local)
+	exited := make(chan struct{})
+	go func() {
+		DatagramLoop(NewDatagramConn(remote), svc, 200*time.Millisecond, make(chan bool))
+		close(exited)
+	}()
+
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := client.Write([]byte("query")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	buf := make([]byte, 64)
+	n, err := client.Read(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if string(buf[:n]) != "QUERY" {
+		t.Errorf("Expected %q, got %q", "QUERY", buf[:n])
+	}
+
+	// the session ends when it is idle
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Datagram loop did not exit when idle")
+	}
+}
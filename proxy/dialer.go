@@ -0,0 +1,296 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// States of the circuit breaker of an address
+const (
+	CircuitClosed   = "closed"   // connections are dialed
+	CircuitOpen     = "open"     // connections fail fast
+	CircuitHalfOpen = "halfopen" // one connection probes whether the address recovered
+)
+
+// ErrCircuitOpen is returned when the circuit breaker of an address fails a
+// connection without dialing it
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// DialerOptions configure the timeouts and circuit breakers of proxied
+// connections
+type DialerOptions struct {
+	DialTimeout      time.Duration // give up on a connection after this long; 0 waits for the system timeout
+	IdleTimeout      time.Duration // close a proxied connection when no data crosses it for this long; 0 never
+	FailureThreshold int           // consecutive failed dials that trip the breaker of an address; 0 never trips
+	ResetTimeout     time.Duration // how long a tripped breaker fails fast before it probes the address
+}
+
+// dialerPruneSize is the number of addresses a dialer tracks before it
+// forgets the addresses whose breakers are closed
+var dialerPruneSize = 1000
+
+// DefaultDialerOptions are the dialer options when none are configured
+var DefaultDialerOptions = DialerOptions{
+	DialTimeout:      10 * time.Second,
+	FailureThreshold: 5,
+	ResetTimeout:     10 * time.Second,
+}
+
+// DialStats are the connection counts of an address
+type DialStats struct {
+	Address string
+	State   string // state of the circuit breaker
+	Opened  int64  // connections dialed
+	Failed  int64  // dials that failed or were failed fast
+	Tripped int64  // times the circuit breaker opened
+}
+
+type circuitBreaker struct {
+	state    string
+	failures int       // consecutive failed dials
+	openedAt time.Time // when the breaker last opened
+	opened   int64
+	failed   int64
+	tripped  int64
+}
+
+// Dialer dials addresses with a timeout, and fails fast when an address
+// keeps failing.  After the reset timeout, one connection probes the address
+// and closes the breaker again if it succeeds.
+type Dialer struct {
+	opts     DialerOptions
+	mu       *sync.Mutex
+	breakers map[string]*circuitBreaker
+	now      func() time.Time
+}
+
+// NewDialer returns a dialer with the options
+func NewDialer(opts DialerOptions) *Dialer {
+	return &Dialer{
+		opts:     opts,
+		mu:       &sync.Mutex{},
+		breakers: make(map[string]*circuitBreaker),
+		now:      time.Now,
+	}
+}
+
+// Options returns the options of the dialer
+func (d *Dialer) Options() DialerOptions {
+	return d.opts
+}
+
+// Dial connects to the address on the network
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialFunc(address, func(timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout(network, address, timeout)
+	})
+}
+
+// DialFunc connects to the address with the dial function, which is passed
+// the dial timeout
+func (d *Dialer) DialFunc(address string, dial func(timeout time.Duration) (net.Conn, error)) (net.Conn, error) {
+	if !d.allow(address) {
+		return nil, ErrCircuitOpen
+	}
+	conn, err := dial(d.opts.DialTimeout)
+	d.record(address, err)
+	return conn, err
+}
+
+// allow returns false if the breaker of the address is open
+func (d *Dialer) allow(address string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b, ok := d.breakers[address]
+	if !ok {
+		if len(d.breakers) >= dialerPruneSize {
+			d.prune()
+		}
+		b = &circuitBreaker{state: CircuitClosed}
+		d.breakers[address] = b
+	}
+	switch b.state {
+	case CircuitOpen:
+		if d.now().Sub(b.openedAt) < d.opts.ResetTimeout {
+			b.failed++
+			return false
+		}
+		// let one connection through to see if the address recovered
+		b.state = CircuitHalfOpen
+		return true
+	case CircuitHalfOpen:
+		// the probe is still dialing
+		b.failed++
+		return false
+	}
+	return true
+}
+
+// prune forgets the addresses that are not failing
+func (d *Dialer) prune() {
+	for address, b := range d.breakers {
+		if b.state == CircuitClosed && b.failures == 0 {
+			delete(d.breakers, address)
+		}
+	}
+}
+
+// record updates the breaker of the address with the result of a dial
+func (d *Dialer) record(address string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// the breaker may have been pruned while the address was dialed
+	b, ok := d.breakers[address]
+	if !ok {
+		b = &circuitBreaker{state: CircuitClosed}
+		d.breakers[address] = b
+	}
+	logger := log.WithField("address", address)
+	if err == nil {
+		if b.state != CircuitClosed {
+			logger.Info("Address recovered; closed circuit breaker")
+		}
+		b.state, b.failures = CircuitClosed, 0
+		b.opened++
+		return
+	}
+
+	b.failed++
+	b.failures++
+	if b.state == CircuitHalfOpen || (d.opts.FailureThreshold > 0 && b.failures >= d.opts.FailureThreshold) {
+		if b.state != CircuitOpen {
+			b.tripped++
+		}
+		b.state, b.openedAt = CircuitOpen, d.now()
+		logger.WithError(err).WithFields(logrus.Fields{
+			"failures":     b.failures,
+			"resettimeout": d.opts.ResetTimeout,
+		}).Warn("Opened circuit breaker; failing connections fast")
+	}
+}
+
+// Stats returns the connection counts of each address that was dialed,
+// sorted by address
+func (d *Dialer) Stats() []DialStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := make([]DialStats, 0, len(d.breakers))
+	for address, b := range d.breakers {
+		stats = append(stats, DialStats{
+			Address: address,
+			State:   b.state,
+			Opened:  b.opened,
+			Failed:  b.failed,
+			Tripped: b.tripped,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Address < stats[j].Address })
+	return stats
+}
+
+// CloseIdle wraps a pair of proxied connections so that both are closed
+// when no data crosses either of them for the timeout.  Returns the
+// connections as they are if the timeout is 0.
+func CloseIdle(client, backend net.Conn, timeout time.Duration) (net.Conn, net.Conn) {
+	if timeout <= 0 {
+		return client, backend
+	}
+	w := &idleWatch{
+		last: time.Now().UnixNano(),
+		done: make(chan struct{}),
+		once: &sync.Once{},
+	}
+	go w.watch(timeout, client, backend)
+	return &idleConn{Conn: client, watch: w}, &idleConn{Conn: backend, watch: w}
+}
+
+type idleWatch struct {
+	last int64 // unix nanoseconds of the last read or write
+	done chan struct{}
+	once *sync.Once
+}
+
+func (w *idleWatch) touch() {
+	atomic.StoreInt64(&w.last, time.Now().UnixNano())
+}
+
+func (w *idleWatch) stop() {
+	w.once.Do(func() { close(w.done) })
+}
+
+func (w *idleWatch) watch(timeout time.Duration, client, backend net.Conn) {
+	interval := timeout / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if time.Since(time.Unix(0, atomic.LoadInt64(&w.last))) >= timeout {
+				log.WithFields(logrus.Fields{
+					"remoteaddr": client.RemoteAddr(),
+					"timeout":    timeout,
+				}).Debug("Closing idle connection")
+				client.Close()
+				backend.Close()
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// idleConn records when data crosses the connection
+type idleConn struct {
+	net.Conn
+	watch *idleWatch
+}
+
+// Read implements net.Conn
+func (c *idleConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.watch.touch()
+	}
+	return n, err
+}
+
+// Write implements net.Conn
+func (c *idleConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.watch.touch()
+	}
+	return n, err
+}
+
+// Close implements net.Conn
+func (c *idleConn) Close() error {
+	c.watch.stop()
+	return c.Conn.Close()
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package proxy
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDialerCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	dialer := NewDialer(DialerOptions{
+		DialTimeout:      time.Second,
+		FailureThreshold: 2,
+		ResetTimeout:     10 * time.Second,
+	})
+	dialer.now = func() time.Time { return now }
+
+	dials := 0
+	failing := errors.New("connection refused")
+	var dial = func(err error) error {
+		_, e := dialer.DialFunc("10.0.0.1:22250", func(timeout time.Duration) (net.Conn, error) {
+			if timeout != time.Second {
+				t.Errorf("Expected dial timeout %s, got %s", time.Second, timeout)
+			}
+			dials++
+			if err != nil {
+				return nil, err
+			}
+			client, server := net.Pipe()
+			server.Close()
+			return client, nil
+		})
+		return e
+	}
+
+	// the breaker trips after the threshold of consecutive failures
+	for i := 0; i < 2; i++ {
+		if err := dial(failing); err != failing {
+			t.Fatalf("Expected %s, got %v", failing, err)
+		}
+	}
+	if err := dial(nil); err != ErrCircuitOpen {
+		t.Fatalf("Expected %s, got %v", ErrCircuitOpen, err)
+	}
+	if dials != 2 {
+		t.Errorf("Expected 2 dials, got %d", dials)
+	}
+
+	// a failed probe opens the breaker again
+	now = now.Add(10 * time.Second)
+	if err := dial(failing); err != failing {
+		t.Fatalf("Expected %s, got %v", failing, err)
+	}
+	if err := dial(nil); err != ErrCircuitOpen {
+		t.Fatalf("Expected %s, got %v", ErrCircuitOpen, err)
+	}
+
+	// a successful probe closes the breaker
+	now = now.Add(10 * time.Second)
+	if err := dial(nil); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := dial(nil); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	stats := dialer.Stats()
+	if len(stats) != 1 {
+		t.Fatalf("Expected stats for 1 address, got %d", len(stats))
+	}
+	expected := DialStats{Address: "10.0.0.1:22250", State: CircuitClosed, Opened: 2, Failed: 5, Tripped: 2}
+	if stats[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats[0])
+	}
+}
+
+func TestDialerNoThreshold(t *testing.T) {
+	dialer := NewDialer(DialerOptions{})
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Could not listen: %s", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	// without a threshold the breaker never trips
+	for i := 0; i < 10; i++ {
+		if _, err := dialer.Dial("tcp4", address); err == nil || err == ErrCircuitOpen {
+			t.Fatalf("Expected a dial error, got %v", err)
+		}
+	}
+	if stats := dialer.Stats(); stats[0].State != CircuitClosed || stats[0].Tripped != 0 {
+		t.Errorf("Expected a closed breaker, got %+v", stats[0])
+	}
+}
+
+func TestCloseIdle(t *testing.T) {
+	client, clientPeer := net.Pipe()
+	backend, backendPeer := net.Pipe()
+	defer clientPeer.Close()
+	defer backendPeer.Close()
+
+	client, backend = CloseIdle(client, backend, 100*time.Millisecond)
+
+	// traffic keeps the connections open
+	go func() {
+		buf := make([]byte, 8)
+		for {
+			if _, err := backendPeer.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		if _, err := backend.Write([]byte("ping")); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// both connections close once they are idle
+	done := make(chan error)
+	go func() {
+		_, err := clientPeer.Read(make([]byte, 8))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected the idle client connection to close")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Idle connection was not closed")
+	}
+	if _, err := backend.Write([]byte("ping")); err == nil {
+		t.Error("Expected the idle backend connection to close")
+	}
+}
@@ -34,6 +34,7 @@ var (
 // TCPMux is an implementation of tcp muxing RFC 1078.
 type TCPMux struct {
 	listener    net.Listener    // the connection this mux listens on
+	dialer      *Dialer         // dials the containers the mux proxies to
 	connections chan net.Conn   // stream of accepted connections
 	closing     chan chan error // shutdown noticiation
 	log         *logrus.Entry
@@ -41,14 +42,19 @@ type TCPMux struct {
 
 // NewTCPMux creates a new tcp mux with the given listener. If it succees, it
 // is expected that this object is the owner of the listener and will close it
-// when Close() is called on the TCPMux.
-func NewTCPMux(listener net.Listener) (mux *TCPMux, err error) {
+// when Close() is called on the TCPMux.  Containers are dialed with the
+// timeouts and circuit breakers of the dialer.
+func NewTCPMux(listener net.Listener, dialer *Dialer) (mux *TCPMux, err error) {
 	log.Debug("Starting TCP multiplexer")
 	if listener == nil {
 		return nil, fmt.Errorf("listener can not be nil")
 	}
+	if dialer == nil {
+		dialer = NewDialer(DefaultDialerOptions)
+	}
 	mux = &TCPMux{
 		listener:    listener,
+		dialer:      dialer,
 		connections: make(chan net.Conn),
 		closing:     make(chan chan error),
 		log: log.WithFields(logrus.Fields{
@@ -150,6 +156,12 @@ func (mux *TCPMux) muxConnection(conn net.Conn) {
 		return
 	}
 
+	addrPacked, protocol, err := auth.SplitMuxAddress(addrPacked)
+	if err != nil {
+		log.WithError(err).Warn("Unable to read valid mux address. Closing connection")
+		conn.Close()
+		return
+	}
 	address := utils.UnpackTCPAddressToString(addrPacked)
 
 	// Restore the read deadline
@@ -160,16 +172,30 @@ func (mux *TCPMux) muxConnection(conn net.Conn) {
 		"remoteaddr":    conn.RemoteAddr(),
 		"containeraddr": address,
 	})
-	svc, err := net.Dial("tcp4", address)
+	network := "tcp4"
+	if protocol == auth.MuxProtocolUDP {
+		network = "udp4"
+	}
+	svc, err := mux.dialer.Dial(network, address)
 	if err != nil {
-		log.Debug("Unable to dial container address. Perhaps the container is still starting?")
+		log.WithError(err).Debug("Unable to dial container address. Perhaps the container is still starting?")
 		conn.Close()
 		return
 	}
 
 	// Wire up the incoming connection to the one we just dialed
 	quit := make(chan bool)
-	go ProxyLoop(conn, svc, quit)
+	if protocol == auth.MuxProtocolUDP {
+		go DatagramLoop(NewDatagramConn(conn), svc, DatagramIdleTimeout, quit)
+		return
+	}
+	client, backend := CloseIdle(conn, svc, mux.dialer.Options().IdleTimeout)
+	go ProxyLoop(client, backend, quit)
+}
+
+// Stats returns the connection counts of the containers the mux dialed
+func (mux *TCPMux) Stats() []DialStats {
+	return mux.dialer.Stats()
 }
 
 func ProxyLoop(client net.Conn, backend net.Conn, quit chan bool) {
@@ -130,7 +130,7 @@ func TestTCPMux(t *testing.T) {
 	if err != nil {
 		t.Fatalf("could not create tcpmux endpoint: %s", err)
 	}
-	mux, err := NewTCPMux(muxEndpoint)
+	mux, err := NewTCPMux(muxEndpoint, nil)
 	if err != nil {
 		t.Fatalf("did not expect failure creating TCPMux: %s", err)
 	}
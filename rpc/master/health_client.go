@@ -36,6 +36,21 @@ func (c *Client) GetServicesHealth() (map[string]map[int]map[string]health.Healt
 	return results, err
 }
 
+// GetServiceHealth returns health checks for the instances of a service.
+func (c *Client) GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	results := make(map[int]map[string]health.HealthStatus)
+	err := c.call("GetServiceHealth", serviceID, &results)
+	return results, err
+}
+
+// GetServiceHealthHistory returns the recent results of the health checks of
+// the instances of a service.
+func (c *Client) GetServiceHealthHistory(serviceID string) (map[int]map[string]health.HealthHistory, error) {
+	results := make(map[int]map[string]health.HealthHistory)
+	err := c.call("GetServiceHealthHistory", serviceID, &results)
+	return results, err
+}
+
 // ReportHealthStatus sends an update to the health check status cache.
 func (c *Client) ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) error {
 	request := HealthStatusRequest{
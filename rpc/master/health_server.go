@@ -62,6 +62,27 @@ func (s *Server) GetServicesHealth(unused struct{}, results *map[string]map[int]
 	return nil
 }
 
+// GetServiceHealth returns health checks for the instances of a service.
+func (s *Server) GetServiceHealth(serviceID string, results *map[int]map[string]health.HealthStatus) error {
+	healthStatuses, err := s.f.GetServiceHealth(s.context(), serviceID)
+	if err != nil {
+		return err
+	}
+	*results = healthStatuses
+	return nil
+}
+
+// GetServiceHealthHistory returns the recent results of the health checks of
+// the instances of a service.
+func (s *Server) GetServiceHealthHistory(serviceID string, results *map[int]map[string]health.HealthHistory) error {
+	history, err := s.f.GetServiceHealthHistory(s.context(), serviceID)
+	if err != nil {
+		return err
+	}
+	*results = history
+	return nil
+}
+
 // ReportHealthStatus sends an update to the health check status cache.
 func (s *Server) ReportHealthStatus(request HealthStatusRequest, _ *struct{}) error {
 	s.f.ReportHealthStatus(request.Key, request.Value, request.Expires)
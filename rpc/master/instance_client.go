@@ -27,6 +27,17 @@ func (c *Client) GetServiceInstances(serviceID string) ([]service.Instance, erro
 }
 
 // StopServiceInstance stops a service instance.
+// GetHostInstances returns all instances on a host
+func (c *Client) GetHostInstances(hostID string) ([]service.Instance, error) {
+	insts := []service.Instance{}
+
+	err := c.call("GetHostInstances", hostID, &insts)
+	if err != nil {
+		return nil, err
+	}
+	return insts, nil
+}
+
 func (c *Client) StopServiceInstance(serviceID string, instanceID int) error {
 	req := ServiceInstanceRequest{
 		ServiceID:  serviceID,
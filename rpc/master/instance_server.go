@@ -29,6 +29,16 @@ func (s *Server) GetServiceInstances(serviceID string, res *[]service.Instance)
 	return
 }
 
+// GetHostInstances returns all instances on a host
+func (s *Server) GetHostInstances(hostID string, res *[]service.Instance) (err error) {
+	insts, err := s.f.GetHostInstances(s.context(), time.Now().Add(-time.Hour), hostID)
+	if err != nil {
+		return
+	}
+	*res = insts
+	return
+}
+
 type ServiceInstanceRequest struct {
 	ServiceID  string
 	InstanceID int
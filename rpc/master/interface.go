@@ -18,8 +18,10 @@ import (
 
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/scriptjob"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
@@ -96,6 +98,9 @@ type ClientInterface interface {
 	// GetPoolIPs returns a all IPs in a ResourcePool.
 	GetPoolIPs(poolID string) (*pool.PoolIPs, error)
 
+	// GetPoolUsage returns the resources committed to a ResourcePool against its quotas.
+	GetPoolUsage(poolID string) (*pool.Usage, error)
+
 	// AddVirtualIP adds a VirtualIP to a specific pool
 	AddVirtualIP(requestVirtualIP pool.VirtualIP) error
 
@@ -132,6 +137,9 @@ type ClientInterface interface {
 	// GetServiceInstances returns all running instances of a service
 	GetServiceInstances(serviceID string) ([]service.Instance, error)
 
+	// GetHostInstances returns all running instances on a host
+	GetHostInstances(hostID string) ([]service.Instance, error)
+
 	// Get a service from serviced where all templated properties have been evaluated
 	GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, string, error)
 
@@ -163,6 +171,21 @@ type ClientInterface interface {
 	// Deploy an application template
 	DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest) (tenantIDs []string, err error)
 
+	//--------------------------------------------------------------------------
+	// Script Job Management Functions
+
+	// RunScriptJob starts running a service script on the master
+	RunScriptJob(job scriptjob.Job) (string, error)
+
+	// ResumeScriptJob resumes a failed or interrupted script job
+	ResumeScriptJob(jobID string) error
+
+	// GetScriptJob returns the progress of a script job
+	GetScriptJob(jobID string) (*scriptjob.Job, error)
+
+	// GetScriptJobs returns all script jobs
+	GetScriptJobs() ([]scriptjob.Job, error)
+
 	//--------------------------------------------------------------------------
 	// Volume Management Functions
 
@@ -206,8 +229,20 @@ type ClientInterface interface {
 
 	EnablePublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled bool) error
 
+	SetPublicEndpointPortPolicy(serviceid, endpointName, portAddr string, policy servicedefinition.AccessPolicy) error
+
+	SetPublicEndpointVHostPolicy(serviceid, endpointName, vhost string, policy servicedefinition.AccessPolicy) error
+
 	GetAllPublicEndpoints() ([]service.PublicEndpoint, error)
 
+	SetPublicEndpointCertificate(serviceid, endpointName, certType, name, certPEM, keyPEM string) (*certificate.Certificate, error)
+
+	RemovePublicEndpointCertificate(certType, name string) error
+
+	SetPublicEndpointACME(serviceid, endpointName, certType, name string, domains []string) (*certificate.Certificate, error)
+
+	GetPublicEndpointCertificates() ([]certificate.Certificate, error)
+
 	//--------------------------------------------------------------------------
 	// User Management Functions
 
@@ -226,6 +261,13 @@ type ClientInterface interface {
 	// GetServicesHealth returns health checks for all services.
 	GetServicesHealth() (map[string]map[int]map[string]health.HealthStatus, error)
 
+	// GetServiceHealth returns health checks for the instances of a service.
+	GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error)
+
+	// GetServiceHealthHistory returns the recent results of the health checks
+	// of the instances of a service.
+	GetServiceHealthHistory(serviceID string) (map[int]map[string]health.HealthHistory, error)
+
 	// ReportHealthStatus sends an update to the health check status cache.
 	ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) error
 
@@ -1,12 +1,14 @@
 package mocks
 
 import applicationendpoint "github.com/control-center/serviced/domain/applicationendpoint"
+import certificate "github.com/control-center/serviced/domain/certificate"
 import health "github.com/control-center/serviced/health"
 import host "github.com/control-center/serviced/domain/host"
 import isvcs "github.com/control-center/serviced/isvcs"
 import master "github.com/control-center/serviced/rpc/master"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
+import scriptjob "github.com/control-center/serviced/domain/scriptjob"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
@@ -483,6 +485,29 @@ func (_m *ClientInterface) GetHost(hostID string) (*host.Host, error) {
 	return r0, r1
 }
 
+// GetHostInstances provides a mock function with given fields: hostID
+func (_m *ClientInterface) GetHostInstances(hostID string) ([]service.Instance, error) {
+	ret := _m.Called(hostID)
+
+	var r0 []service.Instance
+	if rf, ok := ret.Get(0).(func(string) []service.Instance); ok {
+		r0 = rf(hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Instance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetHostPublicKey provides a mock function with given fields: hostID
 func (_m *ClientInterface) GetHostPublicKey(hostID string) ([]byte, error) {
 	ret := _m.Called(hostID)
@@ -575,6 +600,52 @@ func (_m *ClientInterface) GetPoolIPs(poolID string) (*pool.PoolIPs, error) {
 	return r0, r1
 }
 
+// GetPoolUsage provides a mock function with given fields: poolID
+func (_m *ClientInterface) GetPoolUsage(poolID string) (*pool.Usage, error) {
+	ret := _m.Called(poolID)
+
+	var r0 *pool.Usage
+	if rf, ok := ret.Get(0).(func(string) *pool.Usage); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pool.Usage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicEndpointCertificates provides a mock function with given fields: 
+func (_m *ClientInterface) GetPublicEndpointCertificates() ([]certificate.Certificate, error) {
+	ret := _m.Called()
+
+	var r0 []certificate.Certificate
+	if rf, ok := ret.Get(0).(func() []certificate.Certificate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetResourcePool provides a mock function with given fields: poolID
 func (_m *ClientInterface) GetResourcePool(poolID string) (*pool.ResourcePool, error) {
 	ret := _m.Called(poolID)
@@ -621,6 +692,52 @@ func (_m *ClientInterface) GetResourcePools() ([]pool.ResourcePool, error) {
 	return r0, r1
 }
 
+// GetScriptJob provides a mock function with given fields: jobID
+func (_m *ClientInterface) GetScriptJob(jobID string) (*scriptjob.Job, error) {
+	ret := _m.Called(jobID)
+
+	var r0 *scriptjob.Job
+	if rf, ok := ret.Get(0).(func(string) *scriptjob.Job); ok {
+		r0 = rf(jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*scriptjob.Job)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetScriptJobs provides a mock function with given fields: 
+func (_m *ClientInterface) GetScriptJobs() ([]scriptjob.Job, error) {
+	ret := _m.Called()
+
+	var r0 []scriptjob.Job
+	if rf, ok := ret.Get(0).(func() []scriptjob.Job); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]scriptjob.Job)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceDetails provides a mock function with given fields: serviceID
 func (_m *ClientInterface) GetServiceDetails(serviceID string) (*service.ServiceDetails, error) {
 	ret := _m.Called(serviceID)
@@ -690,6 +807,52 @@ func (_m *ClientInterface) GetServiceEndpoints(serviceIDs []string, reportImport
 	return r0, r1
 }
 
+// GetServiceHealth provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 map[int]map[string]health.HealthStatus
+	if rf, ok := ret.Get(0).(func(string) map[int]map[string]health.HealthStatus); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]map[string]health.HealthStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceHealthHistory provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceHealthHistory(serviceID string) (map[int]map[string]health.HealthHistory, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 map[int]map[string]health.HealthHistory
+	if rf, ok := ret.Get(0).(func(string) map[int]map[string]health.HealthHistory); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]map[string]health.HealthHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceInstances provides a mock function with given fields: serviceID
 func (_m *ClientInterface) GetServiceInstances(serviceID string) ([]service.Instance, error) {
 	ret := _m.Called(serviceID)
@@ -884,6 +1047,20 @@ func (_m *ClientInterface) RemoveHost(hostID string) error {
 	return r0
 }
 
+// RemovePublicEndpointCertificate provides a mock function with given fields: certType, name
+func (_m *ClientInterface) RemovePublicEndpointCertificate(certType string, name string) error {
+	ret := _m.Called(certType, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(certType, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemovePublicEndpointPort provides a mock function with given fields: serviceid, endpointName, portAddr
 func (_m *ClientInterface) RemovePublicEndpointPort(serviceid string, endpointName string, portAddr string) error {
 	ret := _m.Called(serviceid, endpointName, portAddr)
@@ -1042,6 +1219,41 @@ func (_m *ClientInterface) ResolveServicePath(path string, noprefix bool) ([]ser
 	return r0, r1
 }
 
+// ResumeScriptJob provides a mock function with given fields: jobID
+func (_m *ClientInterface) ResumeScriptJob(jobID string) error {
+	ret := _m.Called(jobID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(jobID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RunScriptJob provides a mock function with given fields: job
+func (_m *ClientInterface) RunScriptJob(job scriptjob.Job) (string, error) {
+	ret := _m.Called(job)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(scriptjob.Job) string); ok {
+		r0 = rf(job)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(scriptjob.Job) error); ok {
+		r1 = rf(job)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendDockerAction provides a mock function with given fields: serviceID, instanceID, action, args
 func (_m *ClientInterface) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
 	ret := _m.Called(serviceID, instanceID, action, args)
@@ -1077,6 +1289,80 @@ func (_m *ClientInterface) ServiceUse(tenantID string, serviceID string, imageID
 	return r0, r1
 }
 
+// SetPublicEndpointACME provides a mock function with given fields: serviceid, endpointName, certType, name, domains
+func (_m *ClientInterface) SetPublicEndpointACME(serviceid string, endpointName string, certType string, name string, domains []string) (*certificate.Certificate, error) {
+	ret := _m.Called(serviceid, endpointName, certType, name, domains)
+
+	var r0 *certificate.Certificate
+	if rf, ok := ret.Get(0).(func(string, string, string, string, []string) *certificate.Certificate); ok {
+		r0 = rf(serviceid, endpointName, certType, name, domains)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string, []string) error); ok {
+		r1 = rf(serviceid, endpointName, certType, name, domains)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPublicEndpointCertificate provides a mock function with given fields: serviceid, endpointName, certType, name, certPEM, keyPEM
+func (_m *ClientInterface) SetPublicEndpointCertificate(serviceid string, endpointName string, certType string, name string, certPEM string, keyPEM string) (*certificate.Certificate, error) {
+	ret := _m.Called(serviceid, endpointName, certType, name, certPEM, keyPEM)
+
+	var r0 *certificate.Certificate
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string, string) *certificate.Certificate); ok {
+		r0 = rf(serviceid, endpointName, certType, name, certPEM, keyPEM)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string, string) error); ok {
+		r1 = rf(serviceid, endpointName, certType, name, certPEM, keyPEM)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPublicEndpointPortPolicy provides a mock function with given fields: serviceid, endpointName, portAddr, policy
+func (_m *ClientInterface) SetPublicEndpointPortPolicy(serviceid string, endpointName string, portAddr string, policy servicedefinition.AccessPolicy) error {
+	ret := _m.Called(serviceid, endpointName, portAddr, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(serviceid, endpointName, portAddr, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPublicEndpointVHostPolicy provides a mock function with given fields: serviceid, endpointName, vhost, policy
+func (_m *ClientInterface) SetPublicEndpointVHostPolicy(serviceid string, endpointName string, vhost string, policy servicedefinition.AccessPolicy) error {
+	ret := _m.Called(serviceid, endpointName, vhost, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(serviceid, endpointName, vhost, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StopServiceInstance provides a mock function with given fields: serviceID, instanceID
 func (_m *ClientInterface) StopServiceInstance(serviceID string, instanceID int) error {
 	ret := _m.Called(serviceID, instanceID)
@@ -59,6 +59,15 @@ func (c *Client) GetPoolIPs(poolID string) (*pool.PoolIPs, error) {
 	return &poolIPs, nil
 }
 
+//GetPoolUsage returns the resources committed to a ResourcePool against its quotas.
+func (c *Client) GetPoolUsage(poolID string) (*pool.Usage, error) {
+	var usage pool.Usage
+	if err := c.call("GetPoolUsage", poolID, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
 //AddVirtualIP adds a VirtualIP to a specificpool
 func (c *Client) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	return c.call("AddVirtualIP", requestVirtualIP, nil)
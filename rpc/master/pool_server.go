@@ -71,6 +71,16 @@ func (s *Server) GetPoolIPs(poolID string, reply *pool.PoolIPs) error {
 	return nil
 }
 
+// GetPoolUsage gets the resources committed to a pool against its quotas
+func (s *Server) GetPoolUsage(poolID string, reply *pool.Usage) error {
+	response, err := s.f.GetPoolUsage(s.context(), poolID)
+	if err != nil {
+		return err
+	}
+	*reply = *response
+	return nil
+}
+
 // AddVirtualIP adds a specific virtual IP to a pool
 func (s *Server) AddVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) error {
 	return s.f.AddVirtualIP(s.context(), requestVirtualIP)
@@ -14,6 +14,7 @@
 package master
 
 import (
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 )
@@ -92,6 +93,28 @@ func (c *Client) EnablePublicEndpointVHost(serviceid, endpointName, vhost string
 	return c.call("EnablePublicEndpointVHost", request, nil)
 }
 
+// Sets the access policy of a port public endpoint.
+func (c *Client) SetPublicEndpointPortPolicy(serviceid, endpointName, portAddr string, policy servicedefinition.AccessPolicy) error {
+	request := &PublicEndpointPolicyRequest{
+		Serviceid:    serviceid,
+		EndpointName: endpointName,
+		Name:         portAddr,
+		Policy:       policy,
+	}
+	return c.call("SetPublicEndpointPortPolicy", request, nil)
+}
+
+// Sets the access policy of a vhost public endpoint.
+func (c *Client) SetPublicEndpointVHostPolicy(serviceid, endpointName, vhost string, policy servicedefinition.AccessPolicy) error {
+	request := &PublicEndpointPolicyRequest{
+		Serviceid:    serviceid,
+		EndpointName: endpointName,
+		Name:         vhost,
+		Policy:       policy,
+	}
+	return c.call("SetPublicEndpointVHostPolicy", request, nil)
+}
+
 // GetAllPublicEndpoints
 func (c *Client) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	var response []service.PublicEndpoint
@@ -100,3 +123,50 @@ func (c *Client) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	}
 	return response, nil
 }
+
+// Sets the certificate of a vhost or port public endpoint.
+func (c *Client) SetPublicEndpointCertificate(serviceid, endpointName, certType, name, certPEM, keyPEM string) (*certificate.Certificate, error) {
+	request := &PublicEndpointCertificateRequest{
+		Serviceid:    serviceid,
+		EndpointName: endpointName,
+		Type:         certType,
+		Name:         name,
+		CertPEM:      certPEM,
+		KeyPEM:       keyPEM,
+	}
+	var result certificate.Certificate
+	err := c.call("SetPublicEndpointCertificate", request, &result)
+	return &result, err
+}
+
+// Removes the certificate of a vhost or port public endpoint.
+func (c *Client) RemovePublicEndpointCertificate(certType, name string) error {
+	request := &PublicEndpointCertificateRequest{
+		Type: certType,
+		Name: name,
+	}
+	return c.call("RemovePublicEndpointCertificate", request, nil)
+}
+
+// Requests an acme certificate for a vhost or port public endpoint.
+func (c *Client) SetPublicEndpointACME(serviceid, endpointName, certType, name string, domains []string) (*certificate.Certificate, error) {
+	request := &PublicEndpointCertificateRequest{
+		Serviceid:    serviceid,
+		EndpointName: endpointName,
+		Type:         certType,
+		Name:         name,
+		Domains:      domains,
+	}
+	var result certificate.Certificate
+	err := c.call("SetPublicEndpointACME", request, &result)
+	return &result, err
+}
+
+// Gets the certificates of all public endpoints, without their keys.
+func (c *Client) GetPublicEndpointCertificates() ([]certificate.Certificate, error) {
+	var response []certificate.Certificate
+	if err := c.call("GetPublicEndpointCertificates", empty, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
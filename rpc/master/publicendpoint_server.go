@@ -14,6 +14,7 @@
 package master
 
 import (
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 )
@@ -29,6 +30,26 @@ type PublicEndpointRequest struct {
 	Restart      bool
 }
 
+// Defines a request to set the certificate of a vhost or port public endpoint
+type PublicEndpointCertificateRequest struct {
+	Serviceid    string
+	EndpointName string
+	Type         string
+	Name         string
+	CertPEM      string
+	KeyPEM       string
+	Domains      []string
+}
+
+// Defines a request to set the access policy of a vhost or port public
+// endpoint
+type PublicEndpointPolicyRequest struct {
+	Serviceid    string
+	EndpointName string
+	Name         string
+	Policy       servicedefinition.AccessPolicy
+}
+
 // Adds a port public endpoint to a service.
 func (s *Server) AddPublicEndpointPort(request *PublicEndpointRequest, reply *servicedefinition.Port) error {
 	port, err := s.f.AddPublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name,
@@ -71,6 +92,16 @@ func (s *Server) EnablePublicEndpointVHost(request *PublicEndpointRequest, _ *st
 	return s.f.EnablePublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name, request.IsEnabled)
 }
 
+// Sets the access policy of a port public endpoint.
+func (s *Server) SetPublicEndpointPortPolicy(request *PublicEndpointPolicyRequest, _ *struct{}) error {
+	return s.f.SetPublicEndpointPortPolicy(s.context(), request.Serviceid, request.EndpointName, request.Name, request.Policy)
+}
+
+// Sets the access policy of a vhost public endpoint.
+func (s *Server) SetPublicEndpointVHostPolicy(request *PublicEndpointPolicyRequest, _ *struct{}) error {
+	return s.f.SetPublicEndpointVHostPolicy(s.context(), request.Serviceid, request.EndpointName, request.Name, request.Policy)
+}
+
 // GetAllPublicEndpoints get all public endpoints
 func (s *Server) GetAllPublicEndpoints(empty struct{}, publicEndpoints *[]service.PublicEndpoint) error {
 	peps, err := s.f.GetAllPublicEndpoints(s.context())
@@ -80,3 +111,45 @@ func (s *Server) GetAllPublicEndpoints(empty struct{}, publicEndpoints *[]servic
 	*publicEndpoints = peps
 	return nil
 }
+
+// Sets the certificate of a vhost or port public endpoint.
+func (s *Server) SetPublicEndpointCertificate(request *PublicEndpointCertificateRequest, reply *certificate.Certificate) error {
+	cert, err := s.f.SetPublicEndpointCertificate(s.context(), request.Serviceid, request.EndpointName, request.Type,
+		request.Name, request.CertPEM, request.KeyPEM)
+	if err != nil {
+		return err
+	}
+	*reply = *cert
+	reply.KeyPEM = ""
+	return nil
+}
+
+// Removes the certificate of a vhost or port public endpoint.
+func (s *Server) RemovePublicEndpointCertificate(request *PublicEndpointCertificateRequest, _ *struct{}) error {
+	return s.f.RemovePublicEndpointCertificate(s.context(), request.Type, request.Name)
+}
+
+// Requests an acme certificate for a vhost or port public endpoint.
+func (s *Server) SetPublicEndpointACME(request *PublicEndpointCertificateRequest, reply *certificate.Certificate) error {
+	cert, err := s.f.SetPublicEndpointACME(s.context(), request.Serviceid, request.EndpointName, request.Type,
+		request.Name, request.Domains)
+	if err != nil {
+		return err
+	}
+	*reply = *cert
+	reply.KeyPEM = ""
+	return nil
+}
+
+// Gets the certificates of all public endpoints, without their keys.
+func (s *Server) GetPublicEndpointCertificates(empty struct{}, reply *[]certificate.Certificate) error {
+	certs, err := s.f.GetPublicEndpointCertificates(s.context())
+	if err != nil {
+		return err
+	}
+	for i := range certs {
+		certs[i].KeyPEM = ""
+	}
+	*reply = certs
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/scriptjob"
+)
+
+// RunScriptJob starts running a service script on the master and returns
+// the id of the job
+func (c *Client) RunScriptJob(job scriptjob.Job) (string, error) {
+	response := ""
+	if err := c.call("RunScriptJob", job, &response); err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
+// ResumeScriptJob resumes a failed or interrupted script job
+func (c *Client) ResumeScriptJob(jobID string) error {
+	return c.call("ResumeScriptJob", jobID, nil)
+}
+
+// GetScriptJob returns the progress of a script job
+func (c *Client) GetScriptJob(jobID string) (*scriptjob.Job, error) {
+	response := scriptjob.Job{}
+	if err := c.call("GetScriptJob", jobID, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetScriptJobs returns all script jobs
+func (c *Client) GetScriptJobs() ([]scriptjob.Job, error) {
+	response := []scriptjob.Job{}
+	if err := c.call("GetScriptJobs", empty, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/scriptjob"
+)
+
+// RunScriptJob starts running a service script on the master
+func (s *Server) RunScriptJob(job scriptjob.Job, response *string) error {
+	jobID, err := s.f.RunScriptJob(s.context(), &job)
+	if err != nil {
+		return err
+	}
+	*response = jobID
+	return nil
+}
+
+// ResumeScriptJob resumes a failed or interrupted script job
+func (s *Server) ResumeScriptJob(jobID string, _ *struct{}) error {
+	return s.f.ResumeScriptJob(s.context(), jobID)
+}
+
+// GetScriptJob returns the progress of a script job
+func (s *Server) GetScriptJob(jobID string, response *scriptjob.Job) error {
+	job, err := s.f.GetScriptJob(s.context(), jobID)
+	if err != nil {
+		return err
+	}
+	*response = *job
+	return nil
+}
+
+// GetScriptJobs returns all script jobs
+func (s *Server) GetScriptJobs(unused struct{}, response *[]scriptjob.Job) error {
+	jobs, err := s.f.GetScriptJobs(s.context())
+	if err != nil {
+		return err
+	}
+	*response = jobs
+	return nil
+}
@@ -40,6 +40,7 @@ var (
 		"Master.GetHosts":                        struct{}{},
 		"Master.GetEvaluatedService":             struct{}{},
 		"Master.GetSystemUser":                   struct{}{},
+		"Master.GetServiceHealth":                struct{}{},
 		"Master.ReportHealthStatus":              struct{}{},
 		"Master.ReportInstanceDead":              struct{}{},
 		"Master.UpdateHost":                      struct{}{},
@@ -47,6 +48,7 @@ var (
 		"ControlCenterAgent.GetHostID":           struct{}{},
 		"ControlCenterAgent.GetZkInfo":           struct{}{},
 		"ControlCenterAgent.GetISvcEndpoints":    struct{}{},
+		"ControlCenterAgent.GetServiceHealth":    struct{}{},
 		"ControlCenterAgent.ReportHealthStatus":  struct{}{},
 		"ControlCenterAgent.ReportInstanceDead":  struct{}{},
 		"ControlCenterAgent.SendLogMessage":      struct{}{},
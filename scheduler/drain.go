@@ -0,0 +1,236 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// drainInterval is how often the leader moves instances off of drained hosts
+const drainInterval = 10 * time.Second
+
+// migrationTimeout is how long the leader waits for an instance it moved off
+// of a drained host to be running and healthy elsewhere before it pauses
+// draining the host
+const migrationTimeout = 10 * time.Minute
+
+// migration tracks an instance that was stopped on a drained host so that it
+// is rescheduled on another host.
+type migration struct {
+	serviceID  string
+	instanceID int
+	started    time.Time
+	paused     bool
+}
+
+// drain periodically moves the instances off of the drained hosts in the pool
+// until the leader shuts down
+func (l *leader) drain() {
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.drainHosts()
+		case <-l.shutdown:
+			return
+		}
+	}
+}
+
+// drainHosts moves the next instance off of each drained host in the pool
+func (l *leader) drainHosts() {
+	ctx := datastore.Get()
+	hosts, err := l.facade.FindHostsInPool(ctx, l.poolID)
+	if err != nil {
+		plog.WithField("poolid", l.poolID).WithError(err).Warn("Could not look up hosts to drain")
+		return
+	}
+
+	drained := make(map[string]struct{})
+	for _, h := range hosts {
+		if !h.Drained {
+			continue
+		}
+		drained[h.ID] = struct{}{}
+		if err := l.drainHost(h.ID); err != nil {
+			plog.WithFields(log.Fields{
+				"poolid": l.poolID,
+				"hostid": h.ID,
+			}).WithError(err).Warn("Could not drain host")
+		}
+	}
+
+	// forget about hosts that are no longer drained
+	for hostID := range l.migrations {
+		if _, ok := drained[hostID]; !ok {
+			delete(l.migrations, hostID)
+		}
+	}
+}
+
+// drainHost stops the next instance on a drained host that can be placed on
+// another host, so that the scheduler moves it there, once the instance it
+// moved last is running and healthy.  If that instance does not move within
+// the migration timeout, draining the host is paused until it does or the
+// host is undrained.
+func (l *leader) drainHost(hostID string) error {
+	ctx := datastore.Get()
+	logger := plog.WithFields(log.Fields{
+		"poolid": l.poolID,
+		"hostid": hostID,
+	})
+
+	if m, ok := l.migrations[hostID]; ok {
+		mlogger := logger.WithFields(log.Fields{
+			"serviceid":  m.serviceID,
+			"instanceid": m.instanceID,
+		})
+		insts, err := l.facade.GetServiceInstances(ctx, time.Now(), m.serviceID)
+		if err != nil {
+			return err
+		}
+		if !migrated(insts, m, hostID) {
+			if !m.paused && time.Since(m.started) >= migrationTimeout {
+				m.paused = true
+				mlogger.Warn("Timed out waiting for instance to move off of drained host; pausing the drain until it has moved or the host is undrained")
+			}
+			return nil
+		}
+		if m.paused {
+			mlogger.Info("Moved instance off of drained host; resuming the drain")
+		} else {
+			mlogger.Info("Moved instance off of drained host")
+		}
+		delete(l.migrations, hostID)
+	}
+
+	insts, err := l.facade.GetHostInstances(ctx, time.Now(), hostID)
+	if err != nil {
+		return err
+	}
+	candidates := migrationOrder(insts)
+	if len(candidates) == 0 {
+		return nil
+	}
+	hosts, err := l.authenticatedHosts()
+	if err != nil {
+		return err
+	}
+
+	// only stop an instance that the scheduler can start on another host
+	var next *service.Instance
+	for _, inst := range candidates {
+		ilogger := logger.WithFields(log.Fields{
+			"serviceid":  inst.ServiceID,
+			"instanceid": inst.InstanceID,
+		})
+		if err := l.checkPlacement(inst.ServiceID, hosts); err != nil {
+			ilogger.WithError(err).Debug("Instance cannot be placed on another host")
+			continue
+		}
+		next = inst
+		break
+	}
+	if next == nil {
+		return fmt.Errorf("none of the %d instance(s) left on the host can be placed on another host", len(candidates))
+	}
+	if err := l.facade.StopServiceInstance(ctx, next.ServiceID, next.InstanceID); err != nil {
+		return err
+	}
+	l.migrations[hostID] = &migration{
+		serviceID:  next.ServiceID,
+		instanceID: next.InstanceID,
+		started:    time.Now(),
+	}
+	logger.WithFields(log.Fields{
+		"serviceid":  next.ServiceID,
+		"instanceid": next.InstanceID,
+		"remaining":  len(candidates),
+	}).Info("Moving instance off of drained host")
+	return nil
+}
+
+// checkPlacement returns an error if an instance of the service could not be
+// scheduled on any of the hosts that are not drained.
+func (l *leader) checkPlacement(serviceID string, hosts []host.Host) error {
+	svc, err := l.facade.GetService(datastore.Get(), serviceID)
+	if err != nil {
+		return err
+	}
+	sn, err := zkservice.NewServiceNodeFromService(svc)
+	if err != nil {
+		return err
+	}
+	_, err = l.selectSchedulableHost(sn, hosts)
+	return err
+}
+
+// migrationOrder returns the instances to move off of a drained host, in the
+// order they are moved, leaving out those that are already stopping.
+func migrationOrder(insts []service.Instance) []*service.Instance {
+	order := []*service.Instance{}
+	for i := range insts {
+		if insts[i].DesiredState != service.SVCStop {
+			order = append(order, &insts[i])
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].ServiceID != order[j].ServiceID {
+			return order[i].ServiceID < order[j].ServiceID
+		}
+		return order[i].InstanceID < order[j].InstanceID
+	})
+	return order
+}
+
+// migrated returns true if the instance moved off of the drained host is
+// running and passing its health checks on another host.
+func migrated(insts []service.Instance, m *migration, hostID string) bool {
+	for _, inst := range insts {
+		if inst.InstanceID != m.instanceID {
+			continue
+		}
+		if inst.HostID == hostID || inst.CurrentState != service.StateRunning {
+			return false
+		}
+		for _, status := range inst.HealthStatus {
+			if status != health.OK {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// schedulableHosts returns the hosts that are not drained
+func schedulableHosts(hosts []host.Host) []host.Host {
+	schedulable := []host.Host{}
+	for _, h := range hosts {
+		if !h.Drained {
+			schedulable = append(schedulable, h)
+		}
+	}
+	return schedulable
+}
@@ -0,0 +1,314 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/facade/mocks"
+	"github.com/control-center/serviced/health"
+	"github.com/stretchr/testify/mock"
+)
+
+// drainTestHosts are registered with the leader in the drain tests; host-1 is
+// being drained.
+type drainTestHosts []host.Host
+
+func (h drainTestHosts) GetRegisteredHosts(cancel <-chan interface{}) ([]host.Host, error) {
+	return h, nil
+}
+
+// drainTestLeader returns a leader of the default pool with a drained host-1
+// and a schedulable host-2, and the facade mock it drains them with.
+func drainTestLeader() (*leader, *mocks.FacadeInterface) {
+	f := &mocks.FacadeInterface{}
+	f.On("HostIsAuthenticated", mock.Anything, mock.AnythingOfType("string")).Return(true, nil)
+	f.On("GetHostStrategyInstances", mock.Anything, mock.Anything).Return([]*service.StrategyInstance{}, nil)
+
+	// web has an address assigned on the drained host, so it cannot move
+	f.On("GetService", mock.Anything, "web").Return(&service.Service{
+		ID:   "web",
+		Name: "web",
+		Endpoints: []service.ServiceEndpoint{
+			{
+				Name:          "http",
+				AddressConfig: servicedefinition.AddressResourceConfig{Port: 8080, Protocol: "tcp"},
+				AddressAssignment: addressassignment.AddressAssignment{
+					AssignmentType: commons.STATIC,
+					HostID:         "host-1",
+					IPAddr:         "10.0.0.1",
+				},
+			},
+		},
+	}, nil)
+	f.On("GetService", mock.Anything, "zope").Return(&service.Service{ID: "zope", Name: "zope"}, nil)
+
+	l := &leader{
+		facade: f,
+		poolID: "default",
+		hreg: drainTestHosts{
+			{ID: "host-1", PoolID: "default", Cores: 4, Memory: 4 << 30, Drained: true},
+			{ID: "host-2", PoolID: "default", Cores: 4, Memory: 4 << 30},
+		},
+		migrations: make(map[string]*migration),
+	}
+	return l, f
+}
+
+func TestMigrationOrder(t *testing.T) {
+	insts := []service.Instance{
+		{ServiceID: "web", InstanceID: 1, DesiredState: service.SVCRun},
+		{ServiceID: "db", InstanceID: 0, DesiredState: service.SVCStop},
+		{ServiceID: "web", InstanceID: 0, DesiredState: service.SVCRun},
+		{ServiceID: "zope", InstanceID: 0, DesiredState: service.SVCRun},
+	}
+	order := migrationOrder(insts)
+	expected := []string{"web/0", "web/1", "zope/0"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %d instances to move, got %d", len(expected), len(order))
+	}
+	for i, inst := range order {
+		if key := fmt.Sprintf("%s/%d", inst.ServiceID, inst.InstanceID); key != expected[i] {
+			t.Errorf("Expected %s to move at %d, got %s", expected[i], i, key)
+		}
+	}
+
+	// instances that are already stopping are not moved
+	if order := migrationOrder(insts[1:2]); len(order) != 0 {
+		t.Errorf("Expected no instance to move, got %+v", order)
+	}
+	if order := migrationOrder(nil); len(order) != 0 {
+		t.Errorf("Expected no instance to move, got %+v", order)
+	}
+}
+
+func TestMigrated(t *testing.T) {
+	m := &migration{serviceID: "web", instanceID: 1}
+	inst := service.Instance{
+		ServiceID:    "web",
+		InstanceID:   1,
+		HostID:       "host-2",
+		CurrentState: service.StateRunning,
+		HealthStatus: map[string]health.Status{"answering": health.OK},
+	}
+	other := service.Instance{ServiceID: "web", InstanceID: 0, HostID: "host-1", CurrentState: service.StateRunning}
+
+	if !migrated([]service.Instance{other, inst}, m, "host-1") {
+		t.Error("Expected the instance to have moved")
+	}
+
+	// not yet rescheduled
+	if migrated([]service.Instance{other}, m, "host-1") {
+		t.Error("Expected the instance not to have moved")
+	}
+
+	// still on the drained host
+	onHost := inst
+	onHost.HostID = "host-1"
+	if migrated([]service.Instance{onHost}, m, "host-1") {
+		t.Error("Expected the instance not to have moved")
+	}
+
+	// not yet running
+	starting := inst
+	starting.CurrentState = service.StateStarting
+	if migrated([]service.Instance{starting}, m, "host-1") {
+		t.Error("Expected the instance not to have moved")
+	}
+
+	// failing health checks
+	unhealthy := inst
+	unhealthy.HealthStatus = map[string]health.Status{"answering": health.Failed}
+	if migrated([]service.Instance{unhealthy}, m, "host-1") {
+		t.Error("Expected the instance not to have moved")
+	}
+}
+
+func TestSchedulableHosts(t *testing.T) {
+	hosts := []host.Host{
+		{ID: "host-1", Drained: true},
+		{ID: "host-2"},
+		{ID: "host-3", Drained: true},
+	}
+	schedulable := schedulableHosts(hosts)
+	if len(schedulable) != 1 || schedulable[0].ID != "host-2" {
+		t.Errorf("Expected only host-2 to be schedulable, got %+v", schedulable)
+	}
+	if schedulable := schedulableHosts(hosts[:1]); len(schedulable) != 0 {
+		t.Errorf("Expected no schedulable hosts, got %+v", schedulable)
+	}
+}
+
+func TestCheckPlacement(t *testing.T) {
+	l, _ := drainTestLeader()
+	hosts, err := l.authenticatedHosts()
+	if err != nil {
+		t.Fatalf("Could not look up hosts: %s", err)
+	}
+
+	if err := l.checkPlacement("zope", hosts); err != nil {
+		t.Errorf("Expected zope to be placed on another host, got %s", err)
+	}
+	if err := l.checkPlacement("web", hosts); err == nil {
+		t.Error("Expected web not to be placed on another host")
+	}
+
+	// no host is left once all of them are drained
+	if err := l.checkPlacement("zope", hosts[:1]); err != ErrNoSchedulableHosts {
+		t.Errorf("Expected %s, got %v", ErrNoSchedulableHosts, err)
+	}
+}
+
+func TestDrainHostStopsPlaceableInstance(t *testing.T) {
+	l, f := drainTestLeader()
+	f.On("GetHostInstances", mock.Anything, mock.Anything, "host-1").Return([]service.Instance{
+		{ServiceID: "zope", InstanceID: 0, HostID: "host-1", DesiredState: service.SVCRun},
+		{ServiceID: "web", InstanceID: 0, HostID: "host-1", DesiredState: service.SVCRun},
+	}, nil)
+	f.On("StopServiceInstance", mock.Anything, "zope", 0).Return(nil)
+
+	if err := l.drainHost("host-1"); err != nil {
+		t.Fatalf("Could not drain host: %s", err)
+	}
+	f.AssertCalled(t, "StopServiceInstance", mock.Anything, "zope", 0)
+	f.AssertNotCalled(t, "StopServiceInstance", mock.Anything, "web", 0)
+	m, ok := l.migrations["host-1"]
+	if !ok {
+		t.Fatal("Expected a migration off of host-1")
+	}
+	if m.serviceID != "zope" || m.instanceID != 0 || m.paused {
+		t.Errorf("Expected zope/0 to be moving, got %+v", m)
+	}
+}
+
+func TestDrainHostNoPlaceableInstance(t *testing.T) {
+	l, f := drainTestLeader()
+	f.On("GetHostInstances", mock.Anything, mock.Anything, "host-1").Return([]service.Instance{
+		{ServiceID: "web", InstanceID: 0, HostID: "host-1", DesiredState: service.SVCRun},
+	}, nil)
+
+	if err := l.drainHost("host-1"); err == nil {
+		t.Error("Expected an error draining a host with no instance to move")
+	}
+	f.AssertNotCalled(t, "StopServiceInstance", mock.Anything, mock.Anything, mock.Anything)
+	if _, ok := l.migrations["host-1"]; ok {
+		t.Error("Expected no migration off of host-1")
+	}
+}
+
+func TestDrainHostPausesOnMigrationTimeout(t *testing.T) {
+	l, f := drainTestLeader()
+	f.On("GetServiceInstances", mock.Anything, mock.Anything, "zope").Return([]service.Instance{
+		{ServiceID: "zope", InstanceID: 0, HostID: "host-1", CurrentState: service.StateStopping},
+	}, nil)
+
+	// waits for the instance to move before the timeout
+	m := &migration{serviceID: "zope", instanceID: 0, started: time.Now()}
+	l.migrations["host-1"] = m
+	if err := l.drainHost("host-1"); err != nil {
+		t.Fatalf("Could not drain host: %s", err)
+	}
+	if m.paused {
+		t.Error("Expected the drain not to be paused")
+	}
+
+	// pauses once the timeout has passed
+	m.started = time.Now().Add(-migrationTimeout)
+	if err := l.drainHost("host-1"); err != nil {
+		t.Fatalf("Could not drain host: %s", err)
+	}
+	if !m.paused {
+		t.Error("Expected the drain to be paused")
+	}
+	if l.migrations["host-1"] != m {
+		t.Error("Expected the migration to be kept")
+	}
+	f.AssertNotCalled(t, "GetHostInstances", mock.Anything, mock.Anything, mock.Anything)
+	f.AssertNotCalled(t, "StopServiceInstance", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDrainHostResumesOnceMoved(t *testing.T) {
+	l, f := drainTestLeader()
+	f.On("GetServiceInstances", mock.Anything, mock.Anything, "zope").Return([]service.Instance{
+		{
+			ServiceID:    "zope",
+			InstanceID:   0,
+			HostID:       "host-2",
+			CurrentState: service.StateRunning,
+			HealthStatus: map[string]health.Status{"answering": health.OK},
+		},
+	}, nil)
+	f.On("GetHostInstances", mock.Anything, mock.Anything, "host-1").Return([]service.Instance{
+		{ServiceID: "zope", InstanceID: 1, HostID: "host-1", DesiredState: service.SVCRun},
+	}, nil)
+	f.On("StopServiceInstance", mock.Anything, "zope", 1).Return(nil)
+
+	l.migrations["host-1"] = &migration{
+		serviceID:  "zope",
+		instanceID: 0,
+		started:    time.Now().Add(-2 * migrationTimeout),
+		paused:     true,
+	}
+	if err := l.drainHost("host-1"); err != nil {
+		t.Fatalf("Could not drain host: %s", err)
+	}
+	f.AssertCalled(t, "StopServiceInstance", mock.Anything, "zope", 1)
+	m, ok := l.migrations["host-1"]
+	if !ok {
+		t.Fatal("Expected a migration off of host-1")
+	}
+	if m.serviceID != "zope" || m.instanceID != 1 || m.paused {
+		t.Errorf("Expected zope/1 to be moving, got %+v", m)
+	}
+}
+
+func TestDrainHostsForgetsUndrainedHosts(t *testing.T) {
+	l, f := drainTestLeader()
+	f.On("FindHostsInPool", mock.Anything, "default").Return([]host.Host{
+		{ID: "host-1", PoolID: "default", Drained: true},
+		{ID: "host-2", PoolID: "default"},
+	}, nil)
+	f.On("GetServiceInstances", mock.Anything, mock.Anything, "zope").Return([]service.Instance{
+		{ServiceID: "zope", InstanceID: 0, HostID: "host-1", CurrentState: service.StateStopping},
+	}, nil)
+
+	l.migrations["host-1"] = &migration{serviceID: "zope", instanceID: 0, started: time.Now()}
+	l.migrations["host-2"] = &migration{serviceID: "zope", instanceID: 1, started: time.Now(), paused: true}
+	l.drainHosts()
+	if _, ok := l.migrations["host-1"]; !ok {
+		t.Error("Expected the migration off of drained host-1 to be kept")
+	}
+	if _, ok := l.migrations["host-2"]; ok {
+		t.Error("Expected the migration off of undrained host-2 to be forgotten")
+	}
+
+	// keeps the migrations if the hosts could not be looked up
+	l, f = drainTestLeader()
+	f.On("FindHostsInPool", mock.Anything, "default").Return(nil, errors.New("lookup failed"))
+	l.migrations["host-2"] = &migration{serviceID: "zope", instanceID: 1, started: time.Now()}
+	l.drainHosts()
+	if _, ok := l.migrations["host-2"]; !ok {
+		t.Error("Expected the migration off of host-2 to be kept")
+	}
+}
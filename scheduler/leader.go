@@ -15,6 +15,8 @@ package scheduler
 
 import (
 	"errors"
+	"sync"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/commons"
@@ -22,20 +24,35 @@ import (
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/scheduler/strategy"
 	"github.com/control-center/serviced/zzk"
 	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
+// autoscaleInterval is how often the leader evaluates autoscale rules
+const autoscaleInterval = time.Minute
+
+// hostRegistry looks up the hosts that are registered in the pool
+type hostRegistry interface {
+	GetRegisteredHosts(cancel <-chan interface{}) ([]host.Host, error)
+}
+
 type leader struct {
 	shutdown <-chan interface{}
 	conn     coordclient.Connection
 	cpClient dao.ControlPlane
-	facade   *facade.Facade
+	facade   facade.FacadeInterface
 	poolID   string
 
-	hreg *zkservice.HostRegistryListener
+	hreg hostRegistry
+
+	preemptMu   sync.Mutex
+	preemptions map[string]*preemption
+
+	// migrations are only accessed by the drain loop
+	migrations map[string]*migration
 }
 
 // Lead is executed by the "leader" of the control center cluster to handle its management responsibilities of:
@@ -48,15 +65,47 @@ func Lead(shutdown <-chan interface{}, conn coordclient.Connection, cpClient dao
 	unassignmentHandler := zkservice.NewZKHostUnassignmentHandler(conn)
 	hreg := zkservice.NewHostRegistryListener(poolID, unassignmentHandler)
 
-	leader := leader{shutdown, conn, cpClient, facade, poolID, hreg}
+	leader := leader{
+		shutdown:    shutdown,
+		conn:        conn,
+		cpClient:    cpClient,
+		facade:      facade,
+		poolID:      poolID,
+		hreg:        hreg,
+		preemptions: make(map[string]*preemption),
+		migrations:  make(map[string]*migration),
+	}
 
 	// creates a listener for services
 	serviceListener := zkservice.NewServiceListener(poolID, &leader)
 
+	// evaluates the autoscale rules of the services in the pool
+	go leader.autoscale()
+
+	// moves the instances off of drained hosts in the pool
+	go leader.drain()
+
 	// starts all of the listeners
 	zzk.Start(shutdown, conn, serviceListener, hreg)
 }
 
+// autoscale periodically scales the services in the pool by their autoscale
+// rules until the leader shuts down
+func (l *leader) autoscale() {
+	ticker := time.NewTicker(autoscaleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := l.facade.AutoscaleServices(datastore.Get(), l.poolID); err != nil {
+				plog.WithField("poolid", l.poolID).WithError(err).Warn("Could not autoscale services")
+			}
+		case <-l.shutdown:
+			return
+		}
+	}
+}
+
 // SelectHost chooses a host from the pool for the specified service. If the
 // service has an address assignment the host will already be selected. If not
 // the host with the least amount of memory committed to running containers will
@@ -67,23 +116,40 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 		"servicename": sn.Name,
 	})
 
+	hosts, err := l.authenticatedHosts()
+	if err != nil {
+		return "", err
+	}
+
+	// leave the instance unscheduled while it would exceed the pool's quota
+	if err := l.checkQuota(sn, hosts); err != nil {
+		logger.WithError(err).Warn("Service instance is waiting for pool resources")
+		return "", err
+	}
+
+	return l.selectSchedulableHost(sn, hosts)
+}
+
+// authenticatedHosts returns the hosts in the pool that are registered and
+// authenticated.
+func (l *leader) authenticatedHosts() ([]host.Host, error) {
 	plog.Debug("Looking for available hosts in resource pool")
 	reghosts, err := l.hreg.GetRegisteredHosts(l.shutdown)
 	if err != nil {
 		plog.WithError(err).Debug("Could not get available hosts from resource pool")
-		return "", err
+		return nil, err
 	}
 
 	// if no hosts are returned, then a shutdown has been triggered
 	if len(reghosts) == 0 {
 		plog.Debug("Scheduler is shutting down")
-		return "", errors.New("scheduler is shutting down")
+		return nil, errors.New("scheduler is shutting down")
 	}
 
 	// filter out hosts that have not been authenticated
 	hosts := []host.Host{}
 	for _, h := range reghosts {
-		hlogger := logger.WithField("hostid", h.ID)
+		hlogger := plog.WithField("hostid", h.ID)
 		isAuthenticated, err := l.facade.HostIsAuthenticated(datastore.Get(), h.ID)
 		if err != nil {
 			hlogger.WithError(err).Debug("Unable to check if host is authenticated")
@@ -96,7 +162,23 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 
 	//  Are there any hosts left?
 	if len(hosts) == 0 {
-		return "", ErrNoAuthenticatedHosts
+		return nil, ErrNoAuthenticatedHosts
+	}
+	return hosts, nil
+}
+
+// selectSchedulableHost chooses the host for an instance of the service from
+// the hosts that are not drained, by its address assignment or host policy.
+func (l *leader) selectSchedulableHost(sn *zkservice.ServiceNode, hosts []host.Host) (string, error) {
+	logger := plog.WithFields(log.Fields{
+		"serviceid":   sn.ID,
+		"servicename": sn.Name,
+	})
+
+	// filter out hosts that are being drained
+	hosts = schedulableHosts(hosts)
+	if len(hosts) == 0 {
+		return "", ErrNoSchedulableHosts
 	}
 
 	assignment := sn.AddressAssignment
@@ -115,6 +197,7 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 		// find which host the address belongs to
 		hostID := assignment.HostID
 		if assignment.AssignmentType == commons.VIRTUAL {
+			var err error
 			hostID, err = zkservice.GetHostID(l.conn, l.poolID, assignment.IPAddr)
 			if err != nil {
 				logger.WithError(err).Debug("Could not get host assignment of virtual ip")
@@ -155,3 +238,45 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 	return StrategySelectHost(sn, hosts, strat, l.facade)
 }
 
+// checkQuota returns a pool.QuotaError if another instance of the service
+// would commit more cores or memory than the pool allows, counting the
+// instances already running on the hosts.  If the pool's preemption policy
+// allows it, lower priority instances are preempted to make room.
+func (l *leader) checkQuota(sn *zkservice.ServiceNode, hosts []host.Host) error {
+	if sn.CPUCommitment == 0 && sn.RAMCommitment.Value == 0 {
+		return nil
+	}
+	p, err := l.facade.GetResourcePool(datastore.Get(), l.poolID)
+	if err != nil {
+		return err
+	} else if p == nil {
+		return nil
+	}
+	usage := quotaUsage(p)
+	if usage.CoreLimit == 0 && usage.MemoryLimit == 0 {
+		return nil
+	}
+	insts, err := l.facade.GetHostStrategyInstances(datastore.Get(), hosts)
+	if err != nil {
+		return err
+	}
+	for _, inst := range insts {
+		usage.Commit(inst.CPUCommitment, inst.RAMCommitment, 1)
+	}
+	if p.PreemptionPolicy == pool.PreemptLowerPriority {
+		return l.preempt(sn, usage, insts)
+	}
+	return usage.Check(sn.CPUCommitment, sn.RAMCommitment.Value)
+}
+
+// quotaUsage returns the usage that instances of the pool are checked
+// against.  A pool that preempts lower priority instances but has no quota
+// is limited to the memory capacity of its hosts, so that preemption makes
+// room once the hosts are full.
+func quotaUsage(p *pool.ResourcePool) *pool.Usage {
+	usage := pool.NewUsage(p)
+	if p.PreemptionPolicy == pool.PreemptLowerPriority && p.CoreLimit == 0 && p.MemoryLimit == 0 {
+		usage.MemoryLimit = p.MemoryCapacity
+	}
+	return usage
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// preemptionTimeout is how long a preempting service holds back lower
+// priority services while it waits for resources
+const preemptionTimeout = 5 * time.Minute
+
+// ErrPreemptionPending is returned for instances of lower priority services
+// while a higher priority service waits for preempted instances to stop.
+var ErrPreemptionPending = errors.New("waiting for a higher priority service to be scheduled")
+
+// preemption tracks the instances stopped to make room for an instance of a
+// higher priority service.
+type preemption struct {
+	priority int
+	victims  map[string]struct{}
+	expires  time.Time
+}
+
+func instanceKey(serviceID string, instanceID int) string {
+	return fmt.Sprintf("%s/%d", serviceID, instanceID)
+}
+
+// preempt checks whether another instance of the service fits within the
+// quotas of the pool, stopping preemptible instances of lower priority
+// services to make room when it does not.  Returns an error while the
+// instance has to wait for resources.
+func (l *leader) preempt(sn *zkservice.ServiceNode, usage *pool.Usage, insts []*service.StrategyInstance) error {
+	logger := plog.WithFields(log.Fields{
+		"serviceid":   sn.ID,
+		"servicename": sn.Name,
+		"priority":    sn.Priority,
+	})
+
+	l.preemptMu.Lock()
+	defer l.preemptMu.Unlock()
+	l.prunePreemptions(insts)
+
+	// hold back lower priority services while a preemption is pending, so
+	// that they do not take the resources that were freed up
+	for serviceID, p := range l.preemptions {
+		if serviceID != sn.ID && p.priority > sn.Priority {
+			return ErrPreemptionPending
+		}
+	}
+
+	err := usage.Check(sn.CPUCommitment, sn.RAMCommitment.Value)
+	if err == nil {
+		delete(l.preemptions, sn.ID)
+		return nil
+	}
+
+	// wait for the instances that were already preempted to stop
+	if p, ok := l.preemptions[sn.ID]; ok && len(p.victims) > 0 {
+		return err
+	}
+
+	victims := selectVictims(sn, *usage, insts)
+	if len(victims) == 0 {
+		return err
+	}
+	p := &preemption{
+		priority: sn.Priority,
+		victims:  make(map[string]struct{}),
+		expires:  time.Now().Add(preemptionTimeout),
+	}
+	for _, v := range victims {
+		vlogger := logger.WithFields(log.Fields{
+			"victimid":   v.ServiceID,
+			"instanceid": v.InstanceID,
+		})
+		if verr := l.facade.PreemptServiceInstance(datastore.Get(), v.ServiceID, v.InstanceID, sn.ID); verr != nil {
+			vlogger.WithError(verr).Warn("Could not preempt service instance")
+			continue
+		}
+		vlogger.Debug("Preempted service instance")
+		p.victims[instanceKey(v.ServiceID, v.InstanceID)] = struct{}{}
+	}
+	l.preemptions[sn.ID] = p
+	return err
+}
+
+// prunePreemptions forgets preempted instances that have stopped and
+// preemptions that have expired.
+func (l *leader) prunePreemptions(insts []*service.StrategyInstance) {
+	running := make(map[string]struct{})
+	for _, inst := range insts {
+		running[instanceKey(inst.ServiceID, inst.InstanceID)] = struct{}{}
+	}
+	now := time.Now()
+	for serviceID, p := range l.preemptions {
+		if now.After(p.expires) {
+			delete(l.preemptions, serviceID)
+			continue
+		}
+		for key := range p.victims {
+			if _, ok := running[key]; !ok {
+				delete(p.victims, key)
+			}
+		}
+	}
+}
+
+// selectVictims picks the running instances to stop so that another instance
+// of the service fits within the quotas of the pool.  Only preemptible
+// instances of lower priority services are picked, lowest priority first and
+// the highest instance ids of a service first.  Returns nil if the instance
+// would not fit even after stopping every candidate.
+func selectVictims(sn *zkservice.ServiceNode, usage pool.Usage, insts []*service.StrategyInstance) []*service.StrategyInstance {
+	candidates := []*service.StrategyInstance{}
+	for _, inst := range insts {
+		if inst.ServiceID == sn.ID || inst.NonPreemptible || inst.Priority >= sn.Priority {
+			continue
+		}
+		if inst.CPUCommitment == 0 && inst.RAMCommitment == 0 {
+			continue
+		}
+		candidates = append(candidates, inst)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.ServiceID != b.ServiceID {
+			return a.ServiceID < b.ServiceID
+		}
+		return a.InstanceID > b.InstanceID
+	})
+
+	victims := []*service.StrategyInstance{}
+	for _, inst := range candidates {
+		if usage.Check(sn.CPUCommitment, sn.RAMCommitment.Value) == nil {
+			break
+		}
+		usage.Release(inst.CPUCommitment, inst.RAMCommitment, 1)
+		victims = append(victims, inst)
+	}
+	if usage.Check(sn.CPUCommitment, sn.RAMCommitment.Value) != nil {
+		return nil
+	}
+	return victims
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+func preemptTestInstances() []*service.StrategyInstance {
+	return []*service.StrategyInstance{
+		{ServiceID: "critical", InstanceID: 0, RAMCommitment: 512, Priority: 0, NonPreemptible: true},
+		{ServiceID: "batch", InstanceID: 0, RAMCommitment: 256, Priority: 1},
+		{ServiceID: "batch", InstanceID: 1, RAMCommitment: 256, Priority: 1},
+		{ServiceID: "reports", InstanceID: 0, RAMCommitment: 256, Priority: 5},
+		{ServiceID: "web", InstanceID: 0, RAMCommitment: 256, Priority: 10},
+	}
+}
+
+func preemptTestUsage(insts []*service.StrategyInstance) pool.Usage {
+	usage := pool.NewUsage(&pool.ResourcePool{ID: "default", MemoryLimit: 1536})
+	for _, inst := range insts {
+		usage.Commit(inst.CPUCommitment, inst.RAMCommitment, 1)
+	}
+	return *usage
+}
+
+func TestSelectVictims_LowestPriorityFirst(t *testing.T) {
+	insts := preemptTestInstances()
+	sn := &zkservice.ServiceNode{ID: "web", Priority: 10, RAMCommitment: utils.EngNotation{Value: 256}}
+
+	victims := selectVictims(sn, preemptTestUsage(insts), insts)
+	if len(victims) != 1 {
+		t.Fatalf("expected 1 victim, got %d", len(victims))
+	}
+	if victims[0].ServiceID != "batch" || victims[0].InstanceID != 1 {
+		t.Errorf("expected batch/1 to be preempted, got %s/%d", victims[0].ServiceID, victims[0].InstanceID)
+	}
+}
+
+func TestSelectVictims_MultipleInstances(t *testing.T) {
+	insts := preemptTestInstances()
+	sn := &zkservice.ServiceNode{ID: "web", Priority: 10, RAMCommitment: utils.EngNotation{Value: 768}}
+
+	victims := selectVictims(sn, preemptTestUsage(insts), insts)
+	if len(victims) != 3 {
+		t.Fatalf("expected 3 victims, got %d", len(victims))
+	}
+	if victims[2].ServiceID != "reports" {
+		t.Errorf("expected reports to be preempted last, got %s", victims[2].ServiceID)
+	}
+}
+
+func TestSelectVictims_NonPreemptible(t *testing.T) {
+	insts := preemptTestInstances()
+	sn := &zkservice.ServiceNode{ID: "web", Priority: 10, RAMCommitment: utils.EngNotation{Value: 1024}}
+
+	// only critical could make room, and it cannot be preempted
+	if victims := selectVictims(sn, preemptTestUsage(insts), insts); victims != nil {
+		t.Errorf("expected no victims, got %d", len(victims))
+	}
+}
+
+func TestSelectVictims_LowerPriority(t *testing.T) {
+	insts := preemptTestInstances()
+	sn := &zkservice.ServiceNode{ID: "batch", Priority: 1, RAMCommitment: utils.EngNotation{Value: 256}}
+
+	if victims := selectVictims(sn, preemptTestUsage(insts), insts); victims != nil {
+		t.Errorf("expected no victims, got %d", len(victims))
+	}
+}
+
+func TestPrunePreemptions(t *testing.T) {
+	l := &leader{preemptions: map[string]*preemption{
+		"web": {
+			priority: 10,
+			victims: map[string]struct{}{
+				instanceKey("batch", 1):   {},
+				instanceKey("reports", 3): {},
+			},
+			expires: time.Now().Add(time.Minute),
+		},
+		"expired": {priority: 10, expires: time.Now().Add(-time.Minute)},
+	}}
+
+	l.prunePreemptions(preemptTestInstances())
+	if _, ok := l.preemptions["expired"]; ok {
+		t.Errorf("expected expired preemption to be pruned")
+	}
+	victims := l.preemptions["web"].victims
+	if _, ok := victims[instanceKey("batch", 1)]; !ok || len(victims) != 1 {
+		t.Errorf("expected only the running victim to remain, got %v", victims)
+	}
+}
+
+func TestQuotaUsage(t *testing.T) {
+	p := &pool.ResourcePool{ID: "default", MemoryCapacity: 2048, CoreCapacity: 4}
+	if usage := quotaUsage(p); usage.CoreLimit != 0 || usage.MemoryLimit != 0 {
+		t.Errorf("expected no limits, got %d cores and %d memory", usage.CoreLimit, usage.MemoryLimit)
+	}
+
+	// preemption without a quota is limited to the memory of the hosts
+	p.PreemptionPolicy = pool.PreemptLowerPriority
+	if usage := quotaUsage(p); usage.CoreLimit != 0 || usage.MemoryLimit != 2048 {
+		t.Errorf("expected a memory limit of 2048, got %d cores and %d memory", usage.CoreLimit, usage.MemoryLimit)
+	}
+
+	// the quota of the pool takes precedence
+	p.CoreLimit = 2
+	if usage := quotaUsage(p); usage.CoreLimit != 2 || usage.MemoryLimit != 0 {
+		t.Errorf("expected a core limit of 2, got %d cores and %d memory", usage.CoreLimit, usage.MemoryLimit)
+	}
+}
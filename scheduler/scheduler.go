@@ -36,6 +36,7 @@ var plog = logging.PackageLogger()
 
 var (
 	ErrNoAuthenticatedHosts = errors.New("no authenticated hosts found")
+	ErrNoSchedulableHosts   = errors.New("all authenticated hosts are drained")
 )
 
 type leaderFunc func(<-chan interface{}, coordclient.Connection, dao.ControlPlane, *facade.Facade, string)
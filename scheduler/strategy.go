@@ -44,7 +44,7 @@ type StrategyService struct {
 	svc *zkservice.ServiceNode
 }
 
-func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat strategy.Strategy, facade *facade.Facade) (string, error) {
+func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat strategy.Strategy, facade facade.FacadeInterface) (string, error) {
 
 	glog.V(2).Infof("Applying %s strategy for service %s", strat.Name(), sn.ID)
 
@@ -11,6 +11,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/control-center/serviced/commons"
 )
 
 func evalEmpty(r *runner, n node) error {
@@ -40,12 +42,36 @@ func evalSnapshot(r *runner, n node) error {
 	if err != nil {
 		return err
 	}
+	r.trackSnapshot(mySnapshotID)
+	return nil
+}
+
+// trackSnapshot keeps track of a snapshot so that it can be restored if the
+// script fails.
+func (r *runner) trackSnapshot(mySnapshotID string) {
 	r.snapshotID = mySnapshotID //keep track of the latest snapshot to rollback to
+	if r.firstSnapshotID == "" {
+		r.firstSnapshotID = mySnapshotID
+	}
 	logger := plog.WithField("snapshotid", mySnapshotID)
 	logger.Debug("performing snapshot")
 
 	exitFunc := func(failed bool) {
-		if failed && r.snapshotID == mySnapshotID {
+		// by default the latest snapshot is restored; ON_FAILURE ROLLBACK
+		// restores the state from before the script ran and ON_FAILURE
+		// ABORT leaves the system as it is.
+		restoreID := r.snapshotID
+		switch r.onFailure {
+		case ROLLBACK:
+			restoreID = r.firstSnapshotID
+		case ABORT:
+			restoreID = ""
+		}
+		if failed && restoreID == mySnapshotID {
+			if r.restore == nil {
+				logger.Error("Unable to restore snapshot: no restore function provided")
+				return
+			}
 			if err := r.restore(mySnapshotID, true); err != nil {
 				logger.WithError(err).Error("Unable to restore snapshot")
 
@@ -54,7 +80,6 @@ func evalSnapshot(r *runner, n node) error {
 		}
 	}
 	r.addExitFunction(exitFunc)
-	return nil
 }
 
 // override images with new one for services under a tenant or only for a particular service if it is specified
@@ -102,10 +127,11 @@ func evalUSE(r *runner, n node) error {
 
 	}
 
-	_, err := r.svcUse(tenantID, serviceID, imageName, r.config.DockerRegistry, replaceImgs, r.config.NoOp)
+	imageID, err := r.svcUse(tenantID, serviceID, imageName, r.config.DockerRegistry, replaceImgs, r.config.NoOp)
 	if err != nil {
 		return err
 	}
+	r.imageID = imageID
 	logger.Info("Successfully pulled and tagged new image")
 	return nil
 }
@@ -405,3 +431,143 @@ func evalRequireSvc(r *runner, n node) error {
 	r.env["TENANT_ID"] = tID
 	return nil
 }
+
+func evalSet(r *runner, n node) error {
+	name := n.args[0]
+	value := strings.Join(n.args[1:], " ")
+	plog.WithFields(log.Fields{
+		"variable": name,
+		"value":    value,
+	}).Debug("Setting variable")
+	r.env[name] = value
+	return nil
+}
+
+func evalIf(r *runner, n node) error {
+	result, err := evalCondition(r, n.args)
+	if err != nil {
+		return err
+	}
+	plog.WithFields(log.Fields{
+		"condition": strings.Join(n.args, " "),
+		"result":    result,
+	}).Info("Evaluated condition")
+	if result {
+		return r.evalBlock(n.body)
+	}
+	return r.evalBlock(n.elseBody)
+}
+
+// evalCondition returns the result of a condition tested by IF
+func evalCondition(r *runner, args []string) (bool, error) {
+	if args[0] == NOT {
+		result, err := evalCondition(r, args[1:])
+		return !result, err
+	}
+
+	if args[0] == COND_VAR {
+		return r.env[args[1]] == args[2], nil
+	}
+
+	if r.svcFromPath == nil {
+		return false, fmt.Errorf("no service id lookup function for %s", IF)
+	}
+	tenantID, found := r.env["TENANT_ID"]
+	if !found {
+		return false, fmt.Errorf("no service tenant id specified for %s", IF)
+	}
+	svcPath := args[1]
+	svcID, err := r.svcFromPath(tenantID, svcPath)
+	if args[0] == COND_EXISTS {
+		return err == nil && svcID != "", nil
+	} else if err != nil {
+		return false, err
+	} else if svcID == "" {
+		return false, fmt.Errorf("no service id found for %s", svcPath)
+	}
+
+	switch args[0] {
+	case COND_STATE:
+		if r.svcState == nil {
+			return false, fmt.Errorf("no service state lookup function for %s", IF)
+		}
+		state, err := r.svcState(svcID)
+		if err != nil {
+			return false, err
+		}
+		return state == ServiceState(args[2]), nil
+	case COND_IMAGE:
+		if r.svcImage == nil {
+			return false, fmt.Errorf("no service image lookup function for %s", IF)
+		}
+		imageID, err := r.svcImage(svcID)
+		if err != nil {
+			return false, err
+		}
+		return imageMatches(imageID, args[2])
+	}
+	return false, fmt.Errorf("unknown condition %s", args[0])
+}
+
+// imageMatches returns true if the image has the repo (and tag, if specified)
+// of the given image string, regardless of the registry it is served from.
+func imageMatches(imageID, match string) (bool, error) {
+	image, err := commons.ParseImageID(imageID)
+	if err != nil {
+		return false, err
+	}
+	expected, err := commons.ParseImageID(match)
+	if err != nil {
+		return false, err
+	}
+	if image.User != expected.User || image.Repo != expected.Repo {
+		return false, nil
+	}
+	return expected.Tag == "" || image.Tag == expected.Tag, nil
+}
+
+func evalFor(r *runner, n node) error {
+	if r.svcFromPath == nil {
+		return fmt.Errorf("no service id lookup function for %s", FOR)
+	}
+	if r.svcChildren == nil {
+		return fmt.Errorf("no service children lookup function for %s", FOR)
+	}
+
+	variable, svcPath := n.args[1], n.args[3]
+	tenantID, found := r.env["TENANT_ID"]
+	if !found {
+		return fmt.Errorf("no service tenant id specified for %s", FOR)
+	}
+	svcID, err := r.svcFromPath(tenantID, svcPath)
+	if err != nil {
+		return err
+	}
+	if svcID == "" {
+		return fmt.Errorf("no service id found for %s", svcPath)
+	}
+	children, err := r.svcChildren(tenantID, svcPath)
+	if err != nil {
+		return err
+	}
+
+	previous, wasSet := r.env[variable]
+	defer func() {
+		if wasSet {
+			r.env[variable] = previous
+		} else {
+			delete(r.env, variable)
+		}
+	}()
+	for _, child := range children {
+		plog.WithFields(log.Fields{
+			"variable": variable,
+			"value":    child,
+		}).Info("Evaluating loop over child service")
+		r.env[variable] = child
+		if err := r.evalBlock(n.body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -28,6 +28,24 @@ var (
 	SVC_RESTART = "SVC_RESTART"
 	SVC_WAIT    = "SVC_WAIT"
 	DEPENDENCY  = "DEPENDENCY"
+	SET         = "SET"
+	IF          = "IF"
+	ELSE        = "ELSE"
+	END_IF      = "END_IF"
+	FOR         = "FOR"
+	END_FOR     = "END_FOR"
+	ON_FAILURE  = "ON_FAILURE"
+
+	// conditions that can be tested by IF
+	NOT         = "NOT"
+	COND_STATE  = "SVC_STATE"
+	COND_IMAGE  = "SVC_IMAGE"
+	COND_VAR    = "VAR"
+	COND_EXISTS = "SVC_EXISTS"
+
+	// policies for ON_FAILURE
+	ROLLBACK = "ROLLBACK"
+	ABORT    = "ABORT"
 
 	EMPTY     = "EMPTY"
 	emptyNode = node{cmd: EMPTY}
@@ -58,18 +76,38 @@ func init() {
 		SVC_STOP:    require([]string{REQUIRE_SVC}, parseArgMatch(1, "^recurse$|^auto$", true, parseArgCount(bounds(1, 2), buildNode))),
 		SVC_WAIT:    require([]string{REQUIRE_SVC}, parseWaitCmd(parseArgsUntil("^started$|^stopped$|^paused$", parseArgMatch(0, "^started$|^stopped$|^paused$", false, parseArgCount(max(3), buildNode))))),
 		DEPENDENCY:  validParents([]string{DESCRIPTION, VERSION}, atMost(1, parseArgCount(equals(1), buildNode))),
+		// SET <variable> <value>
+		SET: parseArgMatch(0, variablePattern, false, parseArgCount(min(2), buildNode)),
+		// IF [NOT] SVC_STATE <service path> (started|stopped|paused)
+		// IF [NOT] SVC_IMAGE <service path> <repo[:tag]>
+		// IF [NOT] SVC_EXISTS <service path>
+		// IF [NOT] VAR <variable> <value>
+		IF:     parseIfCmd(parseArgCount(min(2), buildNode)),
+		ELSE:   parseArgCount(equals(0), buildNode),
+		END_IF: parseArgCount(equals(0), buildNode),
+		// FOR EACH <variable> IN <service path>
+		FOR:     require([]string{REQUIRE_SVC}, parseForCmd(parseArgCount(equals(4), buildNode))),
+		END_FOR: parseArgCount(equals(0), buildNode),
+		// ON_FAILURE (ROLLBACK|ABORT)
+		ON_FAILURE: atMost(1, parseArgMatch(0, "^ROLLBACK$|^ABORT$", false, parseArgCount(equals(1), buildNode))),
 	}
 }
 
 // node is the struct created from parsing a line; cmd is the command on the line, args are the remainder of the line, line is
-// the original line and lineNum is the line number where the line occurred.
+// the original line and lineNum is the line number where the line occurred. Block commands (IF, FOR) keep the nodes they
+// enclose in body, and IF keeps the nodes following its ELSE in elseBody.
 type node struct {
-	cmd     string
-	args    []string
-	line    string
-	lineNum int
+	cmd      string
+	args     []string
+	line     string
+	lineNum  int
+	body     []node
+	elseBody []node
 }
 
+// variablePattern is the pattern that a variable name must match
+const variablePattern = "^[A-Za-z_][A-Za-z0-9_]*$"
+
 type lineParser func(*parseContext, string, []string) (node, error)
 
 type match func(int) error
@@ -211,6 +249,10 @@ func parseUseCmd(parser lineParser) lineParser {
 	return func(ctx *parseContext, cmd string, args []string) (node, error) {
 		n, err := parser(ctx, cmd, args)
 		if err == nil {
+			if hasVariable(args[0]) {
+				// images that reference variables are validated when the script is run
+				return n, nil
+			}
 			_, err := commons.ParseImageID(args[0])
 			if err != nil {
 				return node{}, err
@@ -226,6 +268,9 @@ func parseUseCmd(parser lineParser) lineParser {
 						}
 						break
 					}
+					if hasVariable(arg) {
+						continue
+					}
 					image, err := commons.ParseImageID(arg)
 					if err != nil {
 						return node{}, err
@@ -271,6 +316,65 @@ func parseWaitCmd(parser lineParser) lineParser {
 	}
 }
 
+// parseIfCmd makes sure that the condition tested by IF is well formed
+// IF [NOT] (SVC_STATE <svc> <state>|SVC_IMAGE <svc> <image>|SVC_EXISTS <svc>|VAR <variable> <value>)
+func parseIfCmd(parser lineParser) lineParser {
+	return func(ctx *parseContext, cmd string, args []string) (node, error) {
+		n, err := parser(ctx, cmd, args)
+		if err == nil {
+			cond := args
+			if cond[0] == NOT {
+				cond = cond[1:]
+			}
+			if len(cond) == 0 {
+				return node{}, fmt.Errorf("line %d: no condition specified: %s", ctx.lineNum, ctx.line)
+			}
+			var argCount match
+			switch cond[0] {
+			case COND_STATE:
+				argCount = equals(2)
+				if len(cond) == 3 {
+					if _, err := ScriptStateToDesiredState(ServiceState(cond[2])); err != nil && !hasVariable(cond[2]) {
+						return node{}, fmt.Errorf("line %d: %v", ctx.lineNum, err)
+					}
+				}
+			case COND_IMAGE, COND_VAR:
+				argCount = equals(2)
+			case COND_EXISTS:
+				argCount = equals(1)
+			default:
+				return node{}, fmt.Errorf("line %d: unknown condition %s: %s", ctx.lineNum, cond[0], ctx.line)
+			}
+			if err := argCount(len(cond) - 1); err != nil {
+				return node{}, fmt.Errorf("line %d: %s %v: %s", ctx.lineNum, cond[0], err, ctx.line)
+			}
+			if cond[0] == COND_VAR {
+				if matched, _ := regexp.MatchString(variablePattern, cond[1]); !matched {
+					return node{}, fmt.Errorf("line %d: invalid variable name %s", ctx.lineNum, cond[1])
+				}
+			}
+		}
+		return n, err
+	}
+}
+
+// parseForCmd makes sure that the FOR loop is well formed
+// FOR EACH <variable> IN <svc>
+func parseForCmd(parser lineParser) lineParser {
+	return func(ctx *parseContext, cmd string, args []string) (node, error) {
+		n, err := parser(ctx, cmd, args)
+		if err == nil {
+			if args[0] != "EACH" || args[2] != "IN" {
+				return node{}, fmt.Errorf("line %d: expected FOR EACH <variable> IN <service>: %s", ctx.lineNum, ctx.line)
+			}
+			if matched, _ := regexp.MatchString(variablePattern, args[1]); !matched {
+				return node{}, fmt.Errorf("line %d: invalid variable name %s", ctx.lineNum, args[1])
+			}
+		}
+		return n, err
+	}
+}
+
 //validParents checks that there are no previous command or previous commands are only in parents list
 func validParents(parents []string, parser lineParser) lineParser {
 	f := func(ctx *parseContext, cmd string, args []string) (node, error) {
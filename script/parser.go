@@ -20,6 +20,9 @@ type parseContext struct {
 	line    string
 	errors  []error
 	nodes   []node
+	program []node // nodes nested by block, built once all lines are parsed
+
+	variables map[string]struct{} // variables declared by SET and FOR, which the runner substitutes
 }
 
 func newParseContext() *parseContext {
@@ -49,9 +52,135 @@ func parseDescriptor(r io.Reader) (*parseContext, error) {
 	if err := ForEachLine(r, parse); err != nil {
 		return nil, err
 	}
+	buildProgram(ctx)
 	return ctx, nil
 }
 
+// ParseErrors are all of the errors found while parsing a script
+type ParseErrors []error
+
+func (errs ParseErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ParseFile parses a script file and reports every error found, including
+// the line number where it occurred.
+func ParseFile(fileName string) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse parses a script and reports every error found, including the line
+// number where it occurred.
+func Parse(r io.Reader) error {
+	ctx, err := parseDescriptor(r)
+	if err != nil {
+		return err
+	}
+	if len(ctx.errors) > 0 {
+		return ParseErrors(ctx.errors)
+	}
+	return nil
+}
+
+// buildProgram nests the flat list of parsed nodes into the blocks opened by
+// IF and FOR, reporting unbalanced blocks as parse errors.
+func buildProgram(ctx *parseContext) {
+	ctx.program = []node{}
+	i := 0
+	for i < len(ctx.nodes) {
+		var block []node
+		var end *node
+		block, end, i = buildBlock(ctx, i)
+		ctx.program = append(ctx.program, block...)
+		if end != nil {
+			ctx.addErrorf("line %d: %s without matching %s", end.lineNum, end.cmd, blockOpener(end.cmd))
+		}
+	}
+
+	// only declared variables are substituted, so that references meant for
+	// the shell of a container are passed through untouched
+	ctx.variables = make(map[string]struct{})
+	for _, n := range ctx.nodes {
+		switch n.cmd {
+		case SET:
+			ctx.variables[n.args[0]] = struct{}{}
+		case FOR:
+			ctx.variables[n.args[1]] = struct{}{}
+		}
+	}
+
+	// a rollback needs a snapshot to roll back to
+	hasSnapshot := false
+	for _, n := range ctx.nodes {
+		if n.cmd == SNAPSHOT {
+			hasSnapshot = true
+		}
+	}
+	for _, n := range ctx.nodes {
+		if n.cmd == ON_FAILURE && n.args[0] == ROLLBACK && !hasSnapshot {
+			ctx.addErrorf("line %d: %s %s depends on %s", n.lineNum, ON_FAILURE, ROLLBACK, SNAPSHOT)
+		}
+	}
+}
+
+// buildBlock collects nodes starting at index i until a block terminator
+// (ELSE, END_IF, END_FOR) or the end of the script is reached. It returns the
+// block, the terminator (if any) and the index of the next node to process.
+func buildBlock(ctx *parseContext, i int) ([]node, *node, int) {
+	block := []node{}
+	for i < len(ctx.nodes) {
+		n := ctx.nodes[i]
+		i++
+		switch n.cmd {
+		case ELSE, END_IF, END_FOR:
+			return block, &n, i
+		case IF:
+			var end *node
+			n.body, end, i = buildBlock(ctx, i)
+			if end != nil && end.cmd == ELSE {
+				n.elseBody, end, i = buildBlock(ctx, i)
+			}
+			closeBlock(ctx, n, end, END_IF)
+		case FOR:
+			var end *node
+			n.body, end, i = buildBlock(ctx, i)
+			closeBlock(ctx, n, end, END_FOR)
+		}
+		if n.cmd == ON_FAILURE {
+			// the failure policy applies to the whole script and is not a step
+			continue
+		}
+		block = append(block, n)
+	}
+	return block, nil, i
+}
+
+// closeBlock verifies that a block was terminated by the expected command
+func closeBlock(ctx *parseContext, opener node, end *node, expected string) {
+	if end == nil {
+		ctx.addErrorf("line %d: %s is not closed by %s", opener.lineNum, opener.cmd, expected)
+	} else if end.cmd != expected {
+		ctx.addErrorf("line %d: expected %s to close %s on line %d, got %s", end.lineNum, expected, opener.cmd, opener.lineNum, end.cmd)
+	}
+}
+
+// blockOpener returns the command that opens the block closed by cmd
+func blockOpener(cmd string) string {
+	if cmd == END_FOR {
+		return FOR
+	}
+	return IF
+}
+
 func ForEachLine(r io.Reader, apply func(num int, line string) error) error {
 	scanner := bufio.NewScanner(r)
 	i := 0
@@ -2,6 +2,7 @@
 // Use of this source code is governed by a
 // license that can be found in the LICENSE file.
 
+//go:build unit
 // +build unit
 
 package script
@@ -201,3 +202,98 @@ func (vs *ScriptSuite) Test_parseBadLines(t *C) {
 		t.Assert(err, ErrorMatches, "invalid command line string")
 	}
 }
+
+func (vs *ScriptSuite) Test_parseBlocks(t *C) {
+	testDescriptor := `
+DESCRIPTION  Zenoss RM 5.0.1 upgrade
+REQUIRE_SVC
+ON_FAILURE ROLLBACK
+SNAPSHOT
+SET IMAGE zenoss/resmgr_5.0:5.0.2
+IF SVC_IMAGE Zenoss.core zenoss/resmgr_5.0:5.0.1
+  SVC_USE ${IMAGE}
+ELSE
+  FOR EACH child IN Zenoss.core/HBase
+    IF NOT SVC_STATE ${child} stopped
+      SVC_STOP ${child}
+    END_IF
+  END_FOR
+END_IF
+SVC_START Zenoss.core
+`
+	r := strings.NewReader(testDescriptor)
+	ctx, err := parseDescriptor(r)
+	t.Assert(err, IsNil)
+	t.Assert(ctx.errors, HasLen, 0)
+	t.Assert(ctx.program, HasLen, 6)
+	t.Assert(ctx.program[0].cmd, Equals, DESCRIPTION)
+	t.Assert(ctx.program[1].cmd, Equals, REQUIRE_SVC)
+	t.Assert(ctx.program[2].cmd, Equals, SNAPSHOT)
+	t.Assert(ctx.program[3].cmd, Equals, SET)
+	t.Assert(ctx.program[5].cmd, Equals, SVC_START)
+
+	ifNode := ctx.program[4]
+	t.Assert(ifNode.cmd, Equals, IF)
+	t.Assert(ifNode.lineNum, Equals, 7)
+	t.Assert(ifNode.body, HasLen, 1)
+	t.Assert(ifNode.body[0].cmd, Equals, USE)
+	t.Assert(ifNode.elseBody, HasLen, 1)
+
+	forNode := ifNode.elseBody[0]
+	t.Assert(forNode.cmd, Equals, FOR)
+	t.Assert(forNode.args, DeepEquals, []string{"EACH", "child", "IN", "Zenoss.core/HBase"})
+	t.Assert(forNode.body, HasLen, 1)
+	t.Assert(forNode.body[0].cmd, Equals, IF)
+	t.Assert(forNode.body[0].body, HasLen, 1)
+	t.Assert(forNode.body[0].body[0].cmd, Equals, SVC_STOP)
+	t.Assert(forNode.body[0].elseBody, HasLen, 0)
+}
+
+func (vs *ScriptSuite) Test_parseBlockErrors(t *C) {
+	testDescriptor := `
+REQUIRE_SVC
+ON_FAILURE ROLLBACK
+IF VAR X 1
+  FOR EACH child IN Zenoss.core
+  END_IF
+ELSE
+END_FOR
+IF VAR X 2
+`
+	r := strings.NewReader(testDescriptor)
+	ctx, err := parseDescriptor(r)
+	t.Assert(err, IsNil)
+	t.Assert(ctx.errors, HasLen, 4)
+	t.Assert(ctx.errors[0], ErrorMatches, "line 6: expected END_FOR to close FOR on line 5, got END_IF")
+	t.Assert(ctx.errors[1], ErrorMatches, "line 8: expected END_IF to close IF on line 4, got END_FOR")
+	t.Assert(ctx.errors[2], ErrorMatches, "line 9: IF is not closed by END_IF")
+	t.Assert(ctx.errors[3], ErrorMatches, "line 3: ON_FAILURE ROLLBACK depends on SNAPSHOT")
+
+	err = ParseErrors(ctx.errors)
+	t.Assert(strings.Split(err.Error(), "\n"), HasLen, 4)
+
+	r = strings.NewReader("IF VAR X 1\nEND_IF\nEND_FOR\n")
+	ctx, err = parseDescriptor(r)
+	t.Assert(err, IsNil)
+	t.Assert(ctx.errors, HasLen, 1)
+	t.Assert(ctx.errors[0], ErrorMatches, "line 3: END_FOR without matching FOR")
+}
+
+func (vs *ScriptSuite) Test_parseBadBlockCommands(t *C) {
+	tests := map[string]string{
+		"IF SVC_STATE Zenoss.core running": "line 1: service state running unknown",
+		"IF NOT":                           "line 1: expected at least 2, got 1: IF NOT",
+		"IF SVC_UNKNOWN Zenoss.core":       "line 1: unknown condition SVC_UNKNOWN: .*",
+		"IF VAR X":                         "line 1: VAR expected 2, got 1: IF VAR X",
+		"IF VAR 1X 1":                      "line 1: invalid variable name 1X",
+		"SET 1X 1":                         "line 1: arg 1X did not match .*",
+		"FOR ALL child IN Zenoss.core":     "line 1: expected FOR EACH <variable> IN <service>: .*",
+		"FOR EACH child- IN Zenoss.core":   "line 1: invalid variable name child-",
+		"ON_FAILURE IGNORE":                "line 1: arg IGNORE did not match .*",
+		"ELSE extra":                       "line 1: expected 0, got 1: ELSE extra",
+	}
+	for line, expected := range tests {
+		_, err := parseDescriptor(strings.NewReader(line))
+		t.Assert(err, ErrorMatches, expected, Commentf("line: %s", line))
+	}
+}
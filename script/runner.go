@@ -10,14 +10,23 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 
 	log "github.com/Sirupsen/logrus"
 )
 
 var (
 	cmdEval map[string]func(*runner, node) error
+
+	// variableRegex matches a reference to a variable, e.g. ${IMAGE}
+	variableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
 )
 
+// hasVariable returns true if the string references a variable
+func hasVariable(s string) bool {
+	return variableRegex.MatchString(s)
+}
+
 func init() {
 	cmdEval = map[string]func(*runner, node) error{
 		"":          evalEmpty,
@@ -33,6 +42,9 @@ func init() {
 		SVC_RESTART: evalSvcRestart,
 		SVC_EXEC:    evalSvcExec,
 		SVC_WAIT:    evalSvcWait,
+		SET:         evalSet,
+		IF:          evalIf,
+		FOR:         evalFor,
 	}
 }
 
@@ -50,10 +62,19 @@ type Config struct {
 	SvcRestart     ServiceControl    // function to restart a service
 	SvcWait        ServiceWait       // function to wait for a service to be in a desired state
 	SvcUse         ServiceUse
+	SvcState       ServiceStateLookup // function to look up the desired state of a service
+	SvcImage       ServiceImageLookup // function to look up the image of a service
+	SvcChildren    ServiceChildren    // function to find the paths of the children of a service
+	OnStep         StepReporter       // function called when the status of a top-level step changes
+	ResumeAt       int                // index of the first top-level step to run; earlier steps already succeeded
+	Snapshots      []string           // snapshots taken by the steps before ResumeAt, in order
+	ImageID        string             // image resolved by the last USE step before ResumeAt
 }
 
 type Runner interface {
 	Run(<-chan struct{}) error
+	// Steps returns the top-level steps of the script
+	Steps() []Step
 }
 
 type runner struct {
@@ -73,6 +94,14 @@ type runner struct {
 	svcWait         ServiceWait
 	execCommand     execCmd
 	svcUse          ServiceUse
+	svcState        ServiceStateLookup // function to look up the desired state of a service
+	svcImage        ServiceImageLookup // function to look up the image of a service
+	svcChildren     ServiceChildren    // function to find the paths of the children of a service
+	onFailure       string             // ON_FAILURE policy; empty restores the latest snapshot
+	firstSnapshotID string             // the first snapshot taken
+	imageID         string             // image resolved by the last USE step
+	nested          int                // steps inside blocks that succeeded during the current top-level step
+	stop            <-chan struct{}    // closed when script evaluation should stop
 }
 
 func NewRunnerFromFile(fileName string, config *Config) (Runner, error) {
@@ -119,6 +148,18 @@ func newRunner(config *Config, pctx *parseContext) *runner {
 		svcRestart:      config.SvcRestart,
 		execCommand:     defaultExec,
 		svcUse:          config.SvcUse,
+		svcState:        config.SvcState,
+		svcImage:        config.SvcImage,
+		svcChildren:     config.SvcChildren,
+		imageID:         config.ImageID,
+	}
+	for _, n := range pctx.nodes {
+		if n.cmd == ON_FAILURE {
+			r.onFailure = n.args[0]
+		}
+	}
+	for _, snapshotID := range config.Snapshots {
+		r.trackSnapshot(snapshotID)
 	}
 	if config.NoOp {
 		plog.Info("creatng no op runner")
@@ -137,13 +178,21 @@ func newRunner(config *Config, pctx *parseContext) *runner {
 }
 
 func (r *runner) Run(stop <-chan struct{}) error {
-	if err := r.evalNodes(r.parseCtx.nodes, stop); err != nil {
+	if err := r.evalNodes(r.parseCtx.program, stop); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+func (r *runner) Steps() []Step {
+	steps := make([]Step, len(r.parseCtx.program))
+	for i, n := range r.parseCtx.program {
+		steps[i] = Step{LineNum: n.lineNum, Line: n.line, Status: StepPending}
+	}
+	return steps
+}
+
 func (r *runner) evalNodes(nodes []node, stop <-chan struct{}) error {
 
 	failed := true
@@ -154,35 +203,120 @@ func (r *runner) evalNodes(nodes []node, stop <-chan struct{}) error {
 		}
 	}()
 
+	r.stop = stop
 	for i, n := range nodes {
-		logger := plog.WithFields(log.Fields{
-			"step": i,
-			"line": n.line,
-			"command": n.cmd,
-		})
-		if f, found := cmdEval[n.cmd]; found {
-			logger.Info("executing step")
-			if err := f(r, n); err != nil {
-				logger.WithError(err).Error("Unable to execute step")
-				return err
+		if i < r.config.ResumeAt {
+			// steps that only set up variables are evaluated again so that
+			// the steps being resumed have the same context; the image in use
+			// is restored from the config
+			if n.cmd == REQUIRE_SVC || n.cmd == SET {
+				if err := r.evalNode(i, n); err != nil {
+					return err
+				}
 			}
+			continue
+		}
+		step := Step{LineNum: n.lineNum, Line: n.line, Status: StepRunning}
+		r.reportStep(i, step)
+		r.nested = 0
+		err := r.evalNode(i, n)
+		if err != nil {
+			step.Status, step.Error = StepFailed, err.Error()
+			step.NestedSucceeded = r.nested
 		} else {
-			logger.Info("skipping step because of unknown function")
+			step.Status = StepSucceeded
+			if n.cmd == SNAPSHOT {
+				step.SnapshotID = r.snapshotID
+			} else if n.cmd == USE {
+				step.ImageID = r.imageID
+			}
+		}
+		r.reportStep(i, step)
+		if err != nil {
+			return err
 		}
+	}
+	failed = false
 
-		select {
-		case <-stop:
-			logger.Info("Received signal, stopping script evaluation")
-			return fmt.Errorf("received stop signal, error executing step %d: %s", i, n.cmd)
-		default:
+	return nil
+}
+
+// reportStep reports the status of a top-level step, if a reporter is set
+func (r *runner) reportStep(i int, step Step) {
+	if r.config.OnStep != nil {
+		r.config.OnStep(i, step)
+	}
+}
+
+// evalBlock evaluates a list of nodes in order
+func (r *runner) evalBlock(nodes []node) error {
+	for i, n := range nodes {
+		if err := r.evalNode(i, n); err != nil {
+			return err
 		}
+		r.nested++
+	}
+	return nil
+}
 
+// evalNode substitutes variables into the arguments of a node and evaluates it
+func (r *runner) evalNode(i int, n node) error {
+	logger := plog.WithFields(log.Fields{
+		"step":    i,
+		"line":    n.line,
+		"linenum": n.lineNum,
+		"command": n.cmd,
+	})
+	args, err := r.expandArgs(n.args)
+	if err != nil {
+		logger.WithError(err).Error("Unable to execute step")
+		return fmt.Errorf("line %d: %s", n.lineNum, err)
+	}
+	n.args = args
+	if f, found := cmdEval[n.cmd]; found {
+		logger.Info("executing step")
+		if err := f(r, n); err != nil {
+			logger.WithError(err).Error("Unable to execute step")
+			return err
+		}
+	} else {
+		logger.Info("skipping step because of unknown function")
 	}
-	failed = false
 
+	select {
+	case <-r.stop:
+		logger.Info("Received signal, stopping script evaluation")
+		return fmt.Errorf("received stop signal, error executing step %d: %s", i, n.cmd)
+	default:
+	}
 	return nil
 }
 
+// expandArgs returns a copy of args with each ${VARIABLE} declared by the
+// script replaced by its value.  References to other variables, such as
+// ${HOME} in a command for a container shell, are left untouched.
+func (r *runner) expandArgs(args []string) ([]string, error) {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		var missing []string
+		expanded[i] = variableRegex.ReplaceAllStringFunc(arg, func(ref string) string {
+			name := variableRegex.FindStringSubmatch(ref)[1]
+			if _, declared := r.parseCtx.variables[name]; !declared {
+				return ref
+			}
+			value, found := r.env[name]
+			if !found {
+				missing = append(missing, name)
+			}
+			return value
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("variable %s is not set", missing[0])
+		}
+	}
+	return expanded, nil
+}
+
 func (r *runner) addExitFunction(ef func(bool)) {
 	r.exitFunctions = append(r.exitFunctions, ef)
 }
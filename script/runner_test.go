@@ -2,12 +2,15 @@
 // Use of this source code is governed by a
 // license that can be found in the LICENSE file.
 
+//go:build unit
 // +build unit
 
 package script
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	. "gopkg.in/check.v1"
 )
@@ -48,3 +51,169 @@ func (vs *ScriptSuite) Test_Run(t *C) {
 	t.Assert(err, ErrorMatches, "test error id from path")
 
 }
+
+func (vs *ScriptSuite) Test_RunBlocks(t *C) {
+	testDescriptor := `
+REQUIRE_SVC
+SNAPSHOT
+SET PARENT Zenoss.core
+IF SVC_IMAGE ${PARENT} zenoss/core:5.0.1
+  SVC_START ${PARENT}
+ELSE
+  SVC_STOP ${PARENT}
+END_IF
+FOR EACH child IN ${PARENT}
+  IF SVC_STATE ${child} stopped
+    SVC_RESTART ${child}
+  END_IF
+END_FOR
+IF NOT VAR PARENT Zenoss.core
+  SVC_STOP Zenoss.core
+END_IF
+`
+	states := map[string]ServiceState{
+		"Zenoss.core/Zope":   "stopped",
+		"Zenoss.core/HBase":  "started",
+		"Zenoss.core/zenhub": "stopped",
+	}
+	started, stopped, restarted := []string{}, []string{}, []string{}
+	config := Config{
+		ServiceID:     "TEST_SERVICE_ID_12345",
+		TenantLookup:  func(service string) (string, error) { return service, nil },
+		SvcIDFromPath: func(tenantID string, path string) (string, error) { return path, nil },
+		Snapshot:      func(serviceID, description, tag string) (string, error) { return "snap", nil },
+		Restore:       func(snapshotID string, forceRestart bool) error { return nil },
+		SvcStart:      func(serviceID string, recursive bool) error { started = append(started, serviceID); return nil },
+		SvcStop:       func(serviceID string, recursive bool) error { stopped = append(stopped, serviceID); return nil },
+		SvcRestart:    func(serviceID string, recursive bool) error { restarted = append(restarted, serviceID); return nil },
+		SvcState:      func(serviceID string) (ServiceState, error) { return states[serviceID], nil },
+		SvcImage:      func(serviceID string) (string, error) { return "localhost:5000/zenoss/core:5.0.1", nil },
+		SvcChildren: func(tenantID, path string) ([]string, error) {
+			return []string{path + "/HBase", path + "/Zope", path + "/zenhub"}, nil
+		},
+	}
+	runner, err := NewRunner(strings.NewReader(testDescriptor), &config)
+	t.Assert(err, IsNil)
+	err = runner.Run(make(chan struct{}))
+	t.Assert(err, IsNil)
+	t.Assert(started, DeepEquals, []string{"Zenoss.core"})
+	t.Assert(stopped, DeepEquals, []string{})
+	t.Assert(restarted, DeepEquals, []string{"Zenoss.core/Zope", "Zenoss.core/zenhub"})
+
+	// the loop variable is not available after the loop
+	runner, err = NewRunner(strings.NewReader("REQUIRE_SVC\nFOR EACH child IN Zenoss.core\nEND_FOR\nSVC_START ${child}\n"), &config)
+	t.Assert(err, IsNil)
+	err = runner.Run(make(chan struct{}))
+	t.Assert(err, ErrorMatches, "line 4: variable child is not set")
+}
+
+func (vs *ScriptSuite) Test_RunShellVariables(t *C) {
+	testDescriptor := `
+REQUIRE_SVC
+SET SCRIPT upgrade.sh
+SVC_EXEC NO_COMMIT Zenoss.core/Zope ${ZENHOME}/bin/${SCRIPT} --home=${HOME}
+`
+	config := Config{
+		ServiceID:     "TEST_SERVICE_ID_12345",
+		TenantLookup:  func(service string) (string, error) { return service, nil },
+		SvcIDFromPath: func(tenantID string, path string) (string, error) { return path, nil },
+	}
+	r, err := NewRunner(strings.NewReader(testDescriptor), &config)
+	t.Assert(err, IsNil)
+	var executed []string
+	r.(*runner).execCommand = func(name string, args ...string) error {
+		executed = append(executed, args...)
+		return nil
+	}
+	err = r.Run(make(chan struct{}))
+	t.Assert(err, IsNil)
+
+	// only the variable declared by the script is substituted
+	t.Assert(executed[len(executed)-2:], DeepEquals, []string{"${ZENHOME}/bin/upgrade.sh", "--home=${HOME}"})
+}
+
+func (vs *ScriptSuite) Test_RunResume(t *C) {
+	testDescriptor := `
+REQUIRE_SVC
+ON_FAILURE ABORT
+SVC_USE zenoss/core:5.0.2
+SET PARENT Zenoss.core
+IF VAR PARENT Zenoss.core
+  SVC_STOP ${PARENT}
+  SVC_START ${PARENT}
+END_IF
+`
+	var used, stopped []string
+	startErr := errors.New("start failed")
+	steps := map[int]Step{}
+	config := Config{
+		ServiceID:     "TEST_SERVICE_ID_12345",
+		TenantLookup:  func(service string) (string, error) { return service, nil },
+		SvcIDFromPath: func(tenantID string, path string) (string, error) { return path, nil },
+		SvcUse: func(tenantID, serviceID, imageID, registry string, replaceImgs []string, noOp bool) (string, error) {
+			used = append(used, imageID)
+			return "localhost:5000/" + imageID, nil
+		},
+		SvcStop:  func(serviceID string, recursive bool) error { stopped = append(stopped, serviceID); return nil },
+		SvcStart: func(serviceID string, recursive bool) error { return startErr },
+		OnStep:   func(i int, step Step) { steps[i] = step },
+	}
+	r, err := NewRunner(strings.NewReader(testDescriptor), &config)
+	t.Assert(err, IsNil)
+	err = r.Run(make(chan struct{}))
+	t.Assert(err, ErrorMatches, "start failed")
+
+	// the failure inside the block is recorded after the step that succeeded
+	t.Assert(steps[3].Status, Equals, StepFailed)
+	t.Assert(steps[3].NestedSucceeded, Equals, 1)
+	t.Assert(used, DeepEquals, []string{"zenoss/core:5.0.2"})
+	t.Assert(steps[1].ImageID, Equals, "localhost:5000/zenoss/core:5.0.2")
+
+	// resuming at the block restores the image in use without using it again
+	startErr = nil
+	used, stopped, steps = nil, nil, map[int]Step{}
+	config.ResumeAt = 3
+	config.ImageID = "localhost:5000/zenoss/core:5.0.2"
+	r, err = NewRunner(strings.NewReader(testDescriptor), &config)
+	t.Assert(err, IsNil)
+	t.Assert(r.(*runner).imageID, Equals, "localhost:5000/zenoss/core:5.0.2")
+	err = r.Run(make(chan struct{}))
+	t.Assert(err, IsNil)
+	t.Assert(used, IsNil)
+	t.Assert(stopped, DeepEquals, []string{"Zenoss.core"})
+	t.Assert(steps, HasLen, 1)
+	t.Assert(steps[3].Status, Equals, StepSucceeded)
+	t.Assert(steps[3].NestedSucceeded, Equals, 0)
+}
+
+func (vs *ScriptSuite) Test_RunOnFailure(t *C) {
+	testDescriptor := `
+REQUIRE_SVC
+%s
+SNAPSHOT first
+SNAPSHOT second
+SVC_START Zenoss.core
+`
+	var restored []string
+	config := Config{
+		ServiceID:     "TEST_SERVICE_ID_12345",
+		TenantLookup:  func(service string) (string, error) { return service, nil },
+		SvcIDFromPath: func(tenantID string, path string) (string, error) { return path, nil },
+		Snapshot:      func(serviceID, description, tag string) (string, error) { return tag, nil },
+		Restore:       func(snapshotID string, forceRestart bool) error { restored = append(restored, snapshotID); return nil },
+		SvcStart:      func(serviceID string, recursive bool) error { return errors.New("start failed") },
+	}
+
+	for policy, expected := range map[string][]string{
+		"":                    []string{"second"},
+		"ON_FAILURE ROLLBACK": []string{"first"},
+		"ON_FAILURE ABORT":    nil,
+	} {
+		restored = nil
+		runner, err := NewRunner(strings.NewReader(fmt.Sprintf(testDescriptor, policy)), &config)
+		t.Assert(err, IsNil)
+		err = runner.Run(make(chan struct{}))
+		t.Assert(err, ErrorMatches, "start failed")
+		t.Assert(restored, DeepEquals, expected, Commentf("policy: %s", policy))
+	}
+}
@@ -39,6 +39,42 @@ type ServiceState string
 // Wait for a service to be in a particular state
 type ServiceWait func(serviceID []string, serviceState ServiceState, timeout uint32, recursive bool) error
 
+// ServiceStateLookup returns the desired state of a service
+type ServiceStateLookup func(serviceID string) (ServiceState, error)
+
+// ServiceImageLookup returns the image id of a service
+type ServiceImageLookup func(serviceID string) (string, error)
+
+// ServiceChildren returns the paths of the child services of the service at the given path
+type ServiceChildren func(tenantID string, path string) ([]string, error)
+
+// StepStatus is the status of a top-level step of a script
+type StepStatus string
+
+const (
+	StepPending   StepStatus = "pending"
+	StepRunning   StepStatus = "running"
+	StepSucceeded StepStatus = "succeeded"
+	StepFailed    StepStatus = "failed"
+)
+
+// Step is the progress of a top-level step of a script
+type Step struct {
+	LineNum    int
+	Line       string
+	Status     StepStatus
+	Error      string `json:",omitempty"`
+	SnapshotID string `json:",omitempty"` // snapshot taken by a SNAPSHOT step
+	ImageID    string `json:",omitempty"` // image resolved by a USE step
+	// NestedSucceeded is the number of steps inside an IF or FOR block that
+	// succeeded before the block failed
+	NestedSucceeded int `json:",omitempty"`
+}
+
+// StepReporter is called with the index and progress of a top-level step
+// whenever its status changes
+type StepReporter func(index int, step Step)
+
 type execCmd func(string, ...string) error
 
 type findTenant func(string) (string, error)
@@ -55,6 +91,19 @@ func ScriptStateToDesiredState(state ServiceState) (service.DesiredState, error)
 	return service.DesiredState(-99), fmt.Errorf("service state %s unknown", state)
 }
 
+// DesiredStateToScriptState converts a service desired state to the state used by scripts
+func DesiredStateToScriptState(state service.DesiredState) (ServiceState, error) {
+	switch state {
+	case service.SVCStop:
+		return "stopped", nil
+	case service.SVCRun:
+		return "started", nil
+	case service.SVCPause:
+		return "paused", nil
+	}
+	return "", fmt.Errorf("desired state %s has no script state", state)
+}
+
 func defaultExec(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Stderr = os.Stderr
@@ -27,6 +27,11 @@ type ControllerOptions struct {
 	MuxPort                 int      // the TCP port for the remote mux
 	Mux                     bool     // True if a remote mux is used
 	MUXDisableTLS           bool     // True if TLS should be disabled on the mux
+	MuxCertVerify           bool     // True if mux connections must present certificates issued by the internal CA
+	MuxDialTimeout          int      // seconds to wait for a connection through the mux
+	MuxIdleTimeout          int      // seconds before an idle connection through the mux is closed, 0 never
+	MuxBreakerFailures      int      // consecutive failed connections that trip the breaker of a host, 0 never
+	MuxBreakerReset         int      // seconds a tripped breaker fails fast before it probes the host
 	KeyPEMFile              string   // path to the KeyPEMfile
 	CertPEMFile             string   // path to the CertPEMfile
 	ServicedEndpoint        string
@@ -56,6 +61,10 @@ func (c ControllerOptions) toContainerControllerOptions() (options container.Con
 	options.Mux.DisableTLS = c.MUXDisableTLS
 	options.Mux.KeyPEMFile = c.KeyPEMFile
 	options.Mux.CertPEMFile = c.CertPEMFile
+	options.Mux.DialTimeout = time.Duration(c.MuxDialTimeout) * time.Second
+	options.Mux.IdleTimeout = time.Duration(c.MuxIdleTimeout) * time.Second
+	options.Mux.BreakerFailures = c.MuxBreakerFailures
+	options.Mux.BreakerReset = time.Duration(c.MuxBreakerReset) * time.Second
 	options.Logforwarder.Enabled = c.Logstash
 	options.Logforwarder.Path = c.LogstashBinary
 	options.Logforwarder.ConfigFile = c.LogstashConfig
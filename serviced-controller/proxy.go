@@ -20,6 +20,7 @@ import (
 	"strconv"
 
 	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/container"
 	coordzk "github.com/control-center/serviced/coordinator/client/zookeeper"
 	"github.com/control-center/serviced/logging"
@@ -61,6 +62,11 @@ func CmdServiceProxy(ctx *cli.Context) {
 	}
 
 	options.MuxPort = cfg.IntVal("MUX_PORT", options.MuxPort)
+	options.MuxCertVerify = cfg.BoolVal("MUX_CERT_VERIFY", false)
+	options.MuxDialTimeout = cfg.IntVal("MUX_DIAL_TIMEOUT", 10)
+	options.MuxIdleTimeout = cfg.IntVal("MUX_IDLE_TIMEOUT", 0)
+	options.MuxBreakerFailures = cfg.IntVal("MUX_BREAKER_FAILURES", 5)
+	options.MuxBreakerReset = cfg.IntVal("MUX_BREAKER_RESET", 10)
 	options.RPCPort = cfg.IntVal("RPC_PORT", options.RPCPort)
 	options.KeyPEMFile = cfg.StringVal("KEY_FILE", options.KeyPEMFile)		// TODO: Is this set in container.go?
 	options.CertPEMFile = cfg.StringVal("CERT_FILE", options.CertPEMFile)		// TODO: Is this set in container.go?
@@ -75,6 +81,7 @@ func CmdServiceProxy(ctx *cli.Context) {
 
 	rpcutils.RPC_CLIENT_SIZE = 2
 	rpcutils.RPCDisableTLS = options.RPCDisableTLS
+	auth.MuxCertVerify = options.MuxCertVerify
 
 	if err := StartProxy(options); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,79 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stats collects serviced metrics and posts them to the TSDB.
+package stats
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/control-center/serviced/proxy"
+	"github.com/control-center/serviced/utils"
+)
+
+// MuxStatsReporter collects and posts the dial counts and circuit breaker
+// state of the containers dialed through the host's mux to the TSDB.
+type MuxStatsReporter struct {
+	statsReporter
+	hostID    string
+	dialStats func() []proxy.DialStats
+}
+
+// NewMuxStatsReporter creates a new MuxStatsReporter and kicks off the
+// reporting goroutine.
+func NewMuxStatsReporter(destination string, interval time.Duration, dialStats func() []proxy.DialStats) (*MuxStatsReporter, error) {
+	hostID, err := utils.HostID()
+	if err != nil {
+		plog.WithError(err).Debug("Could not determine host ID")
+		return nil, err
+	}
+
+	sr := MuxStatsReporter{
+		statsReporter: statsReporter{
+			destination:  destination,
+			closeChannel: make(chan struct{}),
+		},
+		hostID:    hostID,
+		dialStats: dialStats,
+	}
+
+	sr.statsReporter.updateStatsFunc = func() {}
+	sr.statsReporter.gatherStatsFunc = sr.gatherStats
+	go sr.report(interval)
+	return &sr, nil
+}
+
+// Fills out the metric consumer format.
+func (sr *MuxStatsReporter) gatherStats(t time.Time) []Sample {
+	stats := []Sample{}
+	for _, dial := range sr.dialStats() {
+		tagmap := map[string]string{
+			"controlplane_host_id": sr.hostID,
+			"address":              dial.Address,
+		}
+		circuitOpen := int64(0)
+		if dial.State != proxy.CircuitClosed {
+			circuitOpen = 1
+		}
+		for name, value := range map[string]int64{
+			"opened":      dial.Opened,
+			"failed":      dial.Failed,
+			"tripped":     dial.Tripped,
+			"circuitopen": circuitOpen,
+		} {
+			stats = append(stats, Sample{"net.mux.dial." + name, strconv.FormatInt(value, 10), t.Unix(), tagmap})
+		}
+	}
+	return stats
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stats collects serviced metrics and posts them to the TSDB.
+package stats
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/utils"
+	"github.com/rcrowley/go-metrics"
+)
+
+// Names of the public endpoint metrics
+const (
+	PublicEndpointRequests = "publicendpoint.requests"
+	PublicEndpointErrors   = "publicendpoint.errors"
+	PublicEndpointStatus   = "publicendpoint.status.%dxx"
+	PublicEndpointLatency  = "publicendpoint.latency"
+)
+
+// latencyPercentiles are the percentiles of the request latency that are
+// posted for each public endpoint
+var latencyPercentiles = map[string]float64{
+	"p50": 0.5,
+	"p95": 0.95,
+	"p99": 0.99,
+}
+
+type publicEndpointKey struct {
+	serviceID    string
+	endpointType string
+	endpoint     string
+}
+
+// PublicEndpointStatsReporter collects and posts the request rate, status
+// codes and latency of the vhost and port public endpoints to the TSDB.
+type PublicEndpointStatsReporter struct {
+	statsReporter
+	mu         *sync.Mutex
+	hostID     string
+	registries map[publicEndpointKey]metrics.Registry
+}
+
+// NewPublicEndpointStatsReporter creates a new PublicEndpointStatsReporter
+// and kicks off the reporting goroutine.
+func NewPublicEndpointStatsReporter(destination string, interval time.Duration) (*PublicEndpointStatsReporter, error) {
+	hostID, err := utils.HostID()
+	if err != nil {
+		plog.WithError(err).Debug("Could not determine host ID")
+		return nil, err
+	}
+
+	sr := PublicEndpointStatsReporter{
+		statsReporter: statsReporter{
+			destination:  destination,
+			closeChannel: make(chan struct{}),
+		},
+		mu:         &sync.Mutex{},
+		hostID:     hostID,
+		registries: make(map[publicEndpointKey]metrics.Registry),
+	}
+
+	sr.statsReporter.updateStatsFunc = func() {}
+	sr.statsReporter.gatherStatsFunc = sr.gatherStats
+	go sr.report(interval)
+	return &sr, nil
+}
+
+// Record adds a request to a vhost or port public endpoint of a service
+func (sr *PublicEndpointStatsReporter) Record(serviceID, endpointType, endpoint string, status int, latency time.Duration) {
+	// tsdb tags may not contain colons, so ":8080" is tagged "8080" and
+	// "10.0.0.1:8080" is tagged "10.0.0.1_8080"
+	endpoint = strings.Replace(strings.TrimPrefix(endpoint, ":"), ":", "_", -1)
+	key := publicEndpointKey{serviceID, endpointType, endpoint}
+	sr.mu.Lock()
+	registry, ok := sr.registries[key]
+	if !ok {
+		registry = metrics.NewRegistry()
+		sr.registries[key] = registry
+	}
+	sr.mu.Unlock()
+
+	metrics.GetOrRegisterCounter(PublicEndpointRequests, registry).Inc(1)
+	if status >= 100 && status < 600 {
+		metrics.GetOrRegisterCounter(fmt.Sprintf(PublicEndpointStatus, status/100), registry).Inc(1)
+	}
+	if status >= 500 {
+		metrics.GetOrRegisterCounter(PublicEndpointErrors, registry).Inc(1)
+	}
+	metrics.GetOrRegisterHistogram(PublicEndpointLatency, registry, metrics.NewExpDecaySample(1028, 0.015)).
+		Update(int64(latency / time.Millisecond))
+}
+
+// Fills out the metric consumer format.
+func (sr *PublicEndpointStatsReporter) gatherStats(t time.Time) []Sample {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	stats := []Sample{}
+	for key, registry := range sr.registries {
+		reg, _ := registry.(*metrics.StandardRegistry)
+		reg.Each(func(name string, i interface{}) {
+			tagmap := map[string]string{
+				"controlplane_host_id":             sr.hostID,
+				"controlplane_service_id":          key.serviceID,
+				"controlplane_publicendpoint_type": key.endpointType,
+				"controlplane_publicendpoint":      key.endpoint,
+			}
+			switch metric := i.(type) {
+			case metrics.Counter:
+				stats = append(stats, Sample{name, strconv.FormatInt(metric.Count(), 10), t.Unix(), tagmap})
+			case metrics.Histogram:
+				snapshot := metric.Snapshot()
+				if snapshot.Count() == 0 {
+					return
+				}
+				stats = append(stats, Sample{name + ".mean", strconv.FormatFloat(snapshot.Mean(), 'f', -1, 32), t.Unix(), tagmap})
+				for suffix, p := range latencyPercentiles {
+					stats = append(stats, Sample{name + "." + suffix, strconv.FormatFloat(snapshot.Percentile(p), 'f', -1, 32), t.Unix(), tagmap})
+				}
+			}
+		})
+	}
+	return stats
+}
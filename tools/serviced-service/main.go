@@ -24,6 +24,9 @@ Commands:
 	update TEMPLATE_FILE SOURCE_FILE CHANGES
 		Applies the modifications in the CHANGES file to the SOURCE file.
 
+	validate [--all] TEMPLATE_FILE
+		Checks a template, reporting every issue with --all
+
 	version
 		Prints the version to stdout
 */
@@ -92,6 +95,12 @@ func init() {
 		"Update service definitions from an update file",
 		&Update{},
 	)
+	App.Parser.AddCommand(
+		"validate",
+		"Check a service template",
+		"Check a service template and report the first error, or every issue with --all",
+		&Validate{},
+	)
 	App.Parser.AddCommand(
 		"version",
 		"Print the version and exit",
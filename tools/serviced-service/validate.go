@@ -17,6 +17,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/jessevdk/go-flags"
+
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain"
 	// "github.com/control-center/serviced/domain/logfilter"
@@ -25,6 +27,35 @@ import (
 	"github.com/control-center/serviced/utils"
 )
 
+// Validate is the subcommand for checking a service template
+type Validate struct {
+	All  bool `long:"all" description:"Report every issue in the template instead of the first error"`
+	Args struct {
+		File flags.Filename `positional-arg-name:"TEMPLATE_FILE" description:"Template file"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// Execute checks a template file, returning an error if it is not valid
+func (c *Validate) Execute(args []string) error {
+	App.initializeLogging()
+	tmpl, err := LoadTemplate(string(c.Args.File))
+	if err != nil {
+		return err
+	}
+	if !c.All {
+		return tmpl.ValidEntity()
+	}
+
+	diagnostics := tmpl.Lint()
+	for _, diag := range diagnostics {
+		fmt.Println(diag)
+	}
+	if diagnostics.HasErrors() {
+		return fmt.Errorf("template %s has errors", c.Args.File)
+	}
+	return nil
+}
+
 func (mc *MigrationContext) validate(req dao.ServiceMigrationRequest) error {
 	var svcAll []service.Service
 
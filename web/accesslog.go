@@ -0,0 +1,343 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/zzk/registry"
+)
+
+// Types of public endpoints in request records
+const (
+	EndpointTypeVHost = "vhost"
+	EndpointTypePort  = "port"
+)
+
+// accessLogType is the type of the access log entries in logstash
+const accessLogType = "publicendpoint_access"
+
+var (
+	// accessLogBuffer is the number of entries waiting to be sent to
+	// logstash before entries are dropped
+	accessLogBuffer = 1000
+
+	// accessLogRetryMin and accessLogRetryMax bound how long to wait before
+	// reconnecting to logstash
+	accessLogRetryMin = 500 * time.Millisecond
+	accessLogRetryMax = 90 * time.Second
+)
+
+// RequestRecord describes a request proxied to a vhost or port public
+// endpoint
+type RequestRecord struct {
+	Time         time.Time
+	EndpointType string           // vhost or port
+	Endpoint     string           // name of the vhost or address of the port
+	Routing      registry.Routing // routing of the endpoint
+	ServiceID    string
+	InstanceID   int
+	Application  string
+	Method       string
+	Host         string
+	URI          string
+	Proto        string
+	RemoteAddr   string
+	UserAgent    string
+	Status       int
+	Bytes        int64
+	Duration     time.Duration
+}
+
+// RequestRecorder receives a record of each request proxied to a public
+// endpoint.  Record is called after the response is sent, so it should not
+// block.
+type RequestRecorder interface {
+	Record(record RequestRecord)
+}
+
+// RequestRecorderFunc is a function that implements RequestRecorder
+type RequestRecorderFunc func(record RequestRecord)
+
+// Record implements RequestRecorder
+func (f RequestRecorderFunc) Record(record RequestRecord) {
+	f(record)
+}
+
+var (
+	requestRecordersLock = &sync.RWMutex{}
+	requestRecorders     []RequestRecorder
+)
+
+// SetRequestRecorders sets the recorders of the requests proxied to vhost and
+// http port public endpoints
+func SetRequestRecorders(recorders ...RequestRecorder) {
+	requestRecordersLock.Lock()
+	defer requestRecordersLock.Unlock()
+	requestRecorders = recorders
+}
+
+func getRequestRecorders() []RequestRecorder {
+	requestRecordersLock.RLock()
+	defer requestRecordersLock.RUnlock()
+	return requestRecorders
+}
+
+// statusWriter keeps track of the status code and size of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 && (status >= http.StatusOK || status == http.StatusSwitchingProtocols) {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, so that websockets can be proxied
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not support hijacking")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the original response writer
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// proxyEndpoint sends a request to a vhost or port public endpoint like
+// ProxyRequest, and passes a record of the request to the request recorders.
+func proxyEndpoint(endpointType, name string, useTLS bool, exports Exports, w http.ResponseWriter, r *http.Request) bool {
+	recorders := getRequestRecorders()
+	if len(recorders) == 0 {
+		return ProxyRequest(name, useTLS, exports, w, r)
+	}
+
+	start := time.Now()
+	uri := r.URL.RequestURI()
+	sw := &statusWriter{ResponseWriter: w}
+	export := proxyRequest(name, useTLS, exports, sw, r)
+	if export == nil {
+		return false
+	}
+	status := sw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	record := RequestRecord{
+		Time:         start,
+		EndpointType: endpointType,
+		Endpoint:     name,
+		Routing:      exports.Routing(),
+		ServiceID:    export.ServiceID,
+		InstanceID:   export.InstanceID,
+		Application:  export.Application,
+		Method:       r.Method,
+		Host:         r.Host,
+		URI:          uri,
+		Proto:        r.Proto,
+		RemoteAddr:   r.RemoteAddr,
+		UserAgent:    r.UserAgent(),
+		Status:       status,
+		Bytes:        sw.bytes,
+		Duration:     time.Since(start),
+	}
+	for _, recorder := range recorders {
+		recorder.Record(record)
+	}
+	return true
+}
+
+// accessLogFields are the fields of an access log entry, tagged like the
+// fields of the service logs
+type accessLogFields struct {
+	Type         string  `json:"type"`
+	Service      string  `json:"service"`
+	Instance     string  `json:"instance"`
+	Application  string  `json:"application"`
+	EndpointType string  `json:"endpointtype"`
+	Endpoint     string  `json:"endpoint"`
+	Method       string  `json:"method"`
+	Host         string  `json:"host"`
+	URI          string  `json:"uri"`
+	Proto        string  `json:"proto"`
+	RemoteAddr   string  `json:"remoteaddr"`
+	UserAgent    string  `json:"useragent"`
+	Status       int     `json:"status"`
+	Bytes        int64   `json:"bytes"`
+	DurationMS   float64 `json:"durationms"`
+	Sample       float64 `json:"sample,omitempty"`
+}
+
+// accessLogEntry is an access log entry in the json_lines format of the
+// logstash tcp input
+type accessLogEntry struct {
+	Timestamp string          `json:"@timestamp"`
+	Type      string          `json:"type"`
+	Message   string          `json:"message"`
+	Fields    accessLogFields `json:"fields"`
+}
+
+func newAccessLogEntry(record RequestRecord) accessLogEntry {
+	durationMS := float64(record.Duration) / float64(time.Millisecond)
+	return accessLogEntry{
+		Timestamp: record.Time.UTC().Format(time.RFC3339Nano),
+		Type:      accessLogType,
+		Message: fmt.Sprintf("%s %s %s %s %s %d %d %.3fms", record.RemoteAddr, record.Method, record.Host,
+			record.URI, record.Proto, record.Status, record.Bytes, durationMS),
+		Fields: accessLogFields{
+			Type:         accessLogType,
+			Service:      record.ServiceID,
+			Instance:     strconv.Itoa(record.InstanceID),
+			Application:  record.Application,
+			EndpointType: record.EndpointType,
+			Endpoint:     record.Endpoint,
+			Method:       record.Method,
+			Host:         record.Host,
+			URI:          record.URI,
+			Proto:        record.Proto,
+			RemoteAddr:   record.RemoteAddr,
+			UserAgent:    record.UserAgent,
+			Status:       record.Status,
+			Bytes:        record.Bytes,
+			DurationMS:   durationMS,
+			Sample:       record.Routing.AccessLogSample,
+		},
+	}
+}
+
+// AccessLog sends structured access log entries of the requests to the
+// public endpoints to the tcp input of logstash.  Endpoints may sample their
+// requests, but server errors are always logged.  Entries are dropped when
+// logstash cannot keep up.
+type AccessLog struct {
+	address string
+	entries chan []byte
+	random  func() float64
+}
+
+// NewAccessLog creates an access log that sends its entries to the logstash
+// tcp input at address
+func NewAccessLog(address string) *AccessLog {
+	return &AccessLog{
+		address: address,
+		entries: make(chan []byte, accessLogBuffer),
+		random:  rand.Float64,
+	}
+}
+
+// Record implements RequestRecorder
+func (l *AccessLog) Record(record RequestRecord) {
+	if record.Routing.DisableAccessLog {
+		return
+	}
+	if sample := record.Routing.AccessLogSample; record.Status < http.StatusInternalServerError && sample > 0 && l.random() >= sample {
+		return
+	}
+	data, err := json.Marshal(newAccessLogEntry(record))
+	if err != nil {
+		plog.WithError(err).Debug("Could not marshal access log entry")
+		return
+	}
+	select {
+	case l.entries <- data:
+	default:
+		plog.WithField("address", l.address).Debug("Access log buffer is full, dropping entry")
+	}
+}
+
+// Run sends the access log entries to logstash until shutdown, reconnecting
+// when the connection is lost.
+func (l *AccessLog) Run(shutdown <-chan interface{}) {
+	logger := plog.WithField("address", l.address)
+
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	delay := accessLogRetryMin
+	for {
+		var data []byte
+		select {
+		case data = <-l.entries:
+		case <-shutdown:
+			return
+		}
+
+		for conn == nil {
+			var err error
+			if conn, err = net.DialTimeout("tcp", l.address, time.Second); err == nil {
+				logger.Debug("Connected to logstash for the access log")
+				break
+			}
+			logger.WithError(err).Debug("Could not connect to logstash for the access log, will retry")
+			select {
+			case <-time.After(delay):
+			case <-shutdown:
+				return
+			}
+			if delay *= 2; delay > accessLogRetryMax {
+				delay = accessLogRetryMax
+			}
+		}
+
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			logger.WithError(err).Debug("Lost connection to logstash for the access log")
+			conn.Close()
+			conn = nil
+			continue
+		}
+		delay = accessLogRetryMin
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/control-center/serviced/zzk/registry"
+	"github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestProxyEndpoint_Record(c *C) {
+	ipmap["127.0.0.1"] = struct{}{}
+	defer delete(ipmap, "127.0.0.1")
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "failed", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer backend.Close()
+
+	host, port, err := net.SplitHostPort(backend.Listener.Addr().String())
+	c.Assert(err, IsNil)
+	portNumber, err := strconv.Atoi(port)
+	c.Assert(err, IsNil)
+	exports := NewRoundRobinExports([]registry.ExportDetails{
+		{
+			ExportBinding: service.ExportBinding{Application: "zproxy", PortNumber: uint16(portNumber)},
+			HostIP:        host,
+			PrivateIP:     host,
+			ServiceID:     "serviceid",
+			InstanceID:    2,
+		},
+	}, nil)
+	exports.SetRouting(registry.Routing{AccessLogSample: 0.5})
+
+	var records []RequestRecord
+	SetRequestRecorders(RequestRecorderFunc(func(record RequestRecord) {
+		records = append(records, record)
+	}))
+	defer SetRequestRecorders()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/index.html?a=b", nil)
+	r.Host = "zproxy.example.com"
+	c.Assert(proxyEndpoint(EndpointTypeVHost, "zproxy", false, exports, w, r), Equals, true)
+	w = httptest.NewRecorder()
+	c.Assert(proxyEndpoint(EndpointTypeVHost, "zproxy", false, exports, w, httptest.NewRequest("POST", "/fail", nil)), Equals, true)
+
+	c.Assert(records, HasLen, 2)
+	c.Check(records[0].EndpointType, Equals, EndpointTypeVHost)
+	c.Check(records[0].Endpoint, Equals, "zproxy")
+	c.Check(records[0].Routing.AccessLogSample, Equals, 0.5)
+	c.Check(records[0].ServiceID, Equals, "serviceid")
+	c.Check(records[0].InstanceID, Equals, 2)
+	c.Check(records[0].Application, Equals, "zproxy")
+	c.Check(records[0].Method, Equals, "GET")
+	c.Check(records[0].Host, Equals, "zproxy.example.com")
+	c.Check(records[0].URI, Equals, "/index.html?a=b")
+	c.Check(records[0].Status, Equals, http.StatusOK)
+	c.Check(records[0].Bytes, Equals, int64(5))
+	c.Check(records[1].Method, Equals, "POST")
+	c.Check(records[1].Status, Equals, http.StatusServiceUnavailable)
+
+	// requests that no export can answer are not recorded
+	w = httptest.NewRecorder()
+	c.Check(proxyEndpoint(EndpointTypeVHost, "zproxy", false, NewRoundRobinExports(nil, nil), w, r), Equals, false)
+	c.Check(records, HasLen, 2)
+}
+
+func (s *TestWebSuite) TestAccessLog_Sampling(c *C) {
+	accessLog := NewAccessLog("")
+	random := 0.7
+	accessLog.random = func() float64 { return random }
+
+	record := RequestRecord{Status: http.StatusOK, Routing: registry.Routing{AccessLogSample: 0.5}}
+	accessLog.Record(record)
+	c.Check(accessLog.entries, HasLen, 0)
+
+	// server errors are always logged
+	record.Status = http.StatusBadGateway
+	accessLog.Record(record)
+	c.Check(accessLog.entries, HasLen, 1)
+
+	record.Status = http.StatusOK
+	random = 0.2
+	accessLog.Record(record)
+	c.Check(accessLog.entries, HasLen, 2)
+
+	// no sampling logs every request
+	random = 0.99
+	accessLog.Record(RequestRecord{Status: http.StatusOK})
+	c.Check(accessLog.entries, HasLen, 3)
+
+	accessLog.Record(RequestRecord{Status: http.StatusBadGateway, Routing: registry.Routing{DisableAccessLog: true}})
+	c.Check(accessLog.entries, HasLen, 3)
+}
+
+func (s *TestWebSuite) TestAccessLog_Run(c *C) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer listener.Close()
+
+	lines := make(chan string)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	accessLog := NewAccessLog(listener.Addr().String())
+	shutdown := make(chan interface{})
+	defer close(shutdown)
+	go accessLog.Run(shutdown)
+
+	accessLog.Record(RequestRecord{
+		Time:         time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		EndpointType: EndpointTypePort,
+		Endpoint:     ":22222",
+		ServiceID:    "serviceid",
+		InstanceID:   1,
+		Application:  "zproxy",
+		Method:       "GET",
+		Host:         "example.com:22222",
+		URI:          "/",
+		Proto:        "HTTP/1.1",
+		RemoteAddr:   "192.168.1.5:51234",
+		Status:       http.StatusOK,
+		Bytes:        42,
+		Duration:     1500 * time.Microsecond,
+	})
+
+	var line string
+	select {
+	case line = <-lines:
+	case <-time.After(5 * time.Second):
+		c.Fatal("timed out waiting for the access log entry")
+	}
+
+	var entry accessLogEntry
+	c.Assert(json.Unmarshal([]byte(line), &entry), IsNil)
+	c.Check(entry.Timestamp, Equals, "2026-10-18T12:00:00Z")
+	c.Check(entry.Type, Equals, accessLogType)
+	c.Check(entry.Message, Equals, "192.168.1.5:51234 GET example.com:22222 / HTTP/1.1 200 42 1.500ms")
+	c.Check(entry.Fields.Type, Equals, accessLogType)
+	c.Check(entry.Fields.Service, Equals, "serviceid")
+	c.Check(entry.Fields.Instance, Equals, "1")
+	c.Check(entry.Fields.EndpointType, Equals, EndpointTypePort)
+	c.Check(entry.Fields.Endpoint, Equals, ":22222")
+	c.Check(entry.Fields.Status, Equals, http.StatusOK)
+	c.Check(entry.Fields.DurationMS, Equals, 1.5)
+}
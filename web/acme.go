@@ -0,0 +1,321 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/certificate"
+	"golang.org/x/crypto/acme"
+)
+
+// acmeChallengePath is the url path prefix of http-01 challenges
+const acmeChallengePath = "/.well-known/acme-challenge/"
+
+var (
+	// acmeRetryInterval is how long to wait before retrying a failed
+	// attempt to issue an acme certificate
+	acmeRetryInterval = time.Hour
+
+	// acmeTimeout is how long an attempt to issue an acme certificate may
+	// take
+	acmeTimeout = 5 * time.Minute
+
+	// errNoHTTPChallenge is returned when an authorization does not offer
+	// an http-01 challenge
+	errNoHTTPChallenge = errors.New("no http-01 challenge offered")
+
+	// errInvalidACMEKey is returned when an acme account key is not a P-256
+	// ecdsa key
+	errInvalidACMEKey = errors.New("acme account key is not a P-256 ecdsa key")
+)
+
+// ACMEManager issues and renews the acme certificates of the public
+// endpoints.  It answers the http-01 challenges of the acme server on the
+// vhost http listener, so the domains of the certificates must resolve to
+// the master.
+type ACMEManager struct {
+	client      *acme.Client
+	contact     []string
+	registered  bool
+	renewBefore time.Duration
+	lookup      func() ([]certificate.Certificate, error)
+	save        func(*certificate.Certificate) error
+	certs       *CertificateCache
+	mu          *sync.Mutex
+	tokens      map[string]string
+}
+
+// NewACMEManager creates a manager that loads the certificates with lookup
+// and saves the result of each attempt to issue a certificate with save.
+// Certificates are renewed renewBefore they expire.  If certs is set, it is
+// refreshed when new certificates are issued.
+func NewACMEManager(client *acme.Client, contact []string, renewBefore time.Duration,
+	lookup func() ([]certificate.Certificate, error), save func(*certificate.Certificate) error,
+	certs *CertificateCache) *ACMEManager {
+	return &ACMEManager{
+		client:      client,
+		contact:     contact,
+		renewBefore: renewBefore,
+		lookup:      lookup,
+		save:        save,
+		certs:       certs,
+		mu:          &sync.Mutex{},
+		tokens:      make(map[string]string),
+	}
+}
+
+// Present serves keyAuth for the http-01 challenge token until it is cleaned
+// up.
+func (m *ACMEManager) Present(token, keyAuth string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[token] = keyAuth
+}
+
+// CleanUp stops serving the http-01 challenge token.
+func (m *ACMEManager) CleanUp(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, token)
+}
+
+// HandleChallenge answers a request for an http-01 challenge and returns
+// true, or returns false if the request is not for a challenge.
+func (m *ACMEManager) HandleChallenge(w http.ResponseWriter, r *http.Request) bool {
+	if !strings.HasPrefix(r.URL.Path, acmeChallengePath) {
+		return false
+	}
+	token := strings.TrimPrefix(r.URL.Path, acmeChallengePath)
+	m.mu.Lock()
+	keyAuth, ok := m.tokens[token]
+	m.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return true
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(keyAuth))
+	return true
+}
+
+// Renew issues the acme certificates that are pending or about to expire,
+// and returns the number of certificates that were issued.
+func (m *ACMEManager) Renew() int {
+	certs, err := m.lookup()
+	if err != nil {
+		plog.WithError(err).Warn("Could not load public endpoint certificates for renewal")
+		return 0
+	}
+
+	issued := 0
+	for i := range certs {
+		cert := &certs[i]
+		now := time.Now()
+		if !cert.NeedsRenewal(now, m.renewBefore, acmeRetryInterval) {
+			continue
+		}
+		logger := plog.WithFields(log.Fields{
+			"type":    cert.Type,
+			"name":    cert.Name,
+			"domains": strings.Join(cert.Domains, ","),
+		})
+
+		cert.LastAttempt = now
+		if err := m.issue(cert); err != nil {
+			logger.WithError(err).Warn("Could not issue acme certificate")
+			cert.RenewalStatus = certificate.RenewalFailed
+			cert.RenewalError = err.Error()
+		} else {
+			logger.WithField("notafter", cert.NotAfter).Info("Issued acme certificate")
+			cert.RenewalStatus = certificate.RenewalValid
+			cert.RenewalError = ""
+			issued++
+		}
+		if err := m.save(cert); err != nil {
+			logger.WithError(err).Warn("Could not save acme certificate")
+		}
+	}
+
+	if issued > 0 && m.certs != nil {
+		if err := m.certs.Refresh(); err != nil {
+			plog.WithError(err).Debug("Could not refresh public endpoint certificates")
+		}
+	}
+	return issued
+}
+
+// issue registers the account of the manager if needed and issues a new
+// certificate for the domains of cert.
+func (m *ACMEManager) issue(cert *certificate.Certificate) error {
+	if len(cert.Domains) == 0 {
+		return errors.New("no domains")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), acmeTimeout)
+	defer cancel()
+
+	if !m.registered {
+		_, err := m.client.Register(ctx, &acme.Account{Contact: m.contact}, acme.AcceptTOS)
+		if err != nil && err != acme.ErrAccountAlreadyExists {
+			return err
+		}
+		m.registered = true
+	}
+
+	// prove control of each domain
+	order, err := m.client.AuthorizeOrder(ctx, acme.DomainIDs(cert.Domains...))
+	if err != nil {
+		return err
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err := m.authorize(ctx, authzURL); err != nil {
+			return err
+		}
+	}
+	if order, err = m.client.WaitOrder(ctx, order.URI); err != nil {
+		return err
+	}
+
+	// finalize the order with a request for a certificate with a new key
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: cert.Domains[0]},
+		DNSNames: cert.Domains,
+	}, key)
+	if err != nil {
+		return err
+	}
+	chain, _, err := m.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return err
+	}
+
+	var chainPEM []byte
+	for _, der := range chain {
+		chainPEM = append(chainPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyPEM, err := encodeACMEKey(key)
+	if err != nil {
+		return err
+	}
+	return cert.SetKeyPair(string(chainPEM), string(keyPEM))
+}
+
+// authorize answers the http-01 challenge of an authorization
+func (m *ACMEManager) authorize(ctx context.Context, authzURL string) error {
+	authz, err := m.client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return err
+	} else if authz.Status == acme.StatusValid {
+		return nil
+	}
+	var chal *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "http-01" {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return errNoHTTPChallenge
+	}
+
+	keyAuth, err := m.client.HTTP01ChallengeResponse(chal.Token)
+	if err != nil {
+		return err
+	}
+	m.Present(chal.Token, keyAuth)
+	defer m.CleanUp(chal.Token)
+	if _, err := m.client.Accept(ctx, chal); err != nil {
+		return err
+	}
+	_, err = m.client.WaitAuthorization(ctx, authzURL)
+	return err
+}
+
+// Run renews the acme certificates every interval until shutdown.
+func (m *ACMEManager) Run(shutdown <-chan interface{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.Renew()
+		select {
+		case <-ticker.C:
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// encodeACMEKey returns the pem encoding of a key
+func encodeACMEKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+// loadOrCreateACMEKey reads the acme account key at path, creating it if it
+// does not exist
+func loadOrCreateACMEKey(path string) (*ecdsa.PrivateKey, error) {
+	if data, err := ioutil.ReadFile(path); err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errInvalidACMEKey
+		}
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		} else if key.Curve != elliptic.P256() {
+			return nil, errInvalidACMEKey
+		}
+		return key, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	data, err := encodeACMEKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
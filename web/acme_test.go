@@ -0,0 +1,138 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/domain/certificate"
+	"golang.org/x/crypto/acme"
+	. "gopkg.in/check.v1"
+)
+
+// setupACME starts an acme server that validates challenges against the
+// handler of a manager for a pending acme certificate of the vhost zenoss5.
+// Saved certificates are appended to saved.
+func setupACME(c *C, answer bool, saved *[]certificate.Certificate) (*ACMEManager, *CertificateCache, func()) {
+	certs := []certificate.Certificate{
+		*certificate.NewACME(certificate.TypeVHost, "zenoss5", "svc1", "app", []string{"zenoss5.example.com"}),
+	}
+	lookup := func() ([]certificate.Certificate, error) {
+		result := make([]certificate.Certificate, len(certs))
+		copy(result, certs)
+		return result, nil
+	}
+	save := func(cert *certificate.Certificate) error {
+		cert.UpdatedAt = time.Now()
+		certs[0] = *cert
+		*saved = append(*saved, *cert)
+		return nil
+	}
+	cache := NewCertificateCache(lookup)
+
+	ca := newACMEServer()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	client := &acme.Client{Key: key, DirectoryURL: ca.directoryURL()}
+	mgr := NewACMEManager(client, []string{"mailto:admin@example.com"}, 30*24*time.Hour, lookup, save, cache)
+
+	listener := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if answer && mgr.HandleChallenge(w, r) {
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	ca.challengeAddr = strings.TrimPrefix(listener.URL, "http://")
+	return mgr, cache, func() {
+		listener.Close()
+		ca.close()
+	}
+}
+
+func (s *TestWebSuite) TestACMEManager_Renew(c *C) {
+	var saved []certificate.Certificate
+	mgr, cache, cleanup := setupACME(c, true, &saved)
+	defer cleanup()
+
+	c.Assert(mgr.Renew(), Equals, 1)
+	c.Assert(saved, HasLen, 1)
+	c.Assert(saved[0].RenewalStatus, Equals, certificate.RenewalValid)
+	c.Assert(saved[0].RenewalError, Equals, "")
+	c.Assert(saved[0].Subjects, DeepEquals, []string{"zenoss5.example.com"})
+	c.Assert(saved[0].LastAttempt.IsZero(), Equals, false)
+
+	// the new certificate is served right away
+	cert, err := cache.VHostCertificate(&tls.ClientHelloInfo{ServerName: "zenoss5.example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(commonName(c, cert), Equals, "zenoss5.example.com")
+
+	// valid certificates are not renewed until they are about to expire
+	c.Assert(mgr.Renew(), Equals, 0)
+	c.Assert(saved, HasLen, 1)
+}
+
+func (s *TestWebSuite) TestACMEManager_RenewFailed(c *C) {
+	var saved []certificate.Certificate
+	mgr, _, cleanup := setupACME(c, false, &saved)
+	defer cleanup()
+
+	c.Assert(mgr.Renew(), Equals, 0)
+	c.Assert(saved, HasLen, 1)
+	c.Assert(saved[0].RenewalStatus, Equals, certificate.RenewalFailed)
+	c.Assert(saved[0].RenewalError, Not(Equals), "")
+	c.Assert(saved[0].CertPEM, Equals, "")
+
+	// failed attempts are not retried right away
+	c.Assert(mgr.Renew(), Equals, 0)
+	c.Assert(saved, HasLen, 1)
+}
+
+func (s *TestWebSuite) TestACMEManager_HandleChallenge(c *C) {
+	mgr := NewACMEManager(nil, nil, 0, nil, nil, nil)
+	mgr.Present("token1", "token1.thumbprint")
+
+	w := httptest.NewRecorder()
+	c.Assert(mgr.HandleChallenge(w, httptest.NewRequest("GET", acmeChallengePath+"token1", nil)), Equals, true)
+	c.Assert(w.Code, Equals, http.StatusOK)
+	c.Assert(w.Body.String(), Equals, "token1.thumbprint")
+
+	mgr.CleanUp("token1")
+	w = httptest.NewRecorder()
+	c.Assert(mgr.HandleChallenge(w, httptest.NewRequest("GET", acmeChallengePath+"token1", nil)), Equals, true)
+	c.Assert(w.Code, Equals, http.StatusNotFound)
+
+	w = httptest.NewRecorder()
+	c.Assert(mgr.HandleChallenge(w, httptest.NewRequest("GET", "/", nil)), Equals, false)
+}
+
+func (s *TestWebSuite) TestLoadOrCreateACMEKey(c *C) {
+	path := filepath.Join(c.MkDir(), "acme", "account.key")
+	key, err := loadOrCreateACMEKey(path)
+	c.Assert(err, IsNil)
+
+	// the key is read back from the file
+	loaded, err := loadOrCreateACMEKey(path)
+	c.Assert(err, IsNil)
+	c.Assert(loaded.D.Cmp(key.D), Equals, 0)
+}
@@ -0,0 +1,498 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+type acmeIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type acmeProblem struct {
+	Status int    `json:"status"`
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+type acmeChallenge struct {
+	Type   string       `json:"type"`
+	URL    string       `json:"url"`
+	Token  string       `json:"token"`
+	Status string       `json:"status"`
+	Error  *acmeProblem `json:"error,omitempty"`
+}
+
+type acmeAuthz struct {
+	Status     string          `json:"status"`
+	Identifier acmeIdentifier  `json:"identifier"`
+	Challenges []acmeChallenge `json:"challenges"`
+	account    string
+}
+
+type acmeOrder struct {
+	Status         string           `json:"status"`
+	Identifiers    []acmeIdentifier `json:"identifiers"`
+	Authorizations []string         `json:"authorizations"`
+	Finalize       string           `json:"finalize"`
+	Certificate    string           `json:"certificate,omitempty"`
+	Error          *acmeProblem     `json:"error,omitempty"`
+	authzs         []string
+	names          []string
+}
+
+// acmeServer is an in-memory RFC 8555 server that answers http-01
+// challenges by requesting the key authorization from challengeAddr, with
+// the host header set to the domain being validated.  Every request
+// signature is verified.
+type acmeServer struct {
+	challengeAddr string // host:port of the http-01 responder
+
+	srv      *httptest.Server
+	mu       sync.Mutex
+	caKey    *ecdsa.PrivateKey
+	caCert   *x509.Certificate
+	nonces   map[string]bool
+	accounts map[string]*ecdsa.PublicKey
+	orders   map[string]*acmeOrder
+	authzs   map[string]*acmeAuthz
+	certs    map[string][]byte
+	next     int
+}
+
+// newACMEServer starts an acme server with its own root certificate
+func newACMEServer() *acmeServer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "acme test root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	caCert, _ := x509.ParseCertificate(der)
+
+	s := &acmeServer{
+		caKey:    key,
+		caCert:   caCert,
+		nonces:   make(map[string]bool),
+		accounts: make(map[string]*ecdsa.PublicKey),
+		orders:   make(map[string]*acmeOrder),
+		authzs:   make(map[string]*acmeAuthz),
+		certs:    make(map[string][]byte),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// directoryURL returns the directory url of the server
+func (s *acmeServer) directoryURL() string {
+	return s.url("directory")
+}
+
+func (s *acmeServer) close() {
+	s.srv.Close()
+}
+
+func (s *acmeServer) url(parts ...string) string {
+	return s.srv.URL + "/" + strings.Join(parts, "/")
+}
+
+func (s *acmeServer) newID() string {
+	s.next++
+	return fmt.Sprintf("%d", s.next)
+}
+
+func (s *acmeServer) newNonce(w http.ResponseWriter) {
+	s.mu.Lock()
+	nonce := s.newID()
+	s.nonces[nonce] = true
+	s.mu.Unlock()
+	w.Header().Set("Replay-Nonce", nonce)
+	w.Header().Set("Cache-Control", "no-store")
+}
+
+func acmeError(w http.ResponseWriter, status int, errType, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(acmeProblem{Status: status, Type: "urn:ietf:params:acme:error:" + errType, Detail: detail})
+}
+
+func acmeReply(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *acmeServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.newNonce(w)
+	path := strings.Trim(r.URL.Path, "/")
+	if path == "directory" {
+		acmeReply(w, http.StatusOK, map[string]string{
+			"newNonce":   s.url("nonce"),
+			"newAccount": s.url("account"),
+			"newOrder":   s.url("order"),
+		})
+		return
+	} else if path == "nonce" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != "POST" {
+		acmeError(w, http.StatusMethodNotAllowed, "malformed", "method not allowed")
+		return
+	}
+
+	account, payload, err := s.verify(r)
+	if err == errACMEBadNonce {
+		acmeError(w, http.StatusBadRequest, "badNonce", err.Error())
+		return
+	} else if err != nil {
+		acmeError(w, http.StatusUnauthorized, "unauthorized", err.Error())
+		return
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	id := ""
+	if len(parts) == 2 {
+		id = parts[1]
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch parts[0] {
+	case "account":
+		w.Header().Set("Location", account)
+		acmeReply(w, http.StatusCreated, map[string]string{"status": acme.StatusValid})
+	case "order":
+		if id == "" {
+			s.newOrder(w, account, payload)
+		} else if o, ok := s.orders[id]; ok {
+			acmeReply(w, http.StatusOK, o)
+		} else {
+			acmeError(w, http.StatusNotFound, "malformed", "no such order")
+		}
+	case "authz":
+		if a, ok := s.authzs[id]; ok && a.account == account {
+			acmeReply(w, http.StatusOK, a)
+		} else {
+			acmeError(w, http.StatusNotFound, "malformed", "no such authorization")
+		}
+	case "chall":
+		s.validate(w, account, id)
+	case "finalize":
+		s.finalize(w, id, payload)
+	case "cert":
+		if chain, ok := s.certs[id]; ok {
+			w.Header().Set("Content-Type", "application/pem-certificate-chain")
+			w.Write(chain)
+		} else {
+			acmeError(w, http.StatusNotFound, "malformed", "no such certificate")
+		}
+	default:
+		acmeError(w, http.StatusNotFound, "malformed", "not found")
+	}
+}
+
+var errACMEBadNonce = errors.New("invalid nonce")
+
+// verify checks the ES256 signature, nonce and url of a request and returns
+// the account url and payload.  Accounts are created for the jwk of a
+// new-account request.
+func (s *acmeServer) verify(r *http.Request) (string, []byte, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	jws := struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+		Signature string `json:"signature"`
+	}{}
+	if err := json.Unmarshal(body, &jws); err != nil {
+		return "", nil, err
+	}
+	data, err := base64.RawURLEncoding.DecodeString(jws.Protected)
+	if err != nil {
+		return "", nil, err
+	}
+	header := struct {
+		Alg   string `json:"alg"`
+		Nonce string `json:"nonce"`
+		URL   string `json:"url"`
+		KID   string `json:"kid"`
+		JWK   *struct {
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"jwk"`
+	}{}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return "", nil, err
+	} else if header.Alg != "ES256" {
+		return "", nil, fmt.Errorf("unsupported algorithm %s", header.Alg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.nonces[header.Nonce] {
+		return "", nil, errACMEBadNonce
+	}
+	delete(s.nonces, header.Nonce)
+	if header.URL != s.srv.URL+r.URL.Path {
+		return "", nil, fmt.Errorf("url %s does not match request", header.URL)
+	}
+
+	var account string
+	var pub *ecdsa.PublicKey
+	if header.JWK != nil {
+		if r.URL.Path != "/account" {
+			return "", nil, errors.New("jwk is only allowed for new accounts")
+		} else if header.JWK.Kty != "EC" || header.JWK.Crv != "P-256" {
+			return "", nil, errors.New("jwk is not a P-256 key")
+		}
+		x, err := base64.RawURLEncoding.DecodeString(header.JWK.X)
+		if err != nil {
+			return "", nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(header.JWK.Y)
+		if err != nil {
+			return "", nil, err
+		}
+		pub = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		thumbprint, err := acme.JWKThumbprint(pub)
+		if err != nil {
+			return "", nil, err
+		}
+		account = s.url("account", thumbprint)
+		s.accounts[account] = pub
+	} else if pub = s.accounts[header.KID]; pub == nil {
+		return "", nil, fmt.Errorf("no such account %s", header.KID)
+	} else {
+		account = header.KID
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(jws.Signature)
+	if err != nil || len(sig) != 64 {
+		return "", nil, errors.New("invalid signature")
+	}
+	digest := sha256.Sum256([]byte(jws.Protected + "." + jws.Payload))
+	if !ecdsa.Verify(pub, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+		return "", nil, errors.New("invalid signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return "", nil, err
+	}
+	return account, payload, nil
+}
+
+func (s *acmeServer) newOrder(w http.ResponseWriter, account string, payload []byte) {
+	req := struct {
+		Identifiers []acmeIdentifier `json:"identifiers"`
+	}{}
+	if err := json.Unmarshal(payload, &req); err != nil || len(req.Identifiers) == 0 {
+		acmeError(w, http.StatusBadRequest, "malformed", "no identifiers")
+		return
+	}
+	id := s.newID()
+	o := &acmeOrder{
+		Status:      acme.StatusPending,
+		Identifiers: req.Identifiers,
+		Finalize:    s.url("finalize", id),
+	}
+	for _, ident := range req.Identifiers {
+		authzID := s.newID()
+		s.authzs[authzID] = &acmeAuthz{
+			account:    account,
+			Status:     acme.StatusPending,
+			Identifier: ident,
+			Challenges: []acmeChallenge{{
+				Type:   "http-01",
+				URL:    s.url("chall", authzID),
+				Token:  base64.RawURLEncoding.EncodeToString([]byte(s.newID() + "-" + ident.Value)),
+				Status: acme.StatusPending,
+			}},
+		}
+		o.authzs = append(o.authzs, authzID)
+		o.names = append(o.names, ident.Value)
+		o.Authorizations = append(o.Authorizations, s.url("authz", authzID))
+	}
+	s.orders[id] = o
+	w.Header().Set("Location", s.url("order", id))
+	acmeReply(w, http.StatusCreated, o)
+}
+
+// validate requests the key authorization of the challenge from the
+// responder and updates the authorization and its orders
+func (s *acmeServer) validate(w http.ResponseWriter, account, id string) {
+	a, ok := s.authzs[id]
+	if !ok || a.account != account {
+		acmeError(w, http.StatusNotFound, "malformed", "no such challenge")
+		return
+	}
+	chal := &a.Challenges[0]
+	thumbprint, err := acme.JWKThumbprint(s.accounts[account])
+	if err != nil {
+		acmeError(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+	expected := chal.Token + "." + thumbprint
+
+	// the responder may call back into the server, so don't hold the lock
+	s.mu.Unlock()
+	got, err := s.fetch(a.Identifier.Value, chal.Token)
+	s.mu.Lock()
+
+	if err == nil && got != expected {
+		err = fmt.Errorf("key authorization %q does not match %q", got, expected)
+	}
+	if err != nil {
+		chal.Status, a.Status = acme.StatusInvalid, acme.StatusInvalid
+		chal.Error = &acmeProblem{Status: http.StatusForbidden, Type: "urn:ietf:params:acme:error:unauthorized", Detail: err.Error()}
+	} else {
+		chal.Status, a.Status = acme.StatusValid, acme.StatusValid
+	}
+
+	for _, o := range s.orders {
+		if o.Status != acme.StatusPending {
+			continue
+		}
+		ready := true
+		for _, authzID := range o.authzs {
+			switch s.authzs[authzID].Status {
+			case acme.StatusInvalid:
+				o.Status = acme.StatusInvalid
+				o.Error = chal.Error
+			case acme.StatusPending:
+				ready = false
+			}
+		}
+		if ready && o.Status == acme.StatusPending {
+			o.Status = acme.StatusReady
+		}
+	}
+	acmeReply(w, http.StatusOK, chal)
+}
+
+func (s *acmeServer) fetch(domain, token string) (string, error) {
+	if s.challengeAddr == "" {
+		return "", errors.New("no challenge responder")
+	}
+	req, err := http.NewRequest("GET", "http://"+s.challengeAddr+acmeChallengePath+token, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Host = domain
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("challenge responder returned %s", resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	return strings.TrimSpace(string(body)), err
+}
+
+func (s *acmeServer) finalize(w http.ResponseWriter, id string, payload []byte) {
+	o, ok := s.orders[id]
+	if !ok {
+		acmeError(w, http.StatusNotFound, "malformed", "no such order")
+		return
+	} else if o.Status != acme.StatusReady {
+		acmeError(w, http.StatusForbidden, "orderNotReady", "order is "+o.Status)
+		return
+	}
+	req := struct {
+		CSR string `json:"csr"`
+	}{}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		acmeError(w, http.StatusBadRequest, "malformed", err.Error())
+		return
+	}
+	der, err := base64.RawURLEncoding.DecodeString(req.CSR)
+	if err != nil {
+		acmeError(w, http.StatusBadRequest, "badCSR", err.Error())
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+	if err != nil {
+		acmeError(w, http.StatusBadRequest, "badCSR", err.Error())
+		return
+	}
+	if strings.Join(csr.DNSNames, ",") != strings.Join(o.names, ",") {
+		acmeError(w, http.StatusBadRequest, "badCSR", "names do not match the order")
+		return
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(s.next + 1000)),
+		Subject:      pkix.Name{CommonName: o.names[0]},
+		DNSNames:     o.names,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leaf, err := x509.CreateCertificate(rand.Reader, template, s.caCert, csr.PublicKey, s.caKey)
+	if err != nil {
+		acmeError(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf})
+	chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.caCert.Raw})...)
+	s.certs[id] = chain
+	o.Status = acme.StatusValid
+	o.Certificate = s.url("cert", id)
+	w.Header().Set("Location", s.url("order", id))
+	acmeReply(w, http.StatusOK, o)
+}
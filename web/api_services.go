@@ -102,6 +102,41 @@ func getChildServiceDetails(w *rest.ResponseWriter, r *rest.Request, c *requestC
 	w.WriteJson(details)
 }
 
+// getServiceTraffic returns the dependency map of a service: the traffic to
+// the services it imports endpoints from and from the services importing its
+// endpoints, averaged over the last hour or the "since" milliseconds.
+func getServiceTraffic(w *rest.ResponseWriter, r *rest.Request, c *requestContext) {
+	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return
+	} else if len(serviceID) == 0 {
+		writeJSON(w, "serviceId must be specified", http.StatusBadRequest)
+		return
+	}
+
+	tsince, err := getSinceParameter(r)
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return
+	} else if tsince <= 0 {
+		tsince = time.Hour
+	}
+
+	ctx := c.getDatastoreContext()
+	traffic, err := c.getFacade().GetServiceTraffic(ctx, serviceID, tsince)
+	if datastore.IsErrNoSuchEntity(err) {
+		msg := fmt.Sprintf("Service %v Not Found", serviceID)
+		writeJSON(w, msg, http.StatusNotFound)
+		return
+	} else if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	w.WriteJson(traffic)
+}
+
 func getServiceContext(w *rest.ResponseWriter, r *rest.Request, c *requestContext) {
 	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
 	if err != nil {
@@ -22,6 +22,7 @@ import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/metrics"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
@@ -209,6 +210,46 @@ func (s *TestWebSuite) TestRestGetServiceDetailsShouldReturnStatusNotFoundIfNoSe
 	c.Assert(s.recorder.Code, Equals, http.StatusNotFound)
 }
 
+func (s *TestWebSuite) TestRestGetServiceTrafficShouldDefaultToAnHour(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/services/firstservice/traffic", "")
+	request.PathParams["serviceId"] = "firstservice"
+
+	s.mockFacade.
+		On("GetServiceTraffic", s.ctx.getDatastoreContext(), "firstservice", time.Hour).
+		Return(&metrics.ServiceTraffic{ServiceID: "firstservice"}, nil)
+
+	getServiceTraffic(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+}
+
+func (s *TestWebSuite) TestRestGetServiceTrafficShouldUseSince(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/services/firstservice/traffic?since=60000", "")
+	request.PathParams["serviceId"] = "firstservice"
+
+	s.mockFacade.
+		On("GetServiceTraffic", s.ctx.getDatastoreContext(), "firstservice", time.Minute).
+		Return(&metrics.ServiceTraffic{ServiceID: "firstservice"}, nil)
+
+	getServiceTraffic(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+}
+
+func (s *TestWebSuite) TestRestGetServiceTrafficShouldReturnStatusNotFoundIfNoService(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/services/firstservice/traffic", "")
+	request.PathParams["serviceId"] = "firstservice"
+
+	expectedError := datastore.ErrNoSuchEntity{Key: datastore.NewKey("service", "firstservice")}
+	s.mockFacade.
+		On("GetServiceTraffic", s.ctx.getDatastoreContext(), "firstservice", time.Hour).
+		Return(nil, expectedError)
+
+	getServiceTraffic(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusNotFound)
+}
+
 func (s *TestWebSuite) TestRestGetAllServiceDetailsShouldReturnStatusOK(c *C) {
 	s.mockFacade.On("QueryServiceDetails",
 		mock.Anything,
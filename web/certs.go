@@ -0,0 +1,138 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"crypto/tls"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+// certificateItem is a parsed public endpoint certificate
+type certificateItem struct {
+	updated time.Time
+	cert    *tls.Certificate
+}
+
+// CertificateCache keeps the parsed certificates of the public endpoints, so
+// that tls handshakes can pick the certificate for a vhost or port without
+// looking it up.  Certificates that are not set fall back to the default
+// certificate of the listener.
+type CertificateCache struct {
+	mu     *sync.RWMutex
+	lookup func() ([]certificate.Certificate, error)
+	data   map[string]certificateItem
+}
+
+// NewCertificateCache creates a certificate cache that loads the
+// certificates with lookup.
+func NewCertificateCache(lookup func() ([]certificate.Certificate, error)) *CertificateCache {
+	return &CertificateCache{
+		mu:     &sync.RWMutex{},
+		lookup: lookup,
+		data:   make(map[string]certificateItem),
+	}
+}
+
+// Refresh reloads the certificates, parsing only the ones that were added or
+// updated since the last refresh.
+func (c *CertificateCache) Refresh() error {
+	certs, err := c.lookup()
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	data := make(map[string]certificateItem)
+	for _, cert := range certs {
+		logger := plog.WithFields(log.Fields{
+			"type": cert.Type,
+			"name": cert.Name,
+		})
+		if item, ok := c.data[cert.ID]; ok && item.updated.Equal(cert.UpdatedAt) {
+			data[cert.ID] = item
+			continue
+		}
+		pair, err := cert.KeyPair()
+		if err != nil {
+			logger.WithError(err).Warn("Could not load public endpoint certificate")
+			continue
+		}
+		data[cert.ID] = certificateItem{updated: cert.UpdatedAt, cert: &pair}
+		logger.WithField("notafter", cert.NotAfter).Info("Loaded public endpoint certificate")
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	c.data = data
+	c.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the certificates every interval until shutdown.
+func (c *CertificateCache) Run(shutdown <-chan interface{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Refresh(); err != nil {
+			plog.WithError(err).Debug("Could not refresh public endpoint certificates")
+		}
+		select {
+		case <-ticker.C:
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+func (c *CertificateCache) get(certType, name string) *tls.Certificate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.data[certificate.ID(certType, name)].cert
+}
+
+// VHostCertificate implements tls.Config.GetCertificate.  For a server name
+// of "xyz.domain.com" it returns the certificate of either the
+// "xyz.domain.com" or "xyz" vhost, like the vhost manager.
+func (c *CertificateCache) VHostCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.ToLower(hello.ServerName)
+	if name == "" {
+		return nil, nil
+	}
+	if cert := c.get(certificate.TypeVHost, name); cert != nil {
+		return cert, nil
+	}
+	subdomain := strings.Split(name, ".")[0]
+	return c.get(certificate.TypeVHost, subdomain), nil
+}
+
+// PortCertificate returns a tls.Config.GetCertificate function for a port
+// public endpoint.  It prefers the certificate of the port, then the
+// certificate of a vhost matching the server name, then the default
+// certificate, so that clients without SNI still get the port certificate.
+func (c *CertificateCache) PortCertificate(portAddr string, defaultCert *tls.Certificate) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if cert := c.get(certificate.TypePort, portAddr); cert != nil {
+			return cert, nil
+		}
+		if cert, _ := c.VHostCertificate(hello); cert != nil {
+			return cert, nil
+		}
+		return defaultCert, nil
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/control-center/serviced/domain/certificate"
+	. "gopkg.in/check.v1"
+)
+
+// testCertificate creates a self-signed public endpoint certificate for a
+// common name.
+func testCertificate(c *C, certType, name, commonName string) certificate.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+
+	cert, err := certificate.New(certType, name, "svc1", "app",
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+	c.Assert(err, IsNil)
+	cert.UpdatedAt = time.Now()
+	return *cert
+}
+
+func commonName(c *C, cert *tls.Certificate) string {
+	c.Assert(cert, NotNil)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	c.Assert(err, IsNil)
+	return leaf.Subject.CommonName
+}
+
+func (s *TestWebSuite) TestCertificateCache_VHost(c *C) {
+	certs := []certificate.Certificate{
+		testCertificate(c, certificate.TypeVHost, "zenoss5", "zenoss5.example.com"),
+		testCertificate(c, certificate.TypeVHost, "other.example.com", "other.example.com"),
+	}
+	cache := NewCertificateCache(func() ([]certificate.Certificate, error) { return certs, nil })
+	c.Assert(cache.Refresh(), IsNil)
+
+	cert, err := cache.VHostCertificate(&tls.ClientHelloInfo{ServerName: "zenoss5.example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(commonName(c, cert), Equals, "zenoss5.example.com")
+
+	cert, err = cache.VHostCertificate(&tls.ClientHelloInfo{ServerName: "Other.Example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(commonName(c, cert), Equals, "other.example.com")
+
+	// unknown and missing server names use the default certificate
+	cert, err = cache.VHostCertificate(&tls.ClientHelloInfo{ServerName: "unknown.example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(cert, IsNil)
+	cert, err = cache.VHostCertificate(&tls.ClientHelloInfo{})
+	c.Assert(err, IsNil)
+	c.Assert(cert, IsNil)
+}
+
+func (s *TestWebSuite) TestCertificateCache_Port(c *C) {
+	certs := []certificate.Certificate{
+		testCertificate(c, certificate.TypePort, ":2222", "port.example.com"),
+		testCertificate(c, certificate.TypeVHost, "zenoss5", "zenoss5.example.com"),
+	}
+	cache := NewCertificateCache(func() ([]certificate.Certificate, error) { return certs, nil })
+	c.Assert(cache.Refresh(), IsNil)
+	defaultCert := &tls.Certificate{}
+
+	cert, err := cache.PortCertificate(":2222", defaultCert)(&tls.ClientHelloInfo{ServerName: "zenoss5.example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(commonName(c, cert), Equals, "port.example.com")
+
+	cert, err = cache.PortCertificate(":3333", defaultCert)(&tls.ClientHelloInfo{ServerName: "zenoss5.example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(commonName(c, cert), Equals, "zenoss5.example.com")
+
+	cert, err = cache.PortCertificate(":3333", defaultCert)(&tls.ClientHelloInfo{})
+	c.Assert(err, IsNil)
+	c.Assert(cert, Equals, defaultCert)
+}
+
+func (s *TestWebSuite) TestCertificateCache_Refresh(c *C) {
+	certs := []certificate.Certificate{testCertificate(c, certificate.TypeVHost, "zenoss5", "first.example.com")}
+	var lookupErr error
+	cache := NewCertificateCache(func() ([]certificate.Certificate, error) { return certs, lookupErr })
+	hello := &tls.ClientHelloInfo{ServerName: "zenoss5"}
+
+	c.Assert(cache.Refresh(), IsNil)
+	cert, _ := cache.VHostCertificate(hello)
+	c.Assert(commonName(c, cert), Equals, "first.example.com")
+
+	// an updated certificate is reloaded
+	certs = []certificate.Certificate{testCertificate(c, certificate.TypeVHost, "zenoss5", "second.example.com")}
+	certs[0].UpdatedAt = certs[0].UpdatedAt.Add(time.Second)
+	c.Assert(cache.Refresh(), IsNil)
+	cert, _ = cache.VHostCertificate(hello)
+	c.Assert(commonName(c, cert), Equals, "second.example.com")
+
+	// a failed lookup keeps the loaded certificates
+	lookupErr = errors.New("lookup failed")
+	c.Assert(cache.Refresh(), NotNil)
+	cert, _ = cache.VHostCertificate(hello)
+	c.Assert(commonName(c, cert), Equals, "second.example.com")
+
+	// a certificate that cannot be parsed is skipped
+	lookupErr = nil
+	certs[0].KeyPEM = "invalid"
+	certs[0].UpdatedAt = certs[0].UpdatedAt.Add(time.Second)
+	c.Assert(cache.Refresh(), IsNil)
+	cert, _ = cache.VHostCertificate(hello)
+	c.Assert(cert, IsNil)
+
+	// a removed certificate is dropped
+	certs = []certificate.Certificate{}
+	c.Assert(cache.Refresh(), IsNil)
+	cert, _ = cache.VHostCertificate(hello)
+	c.Assert(cert, IsNil)
+}
+
+func (s *TestWebSuite) TestCertificateCache_Handshake(c *C) {
+	vhost := testCertificate(c, certificate.TypeVHost, "zenoss5", "zenoss5.example.com")
+	fallback := testCertificate(c, certificate.TypeVHost, "default", "default.example.com")
+	defaultCert, err := fallback.KeyPair()
+	c.Assert(err, IsNil)
+
+	cache := NewCertificateCache(func() ([]certificate.Certificate, error) { return []certificate.Certificate{vhost}, nil })
+	c.Assert(cache.Refresh(), IsNil)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates:   []tls.Certificate{defaultCert},
+		GetCertificate: cache.VHostCertificate,
+	})
+	c.Assert(err, IsNil)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	served := func(serverName string) string {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{ServerName: serverName, InsecureSkipVerify: true})
+		c.Assert(err, IsNil)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+	c.Assert(served("zenoss5.example.com"), Equals, "zenoss5.example.com")
+	c.Assert(served("unknown.example.com"), Equals, "default.example.com")
+}
@@ -25,18 +25,22 @@ import (
 	"os/exec"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/config"
 	daoclient "github.com/control-center/serviced/dao/client"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/zzk"
 	"github.com/control-center/serviced/zzk/registry"
 	"github.com/zenoss/go-json-rest"
+	"golang.org/x/crypto/acme"
 )
 
 // UIConfig contains configuration values
@@ -59,6 +63,9 @@ type ServiceConfig struct {
 	uiConfig    UIConfig
 	facade      facade.FacadeInterface
 	vhostmgr    *VHostManager
+	health      *ServiceHealthCache
+	certs       *CertificateCache
+	acme        *ACMEManager
 }
 
 // Auth0Config contains configuration values pertaining to Auth0
@@ -70,6 +77,14 @@ type Auth0Config struct {
 }
 
 var defaultHostAlias string
+
+// certificateRefreshInterval is how often the public endpoint certificates
+// are reloaded
+var certificateRefreshInterval = 15 * time.Second
+
+// acmeRenewInterval is how often the acme certificates are checked for
+// renewal
+var acmeRenewInterval = time.Minute
 var uiConfig UIConfig
 
 // NewServiceConfig creates a new ServiceConfig
@@ -99,6 +114,12 @@ func NewServiceConfig(bindPort string, agentPort string, stats bool, hostaliases
 		uiConfig:    uiCfg,
 		facade:      facade,
 	}
+	cfg.health = NewServiceHealthCache(5*time.Second, func(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+		return cfg.facade.GetServiceHealth(datastore.Get(), serviceID)
+	})
+	cfg.certs = NewCertificateCache(func() ([]certificate.Certificate, error) {
+		return cfg.facade.GetPublicEndpointCertificates(datastore.Get())
+	})
 
 	hostAddrs, err := utils.GetIPv4Addresses()
 	if err != nil {
@@ -116,6 +137,30 @@ func NewServiceConfig(bindPort string, agentPort string, stats bool, hostaliases
 	return &cfg
 }
 
+// SetACME enables the issuance and renewal of acme certificates for the
+// public endpoints with the acme server at directoryURL.  The account key is
+// created in accountKeyFile if it does not exist.
+func (sc *ServiceConfig) SetACME(directoryURL, email, accountKeyFile string, renewDays int) error {
+	key, err := loadOrCreateACMEKey(accountKeyFile)
+	if err != nil {
+		return err
+	}
+	var contact []string
+	if email != "" {
+		contact = []string{"mailto:" + email}
+	}
+	client := &acme.Client{Key: key, DirectoryURL: directoryURL}
+	sc.acme = NewACMEManager(client, contact, time.Duration(renewDays)*24*time.Hour,
+		func() ([]certificate.Certificate, error) {
+			return sc.facade.GetPublicEndpointCertificates(datastore.Get())
+		},
+		func(cert *certificate.Certificate) error {
+			return sc.facade.UpdatePublicEndpointCertificate(datastore.Get(), cert)
+		},
+		sc.certs)
+	return nil
+}
+
 // borrowed from gorilla mux, which was cleaning the public endpoint urls.
 func cleanPath(p string) string {
 	if p == "" {
@@ -140,6 +185,15 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 	logger := plog.WithField("bindport", sc.bindPort)
 	logger.Debug("Starting vhost synching")
 
+	// keep the public endpoint certificates up to date, so that new
+	// certificates are served without a restart
+	go sc.certs.Run(shutdown, certificateRefreshInterval)
+
+	// issue and renew the acme certificates of the public endpoints
+	if sc.acme != nil {
+		go sc.acme.Run(shutdown, acmeRenewInterval)
+	}
+
 	// start public port listener
 	sc.startPublicPortListener(shutdown)
 
@@ -226,6 +280,10 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 
 	go func() {
 		redirect := func(w http.ResponseWriter, req *http.Request) {
+			// answer the http-01 challenges of the acme server
+			if sc.acme != nil && sc.acme.HandleChallenge(w, req) {
+				return
+			}
 			// bindPort has already been validated, so the Split/access below won't break.
 			http.Redirect(w, req, fmt.Sprintf("https://%s:%s%s", req.Host, strings.Split(sc.bindPort, ":")[1], req.URL), http.StatusMovedPermanently)
 		}
@@ -242,6 +300,7 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 			MinVersion:               utils.MinTLS("http"),
 			PreferServerCipherSuites: true,
 			CipherSuites:             utils.CipherSuites("http"),
+			GetCertificate:           sc.certs.VHostCertificate,
 		}
 		server := &http.Server{Addr: sc.bindPort, TLSConfig: config, Handler: http.HandlerFunc(httphandler)}
 		logger.WithField("ciphersuite", utils.CipherSuitesByName(config)).Info("Creating HTTP server")
@@ -441,7 +500,7 @@ type getRoutes func(sc *ServiceConfig) []rest.Route
 // changes in state
 func (sc *ServiceConfig) startPublicPortListener(shutdown <-chan interface{}) {
 	// set up the public port manager
-	pubmgr := NewPublicPortManager("", sc.certPEMFile, sc.keyPEMFile, func(portAddress string, err error) {
+	pubmgr := NewPublicPortManager("", sc.certPEMFile, sc.keyPEMFile, sc.certs, sc.health.IsHealthy, func(portAddress string, err error) {
 		logger := plog.WithField("portaddress", portAddress).WithError(err)
 
 		// connect to zookeeper
@@ -497,7 +556,7 @@ func (sc *ServiceConfig) startPublicPortListener(shutdown <-chan interface{}) {
 // startVHostListener manages proxies for all vhosts
 func (sc *ServiceConfig) startVHostListener(shutdown <-chan interface{}) {
 	// set up the vhost manager
-	sc.vhostmgr = NewVHostManager(sc.muxTLS)
+	sc.vhostmgr = NewVHostManager(sc.muxTLS, sc.health.IsHealthy)
 
 	// set up the vhost listener
 	listener := registry.NewVHostListener("master", sc.vhostmgr)
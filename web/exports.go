@@ -14,10 +14,13 @@
 package web
 
 import (
+	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/zzk/registry"
 )
 
@@ -29,19 +32,44 @@ func init() {
 type Exports interface {
 	Set(data []registry.ExportDetails)
 	Next() *registry.ExportDetails
+	SetRouting(routing registry.Routing)
+	Routing() registry.Routing
+	Pick(clientKey string, tried map[string]bool) *registry.ExportDetails
+}
+
+// HealthFunc returns false if an instance of a service is failing its health
+// checks
+type HealthFunc func(serviceID string, instanceID int) bool
+
+// ExportKey identifies an export across updates to the export list
+func ExportKey(export *registry.ExportDetails) string {
+	return fmt.Sprintf("%s/%s:%d", export.HostIP, export.PrivateIP, export.PortNumber)
+}
+
+// AffinityID identifies an export in an affinity cookie without giving away
+// its address
+func AffinityID(export *registry.ExportDetails) string {
+	h := fnv.New64a()
+	h.Write([]byte(ExportKey(export)))
+	return fmt.Sprintf("%x", h.Sum64())
 }
 
 // RoundRobinExports returns the next export in a round-robin manner
 type RoundRobinExports struct {
-	mu   *sync.Mutex
-	xid  int
-	data []registry.ExportDetails
+	mu      *sync.Mutex
+	xid     int
+	version int // changes whenever data is replaced
+	data    []registry.ExportDetails
+	healthy HealthFunc
+	routing registry.Routing
 }
 
-// NewRoundRobinExports creates a new round robin list of exports
-func NewRoundRobinExports(data []registry.ExportDetails) *RoundRobinExports {
+// NewRoundRobinExports creates a new round robin list of exports.  Exports
+// that are not healthy are skipped while a healthy export is available.
+func NewRoundRobinExports(data []registry.ExportDetails, healthy HealthFunc) *RoundRobinExports {
 	e := &RoundRobinExports{
-		mu: &sync.Mutex{},
+		mu:      &sync.Mutex{},
+		healthy: healthy,
 	}
 	e.set(data)
 	return e
@@ -60,6 +88,7 @@ func (e *RoundRobinExports) set(data []registry.ExportDetails) {
 
 	// reset the counter
 	e.xid = 0
+	e.version++
 
 	// randomize the exports
 	e.data = make([]registry.ExportDetails, len(data))
@@ -68,16 +97,105 @@ func (e *RoundRobinExports) set(data []registry.ExportDetails) {
 	}
 }
 
+// SetRouting updates how clients are routed to the exports
+func (e *RoundRobinExports) SetRouting(routing registry.Routing) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.routing = routing
+}
+
+// Routing returns how clients are routed to the exports
+func (e *RoundRobinExports) Routing() registry.Routing {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.routing
+}
+
 // Next returns the next available export
 func (e *RoundRobinExports) Next() *registry.ExportDetails {
+	return e.Pick("", nil)
+}
+
+// Pick returns the export for a client, skipping the exports that have
+// already been tried.  With cookie affinity the client key is the affinity id
+// from the client's cookie, and with source ip affinity it is the client's
+// address; otherwise the exports are picked in turn.
+func (e *RoundRobinExports) Pick(clientKey string, tried map[string]bool) *registry.ExportDetails {
+	// the data is replaced rather than changed, so the health of the exports
+	// can be checked without holding the lock while the master is asked
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	data, routing, version := e.data, e.routing, e.version
+	e.mu.Unlock()
+
+	var healthy, untried []int
+	for i := range data {
+		if tried[ExportKey(&data[i])] {
+			continue
+		}
+		untried = append(untried, i)
+		if e.healthy == nil || e.healthy(data[i].ServiceID, data[i].InstanceID) {
+			healthy = append(healthy, i)
+		}
+	}
+
+	// fall back to the unhealthy exports rather than failing the request
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = untried
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if clientKey != "" {
+		switch routing.Affinity {
+		case servicedefinition.AffinityCookie:
+			for _, i := range candidates {
+				if AffinityID(&data[i]) == clientKey {
+					dat := data[i]
+					return &dat
+				}
+			}
+		case servicedefinition.AffinitySourceIP:
+			// rendezvous hashing, so a client only moves when its export
+			// goes away
+			var best int
+			var max uint32
+			for n, i := range candidates {
+				if score := hashScore(clientKey, ExportKey(&data[i])); n == 0 || score > max {
+					best, max = i, score
+				}
+			}
+			dat := data[best]
+			return &dat
+		}
+	}
 
-	// make sure there is data to submit
-	if size := len(e.data); size > 0 {
-		dat := e.data[e.xid]
-		e.xid = (e.xid + 1) % size
-		return &dat
+	// take the next candidate at or after the counter
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	xid := 0
+	if e.version == version {
+		xid = e.xid
+	}
+	i := candidates[0]
+	for _, c := range candidates {
+		if c >= xid {
+			i = c
+			break
+		}
 	}
-	return nil
+	if e.version == version {
+		e.xid = (i + 1) % len(data)
+	}
+	dat := data[i]
+	return &dat
+}
+
+func hashScore(clientKey, exportKey string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(clientKey))
+	h.Write([]byte{0})
+	h.Write([]byte(exportKey))
+	return h.Sum32()
 }
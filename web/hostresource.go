@@ -159,8 +159,13 @@ func restGetServiceMonitoringProfile(w *rest.ResponseWriter, r *rest.Request, ct
 	if svc, err := facade.GetService(dataCtx, serviceID); err != nil {
 		restServerError(w, err)
 		return
-	} else if svc.Instances > 0 {
-		mp.GraphConfigs = append(mp.GraphConfigs, getInternalGraphConfigs(serviceID)...)
+	} else {
+		if svc.Instances > 0 {
+			mp.GraphConfigs = append(mp.GraphConfigs, getInternalGraphConfigs(serviceID)...)
+		}
+		if hasPublicEndpoints(svc) {
+			mp.GraphConfigs = append(mp.GraphConfigs, getPublicEndpointGraphConfigs(serviceID)...)
+		}
 	}
 
 	// we want to try to include monitoring data for the tenant, as well
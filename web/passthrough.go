@@ -0,0 +1,126 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// clientHelloTimeout is how long a client of a tls-passthrough port has to
+// send its tls client hello
+var clientHelloTimeout = 10 * time.Second
+
+// errClientHelloRead stops the handshake once the client hello is read
+var errClientHelloRead = errors.New("read tls client hello")
+
+// ServeTLSPassthrough proxies tls connections to exports that terminate tls
+// themselves.  If the routing names servers, connections that ask for
+// another server are closed.
+func ServeTLSPassthrough(cancel <-chan struct{}, listener net.Listener, exports Exports, policy *EndpointPolicy) {
+	serveStream(cancel, listener, exports, policy, func(local net.Conn) (net.Conn, bool) {
+		logger := plog.WithField("client", local.RemoteAddr().String())
+
+		name, local, err := peekServerName(local)
+		if err != nil {
+			logger.WithError(err).Debug("Could not read tls client hello")
+			return local, false
+		}
+		if names := exports.Routing().ServerNames; len(names) > 0 && !matchServerName(names, name) {
+			logger.WithFields(log.Fields{
+				"servername":  name,
+				"servernames": names,
+			}).Debug("Refused tls connection for another server")
+			return local, false
+		}
+		return local, true
+	})
+}
+
+// peekServerName reads the server name (SNI) from the tls client hello of the
+// connection without terminating tls.  The returned connection reads the
+// client hello again before the rest of the stream.
+func peekServerName(conn net.Conn) (string, net.Conn, error) {
+	hello := &bytes.Buffer{}
+	sniff := &helloConn{Conn: conn, reader: io.TeeReader(conn, hello)}
+
+	var name string
+	var found bool
+	conn.SetReadDeadline(time.Now().Add(clientHelloTimeout))
+	err := tls.Server(sniff, &tls.Config{
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			name, found = info.ServerName, true
+			return nil, errClientHelloRead
+		},
+	}).Handshake()
+	conn.SetReadDeadline(time.Time{})
+
+	replay := &replayConn{Conn: conn, reader: io.MultiReader(hello, conn)}
+	if !found {
+		return "", replay, err
+	}
+	return name, replay, nil
+}
+
+// helloConn feeds the client hello to a handshake that is never answered
+type helloConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+// Read implements net.Conn
+func (c *helloConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// Write implements net.Conn
+func (c *helloConn) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// replayConn reads the sniffed client hello again before the rest of the
+// connection
+type replayConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+// Read implements net.Conn
+func (c *replayConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// matchServerName returns true if the name is one of the server names, or
+// is a subdomain one level below a "*." server name
+func matchServerName(serverNames []string, name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, serverName := range serverNames {
+		serverName = strings.ToLower(serverName)
+		if strings.HasPrefix(serverName, "*.") {
+			if i := strings.Index(name, "."); i > 0 && name[i:] == serverName[1:] {
+				return true
+			}
+		} else if name == serverName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/control-center/serviced/zzk/registry"
+	"github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestMatchServerName(c *C) {
+	names := []string{"db.example.com", "*.apps.example.com"}
+	c.Check(matchServerName(names, "db.example.com"), Equals, true)
+	c.Check(matchServerName(names, "DB.Example.com."), Equals, true)
+	c.Check(matchServerName(names, "web.apps.example.com"), Equals, true)
+	c.Check(matchServerName(names, "apps.example.com"), Equals, false)
+	c.Check(matchServerName(names, "a.web.apps.example.com"), Equals, false)
+	c.Check(matchServerName(names, "example.com"), Equals, false)
+	c.Check(matchServerName(names, ""), Equals, false)
+}
+
+func (s *TestWebSuite) TestServeTLSPassthrough(c *C) {
+	ipmap["127.0.0.1"] = struct{}{}
+	defer delete(ipmap, "127.0.0.1")
+
+	// the backend terminates tls itself
+	backend := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.ServerName))
+	}))
+	defer backend.Close()
+
+	host, port, err := net.SplitHostPort(backend.Listener.Addr().String())
+	c.Assert(err, IsNil)
+	portNumber, err := strconv.Atoi(port)
+	c.Assert(err, IsNil)
+	exports := NewRoundRobinExports([]registry.ExportDetails{
+		{
+			ExportBinding: service.ExportBinding{Application: "db", PortNumber: uint16(portNumber)},
+			HostIP:        host,
+			PrivateIP:     host,
+		},
+	}, nil)
+	exports.SetRouting(registry.Routing{ServerNames: []string{"*.example.com"}})
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		ServeTLSPassthrough(cancel, listener, exports, nil)
+		close(done)
+	}()
+	defer func() {
+		close(cancel)
+		<-done
+	}()
+
+	get := func(serverName string) (string, error) {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+		}}
+		resp, err := client.Get("https://" + listener.Addr().String() + "/")
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	// the handshake is with the backend, which sees the server name
+	body, err := get("db.example.com")
+	c.Assert(err, IsNil)
+	c.Check(body, Equals, "db.example.com")
+
+	// other server names are refused
+	_, err = get("db.example.org")
+	c.Check(err, NotNil)
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// Reasons a client is refused by an endpoint policy
+const (
+	refusedDenied         = "client address is not allowed"
+	refusedConnectionRate = "connection rate exceeded"
+	refusedRequestRate    = "request rate exceeded"
+	refusedMaxConnections = "too many connections"
+)
+
+// rateLimiterPruneSize is the number of clients a rate limiter tracks before
+// it forgets the clients that have not used their burst
+var rateLimiterPruneSize = 10000
+
+// rateLimiter is a token bucket for each client ip
+type rateLimiter struct {
+	rate    float64
+	burst   float64
+	mu      *sync.Mutex
+	clients map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a rate limiter that lets each client through rate
+// times per second, or nil if the rate is not limited.  The burst defaults to
+// the rate.
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	b := float64(burst)
+	if b <= 0 {
+		b = math.Max(1, math.Ceil(rate))
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   b,
+		mu:      &sync.Mutex{},
+		clients: make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes a token from the bucket of the client and returns false if the
+// bucket is empty
+func (l *rateLimiter) allow(client string, now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.clients[client]
+	if ok {
+		bucket.tokens = l.fill(bucket, now)
+		bucket.last = now
+	} else {
+		if len(l.clients) >= rateLimiterPruneSize {
+			l.prune(now)
+		}
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.clients[client] = bucket
+	}
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+func (l *rateLimiter) fill(bucket *tokenBucket, now time.Time) float64 {
+	return math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+}
+
+// prune forgets the clients whose buckets are full again
+func (l *rateLimiter) prune(now time.Time) {
+	for client, bucket := range l.clients {
+		if l.fill(bucket, now) >= l.burst {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// EndpointPolicy enforces the access policy of a vhost or public port.  A nil
+// policy admits every client.
+type EndpointPolicy struct {
+	name        string
+	mu          *sync.RWMutex
+	policy      servicedefinition.AccessPolicy
+	allow       []*net.IPNet
+	deny        []*net.IPNet
+	connections *rateLimiter
+	requests    *rateLimiter
+	active      int64
+	now         func() time.Time
+}
+
+// NewEndpointPolicy creates a policy for the vhost or port public endpoint
+// that admits every client until it is set
+func NewEndpointPolicy(name string) *EndpointPolicy {
+	return &EndpointPolicy{
+		name: name,
+		mu:   &sync.RWMutex{},
+		now:  time.Now,
+	}
+}
+
+// Set updates the access policy.  The clients of a rate limit are forgotten
+// when the limit changes.
+func (p *EndpointPolicy) Set(policy servicedefinition.AccessPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if reflect.DeepEqual(p.policy, policy) {
+		return
+	}
+	logger := plog.WithField("endpoint", p.name)
+
+	p.allow = parseNetworks(logger, policy.AllowCIDRs)
+	p.deny = parseNetworks(logger, policy.DenyCIDRs)
+	if policy.ConnectionRate != p.policy.ConnectionRate || policy.RateBurst != p.policy.RateBurst {
+		p.connections = newRateLimiter(policy.ConnectionRate, policy.RateBurst)
+	}
+	if policy.RequestRate != p.policy.RequestRate || policy.RateBurst != p.policy.RateBurst {
+		p.requests = newRateLimiter(policy.RequestRate, policy.RateBurst)
+	}
+	p.policy = policy
+
+	logger.WithFields(log.Fields{
+		"allow":          policy.AllowCIDRs,
+		"deny":           policy.DenyCIDRs,
+		"connectionrate": policy.ConnectionRate,
+		"requestrate":    policy.RequestRate,
+		"burst":          policy.RateBurst,
+		"maxconnections": policy.MaxConnections,
+	}).Info("Updated public endpoint access policy")
+}
+
+func parseNetworks(logger *log.Entry, cidrs []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.WithError(err).WithField("cidr", cidr).Warn("Ignoring invalid network in access policy")
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// allowed returns false if the client ip is denied, or if there is an allow
+// list that does not contain it
+func (p *EndpointPolicy) allowed(ip net.IP) bool {
+	for _, network := range p.deny {
+		if ip != nil && network.Contains(ip) {
+			return false
+		}
+	}
+	if len(p.allow) == 0 {
+		return true
+	}
+	for _, network := range p.allow {
+		if ip != nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// acquire counts an open connection or request in flight, and returns false
+// if there are already as many as the policy allows
+func (p *EndpointPolicy) acquire() bool {
+	n := atomic.AddInt64(&p.active, 1)
+	if max := int64(p.policy.MaxConnections); max > 0 && n > max {
+		atomic.AddInt64(&p.active, -1)
+		return false
+	}
+	return true
+}
+
+func (p *EndpointPolicy) release() {
+	atomic.AddInt64(&p.active, -1)
+}
+
+// admitConnection returns the reason a new connection from the client is
+// refused, or an empty string if it is admitted.  An admitted connection
+// must be released when it is closed.
+func (p *EndpointPolicy) admitConnection(addr net.Addr) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	client := clientHost(addr.String())
+	if !p.allowed(net.ParseIP(client)) {
+		return refusedDenied
+	}
+	if !p.connections.allow(client, p.now()) {
+		return refusedConnectionRate
+	}
+	if !p.acquire() {
+		return refusedMaxConnections
+	}
+	return ""
+}
+
+// admitRequest sends an error response and returns false if the request is
+// refused.  If inFlight is set, the request counts against the connection
+// limit until done is called; ports count their connections instead.
+func (p *EndpointPolicy) admitRequest(w http.ResponseWriter, r *http.Request, inFlight bool) (done func(), ok bool) {
+	if p == nil {
+		return func() {}, true
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	client := clientHost(r.RemoteAddr)
+	reason, status := "", 0
+	if !p.allowed(net.ParseIP(client)) {
+		reason, status = refusedDenied, http.StatusForbidden
+	} else if !p.requests.allow(client, p.now()) {
+		reason, status = refusedRequestRate, http.StatusTooManyRequests
+		w.Header().Set("Retry-After", "1")
+	} else if inFlight && !p.acquire() {
+		reason, status = refusedMaxConnections, http.StatusServiceUnavailable
+	}
+	if reason != "" {
+		plog.WithFields(log.Fields{
+			"endpoint": p.name,
+			"client":   client,
+			"reason":   reason,
+		}).Debug("Refused public endpoint request")
+		http.Error(w, reason, status)
+		return nil, false
+	}
+	if inFlight {
+		return p.release, true
+	}
+	return func() {}, true
+}
+
+// Listener returns a listener that closes the connections the policy
+// refuses as soon as they are accepted
+func (p *EndpointPolicy) Listener(listener net.Listener) net.Listener {
+	if p == nil {
+		return listener
+	}
+	return &policyListener{Listener: listener, policy: p}
+}
+
+type policyListener struct {
+	net.Listener
+	policy *EndpointPolicy
+}
+
+// Accept implements net.Listener
+func (l *policyListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if reason := l.policy.admitConnection(conn.RemoteAddr()); reason != "" {
+			plog.WithFields(log.Fields{
+				"endpoint": l.policy.name,
+				"client":   conn.RemoteAddr().String(),
+				"reason":   reason,
+			}).Debug("Refused public endpoint connection")
+			conn.Close()
+			continue
+		}
+		return &policyConn{Conn: conn, once: &sync.Once{}, release: l.policy.release}, nil
+	}
+}
+
+// policyConn releases its place in the connection limit when it is closed
+type policyConn struct {
+	net.Conn
+	once    *sync.Once
+	release func()
+}
+
+// Close implements net.Conn
+func (c *policyConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
+
+// clientHost returns the host of a client address
+func clientHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
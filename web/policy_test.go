@@ -0,0 +1,157 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestRateLimiter(c *C) {
+	c.Assert(newRateLimiter(0, 10), IsNil)
+
+	now := time.Now()
+	limiter := newRateLimiter(2, 0)
+	c.Check(limiter.allow("10.0.0.1", now), Equals, true)
+	c.Check(limiter.allow("10.0.0.1", now), Equals, true)
+	c.Check(limiter.allow("10.0.0.1", now), Equals, false)
+
+	// other clients have their own bucket
+	c.Check(limiter.allow("10.0.0.2", now), Equals, true)
+
+	// tokens come back at the rate
+	now = now.Add(500 * time.Millisecond)
+	c.Check(limiter.allow("10.0.0.1", now), Equals, true)
+	c.Check(limiter.allow("10.0.0.1", now), Equals, false)
+
+	// clients with a full bucket are forgotten
+	defer func(size int) { rateLimiterPruneSize = size }(rateLimiterPruneSize)
+	rateLimiterPruneSize = 2
+	c.Check(limiter.allow("10.0.0.3", now.Add(time.Minute)), Equals, true)
+	c.Check(limiter.clients, HasLen, 1)
+}
+
+func (s *TestWebSuite) TestEndpointPolicy_AdmitRequest(c *C) {
+	policy := NewEndpointPolicy("zproxy")
+	policy.Set(servicedefinition.AccessPolicy{
+		AllowCIDRs:     []string{"10.0.0.0/8"},
+		DenyCIDRs:      []string{"10.0.5.0/24"},
+		RequestRate:    1,
+		MaxConnections: 1,
+	})
+
+	request := func(remoteAddr string) (func(), int) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		done, ok := policy.admitRequest(w, r, true)
+		if !ok {
+			return nil, w.Code
+		}
+		return done, http.StatusOK
+	}
+
+	_, status := request("192.168.1.5:51234")
+	c.Check(status, Equals, http.StatusForbidden)
+	_, status = request("10.0.5.7:51234")
+	c.Check(status, Equals, http.StatusForbidden)
+
+	done, status := request("10.0.1.7:51234")
+	c.Assert(status, Equals, http.StatusOK)
+	_, status = request("10.0.1.7:51235")
+	c.Check(status, Equals, http.StatusTooManyRequests)
+
+	// the request in flight holds the only connection
+	_, status = request("10.0.2.7:51234")
+	c.Check(status, Equals, http.StatusServiceUnavailable)
+	done()
+	done, status = request("10.0.3.7:51234")
+	c.Check(status, Equals, http.StatusOK)
+	done()
+
+	// an empty policy admits everyone
+	policy.Set(servicedefinition.AccessPolicy{})
+	for i := 0; i < 5; i++ {
+		_, status = request("192.168.1.5:51234")
+		c.Check(status, Equals, http.StatusOK)
+	}
+
+	var nilPolicy *EndpointPolicy
+	_, ok := nilPolicy.admitRequest(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), true)
+	c.Check(ok, Equals, true)
+}
+
+func (s *TestWebSuite) TestEndpointPolicy_Listener(c *C) {
+	policy := NewEndpointPolicy(":22222")
+	policy.Set(servicedefinition.AccessPolicy{MaxConnections: 1})
+
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	listener := policy.Listener(l)
+	defer listener.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	dial := func() net.Conn {
+		conn, err := net.Dial("tcp4", l.Addr().String())
+		c.Assert(err, IsNil)
+		return conn
+	}
+
+	first := dial()
+	defer first.Close()
+	var conn net.Conn
+	select {
+	case conn = <-accepted:
+	case <-time.After(5 * time.Second):
+		c.Fatal("timed out waiting for the first connection")
+	}
+
+	// the second connection is closed while the first is open
+	second := dial()
+	defer second.Close()
+	second.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = second.Read(make([]byte, 1))
+	c.Check(err, NotNil)
+	if netErr, ok := err.(net.Error); ok {
+		c.Check(netErr.Timeout(), Equals, false)
+	}
+
+	conn.Close()
+	third := dial()
+	defer third.Close()
+	select {
+	case conn = <-accepted:
+		conn.Close()
+	case <-time.After(5 * time.Second):
+		c.Fatal("timed out waiting for the third connection")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
+	"net/http"
 	"net/url"
 
 	"fmt"
@@ -211,6 +212,30 @@ func restGetPoolIps(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	w.WriteJson(&ips)
 }
 
+func restGetPoolUsage(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	poolID, err := url.QueryUnescape(r.PathParam("poolId"))
+	if err != nil {
+		restBadRequest(w, err)
+		return
+	} else if len(poolID) == 0 {
+		restBadRequest(w, fmt.Errorf("poolID must be specified for GET"))
+		return
+	}
+
+	usage, err := ctx.getFacade().GetPoolUsage(ctx.getDatastoreContext(), poolID)
+	if err == facade.ErrPoolNotExists {
+		writeJSON(w, &simpleResponse{"Resource pool not found", poolsLinks()}, http.StatusNotFound)
+		return
+	} else if err != nil {
+		glog.Error("Could not get resource pool usage: ", err)
+		restServerError(w, err)
+		return
+	}
+
+	glog.V(4).Infof("restGetPoolUsage: id %s, usage %#v", poolID, usage)
+	w.WriteJson(&usage)
+}
+
 func getPoolHostIds(poolID string, facade facade.FacadeInterface, dataCtx datastore.Context) ([]string, error) {
 	hosts, err := facade.FindHostsInPool(dataCtx, poolID)
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/facade"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
@@ -164,6 +165,42 @@ func (s *TestWebSuite) TestRestGetPoolFails(c *C) {
 	s.assertServerError(c, expectedError)
 }
 
+func (s *TestWebSuite) TestRestGetPoolUsage(c *C) {
+	poolID := "somePool"
+	expectedUsage := pool.Usage{
+		PoolID:           poolID,
+		CoreLimit:        8,
+		CoreCommitment:   2,
+		MemoryLimit:      1024,
+		MemoryCommitment: 512,
+	}
+	request := s.buildRequest("GET", "/pools/somePool/usage", "")
+	request.PathParams["poolId"] = poolID
+	s.mockFacade.
+		On("GetPoolUsage", s.ctx.getDatastoreContext(), poolID).
+		Return(&expectedUsage, nil)
+
+	restGetPoolUsage(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	actualResult := pool.Usage{}
+	s.getResult(c, &actualResult)
+	c.Assert(actualResult, DeepEquals, expectedUsage)
+}
+
+func (s *TestWebSuite) TestRestGetPoolUsageNotFound(c *C) {
+	poolID := "somePool"
+	request := s.buildRequest("GET", "/pools/somePool/usage", "")
+	request.PathParams["poolId"] = poolID
+	s.mockFacade.
+		On("GetPoolUsage", s.ctx.getDatastoreContext(), poolID).
+		Return(nil, facade.ErrPoolNotExists)
+
+	restGetPoolUsage(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusNotFound)
+}
+
 func (s *TestWebSuite) TestRestGetPoolFailsForInvalidURL(c *C) {
 	request := s.buildRequest("GET", "/pools/%zzz", "")
 	request.PathParams["poolId"] = "%zzz"
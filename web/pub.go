@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/zzk/registry"
 )
@@ -31,17 +32,21 @@ type PublicPortManager struct {
 	hostID    string
 	certFile  string
 	keyFile   string
+	certs     *CertificateCache
+	healthy   HealthFunc
 	onFailure func(portNumber string, err error)
 	mu        *sync.RWMutex
 	ports     map[string]*PublicPortHandler
 }
 
 // NewPublicPortManager creates a new public port manager for a host id
-func NewPublicPortManager(hostID, certFile, keyFile string, onFailure func(portAddr string, err error)) *PublicPortManager {
+func NewPublicPortManager(hostID, certFile, keyFile string, certs *CertificateCache, healthy HealthFunc, onFailure func(portAddr string, err error)) *PublicPortManager {
 	return &PublicPortManager{
 		hostID:    hostID,
 		certFile:  certFile,
 		keyFile:   keyFile,
+		certs:     certs,
+		healthy:   healthy,
 		onFailure: onFailure,
 		mu:        &sync.RWMutex{},
 		ports:     make(map[string]*PublicPortHandler),
@@ -56,12 +61,12 @@ func (m *PublicPortManager) Enable(portAddr, protocol string, useTLS bool) {
 	// get the port handler or create if it doesn't exist
 	h, ok := m.ports[portAddr]
 	if !ok {
-		h = NewPublicPortHandler(portAddr)
+		h = NewPublicPortHandler(portAddr, m.healthy)
 		m.ports[portAddr] = h
 	}
 
 	// start the port server
-	if err := h.Serve(protocol, useTLS, m.certFile, m.keyFile); err != nil {
+	if err := h.Serve(protocol, useTLS, m.certFile, m.keyFile, m.certs); err != nil {
 		m.onFailure(portAddr, err)
 	}
 }
@@ -87,34 +92,50 @@ func (m *PublicPortManager) Set(portAddr string, data []registry.ExportDetails)
 	if ok {
 		h.SetExports(data)
 	} else {
-		h = NewPublicPortHandler(portAddr, data...)
+		h = NewPublicPortHandler(portAddr, m.healthy, data...)
 		m.ports[portAddr] = h
 	}
 }
 
+// SetRouting updates how connections to a particular port are routed
+func (m *PublicPortManager) SetRouting(portAddr string, routing registry.Routing) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.ports[portAddr]
+	if !ok {
+		h = NewPublicPortHandler(portAddr, m.healthy)
+		m.ports[portAddr] = h
+	}
+	h.SetRouting(routing)
+}
+
 // PublicPortHandler manages the port server at a specific port address
 type PublicPortHandler struct {
 	portAddr string
 	exports  Exports
+	policy   *EndpointPolicy
 	cancel   chan struct{}
 	wg       *sync.WaitGroup
 }
 
 // NewPublicPortHandler sets up a new public port at the given port address
-func NewPublicPortHandler(portAddr string, data ...registry.ExportDetails) *PublicPortHandler {
+func NewPublicPortHandler(portAddr string, healthy HealthFunc, data ...registry.ExportDetails) *PublicPortHandler {
 	cancel := make(chan struct{})
 	close(cancel)
 
 	return &PublicPortHandler{
 		portAddr: portAddr,
-		exports:  NewRoundRobinExports(data), // round-robin is the default
+		exports:  NewRoundRobinExports(data, healthy), // round-robin is the default
+		policy:   NewEndpointPolicy(portAddr),
 		cancel:   cancel,
 		wg:       &sync.WaitGroup{},
 	}
 }
 
-// Serve starts the port server at address
-func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFile string) error {
+// Serve starts the port server at address.  If certs is set, the port is
+// served with the certificate of the public endpoint when it has one.
+func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFile string, certs *CertificateCache) error {
 	logger := plog.WithFields(log.Fields{
 		"portaddress": h.portAddr,
 		"protocol":    protocol,
@@ -130,8 +151,9 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 		return ErrPortServerRunning
 	}
 
+	// tls-passthrough and udp ports never terminate tls
 	var tlsConfig *tls.Config
-	if useTLS {
+	if useTLS && protocol != servicedefinition.PortProtocolTLSPassthrough && protocol != servicedefinition.PortProtocolUDP {
 
 		// get the certificate
 		certFile, keyFile = GetCertFiles(certFile, keyFile)
@@ -149,12 +171,36 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 			MinVersion:               utils.MinTLS("http"),
 			PreferServerCipherSuites: true,
 			CipherSuites:             utils.CipherSuites("http"),
-			Certificates:             []tls.Certificate{cert},
+		}
+		if certs != nil {
+			tlsConfig.GetCertificate = certs.PortCertificate(h.portAddr, &cert)
+		} else {
+			tlsConfig.Certificates = []tls.Certificate{cert}
 		}
 
 		logger.Debug("Set up tls certificate")
 	}
 
+	// udp ports read datagrams instead of accepting connections
+	if protocol == servicedefinition.PortProtocolUDP {
+		conn, err := net.ListenPacket("udp", h.portAddr)
+		if err != nil {
+			logger.WithError(err).Debug("Could not start UDP listener")
+			return err
+		}
+
+		h.wg.Add(1)
+		go func() {
+			logger.Info("Starting port server")
+			defer logger.Debug("Port server exited")
+
+			ServeUDP(h.cancel, conn, h.exports, h.policy)
+			h.wg.Done()
+		}()
+
+		return nil
+	}
+
 	// start listening on the port with a non-tls connection
 	listener, err := net.Listen("tcp", h.portAddr)
 	if err != nil {
@@ -167,10 +213,13 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 		logger.Info("Starting port server")
 		defer logger.Debug("Port server exited")
 
-		if protocol == "http" || protocol == "https" {
-			ServeHTTP(h.cancel, h.portAddr, protocol, listener, tlsConfig, h.exports)
-		} else {
-			ServeTCP(h.cancel, listener, tlsConfig, h.exports)
+		switch protocol {
+		case "http", "https":
+			ServeHTTP(h.cancel, h.portAddr, protocol, listener, tlsConfig, h.exports, h.policy)
+		case servicedefinition.PortProtocolTLSPassthrough:
+			ServeTLSPassthrough(h.cancel, listener, h.exports, h.policy)
+		default:
+			ServeTCP(h.cancel, listener, tlsConfig, h.exports, h.policy)
 		}
 		h.wg.Done()
 	}()
@@ -192,3 +241,10 @@ func (h *PublicPortHandler) Stop() {
 func (h *PublicPortHandler) SetExports(data []registry.ExportDetails) {
 	h.exports.Set(data)
 }
+
+// SetRouting updates how connections to the port are routed and which
+// clients may connect
+func (h *PublicPortHandler) SetRouting(routing registry.Routing) {
+	h.exports.SetRouting(routing)
+	h.policy.Set(routing.Policy)
+}
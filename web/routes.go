@@ -57,10 +57,12 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 
 		// Pools (IPs)
 		rest.Route{"GET", "/pools/:poolId/ips", gz(sc.checkAuth(restGetPoolIps))},
+		rest.Route{"GET", "/pools/:poolId/usage", gz(sc.checkAuth(restGetPoolUsage))},
 
 		// Services (Apps)
 		rest.Route{"GET", "/services", gz(sc.checkAuth(restGetAllServices))},
 		rest.Route{"GET", "/servicehealth", gz(sc.checkAuth(restGetServicesHealth))},
+		rest.Route{"GET", "/servicehealth/:serviceId/history", gz(sc.checkAuth(restGetServiceHealthHistory))},
 		rest.Route{"GET", "/services/:serviceId", gz(sc.authorizedClient(restGetService))},
 		rest.Route{"GET", "/services/:serviceId/running", gz(sc.authorizedClient(restGetRunningForService))},
 		rest.Route{"GET", "/services/:serviceId/:serviceStateId/logs", gz(sc.authorizedClient(restGetServiceStateLogs))},
@@ -139,6 +141,7 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"GET", "/api/v2/services/:serviceId/publicendpoints", gz(sc.checkAuth(restGetServicePublicEndpoints))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/ipassignments", gz(sc.checkAuth(restGetServiceIPAssignments))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/exportendpoints", gz(sc.checkAuth(restGetServiceExportedEndpoints))},
+		rest.Route{"GET", "/api/v2/services/:serviceId/traffic", gz(sc.checkAuth(getServiceTraffic))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/descendantstates", gz(sc.checkAuth(restCountDescendantStates))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(getServiceContext))},
 		rest.Route{"PUT", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(putServiceContext))},
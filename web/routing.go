@@ -0,0 +1,238 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/zzk/registry"
+)
+
+// affinityCookie is the prefix of the cookie that pins a browser to an
+// export of a vhost or public port
+const affinityCookie = "serviced_affinity_"
+
+// affinityCookieName returns the name of the affinity cookie for a vhost or
+// public port.  Public ports share a hostname, so each gets its own cookie.
+func affinityCookieName(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("%s%x", affinityCookie, h.Sum32())
+}
+
+// clientKey returns the key that pins a client to an export, based on the
+// affinity of the routing
+func clientKey(routing registry.Routing, name string, r *http.Request) string {
+	switch routing.Affinity {
+	case servicedefinition.AffinityCookie:
+		if cookie, err := r.Cookie(affinityCookieName(name)); err == nil {
+			return cookie.Value
+		}
+	case servicedefinition.AffinitySourceIP:
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		return host
+	}
+	return ""
+}
+
+// isIdempotent returns true if the request can be safely sent again
+func isIdempotent(r *http.Request) bool {
+	switch r.Method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return r.ContentLength == 0
+	}
+	return false
+}
+
+// ProxyRequest sends the request to an export chosen by the routing of the
+// exports.  Exports failing their health checks are skipped, and if the
+// connection to an export fails, idempotent requests are retried on another
+// export.  Returns false if no export is available.
+func ProxyRequest(name string, useTLS bool, exports Exports, w http.ResponseWriter, r *http.Request) bool {
+	return proxyRequest(name, useTLS, exports, w, r) != nil
+}
+
+// proxyRequest implements ProxyRequest and returns the export that answered
+// the request, or nil if no export is available.
+func proxyRequest(name string, useTLS bool, exports Exports, w http.ResponseWriter, r *http.Request) *registry.ExportDetails {
+	routing := exports.Routing()
+	key := clientKey(routing, name, r)
+
+	export := exports.Pick(key, nil)
+	if export == nil {
+		return nil
+	}
+
+	transport := &failoverTransport{
+		useTLS:  useTLS,
+		exports: exports,
+		export:  export,
+		retry:   !routing.DisableRetry && isIdempotent(r),
+	}
+
+	rp := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "http"
+			if _, ok := req.Header["User-Agent"]; !ok {
+				// explicitly disable User-Agent so it's not set to default value
+				req.Header.Set("User-Agent", "")
+			}
+		},
+		Transport:     transport,
+		FlushInterval: time.Millisecond * 10,
+		ModifyResponse: func(resp *http.Response) error {
+			// pin the browser to the export that answered
+			if routing.Affinity == servicedefinition.AffinityCookie {
+				if id := AffinityID(transport.export); id != key {
+					cookie := &http.Cookie{
+						Name:     affinityCookieName(name),
+						Value:    id,
+						Path:     "/",
+						HttpOnly: true,
+					}
+					resp.Header.Add("Set-Cookie", cookie.String())
+				}
+			}
+			return nil
+		},
+	}
+	rp.ServeHTTP(w, r)
+	return transport.export
+}
+
+// failoverTransport sends a request to an export, moving on to the next
+// export when the connection fails if the request can be retried.
+type failoverTransport struct {
+	useTLS  bool
+	exports Exports
+	export  *registry.ExportDetails // the export the request is sent to
+	retry   bool
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tried := make(map[string]bool)
+	for {
+		logger := plog.WithFields(log.Fields{
+			"application": t.export.Application,
+			"hostip":      t.export.HostIP,
+			"privateip":   t.export.PrivateIP,
+		})
+
+		// use the reverse proxy transport for the export, which dials the
+		// export no matter the address of the request.
+		out := *req
+		url := *req.URL
+		url.Host = fmt.Sprintf("%s:%d", t.export.PrivateIP, t.export.PortNumber)
+		out.URL = &url
+
+		resp, err := GetReverseProxy(t.useTLS, t.export).Transport.RoundTrip(&out)
+		if err == nil || !t.retry {
+			return resp, err
+		}
+
+		tried[ExportKey(t.export)] = true
+		next := t.exports.Pick("", tried)
+		if next == nil {
+			logger.WithError(err).Debug("No exports left to retry the request")
+			return nil, err
+		}
+		logger.WithError(err).Warn("Could not reach export, retrying the request on another export")
+		t.export = next
+	}
+}
+
+// serviceHealthItem is the cached health of the instances of a service
+type serviceHealthItem struct {
+	statuses   map[int]map[string]health.HealthStatus
+	expires    time.Time
+	refreshing bool          // a lookup of the health is in flight
+	ready      chan struct{} // closed once the first lookup is done
+}
+
+// ServiceHealthCache remembers the health of service instances for a short
+// while, so that routing a request does not have to look it up every time.
+// Once the health of a service is known, it is refreshed in the background.
+type ServiceHealthCache struct {
+	mu     *sync.Mutex
+	ttl    time.Duration
+	lookup func(serviceID string) (map[int]map[string]health.HealthStatus, error)
+	data   map[string]*serviceHealthItem
+}
+
+// NewServiceHealthCache creates a health cache that looks up the health of a
+// service at most once every ttl.
+func NewServiceHealthCache(ttl time.Duration, lookup func(serviceID string) (map[int]map[string]health.HealthStatus, error)) *ServiceHealthCache {
+	return &ServiceHealthCache{
+		mu:     &sync.Mutex{},
+		ttl:    ttl,
+		lookup: lookup,
+		data:   make(map[string]*serviceHealthItem),
+	}
+}
+
+// IsHealthy implements HealthFunc.  Instances are treated as healthy if their
+// health cannot be looked up.  Only the first request for a service waits for
+// its health to be looked up; later requests use the cached health while it is
+// refreshed.
+func (c *ServiceHealthCache) IsHealthy(serviceID string, instanceID int) bool {
+	c.mu.Lock()
+	item, ok := c.data[serviceID]
+	if !ok {
+		item = &serviceHealthItem{refreshing: true, ready: make(chan struct{})}
+		c.data[serviceID] = item
+		c.mu.Unlock()
+		c.refresh(serviceID, item)
+	} else {
+		if !item.refreshing && time.Now().After(item.expires) {
+			item.refreshing = true
+			go c.refresh(serviceID, item)
+		}
+		c.mu.Unlock()
+	}
+
+	<-item.ready
+	c.mu.Lock()
+	statuses := item.statuses[instanceID]
+	c.mu.Unlock()
+	return health.IsHealthy(statuses)
+}
+
+// refresh looks up the health of a service and caches it
+func (c *ServiceHealthCache) refresh(serviceID string, item *serviceHealthItem) {
+	statuses, err := c.lookup(serviceID)
+	if err != nil {
+		plog.WithField("serviceid", serviceID).WithError(err).Debug("Could not look up service health")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item.statuses = statuses
+	item.expires = time.Now().Add(c.ttl)
+	item.refreshing = false
+	select {
+	case <-item.ready:
+	default:
+		close(item.ready)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/zzk/registry"
+	"github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func routingExports(n int) []registry.ExportDetails {
+	exports := make([]registry.ExportDetails, n)
+	for i := range exports {
+		exports[i] = registry.ExportDetails{
+			ExportBinding: service.ExportBinding{
+				Application: "app",
+				PortNumber:  8080,
+			},
+			HostIP:     "10.0.0.1",
+			PrivateIP:  fmt.Sprintf("172.17.0.%d", i+1),
+			ServiceID:  "serviceid",
+			InstanceID: i,
+		}
+	}
+	return exports
+}
+
+func (s *TestWebSuite) TestRoundRobinExports_SkipsUnhealthy(c *C) {
+	exports := NewRoundRobinExports(routingExports(3), func(serviceID string, instanceID int) bool {
+		return instanceID != 1
+	})
+
+	counts := make(map[int]int)
+	for i := 0; i < 6; i++ {
+		counts[exports.Next().InstanceID]++
+	}
+	c.Check(counts, DeepEquals, map[int]int{0: 3, 2: 3})
+
+	// nothing healthy, so fall back to everything
+	exports = NewRoundRobinExports(routingExports(2), func(string, int) bool { return false })
+	c.Check(exports.Next(), NotNil)
+}
+
+func (s *TestWebSuite) TestRoundRobinExports_HealthWithoutLock(c *C) {
+	var exports *RoundRobinExports
+	exports = NewRoundRobinExports(routingExports(2), func(string, int) bool {
+		// a slow health lookup must not block updates to the exports
+		exports.Set(routingExports(3))
+		return true
+	})
+	c.Check(exports.Next(), NotNil)
+	c.Check(exports.Next(), NotNil)
+}
+
+func (s *TestWebSuite) TestRoundRobinExports_Tried(c *C) {
+	exports := NewRoundRobinExports(routingExports(2), nil)
+
+	tried := make(map[string]bool)
+	first := exports.Pick("", tried)
+	tried[ExportKey(first)] = true
+	second := exports.Pick("", tried)
+	c.Assert(second, NotNil)
+	c.Check(second.InstanceID, Not(Equals), first.InstanceID)
+	tried[ExportKey(second)] = true
+	c.Check(exports.Pick("", tried), IsNil)
+}
+
+func (s *TestWebSuite) TestRoundRobinExports_SourceIPAffinity(c *C) {
+	exports := NewRoundRobinExports(routingExports(4), nil)
+	exports.SetRouting(registry.Routing{Affinity: servicedefinition.AffinitySourceIP})
+
+	first := exports.Pick("192.168.1.5", nil)
+	for i := 0; i < 5; i++ {
+		c.Check(exports.Pick("192.168.1.5", nil).InstanceID, Equals, first.InstanceID)
+	}
+}
+
+func (s *TestWebSuite) TestRoundRobinExports_CookieAffinity(c *C) {
+	data := routingExports(3)
+	exports := NewRoundRobinExports(data, nil)
+	exports.SetRouting(registry.Routing{Affinity: servicedefinition.AffinityCookie})
+
+	for i := 0; i < 3; i++ {
+		c.Check(exports.Pick(AffinityID(&data[2]), nil).InstanceID, Equals, 2)
+	}
+
+	// an unknown cookie gets the next export
+	c.Check(exports.Pick("unknown", nil), NotNil)
+}
+
+func (s *TestWebSuite) TestProxyRequest_Retry(c *C) {
+	ipmap["127.0.0.1"] = struct{}{}
+	defer delete(ipmap, "127.0.0.1")
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer backend.Close()
+
+	// a port that refuses connections
+	closed, err := net.Listen("tcp4", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	closed.Close()
+
+	export := func(addr string, instanceID int) registry.ExportDetails {
+		host, port, err := net.SplitHostPort(addr)
+		c.Assert(err, IsNil)
+		portNumber, err := strconv.Atoi(port)
+		c.Assert(err, IsNil)
+		return registry.ExportDetails{
+			ExportBinding: service.ExportBinding{PortNumber: uint16(portNumber)},
+			HostIP:        host,
+			PrivateIP:     host,
+			InstanceID:    instanceID,
+		}
+	}
+	data := []registry.ExportDetails{
+		export(closed.Addr().String(), 0),
+		export(backend.Listener.Addr().String(), 1),
+	}
+
+	// every request gets through, even when the closed port is picked first
+	exports := NewRoundRobinExports(data, nil)
+	exports.SetRouting(registry.Routing{Affinity: servicedefinition.AffinityCookie})
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		c.Assert(ProxyRequest("myhost", false, exports, w, r), Equals, true)
+		c.Check(w.Code, Equals, http.StatusOK)
+
+		// the browser is pinned to the export that answered
+		cookies := (&http.Response{Header: w.Header()}).Cookies()
+		c.Assert(cookies, HasLen, 1)
+		c.Check(cookies[0].Value, Equals, AffinityID(&data[1]))
+	}
+
+	// without retries, requests to the closed port fail
+	exports.SetRouting(registry.Routing{DisableRetry: true})
+	codes := make(map[int]int)
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		ProxyRequest("myhost", false, exports, w, r)
+		codes[w.Code]++
+	}
+	c.Check(codes, DeepEquals, map[int]int{http.StatusOK: 1, http.StatusBadGateway: 1})
+
+	// no exports
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	c.Check(ProxyRequest("myhost", false, NewRoundRobinExports(nil, nil), w, r), Equals, false)
+}
+
+func (s *TestWebSuite) TestServiceHealthCache_OneLookup(c *C) {
+	var lookups int32
+	release := make(chan struct{})
+	cache := NewServiceHealthCache(time.Minute, func(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+		atomic.AddInt32(&lookups, 1)
+		<-release
+		return map[int]map[string]health.HealthStatus{
+			1: {"answering": {Status: health.Failed}},
+		}, nil
+	})
+
+	// concurrent requests for a service that is not cached wait on one lookup
+	var wg sync.WaitGroup
+	results := make([]bool, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = cache.IsHealthy("serviceid", i%2)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	c.Assert(atomic.LoadInt32(&lookups), Equals, int32(1))
+	for i, healthy := range results {
+		c.Assert(healthy, Equals, i%2 == 0)
+	}
+}
+
+func (s *TestWebSuite) TestServiceHealthCache_RefreshInBackground(c *C) {
+	var lookups int32
+	refreshing, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	cache := NewServiceHealthCache(time.Millisecond, func(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+		if atomic.AddInt32(&lookups, 1) == 1 {
+			return map[int]map[string]health.HealthStatus{
+				0: {"answering": {Status: health.Failed}},
+			}, nil
+		}
+		refreshing <- struct{}{}
+		<-release
+		return nil, nil
+	})
+	c.Assert(cache.IsHealthy("serviceid", 0), Equals, false)
+	time.Sleep(10 * time.Millisecond)
+
+	// requests after the ttl use the cached health while one lookup refreshes it
+	for i := 0; i < 10; i++ {
+		c.Assert(cache.IsHealthy("serviceid", 0), Equals, false)
+	}
+	select {
+	case <-refreshing:
+	case <-time.After(time.Second):
+		c.Fatalf("health was not refreshed")
+	}
+	c.Assert(atomic.LoadInt32(&lookups), Equals, int32(2))
+}
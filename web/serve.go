@@ -21,6 +21,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/proxy"
 )
 
@@ -39,7 +40,21 @@ func RouteOriginalURL(r *http.Request) {
 }
 
 // ServeTCP sets up a tcp based server connection given a set of exports.
-func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Config, exports Exports) {
+// Connections that the policy refuses are closed.
+func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Config, exports Exports, policy *EndpointPolicy) {
+	serveStream(cancel, listener, exports, policy, func(local net.Conn) (net.Conn, bool) {
+		if tlsConfig != nil {
+			local = tls.Server(local, tlsConfig)
+		}
+		return local, true
+	})
+}
+
+// serveStream proxies the connections accepted by the listener to the
+// exports.  Each connection is prepared before it is proxied, and closed
+// instead if prepare returns false.
+func serveStream(cancel <-chan struct{}, listener net.Listener, exports Exports, policy *EndpointPolicy, prepare func(net.Conn) (net.Conn, bool)) {
+	listener = policy.Listener(listener)
 	stopChan := make(chan bool)
 	wg := &sync.WaitGroup{}
 
@@ -51,43 +66,27 @@ func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Conf
 				return
 			}
 
-			if tlsConfig != nil {
-				local = tls.Server(local, tlsConfig)
-			}
-
-			export := exports.Next()
-			if export == nil {
-				// This happens if the endpoint is accessed and the containers
-				// have died or not come up yet.
-				plog.Warn("Could not retrieve endpoint")
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
 
-				// close the accepted connection and continue waiting for
-				// connections.
-				if err := local.Close(); err != nil {
-					plog.WithError(err).Error("Could not close client connection")
+				local, ok := prepare(local)
+				if !ok {
+					local.Close()
+					return
 				}
-				continue
-			}
-
-			logger := plog.WithFields(log.Fields{
-				"application": export.Application,
-				"hostip":      export.HostIP,
-				"privateip":   export.PrivateIP,
-			})
 
-			// setup remote connection
-			remote, err := GetRemoteConnection(config.MuxTLSIsEnabled(), export)
-			if err != nil {
-				logger.WithError(err).Error("Could not get remote connection for endpoint")
-				continue
-			}
-
-			logger.WithField("remoteaddress", remote.RemoteAddr()).Debug("Established remote connection")
+				remote := dialExport(local, exports)
+				if remote == nil {
+					// close the accepted connection and continue waiting for
+					// connections.
+					if err := local.Close(); err != nil {
+						plog.WithError(err).Error("Could not close client connection")
+					}
+					return
+				}
 
-			wg.Add(1)
-			go func() {
 				proxy.ProxyLoop(local, remote, stopChan)
-				wg.Done()
 			}()
 		}
 	}()
@@ -98,8 +97,51 @@ func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Conf
 	wg.Wait()
 }
 
-// ServeHTTP sets up an http server for handling a collection of endpoints
-func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Listener, tlsConfig *tls.Config, exports Exports) {
+// dialExport connects to an export for the client connection, moving on to
+// the next export if the connection fails.  Returns nil if no export could be
+// reached.
+func dialExport(local net.Conn, exports Exports) net.Conn {
+	// pin the client by its address if the routing asks for it
+	routing := exports.Routing()
+	key := ""
+	if routing.Affinity == servicedefinition.AffinitySourceIP {
+		key, _, _ = net.SplitHostPort(local.RemoteAddr().String())
+	}
+
+	tried := make(map[string]bool)
+	for {
+		export := exports.Pick(key, tried)
+		if export == nil {
+			// This happens if the endpoint is accessed and the containers
+			// have died or not come up yet.
+			plog.Warn("Could not retrieve endpoint")
+			return nil
+		}
+
+		logger := plog.WithFields(log.Fields{
+			"application": export.Application,
+			"hostip":      export.HostIP,
+			"privateip":   export.PrivateIP,
+		})
+
+		remote, err := GetRemoteConnection(config.MuxTLSIsEnabled(), export)
+		if err != nil {
+			logger.WithError(err).Error("Could not get remote connection for endpoint")
+			if routing.DisableRetry {
+				return nil
+			}
+			tried[ExportKey(export)] = true
+			continue
+		}
+
+		logger.WithField("remoteaddress", remote.RemoteAddr()).Debug("Established remote connection")
+		return remote
+	}
+}
+
+// ServeHTTP sets up an http server for handling a collection of endpoints.
+// Connections and requests that the policy refuses are not proxied.
+func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Listener, tlsConfig *tls.Config, exports Exports, policy *EndpointPolicy) {
 	logger := plog.WithFields(log.Fields{
 		"portaddress": address,
 		"protocol":    protocol,
@@ -135,22 +177,14 @@ func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Li
 		default:
 		}
 
-		logger.WithField("handlerrequest", r).Debug("Handler handling (port) request")
-
-		export := exports.Next()
-		if export == nil {
-			http.Error(w, "endpoint not available", http.StatusNotFound)
+		// the listener refuses connections, but requests are limited here
+		done, ok := policy.admitRequest(w, r, false)
+		if !ok {
 			return
 		}
+		defer done()
 
-		rp := GetReverseProxy(config.MuxTLSIsEnabled(), export)
-
-		logger.WithFields(log.Fields{
-			"application": export.Application,
-			"hostip":      export.HostIP,
-			"privateip":   export.PrivateIP,
-			"url":         r.URL,
-		}).Debug("Set up public endpoint proxy")
+		logger.WithField("handlerrequest", r).Debug("Handler handling (port) request")
 
 		// Set up the X-Forwarded-Proto header so that downstream servers know
 		// the request originated as HTTPS.
@@ -161,7 +195,11 @@ func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Li
 		if tlsConfig != nil {
 			w.Header().Add("Strict-Transport-Security","max-age=31536000")
 		}
-		rp.ServeHTTP(w, r)
+
+		// send the request to the next available export
+		if !proxyEndpoint(EndpointTypePort, address, config.MuxTLSIsEnabled(), exports, w, r) {
+			http.Error(w, "endpoint not available", http.StatusNotFound)
+		}
 
 		return
 	}
@@ -175,7 +213,9 @@ func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Li
 			TCPListener: listener.(*net.TCPListener),
 			cancel:      cancel,
 		}
-		listener = tls.NewListener(keepAliveListener, tlsConfig)
+		listener = tls.NewListener(policy.Listener(keepAliveListener), tlsConfig)
+	} else {
+		listener = policy.Listener(listener)
 	}
 
 	wg := &sync.WaitGroup{}
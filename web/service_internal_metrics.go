@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/service"
 )
 
 var internalCounterStats = []string{
@@ -28,10 +29,15 @@ var internalCounterStats = []string{
 	"net.tx_dropped", "net.tx_errors", "net.tx_fifo_errors",
 	"net.tx_heartbeat_errors", "net.tx_packets", "net.tx_window_errors",
 	"cgroup.memory.pgmajfault",
+	"publicendpoint.requests", "publicendpoint.errors", "publicendpoint.status.1xx",
+	"publicendpoint.status.2xx", "publicendpoint.status.3xx", "publicendpoint.status.4xx",
+	"publicendpoint.status.5xx",
 }
 var internalGaugeStats = []string{
 	"cgroup.memory.totalrss", "cgroup.memory.cache", "net.open_connections.tcp", "net.open_connections.udp",
 	"net.open_connections.raw", "docker.usageinkernelmode", "docker.usageinusermode",
+	"publicendpoint.latency.mean", "publicendpoint.latency.p50", "publicendpoint.latency.p95",
+	"publicendpoint.latency.p99",
 }
 var internalTenantStats = []string{
 	"storage.filesystem.available.%s", "storage.filesystem.used.%s",
@@ -287,6 +293,142 @@ func getInternalGraphConfigs(serviceID string) []domain.GraphConfig {
 	}
 }
 
+// publicEndpointRateDataPoint is a data point of the per second rate of a
+// public endpoint counter, summed across the endpoints of the service
+func publicEndpointRateDataPoint(metric, name string) domain.DataPoint {
+	return domain.DataPoint{
+		Aggregator:   "sum",
+		Format:       "%4.2f",
+		Legend:       name,
+		Metric:       metric,
+		MetricSource: "metrics",
+		ID:           metric,
+		Name:         name,
+		Rate:         true,
+		RateOptions: &domain.DataPointRateOptions{
+			Counter: true,
+			// supress extreme outliers
+			ResetThreshold: 1,
+		},
+		Type: "line",
+	}
+}
+
+// getPublicEndpointGraphConfigs returns the graphs of the requests proxied to
+// the vhost and port public endpoints of a service
+func getPublicEndpointGraphConfigs(serviceID string) []domain.GraphConfig {
+	tags := map[string][]string{
+		"controlplane_service_id": []string{serviceID},
+	}
+	tRange := domain.GraphConfigRange{
+		Start: "1h-ago",
+		End:   "0s-ago",
+	}
+	zero := 0
+
+	return []domain.GraphConfig{
+		{
+			// request rate graph
+			ID:          "publicEndpointRequests",
+			Name:        "Public Endpoint Requests",
+			BuiltIn:     true,
+			Format:      "%4.2f",
+			ReturnSet:   "EXACT",
+			Type:        "line",
+			Tags:        tags,
+			YAxisLabel:  "requests/s",
+			Description: "Requests per second to the public endpoints",
+			MinY:        &zero,
+			Range:       &tRange,
+			Units:       "Requests per second",
+			DataPoints: []domain.DataPoint{
+				publicEndpointRateDataPoint("publicendpoint.requests", "Requests"),
+				publicEndpointRateDataPoint("publicendpoint.errors", "Server Errors"),
+			},
+		}, {
+			// status code graph
+			ID:          "publicEndpointStatus",
+			Name:        "Public Endpoint Status Codes",
+			BuiltIn:     true,
+			Format:      "%4.2f",
+			ReturnSet:   "EXACT",
+			Type:        "line",
+			Tags:        tags,
+			YAxisLabel:  "responses/s",
+			Description: "Responses per second from the public endpoints by status code",
+			MinY:        &zero,
+			Range:       &tRange,
+			Units:       "Responses per second",
+			DataPoints: []domain.DataPoint{
+				publicEndpointRateDataPoint("publicendpoint.status.2xx", "2xx"),
+				publicEndpointRateDataPoint("publicendpoint.status.3xx", "3xx"),
+				publicEndpointRateDataPoint("publicendpoint.status.4xx", "4xx"),
+				publicEndpointRateDataPoint("publicendpoint.status.5xx", "5xx"),
+			},
+		}, {
+			// latency graph
+			ID:          "publicEndpointLatency",
+			Name:        "Public Endpoint Latency",
+			BuiltIn:     true,
+			Format:      "%4.2f",
+			ReturnSet:   "EXACT",
+			Type:        "line",
+			Tags:        tags,
+			YAxisLabel:  "ms",
+			Description: "Latency of the requests to the public endpoints",
+			MinY:        &zero,
+			Range:       &tRange,
+			Units:       "Milliseconds",
+			DataPoints: []domain.DataPoint{
+				domain.DataPoint{
+					Aggregator:   "max",
+					Format:       "%4.2f",
+					Legend:       "Median",
+					Metric:       "publicendpoint.latency.p50",
+					MetricSource: "metrics",
+					ID:           "publicendpoint.latency.p50",
+					Name:         "Median",
+					Rate:         false,
+					Type:         "line",
+				},
+				domain.DataPoint{
+					Aggregator:   "max",
+					Format:       "%4.2f",
+					Legend:       "95th Percentile",
+					Metric:       "publicendpoint.latency.p95",
+					MetricSource: "metrics",
+					ID:           "publicendpoint.latency.p95",
+					Name:         "95th Percentile",
+					Rate:         false,
+					Type:         "line",
+				},
+				domain.DataPoint{
+					Aggregator:   "max",
+					Format:       "%4.2f",
+					Legend:       "99th Percentile",
+					Metric:       "publicendpoint.latency.p99",
+					MetricSource: "metrics",
+					ID:           "publicendpoint.latency.p99",
+					Name:         "99th Percentile",
+					Rate:         false,
+					Type:         "line",
+				},
+			},
+		},
+	}
+}
+
+// hasPublicEndpoints returns true if the service has a vhost or port public
+// endpoint
+func hasPublicEndpoints(svc *service.Service) bool {
+	for _, ep := range svc.Endpoints {
+		if len(ep.VHostList) > 0 || len(ep.PortList) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func getTenantGraphConfigs(tenantID string) []domain.GraphConfig {
 	tRange := domain.GraphConfigRange{
 		Start: "1h-ago",
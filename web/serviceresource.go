@@ -14,8 +14,11 @@
 package web
 
 import (
+	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/health"
 	"github.com/zenoss/glog"
@@ -91,3 +94,32 @@ func restGetServicesHealth(w *rest.ResponseWriter, r *rest.Request, ctx *request
 	})
 	return
 }
+
+// restGetServiceHealthHistory returns the recent results of the health checks
+// of the instances of a service
+func restGetServiceHealthHistory(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
+	if err != nil {
+		glog.Errorf("Could not get serviceId: %v", err)
+		restBadRequest(w, err)
+		return
+	}
+
+	history, err := ctx.getFacade().GetServiceHealthHistory(ctx.getDatastoreContext(), serviceID)
+	if datastore.IsErrNoSuchEntity(err) {
+		writeJSON(w, fmt.Sprintf("Service %v Not Found", serviceID), http.StatusNotFound)
+		return
+	} else if err != nil {
+		glog.Errorf("Could not get health history of service %s: %s", serviceID, err)
+		restServerError(w, err)
+		return
+	}
+
+	w.WriteJson(struct {
+		Timestamp int64
+		History   map[int]map[string]health.HealthHistory
+	}{
+		Timestamp: time.Now().UTC().Unix(),
+		History:   history,
+	})
+}
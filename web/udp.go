@@ -0,0 +1,247 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/proxy"
+)
+
+// maxPendingDatagrams is how many datagrams of a client are kept while its
+// session is being opened; later ones are dropped
+const maxPendingDatagrams = 64
+
+// udpSession forwards the datagrams of one client address to an export
+type udpSession struct {
+	last    int64 // unix nanoseconds of the last datagram in either direction
+	mu      sync.Mutex
+	remote  net.Conn // nil while the session is being opened
+	pending [][]byte // datagrams received while the session is being opened
+	closed  bool
+}
+
+func (s *udpSession) touch() {
+	atomic.StoreInt64(&s.last, time.Now().UnixNano())
+}
+
+func (s *udpSession) idle(timeout time.Duration) bool {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&s.last))) >= timeout
+}
+
+// forward sends a datagram to the export, or keeps a copy of it until the
+// session is open
+func (s *udpSession) forward(addr net.Addr, data []byte) {
+	s.touch()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if s.remote == nil {
+		if len(s.pending) < maxPendingDatagrams {
+			s.pending = append(s.pending, append([]byte{}, data...))
+		} else {
+			plog.WithField("client", addr.String()).Debug("Dropped datagram while opening udp session")
+		}
+		return
+	}
+	if _, err := s.remote.Write(data); err != nil {
+		plog.WithError(err).WithField("client", addr.String()).Debug("Could not forward datagram")
+	}
+}
+
+// start sends the pending datagrams to the export once it is connected.  It
+// returns false if the session was closed while it was being opened.
+func (s *udpSession) start(addr net.Addr, remote net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.remote = remote
+	for _, data := range s.pending {
+		if _, err := remote.Write(data); err != nil {
+			plog.WithError(err).WithField("client", addr.String()).Debug("Could not forward datagram")
+		}
+	}
+	s.pending = nil
+	return true
+}
+
+// close stops the session from forwarding any more datagrams
+func (s *udpSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.pending = nil
+	if s.remote != nil {
+		s.remote.Close()
+	}
+}
+
+// ServeUDP forwards the datagrams of each client address to an export, and
+// the replies of the export back to the client, until neither sends a
+// datagram for proxy.DatagramIdleTimeout.  Each client address counts as a
+// connection of the policy; datagrams from clients it refuses are dropped.
+// Sessions are opened in the background, so a slow export does not hold up
+// the datagrams of other clients.
+func ServeUDP(cancel <-chan struct{}, conn net.PacketConn, exports Exports, policy *EndpointPolicy) {
+	mu := &sync.Mutex{}
+	sessions := make(map[string]*udpSession)
+	stopped := false
+	wg := &sync.WaitGroup{}
+
+	go func() {
+		buf := make([]byte, proxy.MaxDatagramSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				plog.WithError(err).Debug("Stopping read on host:port")
+				return
+			}
+
+			mu.Lock()
+			if stopped {
+				mu.Unlock()
+				return
+			}
+			session, ok := sessions[addr.String()]
+			if !ok {
+				session = &udpSession{last: time.Now().UnixNano()}
+				sessions[addr.String()] = session
+				wg.Add(1)
+			}
+			mu.Unlock()
+
+			if !ok {
+				go func(addr net.Addr, session *udpSession) {
+					defer wg.Done()
+					defer func() {
+						mu.Lock()
+						delete(sessions, addr.String())
+						mu.Unlock()
+						session.close()
+					}()
+					remote := openUDPSession(addr, exports, policy)
+					if remote == nil {
+						return
+					}
+					if policy != nil {
+						defer policy.release()
+					}
+					if !session.start(addr, remote) {
+						remote.Close()
+						return
+					}
+					replyUDPSession(conn, addr, session)
+				}(addr, session)
+			}
+
+			session.forward(addr, buf[:n])
+		}
+	}()
+
+	<-cancel
+	conn.Close()
+	mu.Lock()
+	stopped = true
+	for _, session := range sessions {
+		session.close()
+	}
+	mu.Unlock()
+	wg.Wait()
+}
+
+// openUDPSession connects a new client address to an export, or returns nil
+// if the policy refuses the client or no export could be reached
+func openUDPSession(addr net.Addr, exports Exports, policy *EndpointPolicy) net.Conn {
+	if policy != nil {
+		if reason := policy.admitConnection(addr); reason != "" {
+			plog.WithFields(log.Fields{
+				"endpoint": policy.name,
+				"client":   addr.String(),
+				"reason":   reason,
+			}).Debug("Refused public endpoint datagram")
+			return nil
+		}
+	}
+
+	// pin the client by its address if the routing asks for it
+	routing := exports.Routing()
+	key := ""
+	if routing.Affinity == servicedefinition.AffinitySourceIP {
+		key = clientHost(addr.String())
+	}
+
+	tried := make(map[string]bool)
+	for {
+		export := exports.Pick(key, tried)
+		if export == nil {
+			plog.Warn("Could not retrieve endpoint")
+			break
+		}
+
+		logger := plog.WithFields(log.Fields{
+			"application": export.Application,
+			"hostip":      export.HostIP,
+			"privateip":   export.PrivateIP,
+		})
+
+		remote, err := GetRemoteDatagramConnection(config.MuxTLSIsEnabled(), export)
+		if err != nil {
+			logger.WithError(err).Error("Could not get remote connection for endpoint")
+			if routing.DisableRetry {
+				break
+			}
+			tried[ExportKey(export)] = true
+			continue
+		}
+
+		logger.WithField("client", addr.String()).Debug("Opened udp session")
+		return remote
+	}
+
+	if policy != nil {
+		policy.release()
+	}
+	return nil
+}
+
+// replyUDPSession sends the datagrams of the export back to the client until
+// the session is idle or the export fails
+func replyUDPSession(conn net.PacketConn, addr net.Addr, session *udpSession) {
+	buf := make([]byte, proxy.MaxDatagramSize)
+	remote := session.remote
+	for {
+		remote.SetReadDeadline(time.Now().Add(proxy.DatagramIdleTimeout))
+		n, err := remote.Read(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && !session.idle(proxy.DatagramIdleTimeout) {
+				continue
+			}
+			return
+		}
+		session.touch()
+		if _, err := conn.WriteTo(buf[:n], addr); err != nil {
+			return
+		}
+	}
+}
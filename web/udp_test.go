@@ -0,0 +1,202 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/zzk/registry"
+	"github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestServeUDP(c *C) {
+	ipmap["127.0.0.1"] = struct{}{}
+	defer delete(ipmap, "127.0.0.1")
+
+	// the backend answers each datagram with the address it came from
+	backend, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer backend.Close()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := backend.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			backend.WriteTo(append(buf[:n], []byte(" from "+addr.String())...), addr)
+		}
+	}()
+
+	exports := NewRoundRobinExports([]registry.ExportDetails{
+		{
+			ExportBinding: service.ExportBinding{Application: "syslog", PortNumber: uint16(backend.LocalAddr().(*net.UDPAddr).Port)},
+			HostIP:        "127.0.0.1",
+			PrivateIP:     "127.0.0.1",
+		},
+	}, nil)
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	policy := NewEndpointPolicy(conn.LocalAddr().String())
+	policy.Set(servicedefinition.AccessPolicy{MaxConnections: 1})
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		ServeUDP(cancel, conn, exports, policy)
+		close(done)
+	}()
+	defer func() {
+		close(cancel)
+		<-done
+	}()
+
+	dial := func() net.Conn {
+		client, err := net.Dial("udp4", conn.LocalAddr().String())
+		c.Assert(err, IsNil)
+		return client
+	}
+	exchange := func(client net.Conn, message string) (string, error) {
+		if _, err := client.Write([]byte(message)); err != nil {
+			return "", err
+		}
+		client.SetReadDeadline(time.Now().Add(time.Second))
+		buf := make([]byte, 1024)
+		n, err := client.Read(buf)
+		return string(buf[:n]), err
+	}
+
+	// each datagram of a client goes through the same session
+	first := dial()
+	defer first.Close()
+	reply, err := exchange(first, "one")
+	c.Assert(err, IsNil)
+	replyTwo, err := exchange(first, "two")
+	c.Assert(err, IsNil)
+	c.Check(reply[len("one"):], Equals, replyTwo[len("two"):])
+
+	// the session holds the only connection of the policy
+	second := dial()
+	defer second.Close()
+	_, err = exchange(second, "three")
+	c.Check(err, NotNil)
+}
+
+// slowExports holds up picking an export for the first session until it is
+// released
+type slowExports struct {
+	*RoundRobinExports
+	picking chan struct{} // closed when the first session is being opened
+	release chan struct{}
+	picked  int32
+}
+
+func (e *slowExports) Pick(clientKey string, tried map[string]bool) *registry.ExportDetails {
+	if atomic.CompareAndSwapInt32(&e.picked, 0, 1) {
+		close(e.picking)
+		<-e.release
+	}
+	return e.RoundRobinExports.Pick(clientKey, tried)
+}
+
+func (s *TestWebSuite) TestServeUDP_SlowSession(c *C) {
+	ipmap["127.0.0.1"] = struct{}{}
+	defer delete(ipmap, "127.0.0.1")
+
+	backend, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer backend.Close()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := backend.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			backend.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	exports := &slowExports{
+		RoundRobinExports: NewRoundRobinExports([]registry.ExportDetails{
+			{
+				ExportBinding: service.ExportBinding{Application: "syslog", PortNumber: uint16(backend.LocalAddr().(*net.UDPAddr).Port)},
+				HostIP:        "127.0.0.1",
+				PrivateIP:     "127.0.0.1",
+			},
+		}, nil),
+		picking: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		ServeUDP(cancel, conn, exports, nil)
+		close(done)
+	}()
+	released := false
+	defer func() {
+		if !released {
+			close(exports.release)
+		}
+		close(cancel)
+		<-done
+	}()
+
+	read := func(client net.Conn) (string, error) {
+		client.SetReadDeadline(time.Now().Add(time.Second))
+		buf := make([]byte, 1024)
+		n, err := client.Read(buf)
+		return string(buf[:n]), err
+	}
+
+	// the first client's session is still being opened
+	slow, err := net.Dial("udp4", conn.LocalAddr().String())
+	c.Assert(err, IsNil)
+	defer slow.Close()
+	_, err = slow.Write([]byte("one"))
+	c.Assert(err, IsNil)
+	_, err = slow.Write([]byte("two"))
+	c.Assert(err, IsNil)
+	<-exports.picking
+
+	// which does not hold up another client
+	fast, err := net.Dial("udp4", conn.LocalAddr().String())
+	c.Assert(err, IsNil)
+	defer fast.Close()
+	_, err = fast.Write([]byte("three"))
+	c.Assert(err, IsNil)
+	reply, err := read(fast)
+	c.Assert(err, IsNil)
+	c.Check(reply, Equals, "three")
+
+	// the datagrams of the first client are forwarded once it is open
+	close(exports.release)
+	released = true
+	reply, err = read(slow)
+	c.Assert(err, IsNil)
+	c.Check(reply, Equals, "one")
+	reply, err = read(slow)
+	c.Assert(err, IsNil)
+	c.Check(reply, Equals, "two")
+}
@@ -59,6 +59,8 @@
                     $scope.protocols.push({ Label: "HTTP", UseTLS: false, Protocol: "http" });
                     $scope.protocols.push({ Label: "Other, secure (TLS)", UseTLS: true, Protocol: "" });
                     $scope.protocols.push({ Label: "Other, non-secure", UseTLS: false, Protocol: "" });
+                    $scope.protocols.push({ Label: "UDP", UseTLS: false, Protocol: "udp" });
+                    $scope.protocols.push({ Label: "TLS passthrough", UseTLS: false, Protocol: "tls-passthrough" });
 
                     // default public endpoint options
                     $scope.publicEndpoints.add = {
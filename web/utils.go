@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/control-center/serviced/auth"
@@ -103,14 +104,30 @@ func newTlsDialer(config *tls.Config) dialerInterface {
 
 // GetRemoteConnection returns a connection to a remote address
 func GetRemoteConnection(useTLS bool, export *registry.ExportDetails) (remote net.Conn, err error) {
-	var dialer dialerInterface
+	return getRemoteConnection(export, newExportDialer(useTLS, export))
+}
+
+// GetRemoteDatagramConnection returns a connection that sends and receives
+// the datagrams of a udp export
+func GetRemoteDatagramConnection(useTLS bool, export *registry.ExportDetails) (net.Conn, error) {
+	// If the exported endpoint is on this Host, we don't go through the mux.
+	if IsLocalAddress(export.HostIP) {
+		address := net.JoinHostPort(export.PrivateIP, strconv.Itoa(int(export.PortNumber)))
+		return net.Dial("udp4", address)
+	}
+
+	remote, err := dialMux(export, newExportDialer(useTLS, export), auth.MuxProtocolUDP)
+	if err != nil {
+		return nil, err
+	}
+	return proxy.NewDatagramConn(remote), nil
+}
+
+func newExportDialer(useTLS bool, export *registry.ExportDetails) dialerInterface {
 	if useTLS && !IsLocalAddress(export.HostIP) {
-		config := tls.Config{InsecureSkipVerify: true}
-		dialer = newTlsDialer(&config)
-	} else {
-		dialer = newNetDialer()
+		return newTlsDialer(auth.MuxClientTLSConfig())
 	}
-	return getRemoteConnection(export, dialer)
+	return newNetDialer()
 }
 
 func getRemoteConnection(export *registry.ExportDetails, dialer dialerInterface) (net.Conn, error) {
@@ -120,7 +137,12 @@ func getRemoteConnection(export *registry.ExportDetails, dialer dialerInterface)
 		address := fmt.Sprintf("%s:%d", export.PrivateIP, export.PortNumber)
 		return dialer.Dial("tcp4", address)
 	}
+	return dialMux(export, dialer, auth.MuxProtocolTCP)
+}
 
+// dialMux connects to the mux on the host of the export, which proxies the
+// connection to the export with the protocol
+func dialMux(export *registry.ExportDetails, dialer dialerInterface, protocol byte) (net.Conn, error) {
 	// Set up the remote address for the mux
 	remoteAddress := fmt.Sprintf("%s:%d", export.HostIP, export.MuxPort)
 	remote, err := dialer.Dial("tcp4", remoteAddress)
@@ -149,7 +171,7 @@ func getRemoteConnection(export *registry.ExportDetails, dialer dialerInterface)
 		return nil, err
 	}
 
-	if err := auth.AddSignedMuxHeader(remote, muxAddr, token); err != nil {
+	if err := auth.AddSignedMuxProtocolHeader(remote, muxAddr, protocol, token); err != nil {
 		plog.WithError(err).Error("Unable to send authenticated mux header")
 		return nil, err
 	}
@@ -24,17 +24,19 @@ import (
 
 // VHostManager manages all vhosts on a host
 type VHostManager struct {
-	useTLS bool
-	mu     *sync.RWMutex
-	vhosts map[string]*VHostHandler
+	useTLS  bool
+	healthy HealthFunc
+	mu      *sync.RWMutex
+	vhosts  map[string]*VHostHandler
 }
 
 // NewVHostManager creates a new vhost manager for a host
-func NewVHostManager(useTLS bool) *VHostManager {
+func NewVHostManager(useTLS bool, healthy HealthFunc) *VHostManager {
 	return &VHostManager{
-		useTLS: useTLS,
-		mu:     &sync.RWMutex{},
-		vhosts: make(map[string]*VHostHandler),
+		useTLS:  useTLS,
+		healthy: healthy,
+		mu:      &sync.RWMutex{},
+		vhosts:  make(map[string]*VHostHandler),
 	}
 }
 
@@ -45,7 +47,7 @@ func (m *VHostManager) Enable(name string) {
 
 	h, ok := m.vhosts[name]
 	if !ok {
-		h = NewVHostHandler()
+		h = NewVHostHandler(name, m.healthy)
 		m.vhosts[name] = h
 	}
 	h.Enable()
@@ -71,11 +73,24 @@ func (m *VHostManager) Set(name string, data []registry.ExportDetails) {
 	if ok {
 		h.SetExports(data)
 	} else {
-		h = NewVHostHandler(data...)
+		h = NewVHostHandler(name, m.healthy, data...)
 		m.vhosts[name] = h
 	}
 }
 
+// SetRouting updates how requests to the vhost are routed
+func (m *VHostManager) SetRouting(name string, routing registry.Routing) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.vhosts[name]
+	if !ok {
+		h = NewVHostHandler(name, m.healthy)
+		m.vhosts[name] = h
+	}
+	h.SetRouting(routing)
+}
+
 // Handle manages a vhost request and returns true if the vhost is enabled
 func (m *VHostManager) Handle(httphost string, w http.ResponseWriter, r *http.Request) bool {
 	m.mu.RLock()
@@ -98,15 +113,19 @@ func (m *VHostManager) handle(name string, w http.ResponseWriter, r *http.Reques
 
 // VHostHandler manages a vhost endpoint
 type VHostHandler struct {
+	name    string
 	exports Exports
+	policy  *EndpointPolicy
 	mu      *sync.RWMutex
 	enabled bool
 }
 
 // NewVHostHandler instantiates a new vhost handler
-func NewVHostHandler(data ...registry.ExportDetails) *VHostHandler {
+func NewVHostHandler(name string, healthy HealthFunc, data ...registry.ExportDetails) *VHostHandler {
 	return &VHostHandler{
-		exports: NewRoundRobinExports(data), // default to round-robin
+		name:    name,
+		exports: NewRoundRobinExports(data, healthy), // default to round-robin
+		policy:  NewEndpointPolicy(name),
 		mu:      &sync.RWMutex{},
 		enabled: false,
 	}
@@ -131,6 +150,13 @@ func (h *VHostHandler) SetExports(data []registry.ExportDetails) {
 	h.exports.Set(data)
 }
 
+// SetRouting updates how requests to the vhost are routed and which clients
+// may send them
+func (h *VHostHandler) SetRouting(routing registry.Routing) {
+	h.exports.SetRouting(routing)
+	h.policy.Set(routing.Policy)
+}
+
 // Handle is the vhost handler, returns true if the vhost is enabled
 func (h *VHostHandler) Handle(useTLS bool, w http.ResponseWriter, r *http.Request) bool {
 	h.mu.RLock()
@@ -141,26 +167,21 @@ func (h *VHostHandler) Handle(useTLS bool, w http.ResponseWriter, r *http.Reques
 		return false
 	}
 
-	// get the next available export
-	export := h.exports.Next()
-	if export == nil {
-		http.Error(w, "endpoint not available", http.StatusNotFound)
-		return true
-	}
-
 	RouteOriginalURL(r)
 
 	logger := plog.WithFields(log.Fields{
-		"application": export.Application,
-		"hostip":      export.HostIP,
-		"privateip":   export.PrivateIP,
-		"request":     r,
+		"vhost":   h.name,
+		"request": r,
 	})
 
-	logger.Debug("Proxying endpoint")
+	// refuse the clients that the access policy of the vhost does not admit
+	done, ok := h.policy.admitRequest(w, r, true)
+	if !ok {
+		return true
+	}
+	defer done()
 
-	// get the reverse proxy for the export
-	rp := GetReverseProxy(useTLS, export)
+	logger.Debug("Proxying endpoint")
 
 	// Set up the X-Forwarded-Proto header so that downstream servers know
 	// the request originated as HTTPS.
@@ -169,7 +190,11 @@ func (h *VHostHandler) Handle(useTLS bool, w http.ResponseWriter, r *http.Reques
 	}
 
 	w.Header().Add("Strict-Transport-Security", "max-age=31536000")
-	rp.ServeHTTP(w, r)
+
+	// send the request to the next available export
+	if !proxyEndpoint(EndpointTypeVHost, h.name, useTLS, h.exports, w, r) {
+		http.Error(w, "endpoint not available", http.StatusNotFound)
+	}
 
 	return true
 }
@@ -16,3 +16,6 @@ func (_m *PublicPortHandler) Disable(port string) {
 func (_m *PublicPortHandler) Set(port string, exports []registry.ExportDetails) {
 	_m.Called(port, exports)
 }
+func (_m *PublicPortHandler) SetRouting(port string, routing registry.Routing) {
+	_m.Called(port, routing)
+}
@@ -16,3 +16,6 @@ func (_m *VHostHandler) Disable(name string) {
 func (_m *VHostHandler) Set(name string, exports []registry.ExportDetails) {
 	_m.Called(name, exports)
 }
+func (_m *VHostHandler) SetRouting(name string, routing registry.Routing) {
+	_m.Called(name, routing)
+}
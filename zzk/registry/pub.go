@@ -15,6 +15,7 @@ package registry
 
 import (
 	"path"
+	"reflect"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
@@ -27,7 +28,8 @@ type PublicPort struct {
 	ServiceID   string // TODO: search by tenant and application
 	Protocol    string
 	UseTLS      bool
-	version     interface{}
+	Routing
+	version interface{}
 }
 
 // Version implements client.Node
@@ -45,6 +47,7 @@ type PublicPortHandler interface {
 	Enable(port string, protocol string, useTLS bool)
 	Disable(port string)
 	Set(port string, exports []ExportDetails)
+	SetRouting(port string, routing Routing)
 }
 
 // PublicPortListener listens to ports for a provided ip
@@ -100,6 +103,9 @@ func (l *PublicPortListener) Spawn(shutdown <-chan interface{}, portAddr string)
 	// looked up.
 	exportMap := make(map[string]ExportDetails)
 
+	// keep track of the routing of the port
+	routing := Routing{}
+
 	isEnabled := false
 	defer func() {
 		if isEnabled {
@@ -190,6 +196,11 @@ func (l *PublicPortListener) Spawn(shutdown <-chan interface{}, portAddr string)
 			exLogger.Debug("Set new endpoints for export")
 		}
 
+		if !reflect.DeepEqual(dat.Routing, routing) {
+			l.handler.SetRouting(portAddr, dat.Routing)
+			routing = dat.Routing
+		}
+
 		if !isEnabled {
 			l.handler.Enable(portAddr, dat.Protocol, dat.UseTLS)
 			logger.Debug("Enabled port")
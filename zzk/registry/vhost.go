@@ -15,17 +15,31 @@ package registry
 
 import (
 	"path"
+	"reflect"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
+// Routing describes how requests are spread across the exports of a vhost
+// or public port, how they are logged, and which clients may send them
+type Routing struct {
+	Affinity         string                         // cookie or sourceip, empty for no affinity
+	DisableRetry     bool                           // do not retry on another export when a connection fails
+	DisableAccessLog bool                           // do not write requests to the access log
+	AccessLogSample  float64                        // fraction of requests written to the access log; 0 writes every request
+	Policy           servicedefinition.AccessPolicy // clients that are allowed and how often
+	ServerNames      []string                       // server names a tls-passthrough port accepts; empty for every name
+}
+
 // VHost describes a vhost endpoint
 type VHost struct {
 	TenantID    string
 	ServiceID   string
 	Application string
-	version     interface{}
+	Routing
+	version interface{}
 }
 
 // Version implements client.Node
@@ -43,6 +57,7 @@ type VHostHandler interface {
 	Enable(name string)
 	Disable(name string)
 	Set(name string, exports []ExportDetails)
+	SetRouting(name string, routing Routing)
 }
 
 // VHostListener listens for vhosts on a host
@@ -95,6 +110,9 @@ func (l *VHostListener) Spawn(shutdown <-chan interface{}, subdomain string) {
 	// looked up.
 	exportMap := make(map[string]ExportDetails)
 
+	// keep track of the routing of the vhost
+	routing := Routing{}
+
 	// keep track of the on/off state of the export
 	isEnabled := false
 	defer func() {
@@ -188,6 +206,12 @@ func (l *VHostListener) Spawn(shutdown <-chan interface{}, subdomain string) {
 			l.handler.Set(subdomain, exports)
 		}
 
+		// update the routing if it has changed
+		if !reflect.DeepEqual(dat.Routing, routing) {
+			l.handler.SetRouting(subdomain, dat.Routing)
+			routing = dat.Routing
+		}
+
 		// do something if the state of the vhost has changed
 		if !isEnabled {
 			l.handler.Enable(subdomain)
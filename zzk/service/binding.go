@@ -42,6 +42,7 @@ type ImportBinding struct {
 	PortNumber     uint16
 	PortTemplate   string
 	VirtualAddress string
+	LoadBalancing  string // roundrobin, leastconn or hash
 }
 
 // GetPortNumber retrieves a port number for a given instance ID
@@ -22,6 +22,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 // HostStateHandler is the handler for running the HostListener
@@ -54,6 +55,10 @@ type HostStateHandler interface {
 	// PauseContainer pauses a running container.  Returns nil if the container
 	// has stopped or if it doesn't exist.
 	PauseContainer(serviceID string, instanceID int) error
+
+	// ExitCode returns the exit code of the last container that exited for
+	// the service instance, or false if it is not known.
+	ExitCode(serviceID string, instanceID int) (int, bool)
 }
 
 // HostStateListener is the listener for monitoring service instances
@@ -253,6 +258,16 @@ func (l *HostStateListener) Spawn(cancel <-chan interface{}, stateID string) {
 			}
 		}
 
+		// wait out the restart backoff of an exited container
+		var backoff *time.Timer
+		var backoffC <-chan time.Time
+		if containerExit == nil && hsdat.DesiredState == service.SVCRun && !ssdat.Restart.Halted {
+			if d := ssdat.Restart.Backoff(); d > 0 {
+				backoff = time.NewTimer(d)
+				backoffC = backoff.C
+			}
+		}
+
 		hslogger.Debug("Waiting for event on host state")
 		select {
 		case hostStateEvent := <-hsevt:
@@ -279,6 +294,8 @@ func (l *HostStateListener) Spawn(cancel <-chan interface{}, stateID string) {
 				return
 			}
 			containerExit = nil
+		case <-backoffC:
+			hslogger.Debug("Restart backoff expired")
 		case <-cancel:
 			hslogger.Debug("Host state listener received signal to cancel listening")
 			return
@@ -288,6 +305,9 @@ func (l *HostStateListener) Spawn(cancel <-chan interface{}, stateID string) {
 			return
 		}
 
+		if backoff != nil {
+			backoff.Stop()
+		}
 		close(done)
 		done = make(chan struct{})
 	}
@@ -317,15 +337,22 @@ func (l *HostStateListener) setInstanceState(containerExit <-chan time.Time, ssd
 
 	switch hsdat.DesiredState {
 	case service.SVCRun:
-		if containerExit == nil {
+		if containerExit == nil && ssdat.Restart.Halted {
+			logger.Debug("Restart policy will not restart the container")
+		} else if containerExit == nil && ssdat.Restart.Backoff() > 0 {
+			logger.WithField("restartat", ssdat.Restart.RestartAt).Debug("Backing off before restarting the container")
+		} else if containerExit == nil {
 			logger.Debug("Container doesn't exist, starting")
 			// container is detached because it doesn't exist
+			restart := ssdat.Restart
 			ssdat, containerExit, err = l.handler.StartContainer(l.shutdown, serviceID, instanceID)
 			if err != nil {
 				logger.WithError(err).Error("Could not start container")
 				l.cleanUpContainers([]string{stateID}, true)
 				return nil, nil, false
 			}
+			restart.RestartAt = time.Time{}
+			ssdat.Restart = restart
 
 			// set the service state
 			if !l.setExistingThreadOrShutdown(stateID, ssdat, containerExit) {
@@ -368,6 +395,8 @@ func (l *HostStateListener) setInstanceState(containerExit <-chan time.Time, ssd
 			"restarted": ssdat.Restarted,
 		})
 		rsLogger.Debug("checking if restart necessary")
+		// a restart by hand overrides the restart policy
+		ssdat.Restart.Reset()
 		// only try to restart once if the container hasn't already been
 		// restarted.
 		if ssdat.Restarted.Before(ssdat.Started) {
@@ -476,10 +505,34 @@ func (l *HostStateListener) handleContainerExit(timeExit time.Time, ssdat *Servi
 
 	// set the service state
 	ssdat.Terminated = timeExit
+	exitCode := -1
+	if ssdat.RestartPolicy.Condition == servicedefinition.RestartOnFailure {
+		if code, ok := l.handler.ExitCode(req.ServiceID, req.InstanceID); ok {
+			exitCode = code
+		}
+	}
+	restarting := ssdat.Restart.Exited(ssdat.RestartPolicy, exitCode, ssdat.Started, timeExit)
 	l.setExistingThread(stateID, ssdat, nil)
-	logger.WithField("terminated", timeExit).Warn("Container exited, restarting")
 
-	if err := l.updateServiceStateInZK(ssdat, req); err != nil {
+	logger = logger.WithFields(log.Fields{
+		"terminated": timeExit,
+		"restarts":   ssdat.Restart.Restarts,
+	})
+	if !restarting {
+		logger.Warn("Container exited, restart policy will not restart it")
+	} else if !ssdat.Restart.RestartAt.IsZero() {
+		logger.WithField("restartat", ssdat.Restart.RestartAt).Warn("Container exited, restarting after backoff")
+	} else {
+		logger.Warn("Container exited, restarting")
+	}
+
+	if err := UpdateState(l.conn, req, func(s *State) bool {
+		s.ServiceState = *ssdat
+		if restarting && !ssdat.Restart.RestartAt.IsZero() {
+			s.Status = service.StateBackoff
+		}
+		return true
+	}); err != nil {
 		logger.WithError(err).Error("Could not set state for stopped container")
 		// TODO: we currently don't support containers restarting if
 		// shut down during an outage, so don't bother
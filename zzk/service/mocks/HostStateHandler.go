@@ -108,3 +108,24 @@ func (_m *HostStateHandler) PauseContainer(serviceID string, instanceID int) err
 
 	return r0
 }
+
+// ExitCode provides a mock function with given fields: serviceID, instanceID
+func (_m *HostStateHandler) ExitCode(serviceID string, instanceID int) (int, bool) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, int) int); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(string, int) bool); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
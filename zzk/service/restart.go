@@ -0,0 +1,90 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// RestartState tracks how the restart policy of an instance has restarted its
+// container.
+type RestartState struct {
+	Restarts  int         // times the container was restarted after it exited
+	Failures  int         // consecutive restarts that grow the backoff
+	Recent    []time.Time `json:",omitempty"` // restarts counted against the policy's max restarts
+	RestartAt time.Time   // when a backing off container may start again
+	Halted    bool        // the restart policy will not restart the container
+}
+
+// Exited records that the container exited with the given code after running
+// from started until terminated, and decides by the policy whether and when
+// the container starts again.  Returns false if the instance should stay
+// stopped.
+func (r *RestartState) Exited(policy servicedefinition.RestartPolicy, exitCode int, started, terminated time.Time) bool {
+	r.RestartAt = time.Time{}
+	if !policy.Restarts(exitCode) {
+		r.Halted = true
+		return false
+	}
+
+	if policy.MaxRestarts > 0 {
+		recent := r.Recent[:0]
+		for _, t := range r.Recent {
+			if policy.Window == 0 || terminated.Sub(t) < policy.Window {
+				recent = append(recent, t)
+			}
+		}
+		r.Recent = recent
+		if len(r.Recent) >= policy.MaxRestarts {
+			r.Halted = true
+			return false
+		}
+		r.Recent = append(r.Recent, terminated)
+	}
+
+	// a container that stayed up longer than the longest backoff was not
+	// crash looping, so start over at the initial backoff
+	if !started.IsZero() && terminated.Sub(started) >= policy.MaxDelay() {
+		r.Failures = 0
+	}
+	if delay := policy.Delay(r.Failures); delay > 0 {
+		r.RestartAt = terminated.Add(delay)
+	}
+	r.Failures++
+	r.Restarts++
+	r.Halted = false
+	return true
+}
+
+// Reset clears the backoff so that the container starts right away, as when
+// the instance is restarted by hand.
+func (r *RestartState) Reset() {
+	r.Failures = 0
+	r.Recent = nil
+	r.RestartAt = time.Time{}
+	r.Halted = false
+}
+
+// Backoff returns how long the container should wait before starting again.
+func (r RestartState) Backoff() time.Duration {
+	if r.RestartAt.IsZero() {
+		return 0
+	}
+	if d := time.Until(r.RestartAt); d > 0 {
+		return d
+	}
+	return 0
+}
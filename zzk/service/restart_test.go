@@ -0,0 +1,92 @@
+// Copyright 2026 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&RestartStateUnitTestSuite{})
+
+type RestartStateUnitTestSuite struct{}
+
+func (s *RestartStateUnitTestSuite) TestExited_Default(c *C) {
+	r := RestartState{}
+	now := time.Now()
+	c.Assert(r.Exited(servicedefinition.RestartPolicy{}, 1, now.Add(-time.Second), now), Equals, true)
+	c.Assert(r.Restarts, Equals, 1)
+	c.Assert(r.RestartAt.IsZero(), Equals, true)
+	c.Assert(r.Backoff(), Equals, time.Duration(0))
+}
+
+func (s *RestartStateUnitTestSuite) TestExited_Condition(c *C) {
+	now := time.Now()
+	policy := servicedefinition.RestartPolicy{Condition: servicedefinition.RestartOnFailure}
+
+	r := RestartState{}
+	c.Assert(r.Exited(policy, 0, now.Add(-time.Second), now), Equals, false)
+	c.Assert(r.Halted, Equals, true)
+	c.Assert(r.Restarts, Equals, 0)
+
+	r = RestartState{}
+	c.Assert(r.Exited(policy, 2, now.Add(-time.Second), now), Equals, true)
+	c.Assert(r.Halted, Equals, false)
+
+	policy.Condition = servicedefinition.RestartNever
+	r = RestartState{}
+	c.Assert(r.Exited(policy, 2, now.Add(-time.Second), now), Equals, false)
+	c.Assert(r.Halted, Equals, true)
+}
+
+func (s *RestartStateUnitTestSuite) TestExited_Backoff(c *C) {
+	policy := servicedefinition.RestartPolicy{Backoff: time.Second, MaxBackoff: 4 * time.Second}
+	r := RestartState{}
+	now := time.Now()
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		c.Assert(r.Exited(policy, 1, now.Add(-time.Second), now), Equals, true)
+		c.Assert(r.RestartAt, Equals, now.Add(expected))
+	}
+	c.Assert(r.Restarts, Equals, 4)
+
+	// a container that stayed up past the longest backoff starts over
+	c.Assert(r.Exited(policy, 1, now.Add(-time.Minute), now), Equals, true)
+	c.Assert(r.RestartAt, Equals, now.Add(time.Second))
+	c.Assert(r.Restarts, Equals, 5)
+
+	r.Reset()
+	c.Assert(r.Backoff(), Equals, time.Duration(0))
+	c.Assert(r.Failures, Equals, 0)
+}
+
+func (s *RestartStateUnitTestSuite) TestExited_MaxRestarts(c *C) {
+	policy := servicedefinition.RestartPolicy{MaxRestarts: 2, Window: time.Minute}
+	r := RestartState{}
+	now := time.Now()
+	c.Assert(r.Exited(policy, 1, now, now), Equals, true)
+	c.Assert(r.Exited(policy, 1, now, now.Add(10*time.Second)), Equals, true)
+	c.Assert(r.Exited(policy, 1, now, now.Add(20*time.Second)), Equals, false)
+	c.Assert(r.Halted, Equals, true)
+
+	// restarts that fall out of the window no longer count
+	r.Halted = false
+	c.Assert(r.Exited(policy, 1, now, now.Add(65*time.Second)), Equals, true)
+	c.Assert(r.Recent, HasLen, 2)
+	c.Assert(r.Restarts, Equals, 3)
+}
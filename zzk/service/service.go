@@ -45,6 +45,7 @@ type ServiceNode struct {
 	Instances                   int
 	RAMCommitment               utils.EngNotation
 	CPUCommitment               int
+	Priority                    int
 	ChangeOptions               []servicedefinition.ChangeOption
 	AddressAssignment           addressassignment.AddressAssignment
 	ShouldHaveAddressAssignment bool
@@ -60,6 +61,7 @@ func NewServiceNodeFromService(s *service.Service) (*ServiceNode, error) {
 		DesiredState:  s.DesiredState,
 		Instances:     s.Instances,
 		CPUCommitment: int(s.CPUCommitment),
+		Priority:      s.Priority,
 		RAMCommitment: s.RAMCommitment,
 		ChangeOptions: s.ChangeOptions,
 		HostPolicy:    s.HostPolicy,
@@ -25,6 +25,7 @@ import (
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 var ErrInstanceNotFound = errors.New("instance is not scheduled to a host")
@@ -63,7 +64,10 @@ type ServiceState struct {
 	Started     time.Time
 	Restarted   time.Time
 	Terminated  time.Time
-	version     interface{}
+
+	RestartPolicy servicedefinition.RestartPolicy // policy the container was started with
+	Restart       RestartState
+	version       interface{}
 }
 
 type CurrentStateContainer struct {